@@ -32,6 +32,7 @@ type Account struct {
 	PublicKey  keypair.PublicKey
 	Address    common.Address
 	SigScheme  s.SignatureScheme
+	signer     Signer //external signer, private key is nil if set
 }
 
 func NewAccount(encrypt string) *Account {
@@ -106,4 +107,5 @@ type AccountMetadata struct {
 	Key       []byte //PrivateKey in encrypted
 	EncAlg    string //Encrypt alg of private key
 	Hash      string //Hash alg
+	HDPath    string //BIP44 path of account derived from hd seed
 }
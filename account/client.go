@@ -74,6 +74,12 @@ type Client interface {
 	ChangeSigScheme(address string, sigScheme s.SignatureScheme) error
 	//Get the underlying wallet data
 	GetWalletData() *WalletData
+	//HasHDSeed return whether the wallet has a hd seed
+	HasHDSeed() bool
+	//SetHDSeed save the seed of BIP39 mnemonic encrypted by passwd in wallet
+	SetHDSeed(mnemonic, passphrase string, passwd []byte) error
+	//NewHDAccount derive a new account from hd seed along the next unused BIP44 path
+	NewHDAccount(label string, typeCode keypair.KeyType, curveCode byte, sigScheme s.SignatureScheme, passwd []byte) (*Account, error)
 }
 
 func Open(path string) (Client, error) {
@@ -170,6 +176,102 @@ func (this *ClientImpl) NewAccount(label string, typeCode keypair.KeyType, curve
 	}, nil
 }
 
+func (this *ClientImpl) HasHDSeed() bool {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	return this.walletData.HDSeed != nil
+}
+
+func (this *ClientImpl) SetHDSeed(mnemonic, passphrase string, passwd []byte) error {
+	if len(passwd) == 0 {
+		return fmt.Errorf("password cannot empty")
+	}
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return err
+	}
+	hdSeed, err := EncryptHDSeed(seed, passwd, this.walletData.Scrypt)
+	if err != nil {
+		return fmt.Errorf("encrypt hd seed error:%s", err)
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if this.walletData.HDSeed != nil {
+		return fmt.Errorf("wallet already has a hd seed")
+	}
+	this.walletData.HDSeed = hdSeed
+	err = this.save()
+	if err != nil {
+		this.walletData.HDSeed = nil
+		return fmt.Errorf("save error:%s", err)
+	}
+	return nil
+}
+
+func (this *ClientImpl) NewHDAccount(label string, typeCode keypair.KeyType, curveCode byte, sigScheme s.SignatureScheme, passwd []byte) (*Account, error) {
+	if len(passwd) == 0 {
+		return nil, fmt.Errorf("password cannot empty")
+	}
+	this.lock.RLock()
+	hdSeed := this.walletData.HDSeed
+	usedPaths := make(map[string]bool)
+	for _, accData := range this.walletData.Accounts {
+		if accData.HDPath != "" {
+			usedPaths[accData.HDPath] = true
+		}
+	}
+	this.lock.RUnlock()
+	if hdSeed == nil {
+		return nil, fmt.Errorf("wallet has no hd seed")
+	}
+	seed, err := hdSeed.Decrypt(passwd)
+	if err != nil {
+		return nil, err
+	}
+	for index := uint32(0); index < HD_HARDENED_OFFSET; index++ {
+		path, err := GetHDPath(typeCode, curveCode, index)
+		if err != nil {
+			return nil, err
+		}
+		if usedPaths[path] {
+			continue
+		}
+		prvkey, err := DeriveHDKey(seed, path, typeCode, curveCode)
+		if err != nil {
+			return nil, fmt.Errorf("derive hd key error:%s", err)
+		}
+		pubkey := prvkey.Public()
+		address := types.AddressFromPubKey(pubkey)
+		addressBase58 := address.ToBase58()
+		if this.GetAccountMetadataByAddress(addressBase58) != nil {
+			//already imported without hd path
+			continue
+		}
+		prvSecret, err := keypair.EncryptWithCustomScrypt(prvkey, addressBase58, passwd, this.walletData.Scrypt)
+		if err != nil {
+			return nil, fmt.Errorf("encryptPrivateKey error:%s", err)
+		}
+		accData := &AccountData{}
+		accData.Label = label
+		accData.SetKeyPair(prvSecret)
+		accData.SigSch = sigScheme.Name()
+		accData.PubKey = hex.EncodeToString(keypair.SerializePublicKey(pubkey))
+		accData.HDPath = path
+
+		err = this.addAccountData(accData)
+		if err != nil {
+			return nil, err
+		}
+		return &Account{
+			PrivateKey: prvkey,
+			PublicKey:  pubkey,
+			Address:    address,
+			SigScheme:  sigScheme,
+		}, nil
+	}
+	return nil, fmt.Errorf("no unused hd path")
+}
+
 func (this *ClientImpl) addAccountData(accData *AccountData) error {
 	if !this.checkSigScheme(accData.Alg, accData.SigSch) {
 		return fmt.Errorf("sigScheme:%s does not match KeyType:%s", accData.SigSch, accData.Alg)
@@ -341,6 +443,7 @@ func (this *ClientImpl) getAccountMetadata(accData *AccountData) *AccountMetadat
 	accMeta.Hash = accData.Hash
 	accMeta.Curve = accData.Param["curve"]
 	accMeta.Salt = accData.Salt
+	accMeta.HDPath = accData.HDPath
 	return accMeta
 }
 
@@ -38,6 +38,7 @@ type AccountData struct {
 	SigSch    string `json:"signatureScheme"`
 	IsDefault bool   `json:"isDefault"`
 	Lock      bool   `json:"lock"`
+	HDPath    string `json:"hdPath,omitempty"`
 }
 
 func (this *AccountData) SetKeyPair(keyinfo *keypair.ProtectedKey) {
@@ -72,13 +73,14 @@ type WalletData struct {
 	Scrypt     *keypair.ScryptParam `json:"scrypt"`
 	Identities []Identity           `json:"identities,omitempty"`
 	Accounts   []*AccountData       `json:"accounts,omitempty"`
+	HDSeed     *HDSeed              `json:"hdSeed,omitempty"`
 	Extra      string               `json:"extra,omitempty"`
 }
 
 func NewWalletData() *WalletData {
 	return &WalletData{
 		Name:       "MyWallet",
-		Version:    "1.1",
+		Version:    WALLET_VERSION,
 		Scrypt:     keypair.GetScryptParameters(),
 		Identities: nil,
 		Extra:      "",
@@ -90,8 +92,10 @@ func (this *WalletData) Clone() *WalletData {
 	w := WalletData{}
 	w.Name = this.Name
 	w.Version = this.Version
-	sp := *this.Scrypt
-	w.Scrypt = &sp
+	if this.Scrypt != nil {
+		sp := *this.Scrypt
+		w.Scrypt = &sp
+	}
 	w.Accounts = make([]*AccountData, len(this.Accounts))
 	for i, v := range this.Accounts {
 		ac := *v
@@ -99,6 +103,10 @@ func (this *WalletData) Clone() *WalletData {
 		w.Accounts[i] = &ac
 	}
 	w.Identities = this.Identities
+	if this.HDSeed != nil {
+		seed := *this.HDSeed
+		w.HDSeed = &seed
+	}
 	w.Extra = this.Extra
 	return &w
 }
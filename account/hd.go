@@ -0,0 +1,287 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	MNEMONIC_ENTROPY_BITS = 128  //Entropy of mnemonic, 12 words
+	HD_COIN_TYPE          = 1024 //Coin type of ONT registered in SLIP-0044
+	HD_HARDENED_OFFSET    = 0x80000000
+	HD_SEED_ENC_ALG       = "aes-256-gcm"
+)
+
+//Curve seeds of SLIP-0010 master key generation
+var hdCurveSeeds = map[byte]string{
+	keypair.P256:    "Nist256p1 seed",
+	keypair.ED25519: "ed25519 seed",
+}
+
+//HDSeed - encrypted seed of hierarchical deterministic wallet, mnemonic is never stored
+type HDSeed struct {
+	EncAlg string               `json:"enc-alg"`
+	Key    []byte               `json:"key"`
+	Salt   []byte               `json:"salt"`
+	Scrypt *keypair.ScryptParam `json:"scrypt"`
+}
+
+//NewMnemonic return a new BIP39 mnemonic
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(MNEMONIC_ENTROPY_BITS)
+	if err != nil {
+		return "", fmt.Errorf("new entropy error:%s", err)
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+//MnemonicToSeed return BIP39 seed of mnemonic, passphrase is the optional BIP39 password
+func MnemonicToSeed(mnemonic, passphrase string) ([]byte, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic error:%s", err)
+	}
+	return seed, nil
+}
+
+//GetHDPath return BIP44 path of ONT account with index. ECDSA P-256 uses m/44'/1024'/0'/0/index,
+//Ed25519 only supports hardened derivation and uses m/44'/1024'/0'/0'/index'
+func GetHDPath(keyType keypair.KeyType, curve byte, index uint32) (string, error) {
+	switch {
+	case keyType == keypair.PK_ECDSA && curve == keypair.P256:
+		return fmt.Sprintf("m/44'/%d'/0'/0/%d", HD_COIN_TYPE, index), nil
+	case keyType == keypair.PK_EDDSA && curve == keypair.ED25519:
+		return fmt.Sprintf("m/44'/%d'/0'/0'/%d'", HD_COIN_TYPE, index), nil
+	default:
+		return "", fmt.Errorf("hd derivation of key type:%d curve:%d is not defined", keyType, curve)
+	}
+}
+
+//ParseHDPath parse path like m/44'/1024'/0'/0/0 into child indexes
+func ParseHDPath(path string) ([]uint32, error) {
+	items := strings.Split(strings.TrimSpace(path), "/")
+	if len(items) == 0 || items[0] != "m" {
+		return nil, fmt.Errorf("invalid hd path:%s", path)
+	}
+	indexes := make([]uint32, 0, len(items)-1)
+	for _, item := range items[1:] {
+		hardened := strings.HasSuffix(item, "'") || strings.HasSuffix(item, "H")
+		if hardened {
+			item = item[:len(item)-1]
+		}
+		index, err := strconv.ParseUint(item, 10, 32)
+		if err != nil || index >= HD_HARDENED_OFFSET {
+			return nil, fmt.Errorf("invalid hd path:%s", path)
+		}
+		if hardened {
+			index += HD_HARDENED_OFFSET
+		}
+		indexes = append(indexes, uint32(index))
+	}
+	return indexes, nil
+}
+
+//DeriveHDKey derive private key from BIP39 seed along path by SLIP-0010
+func DeriveHDKey(seed []byte, path string, keyType keypair.KeyType, curve byte) (keypair.PrivateKey, error) {
+	curveSeed, ok := hdCurveSeeds[curve]
+	if !ok || (keyType != keypair.PK_ECDSA && keyType != keypair.PK_EDDSA) {
+		return nil, fmt.Errorf("hd derivation of key type:%d curve:%d is not defined", keyType, curve)
+	}
+	indexes, err := ParseHDPath(path)
+	if err != nil {
+		return nil, err
+	}
+	key, chainCode := hdMasterKey([]byte(curveSeed), seed, curve)
+	for _, index := range indexes {
+		key, chainCode, err = hdChildKey(key, chainCode, index, curve)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return hdPrivateKey(key, curve)
+}
+
+func hdMasterKey(curveSeed, seed []byte, curve byte) ([]byte, []byte) {
+	data := seed
+	for {
+		mac := hmac.New(sha512.New, curveSeed)
+		mac.Write(data)
+		I := mac.Sum(nil)
+		if curve == keypair.ED25519 || hdValidKey(I[:32]) {
+			return I[:32], I[32:]
+		}
+		data = I
+	}
+}
+
+func hdChildKey(key, chainCode []byte, index uint32, curve byte) ([]byte, []byte, error) {
+	var data []byte
+	if index >= HD_HARDENED_OFFSET {
+		data = append([]byte{0}, key...)
+	} else {
+		if curve == keypair.ED25519 {
+			return nil, nil, errors.New("ed25519 only supports hardened derivation")
+		}
+		data = hdCompressedPubKey(key)
+	}
+	ser := make([]byte, 4)
+	binary.BigEndian.PutUint32(ser, index)
+	for {
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		mac.Write(ser)
+		I := mac.Sum(nil)
+		if curve == keypair.ED25519 {
+			return I[:32], I[32:], nil
+		}
+		if hdValidKey(I[:32]) {
+			child := new(big.Int).Add(new(big.Int).SetBytes(I[:32]), new(big.Int).SetBytes(key))
+			child.Mod(child, elliptic.P256().Params().N)
+			if child.Sign() != 0 {
+				return hdPadKey(child.Bytes()), I[32:], nil
+			}
+		}
+		data = append([]byte{1}, I[32:]...)
+	}
+}
+
+//hdValidKey check the key is in [1, n-1] of P-256
+func hdValidKey(key []byte) bool {
+	k := new(big.Int).SetBytes(key)
+	return k.Sign() != 0 && k.Cmp(elliptic.P256().Params().N) < 0
+}
+
+func hdPadKey(key []byte) []byte {
+	if len(key) >= 32 {
+		return key
+	}
+	return append(make([]byte, 32-len(key)), key...)
+}
+
+func hdCompressedPubKey(key []byte) []byte {
+	return hdCompressedPoint(elliptic.P256().ScalarBaseMult(key))
+}
+
+func hdCompressedPoint(x, y *big.Int) []byte {
+	prefix := byte(0x02)
+	if y.Bit(0) == 1 {
+		prefix = 0x03
+	}
+	return append([]byte{prefix}, hdPadKey(x.Bytes())...)
+}
+
+func hdPrivateKey(key []byte, curve byte) (keypair.PrivateKey, error) {
+	buf := new(bytes.Buffer)
+	switch curve {
+	case keypair.P256:
+		buf.WriteByte(byte(keypair.PK_ECDSA))
+		buf.WriteByte(keypair.P256)
+		buf.Write(key)
+		buf.Write(hdCompressedPubKey(key))
+	case keypair.ED25519:
+		buf.WriteByte(byte(keypair.PK_EDDSA))
+		buf.WriteByte(keypair.ED25519)
+		buf.Write(ed25519.NewKeyFromSeed(key))
+	}
+	return keypair.DeserializePrivateKey(buf.Bytes())
+}
+
+//NewP256PrivateKey return P-256 private key of 32 bytes big-endian scalar
+func NewP256PrivateKey(key []byte) (keypair.PrivateKey, error) {
+	if len(key) != 32 || !hdValidKey(key) {
+		return nil, fmt.Errorf("invalid P-256 private key")
+	}
+	return hdPrivateKey(key, keypair.P256)
+}
+
+//EncryptHDSeed encrypt seed with password
+func EncryptHDSeed(seed, passwd []byte, param *keypair.ScryptParam) (*HDSeed, error) {
+	if param == nil {
+		param = keypair.GetScryptParameters()
+	}
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("generate salt error:%s", err)
+	}
+	aead, nonce, err := scryptCipher(passwd, salt, param)
+	if err != nil {
+		return nil, err
+	}
+	return &HDSeed{
+		EncAlg: HD_SEED_ENC_ALG,
+		Key:    aead.Seal(nil, nonce, seed, nil),
+		Salt:   salt,
+		Scrypt: param,
+	}, nil
+}
+
+//Decrypt return the seed decrypted with password
+func (this *HDSeed) Decrypt(passwd []byte) ([]byte, error) {
+	if this.EncAlg != HD_SEED_ENC_ALG {
+		return nil, fmt.Errorf("unsupported encrypt algorithm:%s", this.EncAlg)
+	}
+	aead, nonce, err := scryptCipher(passwd, this.Salt, this.Scrypt)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := aead.Open(nil, nonce, this.Key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt hd seed error:%s", err)
+	}
+	return seed, nil
+}
+
+func scryptCipher(passwd, salt []byte, param *keypair.ScryptParam) (cipher.AEAD, []byte, error) {
+	if param == nil || param.DKLen < 64 {
+		return nil, nil, errors.New("invalid scrypt param")
+	}
+	derived, err := scrypt.Key(passwd, salt, param.N, param.R, param.P, param.DKLen)
+	if err != nil {
+		return nil, nil, fmt.Errorf("scrypt error:%s", err)
+	}
+	block, err := aes.NewCipher(derived[32:64])
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, derived[:aead.NonceSize()], nil
+}
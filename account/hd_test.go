@@ -0,0 +1,150 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestMnemonicToSeed(t *testing.T) {
+	seed, err := MnemonicToSeed(testMnemonic, "TREZOR")
+	assert.Nil(t, err)
+	assert.Equal(t, "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		hex.EncodeToString(seed))
+
+	_, err = MnemonicToSeed("abandon abandon abandon", "")
+	assert.NotNil(t, err)
+
+	mnemonic, err := NewMnemonic()
+	assert.Nil(t, err)
+	_, err = MnemonicToSeed(mnemonic, "")
+	assert.Nil(t, err)
+}
+
+//test vector 1 of SLIP-0010
+func TestHDChildKey(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+
+	key, chainCode := hdMasterKey([]byte(hdCurveSeeds[keypair.P256]), seed, keypair.P256)
+	assert.Equal(t, "612091aaa12e22dd2abef664f8a01a82cae99ad7441b7ef8110424915c268bc2", hex.EncodeToString(key))
+	assert.Equal(t, "beeb672fe4621673f722f38529c07392fecaa61015c80c34f29ce8b41b3cb6ea", hex.EncodeToString(chainCode))
+	key, chainCode, err := hdChildKey(key, chainCode, HD_HARDENED_OFFSET, keypair.P256)
+	assert.Nil(t, err)
+	assert.Equal(t, "6939694369114c67917a182c59ddb8cafc3004e63ca5d3b84403ba8613debc0c", hex.EncodeToString(key))
+	assert.Equal(t, "3460cea53e6a6bb5fb391eeef3237ffd8724bf0a40e94943c98b83825342ee11", hex.EncodeToString(chainCode))
+	key, _, err = hdChildKey(key, chainCode, 1, keypair.P256)
+	assert.Nil(t, err)
+	assert.Equal(t, "284e9d38d07d21e4e281b645089a94f4cf5a5a81369acf151a1c3a57f18b2129", hex.EncodeToString(key))
+
+	key, chainCode = hdMasterKey([]byte(hdCurveSeeds[keypair.ED25519]), seed, keypair.ED25519)
+	assert.Equal(t, "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7", hex.EncodeToString(key))
+	assert.Equal(t, "90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb", hex.EncodeToString(chainCode))
+	key, chainCode, err = hdChildKey(key, chainCode, HD_HARDENED_OFFSET, keypair.ED25519)
+	assert.Nil(t, err)
+	assert.Equal(t, "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3", hex.EncodeToString(key))
+	_, _, err = hdChildKey(key, chainCode, 1, keypair.ED25519)
+	assert.NotNil(t, err)
+}
+
+func TestParseHDPath(t *testing.T) {
+	indexes, err := ParseHDPath("m/44'/1024'/0'/0/3")
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{HD_HARDENED_OFFSET + 44, HD_HARDENED_OFFSET + 1024, HD_HARDENED_OFFSET, 0, 3}, indexes)
+
+	_, err = ParseHDPath("44'/1024'")
+	assert.NotNil(t, err)
+	_, err = ParseHDPath("m/2147483648")
+	assert.NotNil(t, err)
+
+	_, err = GetHDPath(keypair.PK_SM2, keypair.SM2P256V1, 0)
+	assert.NotNil(t, err)
+}
+
+func TestDeriveHDKey(t *testing.T) {
+	seed, err := MnemonicToSeed(testMnemonic, "")
+	assert.Nil(t, err)
+	for _, curve := range []struct {
+		keyType keypair.KeyType
+		curve   byte
+	}{{keypair.PK_ECDSA, keypair.P256}, {keypair.PK_EDDSA, keypair.ED25519}} {
+		path, err := GetHDPath(curve.keyType, curve.curve, 0)
+		assert.Nil(t, err)
+		key1, err := DeriveHDKey(seed, path, curve.keyType, curve.curve)
+		assert.Nil(t, err)
+		key2, err := DeriveHDKey(seed, path, curve.keyType, curve.curve)
+		assert.Nil(t, err)
+		assert.Equal(t, keypair.SerializePrivateKey(key1), keypair.SerializePrivateKey(key2))
+		assert.Equal(t, curve.keyType, keypair.GetKeyType(key1.Public()))
+	}
+}
+
+func TestHDSeedEncrypt(t *testing.T) {
+	seed, _ := MnemonicToSeed(testMnemonic, "")
+	param := lowSecurityParam
+	hdSeed, err := EncryptHDSeed(seed, testPasswd, &param)
+	assert.Nil(t, err)
+	dec, err := hdSeed.Decrypt(testPasswd)
+	assert.Nil(t, err)
+	assert.Equal(t, seed, dec)
+	_, err = hdSeed.Decrypt([]byte("wrong"))
+	assert.NotNil(t, err)
+}
+
+func TestClientNewHDAccount(t *testing.T) {
+	path := "./wallet_hd_test.dat"
+	defer os.Remove(path)
+	wallet, err := Open(path)
+	assert.Nil(t, err)
+	_, err = wallet.NewHDAccount("", keypair.PK_ECDSA, keypair.P256, s.SHA256withECDSA, testPasswd)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, wallet.SetHDSeed(testMnemonic, "", testPasswd))
+	assert.True(t, wallet.HasHDSeed())
+	assert.NotNil(t, wallet.SetHDSeed(testMnemonic, "", testPasswd))
+	acc1, err := wallet.NewHDAccount("hd1", keypair.PK_ECDSA, keypair.P256, s.SHA256withECDSA, testPasswd)
+	assert.Nil(t, err)
+	acc2, err := wallet.NewHDAccount("hd2", keypair.PK_ECDSA, keypair.P256, s.SHA256withECDSA, testPasswd)
+	assert.Nil(t, err)
+	assert.NotEqual(t, acc1.Address, acc2.Address)
+	assert.Equal(t, "m/44'/1024'/0'/0/1", wallet.GetAccountMetadataByLabel("hd2").HDPath)
+
+	seed, _ := MnemonicToSeed(testMnemonic, "")
+	key, err := DeriveHDKey(seed, "m/44'/1024'/0'/0/0", keypair.PK_ECDSA, keypair.P256)
+	assert.Nil(t, err)
+	assert.Equal(t, types.AddressFromPubKey(key.Public()), acc1.Address)
+
+	//reload wallet and the seed is still usable
+	wallet, err = Open(path)
+	assert.Nil(t, err)
+	acc, err := wallet.GetAccountByLabel("hd1", testPasswd)
+	assert.Nil(t, err)
+	assert.Equal(t, acc1.Address, acc.Address)
+	acc3, err := wallet.NewHDAccount("hd3", keypair.PK_ECDSA, keypair.P256, s.SHA256withECDSA, testPasswd)
+	assert.Nil(t, err)
+	assert.Equal(t, "m/44'/1024'/0'/0/2", wallet.GetAccountMetadataByAddress(acc3.Address.ToBase58()).HDPath)
+}
@@ -0,0 +1,205 @@
+// +build pkcs11
+
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/asn1"
+	"fmt"
+	"hash"
+	"math/big"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+)
+
+//OID of curve P-256 in CKA_EC_PARAMS
+var oidNamedCurveP256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+
+//Hash of ECDSA signature schemes, CKM_ECDSA signs the digest
+var pkcs11SchemeHashes = map[s.SignatureScheme]func() hash.Hash{
+	s.SHA224withECDSA: sha256.New224,
+	s.SHA256withECDSA: sha256.New,
+	s.SHA384withECDSA: sha512.New384,
+	s.SHA512withECDSA: sha512.New,
+}
+
+//Pkcs11Signer sign with an ECDSA P-256 key kept in a PKCS#11 HSM, eg. SoftHSM
+type Pkcs11Signer struct {
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	key     pkcs11.ObjectHandle
+	pubKey  keypair.PublicKey
+	scheme  s.SignatureScheme
+	lock    sync.Mutex
+}
+
+//NewPkcs11Signer login slot of PKCS#11 module and find the key pair with label
+func NewPkcs11Signer(module string, slot uint, pin, label string, scheme s.SignatureScheme) (*Pkcs11Signer, error) {
+	if _, ok := pkcs11SchemeHashes[scheme]; !ok {
+		return nil, fmt.Errorf("unsupported signature scheme:%s", scheme.Name())
+	}
+	ctx := pkcs11.New(module)
+	if ctx == nil {
+		return nil, fmt.Errorf("load pkcs11 module:%s failed", module)
+	}
+	err := ctx.Initialize()
+	if err != nil {
+		ctx.Destroy()
+		return nil, fmt.Errorf("pkcs11 initialize error:%s", err)
+	}
+	signer := &Pkcs11Signer{ctx: ctx, scheme: scheme}
+	err = signer.open(slot, pin, label)
+	if err != nil {
+		signer.Close()
+		return nil, err
+	}
+	return signer, nil
+}
+
+func (this *Pkcs11Signer) open(slot uint, pin, label string) error {
+	session, err := this.ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return fmt.Errorf("pkcs11 open session error:%s", err)
+	}
+	this.session = session
+	err = this.ctx.Login(session, pkcs11.CKU_USER, pin)
+	if err != nil && err != pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+		return fmt.Errorf("pkcs11 login error:%s", err)
+	}
+	this.key, err = this.findObject(pkcs11.CKO_PRIVATE_KEY, label)
+	if err != nil {
+		return err
+	}
+	pubHandle, err := this.findObject(pkcs11.CKO_PUBLIC_KEY, label)
+	if err != nil {
+		return err
+	}
+	attrs, err := this.ctx.GetAttributeValue(session, pubHandle, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, nil),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return fmt.Errorf("pkcs11 get public key error:%s", err)
+	}
+	this.pubKey, err = parsePkcs11PubKey(attrs[0].Value, attrs[1].Value)
+	return err
+}
+
+func (this *Pkcs11Signer) findObject(class uint, label string) (pkcs11.ObjectHandle, error) {
+	err := this.ctx.FindObjectsInit(this.session, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("pkcs11 find objects error:%s", err)
+	}
+	objs, _, err := this.ctx.FindObjects(this.session, 1)
+	this.ctx.FindObjectsFinal(this.session)
+	if err != nil {
+		return 0, fmt.Errorf("pkcs11 find objects error:%s", err)
+	}
+	if len(objs) == 0 {
+		return 0, fmt.Errorf("cannot find pkcs11 key by label:%s", label)
+	}
+	return objs[0], nil
+}
+
+//parsePkcs11PubKey parse P-256 public key from CKA_EC_PARAMS and DER encoded CKA_EC_POINT
+func parsePkcs11PubKey(params, point []byte) (keypair.PublicKey, error) {
+	var oid asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(params, &oid); err != nil || !oid.Equal(oidNamedCurveP256) {
+		return nil, fmt.Errorf("pkcs11 key is not on curve P-256")
+	}
+	var raw []byte
+	if _, err := asn1.Unmarshal(point, &raw); err != nil {
+		//some modules return the raw point
+		raw = point
+	}
+	x, y := elliptic.Unmarshal(elliptic.P256(), raw)
+	if x == nil {
+		return nil, fmt.Errorf("invalid pkcs11 ec point")
+	}
+	return keypair.DeserializePublicKey(hdCompressedPoint(x, y))
+}
+
+func (this *Pkcs11Signer) PubKey() keypair.PublicKey {
+	return this.pubKey
+}
+
+func (this *Pkcs11Signer) Scheme() s.SignatureScheme {
+	return this.scheme
+}
+
+func (this *Pkcs11Signer) Sign(data []byte) ([]byte, error) {
+	h := pkcs11SchemeHashes[this.scheme]()
+	h.Write(data)
+	digest := h.Sum(nil)
+
+	this.lock.Lock()
+	err := this.ctx.SignInit(this.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}, this.key)
+	if err != nil {
+		this.lock.Unlock()
+		return nil, fmt.Errorf("pkcs11 sign init error:%s", err)
+	}
+	raw, err := this.ctx.Sign(this.session, digest)
+	this.lock.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11 sign error:%s", err)
+	}
+	if len(raw) != 64 {
+		return nil, fmt.Errorf("invalid pkcs11 signature length:%d", len(raw))
+	}
+	sig := &s.Signature{
+		Scheme: this.scheme,
+		Value: &s.DSASignature{
+			R:     new(big.Int).SetBytes(raw[:32]),
+			S:     new(big.Int).SetBytes(raw[32:]),
+			Curve: elliptic.P256(),
+		},
+	}
+	sigData, err := s.Serialize(sig)
+	if err != nil {
+		return nil, err
+	}
+	err = verifySignerResult(this.pubKey, data, sigData)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11 %s", err)
+	}
+	return sigData, nil
+}
+
+//Close logout and release the PKCS#11 module
+func (this *Pkcs11Signer) Close() {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if this.session != 0 {
+		this.ctx.Logout(this.session)
+		this.ctx.CloseSession(this.session)
+		this.session = 0
+	}
+	this.ctx.Finalize()
+	this.ctx.Destroy()
+}
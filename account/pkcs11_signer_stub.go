@@ -0,0 +1,52 @@
+// +build !pkcs11
+
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"fmt"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+)
+
+//Pkcs11Signer is not available without the pkcs11 build tag, as the PKCS#11 binding needs cgo
+type Pkcs11Signer struct{}
+
+//NewPkcs11Signer always fail without the pkcs11 build tag
+func NewPkcs11Signer(module string, slot uint, pin, label string, scheme s.SignatureScheme) (*Pkcs11Signer, error) {
+	return nil, fmt.Errorf("pkcs11 signer is not supported by this build, rebuild with -tags pkcs11")
+}
+
+func (this *Pkcs11Signer) PubKey() keypair.PublicKey {
+	return nil
+}
+
+func (this *Pkcs11Signer) Scheme() s.SignatureScheme {
+	return s.SHA256withECDSA
+}
+
+func (this *Pkcs11Signer) Sign(data []byte) ([]byte, error) {
+	return nil, fmt.Errorf("pkcs11 signer is not supported by this build")
+}
+
+//Close do nothing without the pkcs11 build tag
+func (this *Pkcs11Signer) Close() {
+}
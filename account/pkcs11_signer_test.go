@@ -0,0 +1,55 @@
+// +build pkcs11
+
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+//TestPkcs11Signer needs a P-256 key pair in token, e.g. created in SoftHSM by
+//  softhsm2-util --init-token --slot 0 --label ontology --pin 1234 --so-pin 1234
+//  pkcs11-tool --module <module> --login --pin 1234 --keypairgen --key-type EC:prime256v1 --label consensus
+func TestPkcs11Signer(t *testing.T) {
+	module := os.Getenv("ONT_PKCS11_MODULE")
+	if module == "" {
+		t.Skip("ONT_PKCS11_MODULE is not set")
+	}
+	slot, _ := strconv.ParseUint(os.Getenv("ONT_PKCS11_SLOT"), 10, 32)
+	signer, err := NewPkcs11Signer(module, uint(slot), os.Getenv("ONT_PKCS11_PIN"), os.Getenv("ONT_PKCS11_LABEL"), s.SHA256withECDSA)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer signer.Close()
+
+	acc := NewSignerAccount(signer)
+	data := []byte("test data")
+	sigData, err := acc.Sign(data)
+	assert.Nil(t, err)
+	sig, err := s.Deserialize(sigData)
+	assert.Nil(t, err)
+	assert.True(t, s.Verify(acc.PublicKey, data, sig))
+	assert.False(t, acc.SupportVrf())
+}
@@ -0,0 +1,291 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology-crypto/vrf"
+)
+
+//Remote signer protocol over HTTP, request and response are json:
+//  GET  <url>/pubkey  -> {"public_key":"<hex>","scheme":"SHA256withECDSA","vrf":true}
+//  POST <url>/sign    {"data":"<hex>"} -> {"signature":"<hex>"}
+//  POST <url>/vrf     {"data":"<hex>"} -> {"value":"<hex>","proof":"<hex>"}
+//Failed request responses non 200 status with {"error":"<desc>"}. If token is set, request carries
+//header "Authorization: Bearer <token>"
+const (
+	REMOTE_SIGNER_PATH_PUBKEY = "/pubkey"
+	REMOTE_SIGNER_PATH_SIGN   = "/sign"
+	REMOTE_SIGNER_PATH_VRF    = "/vrf"
+	REMOTE_SIGNER_TIMEOUT     = 10 * time.Second
+	REMOTE_SIGNER_MAX_BODY    = 1024 * 1024
+)
+
+type RemotePubKeyRsp struct {
+	PublicKey string `json:"public_key"`
+	Scheme    string `json:"scheme"`
+	Vrf       bool   `json:"vrf"`
+}
+
+type RemoteSignReq struct {
+	Data string `json:"data"`
+}
+
+type RemoteSignRsp struct {
+	Signature string `json:"signature"`
+}
+
+type RemoteVrfRsp struct {
+	Value string `json:"value"`
+	Proof string `json:"proof"`
+}
+
+type remoteErrorRsp struct {
+	Error string `json:"error"`
+}
+
+//RemoteSigner sign by a remote signer service, so that private key never touches local disk
+type RemoteSigner struct {
+	url        string
+	token      string
+	client     *http.Client
+	pubKey     keypair.PublicKey
+	scheme     s.SignatureScheme
+	supportVrf bool
+}
+
+//NewRemoteSigner return a remote signer of url, and fetch its public key
+func NewRemoteSigner(url, token string) (*RemoteSigner, error) {
+	signer := &RemoteSigner{
+		url:    strings.TrimRight(url, "/"),
+		token:  token,
+		client: &http.Client{Timeout: REMOTE_SIGNER_TIMEOUT},
+	}
+	rsp := &RemotePubKeyRsp{}
+	err := signer.request(http.MethodGet, REMOTE_SIGNER_PATH_PUBKEY, nil, rsp)
+	if err != nil {
+		return nil, err
+	}
+	data, err := hex.DecodeString(rsp.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote public key error:%s", err)
+	}
+	signer.pubKey, err = keypair.DeserializePublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote public key error:%s", err)
+	}
+	signer.scheme, err = s.GetScheme(rsp.Scheme)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signature scheme error:%s", err)
+	}
+	signer.supportVrf = rsp.Vrf && vrf.ValidatePublicKey(signer.pubKey)
+	return signer, nil
+}
+
+func (this *RemoteSigner) request(method, path string, req, rsp interface{}) error {
+	var body []byte
+	if req != nil {
+		var err error
+		body, err = json.Marshal(req)
+		if err != nil {
+			return err
+		}
+	}
+	httpReq, err := http.NewRequest(method, this.url+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("new remote signer request error:%s", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if this.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+this.token)
+	}
+	httpRsp, err := this.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("remote signer request error:%s", err)
+	}
+	defer httpRsp.Body.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(httpRsp.Body, REMOTE_SIGNER_MAX_BODY))
+	if err != nil {
+		return fmt.Errorf("read remote signer response error:%s", err)
+	}
+	if httpRsp.StatusCode != http.StatusOK {
+		errRsp := &remoteErrorRsp{}
+		json.Unmarshal(data, errRsp)
+		return fmt.Errorf("remote signer status:%d error:%s", httpRsp.StatusCode, errRsp.Error)
+	}
+	err = json.Unmarshal(data, rsp)
+	if err != nil {
+		return fmt.Errorf("invalid remote signer response error:%s", err)
+	}
+	return nil
+}
+
+func (this *RemoteSigner) PubKey() keypair.PublicKey {
+	return this.pubKey
+}
+
+func (this *RemoteSigner) Scheme() s.SignatureScheme {
+	return this.scheme
+}
+
+func (this *RemoteSigner) Sign(data []byte) ([]byte, error) {
+	rsp := &RemoteSignRsp{}
+	err := this.request(http.MethodPost, REMOTE_SIGNER_PATH_SIGN, &RemoteSignReq{Data: hex.EncodeToString(data)}, rsp)
+	if err != nil {
+		return nil, err
+	}
+	sigData, err := hex.DecodeString(rsp.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signature error:%s", err)
+	}
+	err = verifySignerResult(this.pubKey, data, sigData)
+	if err != nil {
+		return nil, fmt.Errorf("remote %s", err)
+	}
+	return sigData, nil
+}
+
+func (this *RemoteSigner) SupportVrf() bool {
+	return this.supportVrf
+}
+
+func (this *RemoteSigner) Vrf(data []byte) ([]byte, []byte, error) {
+	if !this.supportVrf {
+		return nil, nil, fmt.Errorf("remote signer does not support vrf")
+	}
+	rsp := &RemoteVrfRsp{}
+	err := this.request(http.MethodPost, REMOTE_SIGNER_PATH_VRF, &RemoteSignReq{Data: hex.EncodeToString(data)}, rsp)
+	if err != nil {
+		return nil, nil, err
+	}
+	value, err := hex.DecodeString(rsp.Value)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid remote vrf value error:%s", err)
+	}
+	proof, err := hex.DecodeString(rsp.Proof)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid remote vrf proof error:%s", err)
+	}
+	ok, err := vrf.Verify(this.pubKey, data, value, proof)
+	if err != nil || !ok {
+		return nil, nil, fmt.Errorf("remote vrf verification failed")
+	}
+	return value, proof, nil
+}
+
+//RemoteSignerHandler serve the remote signer protocol with signer
+type RemoteSignerHandler struct {
+	signer Signer
+	token  string
+}
+
+func NewRemoteSignerHandler(signer Signer, token string) *RemoteSignerHandler {
+	return &RemoteSignerHandler{
+		signer: signer,
+		token:  token,
+	}
+}
+
+func (this *RemoteSignerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if this.token != "" {
+		auth := r.Header.Get("Authorization")
+		if subtle.ConstantTimeCompare([]byte(auth), []byte("Bearer "+this.token)) != 1 {
+			writeRemoteSignerRsp(w, http.StatusUnauthorized, &remoteErrorRsp{Error: "unauthorized"})
+			return
+		}
+	}
+	vrfSigner, supportVrf := this.signer.(VrfSigner)
+	supportVrf = supportVrf && vrfSigner.SupportVrf()
+	switch {
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, REMOTE_SIGNER_PATH_PUBKEY):
+		writeRemoteSignerRsp(w, http.StatusOK, &RemotePubKeyRsp{
+			PublicKey: hex.EncodeToString(keypair.SerializePublicKey(this.signer.PubKey())),
+			Scheme:    this.signer.Scheme().Name(),
+			Vrf:       supportVrf,
+		})
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, REMOTE_SIGNER_PATH_SIGN):
+		data, err := readRemoteSignReq(w, r)
+		if err != nil {
+			writeRemoteSignerRsp(w, http.StatusBadRequest, &remoteErrorRsp{Error: err.Error()})
+			return
+		}
+		sigData, err := this.signer.Sign(data)
+		if err != nil {
+			writeRemoteSignerRsp(w, http.StatusInternalServerError, &remoteErrorRsp{Error: err.Error()})
+			return
+		}
+		writeRemoteSignerRsp(w, http.StatusOK, &RemoteSignRsp{Signature: hex.EncodeToString(sigData)})
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, REMOTE_SIGNER_PATH_VRF):
+		if !supportVrf {
+			writeRemoteSignerRsp(w, http.StatusNotImplemented, &remoteErrorRsp{Error: "vrf is not supported"})
+			return
+		}
+		data, err := readRemoteSignReq(w, r)
+		if err != nil {
+			writeRemoteSignerRsp(w, http.StatusBadRequest, &remoteErrorRsp{Error: err.Error()})
+			return
+		}
+		value, proof, err := vrfSigner.Vrf(data)
+		if err != nil {
+			writeRemoteSignerRsp(w, http.StatusInternalServerError, &remoteErrorRsp{Error: err.Error()})
+			return
+		}
+		writeRemoteSignerRsp(w, http.StatusOK, &RemoteVrfRsp{
+			Value: hex.EncodeToString(value),
+			Proof: hex.EncodeToString(proof),
+		})
+	default:
+		writeRemoteSignerRsp(w, http.StatusNotFound, &remoteErrorRsp{Error: "not found"})
+	}
+}
+
+func readRemoteSignReq(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, REMOTE_SIGNER_MAX_BODY))
+	if err != nil {
+		return nil, fmt.Errorf("read request error:%s", err)
+	}
+	req := &RemoteSignReq{}
+	err = json.Unmarshal(body, req)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request error:%s", err)
+	}
+	data, err := hex.DecodeString(req.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data error:%s", err)
+	}
+	return data, nil
+}
+
+func writeRemoteSignerRsp(w http.ResponseWriter, status int, rsp interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(rsp)
+}
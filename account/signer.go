@@ -0,0 +1,108 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"fmt"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology-crypto/vrf"
+	"github.com/ontio/ontology/core/types"
+)
+
+//Signer sign data for an account, the private key may be kept in local wallet, a PKCS#11 HSM or a remote signer
+type Signer interface {
+	//PubKey return public key of signer
+	PubKey() keypair.PublicKey
+	//Scheme return signature scheme of signer
+	Scheme() s.SignatureScheme
+	//Sign return the serialized signature of data
+	Sign(data []byte) ([]byte, error)
+}
+
+//VrfSigner is a signer able to compute VRF, which is needed by vbft proposer
+type VrfSigner interface {
+	Signer
+	//SupportVrf return whether the key of signer supports VRF
+	SupportVrf() bool
+	//Vrf return VRF value and proof of data
+	Vrf(data []byte) ([]byte, []byte, error)
+}
+
+//NewSignerAccount return an account without private key, which signs by signer
+func NewSignerAccount(signer Signer) *Account {
+	return &Account{
+		PublicKey: signer.PubKey(),
+		Address:   types.AddressFromPubKey(signer.PubKey()),
+		SigScheme: signer.Scheme(),
+		signer:    signer,
+	}
+}
+
+//Sign return the serialized signature of data
+func (this *Account) Sign(data []byte) ([]byte, error) {
+	if this.signer != nil {
+		return this.signer.Sign(data)
+	}
+	if this.PrivateKey == nil {
+		return nil, fmt.Errorf("account:%s has no private key", this.Address.ToBase58())
+	}
+	sig, err := s.Sign(this.SigScheme, this.PrivateKey, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.Serialize(sig)
+}
+
+//SupportVrf return whether the account can compute VRF
+func (this *Account) SupportVrf() bool {
+	if this.signer != nil {
+		vrfSigner, ok := this.signer.(VrfSigner)
+		return ok && vrfSigner.SupportVrf()
+	}
+	return this.PrivateKey != nil && vrf.ValidatePrivateKey(this.PrivateKey) && vrf.ValidatePublicKey(this.PublicKey)
+}
+
+//Vrf return VRF value and proof of data
+func (this *Account) Vrf(data []byte) ([]byte, []byte, error) {
+	if this.signer != nil {
+		vrfSigner, ok := this.signer.(VrfSigner)
+		if !ok {
+			return nil, nil, fmt.Errorf("signer of account:%s does not support vrf", this.Address.ToBase58())
+		}
+		return vrfSigner.Vrf(data)
+	}
+	if this.PrivateKey == nil {
+		return nil, nil, fmt.Errorf("account:%s has no private key", this.Address.ToBase58())
+	}
+	return vrf.Vrf(this.PrivateKey, data)
+}
+
+//verifySignerResult check the signature returned by an external signer
+func verifySignerResult(pubKey keypair.PublicKey, data, sigData []byte) error {
+	sig, err := s.Deserialize(sigData)
+	if err != nil {
+		return fmt.Errorf("invalid signature error:%s", err)
+	}
+	if !s.Verify(pubKey, data, sig) {
+		return fmt.Errorf("signature verification failed")
+	}
+	return nil
+}
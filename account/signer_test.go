@@ -0,0 +1,91 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology-crypto/vrf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountSign(t *testing.T) {
+	acc := NewAccount("")
+	data := []byte("test data")
+	sigData, err := acc.Sign(data)
+	assert.Nil(t, err)
+	sig, err := s.Deserialize(sigData)
+	assert.Nil(t, err)
+	assert.True(t, s.Verify(acc.PublicKey, data, sig))
+	assert.True(t, acc.SupportVrf())
+
+	acc = &Account{PublicKey: acc.PublicKey, Address: acc.Address, SigScheme: acc.SigScheme}
+	_, err = acc.Sign(data)
+	assert.NotNil(t, err)
+	assert.False(t, acc.SupportVrf())
+}
+
+func TestRemoteSigner(t *testing.T) {
+	local := NewAccount("")
+	svr := httptest.NewServer(NewRemoteSignerHandler(local, "token"))
+	defer svr.Close()
+
+	_, err := NewRemoteSigner(svr.URL, "wrong")
+	assert.NotNil(t, err)
+
+	signer, err := NewRemoteSigner(svr.URL+"/", "token")
+	assert.Nil(t, err)
+	assert.Equal(t, keypair.SerializePublicKey(local.PublicKey), keypair.SerializePublicKey(signer.PubKey()))
+	assert.Equal(t, local.SigScheme, signer.Scheme())
+	assert.True(t, signer.SupportVrf())
+
+	acc := NewSignerAccount(signer)
+	assert.Nil(t, acc.PrivateKey)
+	assert.Equal(t, local.Address, acc.Address)
+
+	data := []byte("test data")
+	sigData, err := acc.Sign(data)
+	assert.Nil(t, err)
+	sig, err := s.Deserialize(sigData)
+	assert.Nil(t, err)
+	assert.True(t, s.Verify(local.PublicKey, data, sig))
+
+	assert.True(t, acc.SupportVrf())
+	value, proof, err := acc.Vrf(data)
+	assert.Nil(t, err)
+	ok, err := vrf.Verify(local.PublicKey, data, value, proof)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
+
+func TestRemoteSignerNoVrf(t *testing.T) {
+	local := NewAccount("SHA512withEdDSA")
+	svr := httptest.NewServer(NewRemoteSignerHandler(local, ""))
+	defer svr.Close()
+
+	signer, err := NewRemoteSigner(svr.URL, "")
+	assert.Nil(t, err)
+	acc := NewSignerAccount(signer)
+	assert.False(t, acc.SupportVrf())
+	_, _, err = acc.Vrf([]byte("test data"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/common"
+)
+
+const (
+	WALLET_BACKUP_TYPE    = "ontology-wallet-backup"
+	WALLET_BACKUP_VERSION = "1.0"
+	WALLET_BACKUP_ENC_ALG = "aes-256-gcm"
+)
+
+//WalletBackup - single file backup of wallet data, encrypted by backup password.
+//Checksum is sha256 of encrypted data, so that the integrity of backup can be checked without password
+type WalletBackup struct {
+	Type     string               `json:"type"`
+	Version  string               `json:"version"`
+	EncAlg   string               `json:"enc-alg"`
+	Scrypt   *keypair.ScryptParam `json:"scrypt"`
+	Salt     []byte               `json:"salt"`
+	Data     []byte               `json:"data"`
+	Checksum string               `json:"checksum"`
+}
+
+//NewWalletBackup return backup of wallet data encrypted by passwd
+func NewWalletBackup(wallet *WalletData, passwd []byte, param *keypair.ScryptParam) (*WalletBackup, error) {
+	if len(passwd) == 0 {
+		return nil, fmt.Errorf("password cannot empty")
+	}
+	if param == nil {
+		param = keypair.GetScryptParameters()
+	}
+	data, err := json.Marshal(wallet)
+	if err != nil {
+		return nil, fmt.Errorf("marshal wallet data error:%s", err)
+	}
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("generate salt error:%s", err)
+	}
+	backup := &WalletBackup{
+		Type:    WALLET_BACKUP_TYPE,
+		Version: WALLET_BACKUP_VERSION,
+		EncAlg:  WALLET_BACKUP_ENC_ALG,
+		Scrypt:  param,
+		Salt:    salt,
+	}
+	aead, nonce, err := scryptCipher(passwd, salt, param)
+	if err != nil {
+		return nil, err
+	}
+	backup.Data = aead.Seal(nil, nonce, data, backup.header())
+	backup.Checksum = backup.checksum()
+	return backup, nil
+}
+
+//header is the additional data of encryption, to protect the header from modification
+func (this *WalletBackup) header() []byte {
+	return []byte(this.Type + "|" + this.Version + "|" + this.EncAlg)
+}
+
+func (this *WalletBackup) checksum() string {
+	hash := sha256.Sum256(this.Data)
+	return hex.EncodeToString(hash[:])
+}
+
+//Verify check the format and integrity of backup
+func (this *WalletBackup) Verify() error {
+	if this.Type != WALLET_BACKUP_TYPE {
+		return fmt.Errorf("invalid backup type:%s", this.Type)
+	}
+	if this.Version != WALLET_BACKUP_VERSION {
+		return fmt.Errorf("unsupported backup version:%s", this.Version)
+	}
+	if this.EncAlg != WALLET_BACKUP_ENC_ALG {
+		return fmt.Errorf("unsupported encrypt algorithm:%s", this.EncAlg)
+	}
+	if len(this.Data) == 0 {
+		return errors.New("backup data is empty")
+	}
+	if this.checksum() != this.Checksum {
+		return errors.New("backup checksum mismatch, backup file may be corrupted")
+	}
+	return nil
+}
+
+//Restore return wallet data decrypted by passwd
+func (this *WalletBackup) Restore(passwd []byte) (*WalletData, error) {
+	err := this.Verify()
+	if err != nil {
+		return nil, err
+	}
+	aead, nonce, err := scryptCipher(passwd, this.Salt, this.Scrypt)
+	if err != nil {
+		return nil, err
+	}
+	data, err := aead.Open(nil, nonce, this.Data, this.header())
+	if err != nil {
+		return nil, errors.New("decrypt backup failed, wrong password")
+	}
+	wallet := &WalletData{}
+	err = json.Unmarshal(data, wallet)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal wallet data error:%s", err)
+	}
+	return wallet, nil
+}
+
+func (this *WalletBackup) Save(path string) error {
+	if common.FileExisted(path) {
+		return fmt.Errorf("file:%s already exist", path)
+	}
+	data, err := json.Marshal(this)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+func LoadWalletBackup(path string) (*WalletBackup, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	backup := &WalletBackup{}
+	err = json.Unmarshal(data, backup)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup file error:%s", err)
+	}
+	return backup, nil
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletBackup(t *testing.T) {
+	backupFile := "w.backup"
+	defer os.Remove(backupFile)
+
+	wallet := NewWalletData()
+	acc, _ := genAccountData()
+	wallet.AddAccount(acc)
+	passwd := []byte("backup")
+	backup, err := NewWalletBackup(wallet, passwd, GetLowSecurityScryptParam())
+	if !assert.Nil(t, err) {
+		return
+	}
+	err = backup.Save(backupFile)
+	assert.Nil(t, err)
+	err = backup.Save(backupFile)
+	assert.NotNil(t, err)
+
+	backup, err = LoadWalletBackup(backupFile)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, backup.Verify())
+	_, err = backup.Restore([]byte("wrong"))
+	assert.NotNil(t, err)
+	wallet2, err := backup.Restore(passwd)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, wallet.Scrypt, wallet2.Scrypt)
+	assert.Equal(t, acc.Address, wallet2.Accounts[0].Address)
+	assert.Equal(t, acc.Key, wallet2.Accounts[0].Key)
+
+	backup.Data[0] ^= 1
+	assert.NotNil(t, backup.Verify())
+	_, err = backup.Restore(passwd)
+	assert.NotNil(t, err)
+	backup.Data[0] ^= 1
+
+	backup.Version = "2.0"
+	_, err = backup.Restore(passwd)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology/core/types"
+)
+
+const (
+	WALLET_VERSION = "1.1"
+	KEY_ENC_ALG    = "aes-256-gcm" //Encrypt algorithm of private key in current wallet version
+)
+
+//PasswordFunc return password of key to migrate. id is address of account, ONT ID of identity,
+//or empty string for hd seed
+type PasswordFunc func(id string) ([]byte, error)
+
+//MigrateInfo - changes made by wallet migration
+type MigrateInfo struct {
+	FromVersion string
+	ToVersion   string
+	Reencrypted int //Number of keys re-encrypted
+	Fixed       int //Number of accounts fixed with missing fields
+}
+
+//GetLowSecurityScryptParam return scrypt param for low performance devices
+func GetLowSecurityScryptParam() *keypair.ScryptParam {
+	param := lowSecurityParam
+	return &param
+}
+
+//Migrate upgrade wallet data to current version. Missing fields of old versions are filled by default,
+//and keys not encrypted in current format or by param are re-encrypted. Nil param keeps scrypt param of wallet.
+//Wallet data is not modified if migration failed.
+func (this *WalletData) Migrate(param *keypair.ScryptParam, getPasswd PasswordFunc) (*MigrateInfo, error) {
+	oldParam := this.Scrypt
+	if oldParam == nil {
+		//wallet of early version has no scrypt param, which is the default
+		oldParam = keypair.GetScryptParameters()
+	}
+	if param == nil {
+		param = oldParam
+	}
+	info := &MigrateInfo{
+		FromVersion: this.Version,
+		ToVersion:   WALLET_VERSION,
+	}
+	changeParam := *oldParam != *param
+
+	wallet := this.Clone()
+	wallet.Identities = make([]Identity, len(this.Identities))
+	for i, identity := range this.Identities {
+		identity.Control = append([]Controller{}, identity.Control...)
+		wallet.Identities[i] = identity
+	}
+	hasDefault := false
+	for _, accData := range wallet.Accounts {
+		if accData.IsDefault {
+			if hasDefault {
+				accData.IsDefault = false
+				info.Fixed++
+			}
+			hasDefault = true
+		}
+		if changeParam || accData.EncAlg != KEY_ENC_ALG || accData.PubKey == "" {
+			passwd, err := getPasswd(accData.Address)
+			if err != nil {
+				return nil, err
+			}
+			prvkey, prvSecret, err := reencryptKey(accData.GetKeyPair(), passwd, param, oldParam)
+			if err != nil {
+				return nil, fmt.Errorf("account:%s %s", accData.Address, err)
+			}
+			accData.SetKeyPair(prvSecret)
+			info.Reencrypted++
+			if accData.PubKey == "" {
+				accData.PubKey = hex.EncodeToString(keypair.SerializePublicKey(prvkey.Public()))
+				info.Fixed++
+			}
+		}
+		if accData.SigSch == "" {
+			accData.SigSch = defaultSigScheme(accData.Alg).Name()
+			info.Fixed++
+		}
+	}
+	if !hasDefault && len(wallet.Accounts) > 0 {
+		wallet.Accounts[0].IsDefault = true
+		info.Fixed++
+	}
+	for i := range wallet.Identities {
+		identity := &wallet.Identities[i]
+		var passwd []byte
+		for j := range identity.Control {
+			ctrl := &identity.Control[j]
+			if !changeParam && ctrl.EncAlg == KEY_ENC_ALG {
+				continue
+			}
+			if passwd == nil {
+				var err error
+				passwd, err = getPasswd(identity.ID)
+				if err != nil {
+					return nil, err
+				}
+			}
+			//key of ONT ID may be encrypted by default scrypt param regardless of wallet
+			_, prvSecret, err := reencryptKey(&ctrl.ProtectedKey, passwd, param, oldParam, keypair.GetScryptParameters())
+			if err != nil {
+				return nil, fmt.Errorf("identity:%s controller:%s %s", identity.ID, ctrl.ID, err)
+			}
+			ctrl.ProtectedKey = *prvSecret
+			info.Reencrypted++
+		}
+	}
+	if wallet.HDSeed != nil && (wallet.HDSeed.Scrypt == nil || *wallet.HDSeed.Scrypt != *param) {
+		passwd, err := getPasswd("")
+		if err != nil {
+			return nil, err
+		}
+		seed, err := wallet.HDSeed.Decrypt(passwd)
+		if err != nil {
+			return nil, err
+		}
+		wallet.HDSeed, err = EncryptHDSeed(seed, passwd, param)
+		if err != nil {
+			return nil, err
+		}
+		info.Reencrypted++
+	}
+	wallet.Version = WALLET_VERSION
+	wallet.Scrypt = param
+	*this = *wallet
+	return info, nil
+}
+
+//reencryptKey decrypt key by the first valid param of oldParams, and encrypt it by param
+func reencryptKey(prvSecret *keypair.ProtectedKey, passwd []byte, param *keypair.ScryptParam, oldParams ...*keypair.ScryptParam) (keypair.PrivateKey, *keypair.ProtectedKey, error) {
+	var prvkey keypair.PrivateKey
+	var err error
+	for _, oldParam := range oldParams {
+		prvkey, err = keypair.DecryptWithCustomScrypt(prvSecret, passwd, oldParam)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("decrypt private key error:%s", err)
+	}
+	address := types.AddressFromPubKey(prvkey.Public()).ToBase58()
+	if prvSecret.Address != "" && address != prvSecret.Address {
+		return nil, nil, fmt.Errorf("address of private key:%s mismatch", address)
+	}
+	newSecret, err := keypair.EncryptWithCustomScrypt(prvkey, address, passwd, param)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encrypt private key error:%s", err)
+	}
+	return prvkey, newSecret, nil
+}
+
+//defaultSigScheme return default signature scheme of key algorithm
+func defaultSigScheme(alg string) s.SignatureScheme {
+	switch alg {
+	case "SM2":
+		return s.SM3withSM2
+	case "Ed25519":
+		return s.SHA512withEDDSA
+	default:
+		return s.SHA256withECDSA
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletMigrate(t *testing.T) {
+	passwd := []byte("123456")
+	wallet := NewWalletData()
+	wallet.Version = "1.0"
+	wallet.Scrypt = nil
+	acc1, _ := genAccountData()
+	acc2, _ := genAccountData()
+	pubKey := acc2.PubKey
+	acc2.PubKey = ""
+	acc2.SigSch = ""
+	wallet.AddAccount(acc1)
+	wallet.AddAccount(acc2)
+	identity, err := NewIdentity("id", keypair.PK_ECDSA, keypair.P256, passwd)
+	assert.Nil(t, err)
+	wallet.AddIdentity(identity)
+
+	getPasswd := func(id string) ([]byte, error) {
+		return passwd, nil
+	}
+	param := GetLowSecurityScryptParam()
+	info, err := wallet.Migrate(param, getPasswd)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "1.0", info.FromVersion)
+	assert.Equal(t, WALLET_VERSION, wallet.Version)
+	assert.Equal(t, *param, *wallet.Scrypt)
+	assert.Equal(t, 3, info.Reencrypted)
+	assert.True(t, wallet.Accounts[0].IsDefault)
+	assert.Equal(t, pubKey, wallet.Accounts[1].PubKey)
+	assert.Equal(t, "SHA256withECDSA", wallet.Accounts[1].SigSch)
+	for _, accData := range wallet.Accounts {
+		_, err = keypair.DecryptWithCustomScrypt(accData.GetKeyPair(), passwd, param)
+		assert.Nil(t, err)
+	}
+	_, err = keypair.DecryptWithCustomScrypt(&wallet.Identities[0].Control[0].ProtectedKey, passwd, param)
+	assert.Nil(t, err)
+	//key of identity is not modified in place
+	_, err = keypair.DecryptWithCustomScrypt(&identity.Control[0].ProtectedKey, passwd, keypair.GetScryptParameters())
+	assert.Nil(t, err)
+
+	info, err = wallet.Migrate(nil, func(id string) ([]byte, error) {
+		return nil, errors.New("password should not be required")
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, info.Reencrypted)
+}
+
+func TestWalletMigrateFailed(t *testing.T) {
+	wallet := NewWalletData()
+	acc, _ := genAccountData()
+	wallet.AddAccount(acc)
+	key := acc.Key
+	_, err := wallet.Migrate(GetLowSecurityScryptParam(), func(id string) ([]byte, error) {
+		return []byte("wrong"), nil
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, keypair.GetScryptParameters(), wallet.Scrypt)
+	assert.Equal(t, key, wallet.Accounts[0].Key)
+	assert.False(t, wallet.Accounts[0].IsDefault)
+}
@@ -55,6 +55,8 @@ You can use ./Ontology account --help command to view help information of wallet
 					utils.AccountDefaultFlag,
 					utils.AccountLabelFlag,
 					utils.IdentityFlag,
+					utils.AccountHDFlag,
+					utils.AccountBIP39PassphraseFlag,
 					utils.WalletFileFlag,
 				},
 				Description: ` Add a new account to wallet.
@@ -86,7 +88,28 @@ You can use ./Ontology account --help command to view help information of wallet
    2 sm2    | sm2p256v1 256  | SM3withSM2
    ---------|----------------|----------------------
    3 ed25519|   25519 256    | SHA512withEdDSA
-   -------------------------------------------------`,
+   -------------------------------------------------
+   With --hd option, account is derived from the mnemonic seed of wallet along BIP44 path m/44'/1024'/0'/0/index
+   for ecdsa P-256, or m/44'/1024'/0'/0'/index' for ed25519. Other keys are not supported by hd derivation.
+   If wallet has no seed, a new mnemonic is generated and shown only once, please write it down.`,
+			},
+			{
+				Action:    accountRecover,
+				Name:      "recover",
+				Usage:     "Recover hd accounts from mnemonic",
+				ArgsUsage: "[sub-command options]",
+				Flags: []cli.Flag{
+					utils.AccountQuantityFlag,
+					utils.AccountTypeFlag,
+					utils.AccountKeylenFlag,
+					utils.AccountSigSchemeFlag,
+					utils.AccountDefaultFlag,
+					utils.AccountLabelFlag,
+					utils.AccountBIP39PassphraseFlag,
+					utils.WalletFileFlag,
+				},
+				Description: `Recover hd accounts from BIP39 mnemonic. The seed of mnemonic is saved in wallet encrypted by password,
+   and the first <quantity> accounts are derived along BIP44 path. Wallet should not have a seed already.`,
 			},
 			{
 				Action:    accountList,
@@ -150,22 +173,7 @@ You can use ./Ontology account --help command to view help information of wallet
 )
 
 func accountCreate(ctx *cli.Context) error {
-	reader := bufio.NewReader(os.Stdin)
-	optionType := ""
-	optionCurve := ""
-	optionScheme := ""
-
-	optionDefault := ctx.IsSet(utils.GetFlagName(utils.AccountDefaultFlag))
-	if !optionDefault {
-		optionType = checkType(ctx, reader)
-		optionCurve = checkCurve(ctx, reader, &optionType)
-		optionScheme = checkScheme(ctx, reader, &optionType)
-	} else {
-		PrintInfoMsg("Use default setting '-t ecdsa -b 256 -s SHA256withECDSA'")
-		PrintInfoMsg("	signature algorithm: %s", keyTypeMap[optionType].name)
-		PrintInfoMsg("	curve: %s", curveMap[optionCurve].name)
-		PrintInfoMsg("	signature scheme: %s", schemeMap[optionScheme].name)
-	}
+	optionType, optionCurve, optionScheme := checkKeyOptions(ctx)
 	optionFile := checkFileName(ctx)
 	optionNumber := checkNumber(ctx)
 	optionLabel := checkLabel(ctx)
@@ -178,6 +186,21 @@ func accountCreate(ctx *cli.Context) error {
 		return fmt.Errorf("open wallet error:%s", err)
 	}
 	defer common.ClearPasswd(pass)
+	optionHD := ctx.Bool(utils.GetFlagName(utils.AccountHDFlag))
+	if optionHD {
+		if ctx.Bool(utils.IdentityFlag.Name) {
+			return fmt.Errorf("ONT ID cannot be derived from hd seed")
+		}
+		if _, err := account.GetHDPath(keyType, curve, 0); err != nil {
+			return err
+		}
+		if !wallet.HasHDSeed() {
+			err = newHDSeed(ctx, wallet, pass)
+			if err != nil {
+				return err
+			}
+		}
+	}
 	if ctx.Bool(utils.IdentityFlag.Name) {
 		// create ONT ID
 		wd := wallet.GetWalletData()
@@ -199,21 +222,138 @@ func accountCreate(ctx *cli.Context) error {
 		if label != "" && optionNumber > 1 {
 			label = fmt.Sprintf("%s%d", label, i+1)
 		}
-		acc, err := wallet.NewAccount(label, keyType, curve, scheme, pass)
+		var acc *account.Account
+		if optionHD {
+			acc, err = wallet.NewHDAccount(label, keyType, curve, scheme, pass)
+		} else {
+			acc, err = wallet.NewAccount(label, keyType, curve, scheme, pass)
+		}
 		if err != nil {
 			return fmt.Errorf("new account error:%s", err)
 		}
-		PrintInfoMsg("Index:%d", wallet.GetAccountNum())
-		PrintInfoMsg("Label:%s", label)
-		PrintInfoMsg("Address:%s", acc.Address.ToBase58())
-		PrintInfoMsg("Public key:%s", hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey)))
-		PrintInfoMsg("Signature scheme:%s", acc.SigScheme.Name())
+		printNewAccount(wallet, acc, label)
 	}
 
 	PrintInfoMsg("Create account successfully.")
 	return nil
 }
 
+func accountRecover(ctx *cli.Context) error {
+	optionType, optionCurve, optionScheme := checkKeyOptions(ctx)
+	optionFile := checkFileName(ctx)
+	optionNumber := checkNumber(ctx)
+	optionLabel := checkLabel(ctx)
+	keyType := keyTypeMap[optionType].code
+	curve := curveMap[optionCurve].code
+	scheme := schemeMap[optionScheme].code
+	if _, err := account.GetHDPath(keyType, curve, 0); err != nil {
+		return err
+	}
+	wallet, err := account.Open(optionFile)
+	if err != nil {
+		return fmt.Errorf("open wallet error:%s", err)
+	}
+	if wallet.HasHDSeed() {
+		return fmt.Errorf("wallet:%s already has a hd seed", optionFile)
+	}
+	mnemonic, err := password.GetHiddenInput("Mnemonic:")
+	if err != nil {
+		return fmt.Errorf("input mnemonic error:%s", err)
+	}
+	defer common.ClearPasswd(mnemonic)
+	passphrase, err := getBIP39Passphrase(ctx)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Please input a password to encrypt the seed and accounts")
+	pass, err := password.GetConfirmedPassword()
+	if err != nil {
+		return err
+	}
+	defer common.ClearPasswd(pass)
+	err = wallet.SetHDSeed(string(mnemonic), passphrase, pass)
+	if err != nil {
+		return fmt.Errorf("set hd seed error:%s", err)
+	}
+	for i := 0; i < optionNumber; i++ {
+		label := optionLabel
+		if label != "" && optionNumber > 1 {
+			label = fmt.Sprintf("%s%d", label, i+1)
+		}
+		acc, err := wallet.NewHDAccount(label, keyType, curve, scheme, pass)
+		if err != nil {
+			return fmt.Errorf("recover account error:%s", err)
+		}
+		printNewAccount(wallet, acc, label)
+	}
+
+	PrintInfoMsg("Recover account successfully.")
+	return nil
+}
+
+//checkKeyOptions return key type, curve and signature scheme of new account
+func checkKeyOptions(ctx *cli.Context) (string, string, string) {
+	reader := bufio.NewReader(os.Stdin)
+	optionType := ""
+	optionCurve := ""
+	optionScheme := ""
+
+	optionDefault := ctx.IsSet(utils.GetFlagName(utils.AccountDefaultFlag))
+	if !optionDefault {
+		optionType = checkType(ctx, reader)
+		optionCurve = checkCurve(ctx, reader, &optionType)
+		optionScheme = checkScheme(ctx, reader, &optionType)
+	} else {
+		PrintInfoMsg("Use default setting '-t ecdsa -b 256 -s SHA256withECDSA'")
+		PrintInfoMsg("	signature algorithm: %s", keyTypeMap[optionType].name)
+		PrintInfoMsg("	curve: %s", curveMap[optionCurve].name)
+		PrintInfoMsg("	signature scheme: %s", schemeMap[optionScheme].name)
+	}
+	return optionType, optionCurve, optionScheme
+}
+
+//newHDSeed generate a new mnemonic and save its seed into wallet
+func newHDSeed(ctx *cli.Context, wallet account.Client, pass []byte) error {
+	mnemonic, err := account.NewMnemonic()
+	if err != nil {
+		return err
+	}
+	passphrase, err := getBIP39Passphrase(ctx)
+	if err != nil {
+		return err
+	}
+	err = wallet.SetHDSeed(mnemonic, passphrase, pass)
+	if err != nil {
+		return fmt.Errorf("set hd seed error:%s", err)
+	}
+	PrintWarnMsg("Please write down the mnemonic and keep it safe, it will not be shown again:")
+	PrintInfoMsg("%s", mnemonic)
+	return nil
+}
+
+func getBIP39Passphrase(ctx *cli.Context) (string, error) {
+	if !ctx.Bool(utils.GetFlagName(utils.AccountBIP39PassphraseFlag)) {
+		return "", nil
+	}
+	passphrase, err := password.GetHiddenInput("BIP39 passphrase:")
+	if err != nil {
+		return "", fmt.Errorf("input passphrase error:%s", err)
+	}
+	return string(passphrase), nil
+}
+
+func printNewAccount(wallet account.Client, acc *account.Account, label string) {
+	PrintInfoMsg("Index:%d", wallet.GetAccountNum())
+	PrintInfoMsg("Label:%s", label)
+	PrintInfoMsg("Address:%s", acc.Address.ToBase58())
+	PrintInfoMsg("Public key:%s", hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey)))
+	PrintInfoMsg("Signature scheme:%s", acc.SigScheme.Name())
+	accMeta := wallet.GetAccountMetadataByAddress(acc.Address.ToBase58())
+	if accMeta != nil && accMeta.HDPath != "" {
+		PrintInfoMsg("HD path:%s", accMeta.HDPath)
+	}
+}
+
 func accountList(ctx *cli.Context) error {
 	optionFile := checkFileName(ctx)
 	wallet, err := account.Open(optionFile)
@@ -264,6 +404,9 @@ func accountList(ctx *cli.Context) error {
 		PrintInfoMsg("	Curve: %v", accMeta.Curve)
 		PrintInfoMsg("	Key length: %v bits", len(accMeta.Key)*8)
 		PrintInfoMsg("	Public key: %v", accMeta.PubKey)
+		if accMeta.HDPath != "" {
+			PrintInfoMsg("	HD path: %v", accMeta.HDPath)
+		}
 		PrintInfoMsg("	Signature scheme: %v\n", accMeta.SigSch)
 	}
 	return nil
@@ -20,13 +20,17 @@ package common
 
 import (
 	"fmt"
+	s "github.com/ontio/ontology-crypto/signature"
 	"github.com/ontio/ontology/account"
 	"github.com/ontio/ontology/cmd/utils"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/password"
 	"github.com/urfave/cli"
+	"io/ioutil"
+	"os"
 	"strconv"
+	"strings"
 )
 
 func GetPasswd(ctx *cli.Context) ([]byte, error) {
@@ -135,6 +139,72 @@ func GetAccount(ctx *cli.Context, address ...string) (*account.Account, error) {
 	return GetAccountMulti(wallet, passwd, accAddr)
 }
 
+//Signer types of consensus account
+const (
+	SIGNER_WALLET = "wallet"
+	SIGNER_PKCS11 = "pkcs11"
+	SIGNER_REMOTE = "remote"
+)
+
+//GetSignerAccount return account of signer type. The private key of pkcs11 and remote signer is not in memory
+func GetSignerAccount(ctx *cli.Context) (*account.Account, error) {
+	signerType := ctx.String(utils.GetFlagName(utils.SignerFlag))
+	switch signerType {
+	case "", SIGNER_WALLET:
+		return GetAccount(ctx)
+	case SIGNER_PKCS11:
+		module := ctx.String(utils.GetFlagName(utils.Pkcs11ModuleFlag))
+		label := ctx.String(utils.GetFlagName(utils.Pkcs11LabelFlag))
+		if module == "" || label == "" {
+			return nil, fmt.Errorf("Please config pkcs11 module and key label using --pkcs11-module and --pkcs11-label flag")
+		}
+		pin, err := GetPasswd(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer ClearPasswd(pin)
+		signer, err := account.NewPkcs11Signer(module, ctx.Uint(utils.GetFlagName(utils.Pkcs11SlotFlag)), string(pin),
+			label, s.SHA256withECDSA)
+		if err != nil {
+			return nil, err
+		}
+		return account.NewSignerAccount(signer), nil
+	case SIGNER_REMOTE:
+		url := ctx.String(utils.GetFlagName(utils.RemoteSignerUrlFlag))
+		if url == "" {
+			return nil, fmt.Errorf("Please config remote signer using --remote-signer-url flag")
+		}
+		token, err := GetRemoteSignerToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := account.NewRemoteSigner(url, token)
+		if err != nil {
+			return nil, err
+		}
+		return account.NewSignerAccount(signer), nil
+	default:
+		return nil, fmt.Errorf("unknown signer:%s", signerType)
+	}
+}
+
+//GetRemoteSignerToken return the remote signer token read from token file, or from env if file is not set
+func GetRemoteSignerToken(ctx *cli.Context) (string, error) {
+	tokenFile := ctx.String(utils.GetFlagName(utils.RemoteSignerTokenFileFlag))
+	if tokenFile == "" {
+		return strings.TrimSpace(os.Getenv(utils.REMOTE_SIGNER_TOKEN_ENV)), nil
+	}
+	data, err := ioutil.ReadFile(tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("read remote signer token file error:%s", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("remote signer token file:%s is empty", tokenFile)
+	}
+	return token, nil
+}
+
 func IsBase58Address(address string) bool {
 	if address == "" {
 		return false
@@ -19,6 +19,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/ontio/ontology/cmd/utils"
 	"github.com/ontio/ontology/common"
@@ -26,6 +27,7 @@ import (
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/smartcontract/service/native/governance"
 	"github.com/urfave/cli"
+	"io/ioutil"
 )
 
 func SetOntologyConfig(ctx *cli.Context) (*config.OntologyConfig, error) {
@@ -40,6 +42,12 @@ func SetOntologyConfig(ctx *cli.Context) (*config.OntologyConfig, error) {
 	setRpcConfig(ctx, cfg.Rpc)
 	setRestfulConfig(ctx, cfg.Restful)
 	setWebSocketConfig(ctx, cfg.Ws)
+	setGraphQLConfig(ctx, cfg.GraphQL)
+	setMetricsConfig(ctx, cfg.Metrics)
+	err = setAuthConfig(ctx, cfg.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("setAuthConfig error:%s", err)
+	}
 	if cfg.Genesis.ConsensusType == config.CONSENSUS_TYPE_SOLO {
 		cfg.Ws.EnableHttpWs = true
 		cfg.Restful.EnableHttpRestful = true
@@ -79,6 +87,7 @@ func setGenesis(ctx *cli.Context, cfg *config.OntologyConfig) error {
 		if cfg.Genesis.SOLO.GenBlockTime <= 1 {
 			cfg.Genesis.SOLO.GenBlockTime = config.DEFAULT_GEN_BLOCK_TIME
 		}
+		cfg.Genesis.SOLO.GenBlockOnDemand = ctx.Bool(utils.GetFlagName(utils.TestModeGenBlockOnDemandFlag))
 		return nil
 	}
 
@@ -125,6 +134,8 @@ func setGenesis(ctx *cli.Context, cfg *config.OntologyConfig) error {
 func setCommonConfig(ctx *cli.Context, cfg *config.CommonConfig) {
 	cfg.LogLevel = ctx.Uint(utils.GetFlagName(utils.LogLevelFlag))
 	cfg.EnableEventLog = !ctx.Bool(utils.GetFlagName(utils.DisableEventLogFlag))
+	cfg.EnableAddressIndex = ctx.Bool(utils.GetFlagName(utils.EnableAddressIndexFlag))
+	cfg.EnableContractAbi = ctx.Bool(utils.GetFlagName(utils.EnableContractAbiFlag))
 	cfg.GasLimit = ctx.Uint64(utils.GetFlagName(utils.GasLimitFlag))
 	cfg.GasPrice = ctx.Uint64(utils.GetFlagName(utils.GasPriceFlag))
 	cfg.DataDir = ctx.String(utils.GetFlagName(utils.DataDirFlag))
@@ -146,6 +157,8 @@ func setP2PNodeConfig(ctx *cli.Context, cfg *config.P2PNodeConfig) {
 	cfg.MaxConnInBound = ctx.Uint(utils.GetFlagName(utils.MaxConnInBoundFlag))
 	cfg.MaxConnOutBound = ctx.Uint(utils.GetFlagName(utils.MaxConnOutBoundFlag))
 	cfg.MaxConnInBoundForSingleIP = ctx.Uint(utils.GetFlagName(utils.MaxConnInBoundForSingleIPFlag))
+	cfg.IsEncrypted = ctx.Bool(utils.GetFlagName(utils.P2PEncryptFlag))
+	cfg.NodeKeyPath = ctx.String(utils.GetFlagName(utils.NodeKeyFileFlag))
 
 	rsvfile := ctx.String(utils.GetFlagName(utils.ReservedPeersFileFlag))
 	if cfg.ReservedPeersOnly {
@@ -172,6 +185,7 @@ func setRpcConfig(ctx *cli.Context, cfg *config.RpcConfig) {
 	cfg.EnableHttpJsonRpc = !ctx.Bool(utils.GetFlagName(utils.RPCDisabledFlag))
 	cfg.HttpJsonPort = ctx.Uint(utils.GetFlagName(utils.RPCPortFlag))
 	cfg.HttpLocalPort = ctx.Uint(utils.GetFlagName(utils.RPCLocalProtFlag))
+	cfg.EnableLegacyResponse = ctx.Bool(utils.GetFlagName(utils.RPCLegacyResponseFlag))
 }
 
 func setRestfulConfig(ctx *cli.Context, cfg *config.RestfulConfig) {
@@ -185,6 +199,53 @@ func setWebSocketConfig(ctx *cli.Context, cfg *config.WebSocketConfig) {
 	cfg.HttpWsPort = ctx.Uint(utils.GetFlagName(utils.WsPortFlag))
 }
 
+func setGraphQLConfig(ctx *cli.Context, cfg *config.GraphQLConfig) {
+	cfg.EnableGraphQL = ctx.Bool(utils.GetFlagName(utils.GraphQLEnableFlag))
+	cfg.GraphQLPort = ctx.Uint(utils.GetFlagName(utils.GraphQLPortFlag))
+	cfg.MaxQueryDepth = ctx.Uint(utils.GetFlagName(utils.GraphQLMaxDepthFlag))
+	cfg.MaxQueryCost = ctx.Uint(utils.GetFlagName(utils.GraphQLMaxCostFlag))
+}
+
+func setMetricsConfig(ctx *cli.Context, cfg *config.MetricsConfig) {
+	cfg.EnableMetrics = ctx.Bool(utils.GetFlagName(utils.MetricsEnableFlag))
+	cfg.MetricsPort = ctx.Uint(utils.GetFlagName(utils.MetricsPortFlag))
+}
+
+func setAuthConfig(ctx *cli.Context, cfg *config.AuthConfig) error {
+	cfg.RateLimit = ctx.Uint(utils.GetFlagName(utils.ApiRateLimitFlag))
+	cfg.RateBurst = ctx.Uint(utils.GetFlagName(utils.ApiRateBurstFlag))
+	cfg.MaxRequestBytes = ctx.Uint(utils.GetFlagName(utils.ApiMaxRequestBytesFlag))
+	if !ctx.IsSet(utils.GetFlagName(utils.ApiAuthConfigFlag)) {
+		return nil
+	}
+	authFile := ctx.String(utils.GetFlagName(utils.ApiAuthConfigFlag))
+	if !common.FileExisted(authFile) {
+		return fmt.Errorf("cannot find auth config file:%s", authFile)
+	}
+	data, err := ioutil.ReadFile(authFile)
+	if err != nil {
+		return err
+	}
+	//not print the file content on error, it holds the secrets
+	authCfg := &config.AuthConfig{}
+	err = json.Unmarshal(data, authCfg)
+	if err != nil {
+		return fmt.Errorf("json.Unmarshal auth config error:%s", err)
+	}
+	if len(authCfg.ReadKeys) == 0 && len(authCfg.SubmitKeys) == 0 && len(authCfg.AdminKeys) == 0 &&
+		authCfg.JwtSecret == "" {
+		return fmt.Errorf("no api key or jwt secret in auth config file:%s", authFile)
+	}
+	cfg.EnableAuth = true
+	cfg.PublicRead = authCfg.PublicRead
+	cfg.ReadKeys = authCfg.ReadKeys
+	cfg.SubmitKeys = authCfg.SubmitKeys
+	cfg.AdminKeys = authCfg.AdminKeys
+	cfg.JwtSecret = authCfg.JwtSecret
+	log.Infof("Load auth config:%s", authFile)
+	return nil
+}
+
 func SetRpcPort(ctx *cli.Context) {
 	if ctx.IsSet(utils.GetFlagName(utils.RPCPortFlag)) {
 		config.DefConfig.Rpc.HttpJsonPort = ctx.Uint(utils.GetFlagName(utils.RPCPortFlag))
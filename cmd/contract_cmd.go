@@ -25,7 +25,9 @@ import (
 	"github.com/ontio/ontology/cmd/utils"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/core/txbuilder"
 	httpcom "github.com/ontio/ontology/http/base/common"
+	"github.com/ontio/ontology/smartcontract/abi"
 	"github.com/urfave/cli"
 	"io/ioutil"
 	"strings"
@@ -80,6 +82,12 @@ var (
      Return type support bytearray(encoded to hex string), string, integer, boolean. 
      If return type is object array, enclose array with '[]'. 
      For example: [string,int,bool,string]
+
+  ABI
+     Contract can also be invoked by method name and named arguments defined in ABI json file, with --abi, --method and --args flags.
+     Arguments are checked and converted by parameter types of ABI. If --address flag is not set, the hash of ABI is used.
+     For example: --abi=./token.abi.json --method=transfer --args='{"from":"AXK2KtCfcJnSMyRzSwTuwTKgNrtx5aXfFX","to":"AMFrW7hXhjWwMd6DN7sMbUWfmxuSDN9ikL","amount":100}'
+     With --prepare flag, return value and notify are decoded by return type and events of ABI.
 `,
 				Flags: []cli.Flag{
 					utils.RPCPortFlag,
@@ -90,6 +98,9 @@ var (
 					utils.ContractVersionFlag,
 					utils.ContractPrepareInvokeFlag,
 					utils.ContractReturnTypeFlag,
+					utils.ContractAbiFlag,
+					utils.ContractMethodFlag,
+					utils.ContractArgsFlag,
 					utils.WalletFileFlag,
 					utils.AccountAddressFlag,
 				},
@@ -140,6 +151,10 @@ func deployContract(ctx *cli.Context) error {
 	code := strings.TrimSpace(string(codeStr))
 	gasPrice := ctx.Uint64(utils.GetFlagName(utils.TransactionGasPriceFlag))
 	gasLimit := ctx.Uint64(utils.GetFlagName(utils.TransactionGasLimitFlag))
+	if !ctx.IsSet(utils.GetFlagName(utils.TransactionGasLimitFlag)) {
+		//estimated by node before sending
+		gasLimit = 0
+	}
 	networkId, err := utils.GetNetworkId()
 	if err != nil {
 		return err
@@ -236,6 +251,10 @@ func invokeCodeContract(ctx *cli.Context) error {
 	}
 	gasPrice := ctx.Uint64(utils.GetFlagName(utils.TransactionGasPriceFlag))
 	gasLimit := ctx.Uint64(utils.GetFlagName(utils.TransactionGasLimitFlag))
+	if !ctx.IsSet(utils.GetFlagName(utils.TransactionGasLimitFlag)) {
+		//estimated by node before sending
+		gasLimit = 0
+	}
 	networkId, err := utils.GetNetworkId()
 	if err != nil {
 		return err
@@ -254,20 +273,11 @@ func invokeCodeContract(ctx *cli.Context) error {
 		return fmt.Errorf("get signer account error:%s", err)
 	}
 
-	err = utils.SignTransaction(signer, invokeTx)
-	if err != nil {
-		return fmt.Errorf("SignTransaction error:%s", err)
-	}
-	tx, err := invokeTx.IntoImmutable()
+	txHash, err := utils.InvokeSmartContract(signer, invokeTx)
 	if err != nil {
 		return err
 	}
 
-	txHash, err := utils.SendRawTransaction(tx)
-	if err != nil {
-		return fmt.Errorf("SendTransaction error:%s", err)
-	}
-
 	PrintInfoMsg("TxHash:%s", txHash)
 	PrintInfoMsg("\nTip:")
 	PrintInfoMsg("  Using './ontology info status %s' to query transaction status.", txHash)
@@ -276,6 +286,9 @@ func invokeCodeContract(ctx *cli.Context) error {
 
 func invokeContract(ctx *cli.Context) error {
 	SetRpcPort(ctx)
+	if ctx.IsSet(utils.GetFlagName(utils.ContractAbiFlag)) {
+		return invokeContractByAbi(ctx)
+	}
 	if !ctx.IsSet(utils.GetFlagName(utils.ContractAddrFlag)) {
 		PrintErrorMsg("Missing %s argument.", utils.ContractAddrFlag.Name)
 		cli.ShowSubcommandHelp(ctx)
@@ -326,12 +339,94 @@ func invokeContract(ctx *cli.Context) error {
 		}
 		return nil
 	}
+	return sendInvokeContract(ctx, contractAddr, params)
+}
+
+func invokeContractByAbi(ctx *cli.Context) error {
+	if !ctx.IsSet(utils.GetFlagName(utils.ContractMethodFlag)) {
+		PrintErrorMsg("Missing %s argument.", utils.ContractMethodFlag.Name)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	abiFile := ctx.String(utils.GetFlagName(utils.ContractAbiFlag))
+	abiData, err := ioutil.ReadFile(abiFile)
+	if err != nil {
+		return fmt.Errorf("read abi file:%s error:%s", abiFile, err)
+	}
+	contractAbi, err := utils.NewNeovmContractAbi(abiData)
+	if err != nil {
+		return err
+	}
+	contractAddrStr := ctx.String(utils.GetFlagName(utils.ContractAddrFlag))
+	if contractAddrStr == "" {
+		contractAddrStr = contractAbi.Address
+	}
+	contractAddr, err := common.AddressFromHexString(strings.TrimPrefix(contractAddrStr, "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid contract address error:%s", err)
+	}
+	method := ctx.String(utils.GetFlagName(utils.ContractMethodFlag))
+	funcAbi := contractAbi.GetFunc(method)
+	if funcAbi == nil {
+		return fmt.Errorf("cannot find method:%s in abi", method)
+	}
+	args, err := utils.ParseNeovmNamedArgs(ctx.String(utils.GetFlagName(utils.ContractArgsFlag)), funcAbi)
+	if err != nil {
+		return err
+	}
+	params, err := txbuilder.NeoVMAbiParams(funcAbi, args)
+	if err != nil {
+		return err
+	}
+
+	argsData, _ := json.Marshal(args)
+	PrintInfoMsg("Invoke:%x Method:%s Args:%s", contractAddr[:], funcAbi.Name, argsData)
+
+	if ctx.IsSet(utils.GetFlagName(utils.ContractPrepareInvokeFlag)) {
+		preResult, err := utils.PrepareInvokeNeoVMContract(contractAddr, params)
+		if err != nil {
+			return fmt.Errorf("PrepareInvokeNeoVMSmartContact error:%s", err)
+		}
+		if preResult.State == 0 {
+			return fmt.Errorf("contract invoke failed")
+		}
+		PrintInfoMsg("Contract invoke successfully")
+		PrintInfoMsg("  Gas limit:%d", preResult.Gas)
+
+		value, err := abi.DecodeNeovmValue(funcAbi.ReturnType, preResult.Result)
+		if err != nil {
+			return fmt.Errorf("decode return value:%v type:%s error:%s", preResult.Result, funcAbi.ReturnType, err)
+		}
+		valueData, _ := json.Marshal(value)
+		PrintInfoMsg("  Return:%s", valueData)
+		for _, notify := range preResult.Notify {
+			if notify.ContractAddress == contractAddr {
+				evt, err := contractAbi.DecodeEvent(notify.States)
+				if err == nil {
+					evtData, _ := json.Marshal(evt)
+					PrintInfoMsg("  Notify:%s", evtData)
+					continue
+				}
+			}
+			statesData, _ := json.Marshal(notify.States)
+			PrintInfoMsg("  Notify:%s States:%s (raw value)", notify.ContractAddress.ToHexString(), statesData)
+		}
+		return nil
+	}
+	return sendInvokeContract(ctx, contractAddr, params)
+}
+
+func sendInvokeContract(ctx *cli.Context, contractAddr common.Address, params []interface{}) error {
 	signer, err := cmdcom.GetAccount(ctx)
 	if err != nil {
 		return fmt.Errorf("get signer account error:%s", err)
 	}
 	gasPrice := ctx.Uint64(utils.GetFlagName(utils.TransactionGasPriceFlag))
 	gasLimit := ctx.Uint64(utils.GetFlagName(utils.TransactionGasLimitFlag))
+	if !ctx.IsSet(utils.GetFlagName(utils.TransactionGasLimitFlag)) {
+		//estimated by node before sending
+		gasLimit = 0
+	}
 	networkId, err := utils.GetNetworkId()
 	if err != nil {
 		return err
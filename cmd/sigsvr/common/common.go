@@ -22,17 +22,27 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/cmd/sigsvr/policy"
 	"github.com/ontio/ontology/cmd/sigsvr/store"
+	"github.com/ontio/ontology/core/types"
 )
 
 var DefWalletStore *store.WalletStore
 
+//DefPolicyEngine is nil if sigsvr runs without signing policy
+var DefPolicyEngine *policy.PolicyEngine
+
+//DefAuditLog is nil if sigsvr runs without audit log
+var DefAuditLog *policy.AuditLog
+
 type CliRpcRequest struct {
 	Qid     string          `json:"qid"`
 	Params  json.RawMessage `json:"params"`
 	Account string          `json:"account"`
 	Pwd     string          `json:"pwd"`
 	Method  string          `json:"method"`
+	//Decision of signing policy, recorded in audit log
+	Decision *policy.Decision `json:"-"`
 }
 
 func (this *CliRpcRequest) GetAccount() (*account.Account, error) {
@@ -56,6 +66,30 @@ func (this *CliRpcRequest) GetAccount() (*account.Account, error) {
 	return acc, nil
 }
 
+//CheckTxPolicy evaluate signing policy of request account to transaction
+func (this *CliRpcRequest) CheckTxPolicy(tx *types.MutableTransaction) error {
+	if DefPolicyEngine == nil {
+		return nil
+	}
+	this.Decision = DefPolicyEngine.CheckTx(this.Account, tx)
+	if !this.Decision.Allow {
+		return fmt.Errorf("policy rejected:%s", this.Decision.Reason)
+	}
+	return nil
+}
+
+//CheckDataPolicy evaluate signing policy of request account to raw data
+func (this *CliRpcRequest) CheckDataPolicy() error {
+	if DefPolicyEngine == nil {
+		return nil
+	}
+	this.Decision = DefPolicyEngine.CheckData(this.Account)
+	if !this.Decision.Allow {
+		return fmt.Errorf("policy rejected:%s", this.Decision.Reason)
+	}
+	return nil
+}
+
 type CliRpcResponse struct {
 	Qid       string      `json:"qid"`
 	Method    string      `json:"method"`
@@ -29,6 +29,11 @@ const (
 	CLIERR_ABI_NOT_FOUND       = 1007
 	CLIERR_ABI_UNMATCH         = 1008
 	CLIERR_DUPLICATE_SIG       = 1009
+	CLIERR_SESSION_NOT_FOUND   = 1010
+	CLIERR_SESSION_EXIST       = 1011
+	CLIERR_POLICY_REJECTED     = 1012
+	CLIERR_ACCOUNT_EXIST       = 1013
+	CLIERR_IDENTITY_NOT_FOUND  = 1014
 	CLIERR_INTERNAL_ERR        = 900
 )
 
@@ -42,6 +47,11 @@ var RPCErrorDesc = map[int]string{
 	CLIERR_ABI_NOT_FOUND:       "abi not found",
 	CLIERR_ABI_UNMATCH:         "abi unmatch",
 	CLIERR_DUPLICATE_SIG:       "Duplicate sig",
+	CLIERR_SESSION_NOT_FOUND:   "session not found",
+	CLIERR_SESSION_EXIST:       "session already exist",
+	CLIERR_POLICY_REJECTED:     "rejected by policy",
+	CLIERR_ACCOUNT_EXIST:       "account already exist",
+	CLIERR_IDENTITY_NOT_FOUND:  "identity not found",
 	CLIERR_INTERNAL_ERR:        "internal error",
 }
 
@@ -23,6 +23,13 @@ import "github.com/ontio/ontology/cmd/sigsvr/handlers"
 func init() {
 	DefCliRpcSvr.RegHandler("createaccount", handlers.CreateAccount)
 	DefCliRpcSvr.RegHandler("exportaccount", handlers.ExportAccount)
+	DefCliRpcSvr.RegHandler("listaccount", handlers.ListAccount)
+	DefCliRpcSvr.RegHandler("importaccount", handlers.ImportAccount)
+	DefCliRpcSvr.RegHandler("deleteaccount", handlers.DeleteAccount)
+	DefCliRpcSvr.RegHandler("changepassword", handlers.ChangePassword)
+	DefCliRpcSvr.RegHandler("createidentity", handlers.CreateIdentity)
+	DefCliRpcSvr.RegHandler("listidentity", handlers.ListIdentity)
+	DefCliRpcSvr.RegHandler("deleteidentity", handlers.DeleteIdentity)
 	DefCliRpcSvr.RegHandler("sigdata", handlers.SigData)
 	DefCliRpcSvr.RegHandler("sigrawtx", handlers.SigRawTransaction)
 	DefCliRpcSvr.RegHandler("sigmutilrawtx", handlers.SigMutilRawTransaction)
@@ -30,4 +37,8 @@ func init() {
 	DefCliRpcSvr.RegHandler("signeovminvoketx", handlers.SigNeoVMInvokeTx)
 	DefCliRpcSvr.RegHandler("signeovminvokeabitx", handlers.SigNeoVMInvokeAbiTx)
 	DefCliRpcSvr.RegHandler("signativeinvoketx", handlers.SigNativeInvokeTx)
+	DefCliRpcSvr.RegHandler("createmultisigsession", handlers.CreateMultiSigSession)
+	DefCliRpcSvr.RegHandler("getmultisigsession", handlers.GetMultiSigSession)
+	DefCliRpcSvr.RegHandler("listmultisigsession", handlers.ListMultiSigSession)
+	DefCliRpcSvr.RegHandler("signmultisigsession", handlers.SignMultiSigSession)
 }
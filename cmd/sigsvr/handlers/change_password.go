@@ -0,0 +1,65 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"encoding/json"
+	clisvrcom "github.com/ontio/ontology/cmd/sigsvr/common"
+	"github.com/ontio/ontology/common/log"
+)
+
+type ChangePasswordReq struct {
+	NewPwd string `json:"new_pwd"`
+}
+
+type ChangePasswordRsp struct {
+	Account string `json:"account"`
+}
+
+//ChangePassword re-encrypt private key of request account from pwd to new_pwd
+func ChangePassword(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	chgReq := &ChangePasswordReq{}
+	err := json.Unmarshal(req.Params, chgReq)
+	if err != nil {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	if chgReq.NewPwd == "" {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		resp.ErrorInfo = "new_pwd cannot empty"
+		return
+	}
+	signer, err := req.GetAccount()
+	if err != nil {
+		log.Infof("Cli Qid:%s ChangePassword GetAccount:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_ACCOUNT_UNLOCK
+		return
+	}
+	address := signer.Address.ToBase58()
+	err = clisvrcom.DefWalletStore.ChangeAccountPassword(address, []byte(req.Pwd), []byte(chgReq.NewPwd))
+	if err != nil {
+		log.Errorf("ChangePassword Qid:%s ChangeAccountPassword error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	resp.Result = &ChangePasswordRsp{
+		Account: address,
+	}
+	log.Infof("ChangePassword Qid:%s success account:%s", req.Qid, address)
+}
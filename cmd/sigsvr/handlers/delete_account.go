@@ -0,0 +1,52 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	clisvrcom "github.com/ontio/ontology/cmd/sigsvr/common"
+	"github.com/ontio/ontology/common/log"
+)
+
+type DeleteAccountRsp struct {
+	Account string `json:"account"`
+}
+
+func DeleteAccount(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	signer, err := req.GetAccount()
+	if err != nil {
+		log.Infof("Cli Qid:%s DeleteAccount GetAccount:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_ACCOUNT_UNLOCK
+		return
+	}
+	address := signer.Address.ToBase58()
+	ok, err := clisvrcom.DefWalletStore.DeleteAccountData(address)
+	if err != nil {
+		log.Errorf("DeleteAccount Qid:%s DeleteAccountData error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	if !ok {
+		resp.ErrorCode = clisvrcom.CLIERR_ACCOUNT_UNLOCK
+		return
+	}
+	resp.Result = &DeleteAccountRsp{
+		Account: address,
+	}
+	log.Infof("DeleteAccount Qid:%s success account:%s", req.Qid, address)
+}
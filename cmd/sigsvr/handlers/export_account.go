@@ -20,7 +20,6 @@ package handlers
 import (
 	"encoding/json"
 	"fmt"
-	"github.com/ontio/ontology/account"
 	clisvrcom "github.com/ontio/ontology/cmd/sigsvr/common"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/log"
@@ -57,26 +56,11 @@ func ExportAccount(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse)
 		walletPath = "./"
 	}
 
-	walletStore := clisvrcom.DefWalletStore
-	walletData := &account.WalletData{
-		Name:     walletStore.WalletName,
-		Version:  walletStore.WalletVersion,
-		Scrypt:   walletStore.WalletScrypt,
-		Accounts: make([]*account.AccountData, 0),
-		Extra:    walletStore.WalletExtra,
-	}
-
-	accountCount := walletStore.GetNextAccountIndex()
-	for i := uint32(0); i < accountCount; i++ {
-		accData, err := walletStore.GetAccountDataByIndex(i)
-		if err != nil {
-			log.Errorf("ExportAccount Qid:%s GetAccountDataByIndex:%d error:%s\n", req.Qid, i, err)
-			continue
-		}
-		if accData == nil {
-			continue
-		}
-		walletData.Accounts = append(walletData.Accounts, accData)
+	walletData, err := clisvrcom.DefWalletStore.ExportWalletData()
+	if err != nil {
+		log.Errorf("ExportAccount Qid:%s ExportWalletData error:%s\n", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
 	}
 
 	data, err := json.Marshal(walletData)
@@ -0,0 +1,150 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"encoding/json"
+	clisvrcom "github.com/ontio/ontology/cmd/sigsvr/common"
+	"github.com/ontio/ontology/common/log"
+)
+
+type CreateIdentityReq struct {
+	Label string `json:"label"`
+}
+
+type CreateIdentityRsp struct {
+	OntId string `json:"ontid"`
+}
+
+func CreateIdentity(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	crtReq := &CreateIdentityReq{}
+	if len(req.Params) > 0 {
+		err := json.Unmarshal(req.Params, crtReq)
+		if err != nil {
+			resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+			return
+		}
+	}
+	if req.Pwd == "" {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		resp.ErrorInfo = "pwd cannot empty"
+		return
+	}
+	identity, err := clisvrcom.DefWalletStore.NewIdentity(crtReq.Label, []byte(req.Pwd))
+	if err != nil {
+		log.Errorf("CreateIdentity Qid:%s NewIdentity error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	_, err = clisvrcom.DefWalletStore.AddIdentity(identity)
+	if err != nil {
+		log.Errorf("CreateIdentity Qid:%s AddIdentity error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	resp.Result = &CreateIdentityRsp{
+		OntId: identity.ID,
+	}
+	log.Infof("CreateIdentity Qid:%s success ontid:%s", req.Qid, identity.ID)
+}
+
+type IdentityControlInfo struct {
+	Id     string `json:"id"`
+	PubKey string `json:"pub_key"`
+}
+
+type IdentityInfo struct {
+	OntId    string                 `json:"ontid"`
+	Label    string                 `json:"label"`
+	Controls []*IdentityControlInfo `json:"controls"`
+}
+
+type ListIdentityRsp struct {
+	Identities []*IdentityInfo `json:"identities"`
+}
+
+func ListIdentity(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	identities, err := clisvrcom.DefWalletStore.GetIdentities()
+	if err != nil {
+		log.Errorf("ListIdentity Qid:%s GetIdentities error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	infos := make([]*IdentityInfo, 0, len(identities))
+	for _, identity := range identities {
+		info := &IdentityInfo{
+			OntId:    identity.ID,
+			Label:    identity.Label,
+			Controls: make([]*IdentityControlInfo, 0, len(identity.Control)),
+		}
+		for _, ctrl := range identity.Control {
+			info.Controls = append(info.Controls, &IdentityControlInfo{
+				Id:     ctrl.ID,
+				PubKey: ctrl.Public,
+			})
+		}
+		infos = append(infos, info)
+	}
+	resp.Result = &ListIdentityRsp{
+		Identities: infos,
+	}
+}
+
+type DeleteIdentityReq struct {
+	OntId string `json:"ontid"`
+}
+
+type DeleteIdentityRsp struct {
+	OntId string `json:"ontid"`
+}
+
+func DeleteIdentity(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	delReq := &DeleteIdentityReq{}
+	err := json.Unmarshal(req.Params, delReq)
+	if err != nil || delReq.OntId == "" {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	identity, err := clisvrcom.DefWalletStore.GetIdentity(delReq.OntId)
+	if err != nil {
+		log.Errorf("DeleteIdentity Qid:%s GetIdentity error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	if identity == nil {
+		resp.ErrorCode = clisvrcom.CLIERR_IDENTITY_NOT_FOUND
+		return
+	}
+	err = clisvrcom.DefWalletStore.CheckIdentityPassword(identity, []byte(req.Pwd))
+	if err != nil {
+		log.Infof("Cli Qid:%s DeleteIdentity CheckIdentityPassword:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_ACCOUNT_UNLOCK
+		return
+	}
+	err = clisvrcom.DefWalletStore.DeleteIdentity(identity.ID)
+	if err != nil {
+		log.Errorf("DeleteIdentity Qid:%s DeleteIdentity error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	resp.Result = &DeleteIdentityRsp{
+		OntId: identity.ID,
+	}
+	log.Infof("DeleteIdentity Qid:%s success ontid:%s", req.Qid, identity.ID)
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"encoding/json"
+	clisvrcom "github.com/ontio/ontology/cmd/sigsvr/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIdentity(t *testing.T) {
+	params, _ := json.Marshal(&CreateIdentityReq{Label: "id"})
+	resp := &clisvrcom.CliRpcResponse{}
+	CreateIdentity(&clisvrcom.CliRpcRequest{Qid: "t", Method: "createidentity", Pwd: string(pwd), Params: params}, resp)
+	if !assert.Equal(t, 0, resp.ErrorCode) {
+		return
+	}
+	ontid := resp.Result.(*CreateIdentityRsp).OntId
+
+	resp = &clisvrcom.CliRpcResponse{}
+	ListIdentity(&clisvrcom.CliRpcRequest{Qid: "t", Method: "listidentity"}, resp)
+	assert.Equal(t, 0, resp.ErrorCode)
+	var info *IdentityInfo
+	for _, identity := range resp.Result.(*ListIdentityRsp).Identities {
+		if identity.OntId == ontid {
+			info = identity
+		}
+	}
+	if !assert.NotNil(t, info) {
+		return
+	}
+	assert.Equal(t, "id", info.Label)
+	assert.Equal(t, 1, len(info.Controls))
+
+	params, _ = json.Marshal(&DeleteIdentityReq{OntId: ontid})
+	resp = &clisvrcom.CliRpcResponse{}
+	DeleteIdentity(&clisvrcom.CliRpcRequest{Qid: "t", Method: "deleteidentity", Pwd: "wrong", Params: params}, resp)
+	assert.Equal(t, clisvrcom.CLIERR_ACCOUNT_UNLOCK, resp.ErrorCode)
+
+	resp = &clisvrcom.CliRpcResponse{}
+	DeleteIdentity(&clisvrcom.CliRpcRequest{Qid: "t", Method: "deleteidentity", Pwd: string(pwd), Params: params}, resp)
+	assert.Equal(t, 0, resp.ErrorCode)
+
+	resp = &clisvrcom.CliRpcResponse{}
+	DeleteIdentity(&clisvrcom.CliRpcRequest{Qid: "t", Method: "deleteidentity", Pwd: string(pwd), Params: params}, resp)
+	assert.Equal(t, clisvrcom.CLIERR_IDENTITY_NOT_FOUND, resp.ErrorCode)
+}
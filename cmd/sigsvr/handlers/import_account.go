@@ -0,0 +1,127 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology/account"
+	clisvrcom "github.com/ontio/ontology/cmd/sigsvr/common"
+	"github.com/ontio/ontology/common/log"
+)
+
+//ImportAccountReq import account of WIF or private key. Private key is hex of 32 bytes P-256 key,
+//or serialized private key of ontology-crypto
+type ImportAccountReq struct {
+	WIF        string `json:"wif"`
+	PrivateKey string `json:"private_key"`
+	Label      string `json:"label"`
+	SigScheme  string `json:"sig_scheme"`
+}
+
+type ImportAccountRsp struct {
+	Account string `json:"account"`
+}
+
+func ImportAccount(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	impReq := &ImportAccountReq{}
+	err := json.Unmarshal(req.Params, impReq)
+	if err != nil {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	if req.Pwd == "" {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		resp.ErrorInfo = "pwd cannot empty"
+		return
+	}
+	var prvkey keypair.PrivateKey
+	switch {
+	case impReq.WIF != "" && impReq.PrivateKey == "":
+		prvkey, err = keypair.GetP256KeyPairFromWIF([]byte(impReq.WIF))
+	case impReq.PrivateKey != "" && impReq.WIF == "":
+		var data []byte
+		data, err = hex.DecodeString(impReq.PrivateKey)
+		if err != nil {
+			break
+		}
+		if len(data) == 32 {
+			prvkey, err = account.NewP256PrivateKey(data)
+		} else {
+			prvkey, err = keypair.DeserializePrivateKey(data)
+		}
+	default:
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		resp.ErrorInfo = "one of wif and private_key should be set"
+		return
+	}
+	if err != nil {
+		log.Infof("ImportAccount Qid:%s invalid private key:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		resp.ErrorInfo = "invalid private key"
+		return
+	}
+	sigScheme := s.SHA256withECDSA
+	if impReq.SigScheme != "" {
+		sigScheme, err = s.GetScheme(impReq.SigScheme)
+		if err != nil {
+			resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+			resp.ErrorInfo = "invalid sig_scheme"
+			return
+		}
+	}
+	accData, err := clisvrcom.DefWalletStore.NewAccountDataFromPrivateKey(prvkey, sigScheme, []byte(req.Pwd))
+	if err != nil {
+		log.Errorf("ImportAccount Qid:%s NewAccountDataFromPrivateKey error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	if impReq.SigScheme == "" {
+		//default signature scheme of key type, same as account add
+		switch accData.KeyType {
+		case "SM2":
+			accData.SigSch = s.SM3withSM2.Name()
+		case "Ed25519":
+			accData.SigSch = s.SHA512withEDDSA.Name()
+		}
+	}
+	accData.Label = impReq.Label
+	isExist, err := clisvrcom.DefWalletStore.IsAccountExist(accData.Address)
+	if err != nil {
+		log.Errorf("ImportAccount Qid:%s IsAccountExist error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	if isExist {
+		resp.ErrorCode = clisvrcom.CLIERR_ACCOUNT_EXIST
+		return
+	}
+	_, err = clisvrcom.DefWalletStore.AddAccountData(accData)
+	if err != nil {
+		log.Errorf("ImportAccount Qid:%s AddAccountData error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	resp.Result = &ImportAccountRsp{
+		Account: accData.Address,
+	}
+	log.Infof("ImportAccount Qid:%s success account:%s", req.Qid, accData.Address)
+}
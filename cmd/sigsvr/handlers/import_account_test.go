@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	clisvrcom "github.com/ontio/ontology/cmd/sigsvr/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestImportAccount(t *testing.T) {
+	acc := account.NewAccount("")
+	address := acc.Address.ToBase58()
+	params, _ := json.Marshal(&ImportAccountReq{
+		PrivateKey: hex.EncodeToString(keypair.SerializePrivateKey(acc.PrivateKey)),
+		Label:      "imported",
+	})
+	req := &clisvrcom.CliRpcRequest{
+		Qid:    "t",
+		Method: "importaccount",
+		Pwd:    string(pwd),
+		Params: params,
+	}
+	resp := &clisvrcom.CliRpcResponse{}
+	ImportAccount(req, resp)
+	assert.Equal(t, 0, resp.ErrorCode)
+	assert.Equal(t, address, resp.Result.(*ImportAccountRsp).Account)
+
+	resp = &clisvrcom.CliRpcResponse{}
+	ImportAccount(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_ACCOUNT_EXIST, resp.ErrorCode)
+
+	resp = &clisvrcom.CliRpcResponse{}
+	ListAccount(&clisvrcom.CliRpcRequest{Qid: "t", Method: "listaccount"}, resp)
+	assert.Equal(t, 0, resp.ErrorCode)
+	var info *AccountInfo
+	for _, accInfo := range resp.Result.(*ListAccountRsp).Accounts {
+		if accInfo.Address == address {
+			info = accInfo
+		}
+	}
+	if !assert.NotNil(t, info) {
+		return
+	}
+	assert.Equal(t, "imported", info.Label)
+	assert.Equal(t, hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey)), info.PubKey)
+	assert.False(t, info.IsDefault)
+
+	newPwd := "654321"
+	params, _ = json.Marshal(&ChangePasswordReq{NewPwd: newPwd})
+	resp = &clisvrcom.CliRpcResponse{}
+	ChangePassword(&clisvrcom.CliRpcRequest{Qid: "t", Method: "changepassword", Account: address, Pwd: string(pwd), Params: params}, resp)
+	assert.Equal(t, 0, resp.ErrorCode)
+	_, err := clisvrcom.DefWalletStore.GetAccountByAddress(address, pwd)
+	assert.NotNil(t, err)
+	_, err = clisvrcom.DefWalletStore.GetAccountByAddress(address, []byte(newPwd))
+	assert.Nil(t, err)
+
+	resp = &clisvrcom.CliRpcResponse{}
+	DeleteAccount(&clisvrcom.CliRpcRequest{Qid: "t", Method: "deleteaccount", Account: address, Pwd: string(pwd)}, resp)
+	assert.Equal(t, clisvrcom.CLIERR_ACCOUNT_UNLOCK, resp.ErrorCode)
+
+	resp = &clisvrcom.CliRpcResponse{}
+	DeleteAccount(&clisvrcom.CliRpcRequest{Qid: "t", Method: "deleteaccount", Account: address, Pwd: newPwd}, resp)
+	assert.Equal(t, 0, resp.ErrorCode)
+	isExist, err := clisvrcom.DefWalletStore.IsAccountExist(address)
+	assert.Nil(t, err)
+	assert.False(t, isExist)
+}
+
+func TestImportAccountWIF(t *testing.T) {
+	acc := account.NewAccount("")
+	wif, err := keypair.Key2WIF(acc.PrivateKey)
+	if !assert.Nil(t, err) {
+		return
+	}
+	params, _ := json.Marshal(&ImportAccountReq{WIF: string(wif)})
+	req := &clisvrcom.CliRpcRequest{
+		Qid:    "t",
+		Method: "importaccount",
+		Pwd:    string(pwd),
+		Params: params,
+	}
+	resp := &clisvrcom.CliRpcResponse{}
+	ImportAccount(req, resp)
+	assert.Equal(t, 0, resp.ErrorCode)
+	address := resp.Result.(*ImportAccountRsp).Account
+	assert.Equal(t, acc.Address.ToBase58(), address)
+	impAcc, err := clisvrcom.DefWalletStore.GetAccountByAddress(address, pwd)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "SHA256withECDSA", impAcc.SigScheme.Name())
+	_, err = clisvrcom.DefWalletStore.DeleteAccountData(address)
+	assert.Nil(t, err)
+
+	params, _ = json.Marshal(&ImportAccountReq{WIF: "invalid"})
+	req.Params = params
+	resp = &clisvrcom.CliRpcResponse{}
+	ImportAccount(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_INVALID_PARAMS, resp.ErrorCode)
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	clisvrcom "github.com/ontio/ontology/cmd/sigsvr/common"
+	"github.com/ontio/ontology/common/log"
+)
+
+type AccountInfo struct {
+	Index     int    `json:"index"`
+	Address   string `json:"address"`
+	Label     string `json:"label"`
+	PubKey    string `json:"pub_key"`
+	SigScheme string `json:"sig_scheme"`
+	IsDefault bool   `json:"is_default"`
+	HDPath    string `json:"hd_path,omitempty"`
+}
+
+type ListAccountRsp struct {
+	Accounts []*AccountInfo `json:"accounts"`
+}
+
+func ListAccount(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	accDatas, err := clisvrcom.DefWalletStore.GetAccountDatas()
+	if err != nil {
+		log.Errorf("ListAccount Qid:%s GetAccountDatas error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	accounts := make([]*AccountInfo, 0, len(accDatas))
+	for i, accData := range accDatas {
+		accounts = append(accounts, &AccountInfo{
+			Index:     i + 1,
+			Address:   accData.Address,
+			Label:     accData.Label,
+			PubKey:    accData.PubKey,
+			SigScheme: accData.SigSch,
+			IsDefault: accData.IsDefault,
+			HDPath:    accData.HDPath,
+		})
+	}
+	resp.Result = &ListAccountRsp{
+		Accounts: accounts,
+	}
+}
@@ -0,0 +1,491 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology/account"
+	clisvrcom "github.com/ontio/ontology/cmd/sigsvr/common"
+	cliutil "github.com/ontio/ontology/cmd/utils"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/constants"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+)
+
+const (
+	MULTISIG_SESSION_SIGNING          = "signing"
+	MULTISIG_SESSION_BROADCASTING     = "broadcasting"
+	MULTISIG_SESSION_BROADCASTED      = "broadcasted"
+	MULTISIG_SESSION_BROADCAST_FAILED = "broadcast_failed"
+
+	MULTISIG_SESSION_DEFAULT_EXPIRE = 24 * 3600     //seconds
+	MULTISIG_SESSION_MAX_EXPIRE     = 7 * 24 * 3600 //seconds
+	MULTISIG_SESSION_MAX_SIZE       = 1024
+)
+
+//sendRawTransaction broadcast transaction to ontology node, replaced in test
+var sendRawTransaction = cliutil.SendRawTransaction
+
+//MultiSigSession collect signatures of a transaction from co-signers of an m-of-n address,
+//the id of session is the transaction hash
+type MultiSigSession struct {
+	id         string
+	m          uint16
+	pubKeys    []keypair.PublicKey
+	address    common.Address
+	tx         *types.MutableTransaction
+	status     string
+	err        string
+	expireTime time.Time
+}
+
+type MultiSigSessionMgr struct {
+	sessions map[string]*MultiSigSession
+	lock     sync.Mutex
+}
+
+var DefMultiSigSessionMgr = NewMultiSigSessionMgr()
+
+func NewMultiSigSessionMgr() *MultiSigSessionMgr {
+	return &MultiSigSessionMgr{
+		sessions: make(map[string]*MultiSigSession),
+	}
+}
+
+func (this *MultiSigSessionMgr) removeExpired() {
+	now := time.Now()
+	for id, session := range this.sessions {
+		if now.After(session.expireTime) {
+			delete(this.sessions, id)
+		}
+	}
+}
+
+func (this *MultiSigSessionMgr) Add(session *MultiSigSession) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.removeExpired()
+	if _, ok := this.sessions[session.id]; ok {
+		return fmt.Errorf("session:%s already exist", session.id)
+	}
+	if len(this.sessions) >= MULTISIG_SESSION_MAX_SIZE {
+		return fmt.Errorf("too many sessions")
+	}
+	this.sessions[session.id] = session
+	return nil
+}
+
+func (this *MultiSigSessionMgr) Get(id string) *MultiSigSessionRsp {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.removeExpired()
+	session, ok := this.sessions[id]
+	if !ok {
+		return nil
+	}
+	return session.info()
+}
+
+//getTx return a copy of the transaction of session made under lock, as Sign appends signatures to it.
+//Return nil if session not found
+func (this *MultiSigSessionMgr) getTx(id string) *types.MutableTransaction {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	session, ok := this.sessions[id]
+	if !ok {
+		return nil
+	}
+	mutTx, err := session.copyTx()
+	if err != nil {
+		log.Warnf("multisig session:%s %s", id, err)
+		return nil
+	}
+	return mutTx
+}
+
+//copyTx return a deep copy of the transaction of session
+func (this *MultiSigSession) copyTx() (*types.MutableTransaction, error) {
+	tx, err := this.tx.IntoImmutable()
+	if err != nil {
+		return nil, fmt.Errorf("IntoImmutable error:%s", err)
+	}
+	mutTx, err := tx.IntoMutable()
+	if err != nil {
+		return nil, fmt.Errorf("IntoMutable error:%s", err)
+	}
+	return mutTx, nil
+}
+
+func (this *MultiSigSessionMgr) List() []*MultiSigSessionRsp {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.removeExpired()
+	infos := make([]*MultiSigSessionRsp, 0, len(this.sessions))
+	for _, session := range this.sessions {
+		infos = append(infos, session.info())
+	}
+	return infos
+}
+
+//Sign append signature of signer to session, and broadcast the transaction once the threshold is reached.
+//Sign a completed session which failed to broadcast will retry broadcasting
+func (this *MultiSigSessionMgr) Sign(id string, signer account.Signer) (*MultiSigSessionRsp, error) {
+	this.lock.Lock()
+	session, ok := this.sessions[id]
+	if !ok || time.Now().After(session.expireTime) {
+		this.lock.Unlock()
+		return nil, nil
+	}
+	if session.status == MULTISIG_SESSION_BROADCASTING || session.status == MULTISIG_SESSION_BROADCASTED {
+		this.lock.Unlock()
+		return nil, fmt.Errorf("session status is %s", session.status)
+	}
+	//sign a copy, so a failed sign leaves the transaction of session unchanged
+	mutTx, err := session.copyTx()
+	if err != nil {
+		this.lock.Unlock()
+		return nil, err
+	}
+	err = cliutil.MultiSigTransaction(mutTx, session.m, session.pubKeys, signer)
+	if err != nil {
+		this.lock.Unlock()
+		return nil, err
+	}
+	if mutTx.Hash() != session.tx.Hash() {
+		this.lock.Unlock()
+		return nil, fmt.Errorf("transaction hash changed by signing")
+	}
+	session.tx = mutTx
+	if len(session.signedPubKeys()) < int(session.m) {
+		info := session.info()
+		this.lock.Unlock()
+		return info, nil
+	}
+	session.status = MULTISIG_SESSION_BROADCASTING
+	tx, err := session.tx.IntoImmutable()
+	this.lock.Unlock()
+
+	if err == nil {
+		_, err = sendRawTransaction(tx)
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if err != nil {
+		log.Warnf("multisig session:%s broadcast error:%s", id, err)
+		session.status = MULTISIG_SESSION_BROADCAST_FAILED
+		session.err = err.Error()
+	} else {
+		session.status = MULTISIG_SESSION_BROADCASTED
+		session.err = ""
+	}
+	return session.info(), nil
+}
+
+//signedPubKeys return the public keys which have signed in session
+func (this *MultiSigSession) signedPubKeys() []keypair.PublicKey {
+	txHash := this.tx.Hash()
+	signed := make([]keypair.PublicKey, 0, len(this.pubKeys))
+	for _, sig := range this.tx.Sigs {
+		addr, err := types.AddressFromMultiPubKeys(sig.PubKeys, int(sig.M))
+		if err != nil || addr != this.address {
+			continue
+		}
+		for _, pk := range this.pubKeys {
+			for _, sigData := range sig.SigData {
+				if signature.Verify(pk, txHash.ToArray(), sigData) == nil {
+					signed = append(signed, pk)
+					break
+				}
+			}
+		}
+	}
+	return signed
+}
+
+func (this *MultiSigSession) info() *MultiSigSessionRsp {
+	pubKeys := make([]string, 0, len(this.pubKeys))
+	for _, pk := range this.pubKeys {
+		pubKeys = append(pubKeys, hex.EncodeToString(keypair.SerializePublicKey(pk)))
+	}
+	signedPubKeys := this.signedPubKeys()
+	signed := make([]string, 0, len(signedPubKeys))
+	for _, pk := range signedPubKeys {
+		signed = append(signed, hex.EncodeToString(keypair.SerializePublicKey(pk)))
+	}
+	rawTx := ""
+	tx, err := this.tx.IntoImmutable()
+	if err == nil {
+		sink := common.ZeroCopySink{}
+		err = tx.Serialization(&sink)
+		if err == nil {
+			rawTx = hex.EncodeToString(sink.Bytes())
+		}
+	}
+	return &MultiSigSessionRsp{
+		SessionId:  this.id,
+		Address:    this.address.ToBase58(),
+		M:          int(this.m),
+		PubKeys:    pubKeys,
+		Signed:     signed,
+		Status:     this.status,
+		Error:      this.err,
+		RawTx:      rawTx,
+		ExpireTime: this.expireTime.Unix(),
+	}
+}
+
+//presignedSigner is a signer of a signature made by co-signer outside sigsvr
+type presignedSigner struct {
+	pubKey  keypair.PublicKey
+	sigData []byte
+}
+
+func (this *presignedSigner) PubKey() keypair.PublicKey {
+	return this.pubKey
+}
+
+func (this *presignedSigner) Scheme() s.SignatureScheme {
+	sig, err := s.Deserialize(this.sigData)
+	if err != nil {
+		return s.SHA256withECDSA
+	}
+	return sig.Scheme
+}
+
+func (this *presignedSigner) Sign(data []byte) ([]byte, error) {
+	err := signature.Verify(this.pubKey, data, this.sigData)
+	if err != nil {
+		return nil, err
+	}
+	return this.sigData, nil
+}
+
+type CreateMultiSigSessionReq struct {
+	RawTx   string   `json:"raw_tx"`
+	M       int      `json:"m"`
+	PubKeys []string `json:"pub_keys"`
+	Expire  int      `json:"expire"`
+}
+
+type MultiSigSessionReq struct {
+	SessionId string `json:"session_id"`
+}
+
+type SignMultiSigSessionReq struct {
+	SessionId string `json:"session_id"`
+	PubKey    string `json:"pub_key"`
+	Signature string `json:"signature"`
+}
+
+type MultiSigSessionRsp struct {
+	SessionId  string   `json:"session_id"`
+	Address    string   `json:"address"`
+	M          int      `json:"m"`
+	PubKeys    []string `json:"pub_keys"`
+	Signed     []string `json:"signed"`
+	Status     string   `json:"status"`
+	Error      string   `json:"error,omitempty"`
+	RawTx      string   `json:"raw_tx"`
+	ExpireTime int64    `json:"expire_time"`
+}
+
+type ListMultiSigSessionRsp struct {
+	Sessions []*MultiSigSessionRsp `json:"sessions"`
+}
+
+func CreateMultiSigSession(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	rawReq := &CreateMultiSigSessionReq{}
+	err := json.Unmarshal(req.Params, rawReq)
+	if err != nil {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	numkeys := len(rawReq.PubKeys)
+	if rawReq.M <= 0 || numkeys < rawReq.M || numkeys <= 1 || numkeys > constants.MULTI_SIG_MAX_PUBKEY_SIZE {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	expire := rawReq.Expire
+	if expire == 0 {
+		expire = MULTISIG_SESSION_DEFAULT_EXPIRE
+	}
+	if expire < 0 || expire > MULTISIG_SESSION_MAX_EXPIRE {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		resp.ErrorInfo = fmt.Sprintf("expire should be in (0, %d]", MULTISIG_SESSION_MAX_EXPIRE)
+		return
+	}
+	rawTxData, err := hex.DecodeString(rawReq.RawTx)
+	if err != nil {
+		log.Infof("Cli Qid:%s CreateMultiSigSession hex.DecodeString error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	tmpTx, err := types.TransactionFromRawBytes(rawTxData)
+	if err != nil {
+		log.Infof("Cli Qid:%s CreateMultiSigSession TransactionFromRawBytes error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_TX
+		return
+	}
+	mutTx, err := tmpTx.IntoMutable()
+	if err != nil {
+		log.Infof("Cli Qid:%s CreateMultiSigSession IntoMutable error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_TX
+		return
+	}
+	pubKeys := make([]keypair.PublicKey, 0, numkeys)
+	for _, pkStr := range rawReq.PubKeys {
+		pk, err := parsePubKey(pkStr)
+		if err != nil {
+			log.Infof("Cli Qid:%s CreateMultiSigSession %s", req.Qid, err)
+			resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+			return
+		}
+		pubKeys = append(pubKeys, pk)
+	}
+	address, err := types.AddressFromMultiPubKeys(pubKeys, rawReq.M)
+	if err != nil {
+		log.Infof("Cli Qid:%s CreateMultiSigSession AddressFromMultiPubKeys error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	//fix payer before the session id is taken, otherwise the first sign would set it and change the hash co-signers sign
+	if mutTx.Payer == common.ADDRESS_EMPTY {
+		mutTx.Payer = address
+	}
+	txHash := mutTx.Hash()
+	session := &MultiSigSession{
+		id:         txHash.ToHexString(),
+		m:          uint16(rawReq.M),
+		pubKeys:    pubKeys,
+		address:    address,
+		tx:         mutTx,
+		status:     MULTISIG_SESSION_SIGNING,
+		expireTime: time.Now().Add(time.Duration(expire) * time.Second),
+	}
+	err = DefMultiSigSessionMgr.Add(session)
+	if err != nil {
+		log.Infof("Cli Qid:%s CreateMultiSigSession error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_SESSION_EXIST
+		resp.ErrorInfo = err.Error()
+		return
+	}
+	resp.Result = DefMultiSigSessionMgr.Get(session.id)
+}
+
+func GetMultiSigSession(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	rawReq := &MultiSigSessionReq{}
+	err := json.Unmarshal(req.Params, rawReq)
+	if err != nil {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	info := DefMultiSigSessionMgr.Get(rawReq.SessionId)
+	if info == nil {
+		resp.ErrorCode = clisvrcom.CLIERR_SESSION_NOT_FOUND
+		return
+	}
+	resp.Result = info
+}
+
+func ListMultiSigSession(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	resp.Result = &ListMultiSigSessionRsp{
+		Sessions: DefMultiSigSessionMgr.List(),
+	}
+}
+
+//SignMultiSigSession append a signature to session. If signature is set, it is the signature of pub_key to
+//the transaction hash made outside sigsvr, otherwise sign with account of request
+func SignMultiSigSession(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	rawReq := &SignMultiSigSessionReq{}
+	err := json.Unmarshal(req.Params, rawReq)
+	if err != nil {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	var signer account.Signer
+	if rawReq.Signature != "" {
+		pk, err := parsePubKey(rawReq.PubKey)
+		if err != nil {
+			log.Infof("Cli Qid:%s SignMultiSigSession %s", req.Qid, err)
+			resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+			return
+		}
+		sigData, err := hex.DecodeString(rawReq.Signature)
+		if err != nil {
+			log.Infof("Cli Qid:%s SignMultiSigSession signature hex.DecodeString error:%s", req.Qid, err)
+			resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+			return
+		}
+		signer = &presignedSigner{pubKey: pk, sigData: sigData}
+	} else {
+		acc, err := req.GetAccount()
+		if err != nil {
+			log.Infof("Cli Qid:%s SignMultiSigSession GetAccount:%s", req.Qid, err)
+			resp.ErrorCode = clisvrcom.CLIERR_ACCOUNT_UNLOCK
+			return
+		}
+		tx := DefMultiSigSessionMgr.getTx(rawReq.SessionId)
+		if tx == nil {
+			resp.ErrorCode = clisvrcom.CLIERR_SESSION_NOT_FOUND
+			return
+		}
+		err = req.CheckTxPolicy(tx)
+		if err != nil {
+			log.Infof("Cli Qid:%s SignMultiSigSession CheckTxPolicy:%s", req.Qid, err)
+			resp.ErrorCode = clisvrcom.CLIERR_POLICY_REJECTED
+			resp.ErrorInfo = err.Error()
+			return
+		}
+		signer = acc
+	}
+	info, err := DefMultiSigSessionMgr.Sign(rawReq.SessionId, signer)
+	if err != nil {
+		log.Infof("Cli Qid:%s SignMultiSigSession error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		resp.ErrorInfo = err.Error()
+		return
+	}
+	if info == nil {
+		resp.ErrorCode = clisvrcom.CLIERR_SESSION_NOT_FOUND
+		return
+	}
+	resp.Result = info
+}
+
+func parsePubKey(pkStr string) (keypair.PublicKey, error) {
+	pkData, err := hex.DecodeString(pkStr)
+	if err != nil {
+		return nil, fmt.Errorf("pk hex.DecodeString error:%s", err)
+	}
+	pk, err := keypair.DeserializePublicKey(pkData)
+	if err != nil {
+		return nil, fmt.Errorf("keypair.DeserializePublicKey error:%s", err)
+	}
+	return pk, nil
+}
@@ -0,0 +1,219 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology/account"
+	clisvrcom "github.com/ontio/ontology/cmd/sigsvr/common"
+	"github.com/ontio/ontology/cmd/utils"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiSigSession(t *testing.T) {
+	acc1, err := clisvrcom.DefWalletStore.NewAccountData(keypair.PK_ECDSA, keypair.P256, signature.SHA256withECDSA, pwd)
+	assert.Nil(t, err)
+	clisvrcom.DefWalletStore.AddAccountData(acc1)
+	acc2, err := clisvrcom.DefWalletStore.NewAccountData(keypair.PK_ECDSA, keypair.P256, signature.SHA256withECDSA, pwd)
+	assert.Nil(t, err)
+	clisvrcom.DefWalletStore.AddAccountData(acc2)
+	acc3 := account.NewAccount("")
+	acc3PubKey := hex.EncodeToString(keypair.SerializePublicKey(acc3.PublicKey))
+
+	pubKeys := make([]keypair.PublicKey, 0, 3)
+	for _, pkStr := range []string{acc1.PubKey, acc2.PubKey, acc3PubKey} {
+		pk, err := parsePubKey(pkStr)
+		assert.Nil(t, err)
+		pubKeys = append(pubKeys, pk)
+	}
+	fromAddr, err := types.AddressFromMultiPubKeys(pubKeys, 2)
+	assert.Nil(t, err)
+	tx, err := utils.TransferTx(0, 0, "ont", fromAddr.ToBase58(), acc1.Address, 10)
+	assert.Nil(t, err)
+	tx.Payer = fromAddr
+	immut, err := tx.IntoImmutable()
+	assert.Nil(t, err)
+	sink := common.ZeroCopySink{}
+	err = immut.Serialization(&sink)
+	assert.Nil(t, err)
+
+	var sentTx *types.Transaction
+	sendRawTransaction = func(tx *types.Transaction) (string, error) {
+		if sentTx == nil {
+			sentTx = tx
+			return "", fmt.Errorf("node unavailable")
+		}
+		sentTx = tx
+		return tx.Hash().ToHexString(), nil
+	}
+	defer func() {
+		sendRawTransaction = utils.SendRawTransaction
+	}()
+
+	data, _ := json.Marshal(&CreateMultiSigSessionReq{
+		RawTx:   hex.EncodeToString(sink.Bytes()),
+		M:       2,
+		PubKeys: []string{acc1.PubKey, acc2.PubKey, acc3PubKey},
+	})
+	req := &clisvrcom.CliRpcRequest{Qid: "t", Method: "createmultisigsession", Params: data}
+	resp := &clisvrcom.CliRpcResponse{}
+	CreateMultiSigSession(req, resp)
+	if !assert.Equal(t, clisvrcom.CLIERR_OK, resp.ErrorCode) {
+		return
+	}
+	info := resp.Result.(*MultiSigSessionRsp)
+	assert.Equal(t, fromAddr.ToBase58(), info.Address)
+	assert.Equal(t, MULTISIG_SESSION_SIGNING, info.Status)
+	assert.Equal(t, 0, len(info.Signed))
+	sessionId := info.SessionId
+
+	resp = &clisvrcom.CliRpcResponse{}
+	CreateMultiSigSession(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_SESSION_EXIST, resp.ErrorCode)
+
+	//co-signer in sigsvr wallet
+	data, _ = json.Marshal(&SignMultiSigSessionReq{SessionId: sessionId})
+	req = &clisvrcom.CliRpcRequest{Qid: "t", Method: "signmultisigsession", Params: data, Account: acc1.Address, Pwd: string(pwd)}
+	resp = &clisvrcom.CliRpcResponse{}
+	SignMultiSigSession(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_OK, resp.ErrorCode)
+	info = resp.Result.(*MultiSigSessionRsp)
+	assert.Equal(t, []string{acc1.PubKey}, info.Signed)
+	assert.Nil(t, sentTx)
+
+	//tx of session is returned as a copy, changing it does not change session
+	sessionTx := DefMultiSigSessionMgr.getTx(sessionId)
+	if assert.NotNil(t, sessionTx) && assert.Equal(t, 1, len(sessionTx.Sigs)) {
+		sessionTx.Sigs[0].SigData[0] = []byte("changed")
+	}
+	assert.Equal(t, []string{acc1.PubKey}, DefMultiSigSessionMgr.Get(sessionId).Signed)
+
+	//invalid signature made outside sigsvr
+	txHash := tx.Hash()
+	sigData, err := acc3.Sign([]byte("invalid"))
+	assert.Nil(t, err)
+	data, _ = json.Marshal(&SignMultiSigSessionReq{SessionId: sessionId, PubKey: acc3PubKey, Signature: hex.EncodeToString(sigData)})
+	req = &clisvrcom.CliRpcRequest{Qid: "t", Method: "signmultisigsession", Params: data}
+	resp = &clisvrcom.CliRpcResponse{}
+	SignMultiSigSession(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_INVALID_PARAMS, resp.ErrorCode)
+
+	//threshold reached, broadcast failed
+	sigData, err = acc3.Sign(txHash.ToArray())
+	assert.Nil(t, err)
+	data, _ = json.Marshal(&SignMultiSigSessionReq{SessionId: sessionId, PubKey: acc3PubKey, Signature: hex.EncodeToString(sigData)})
+	req = &clisvrcom.CliRpcRequest{Qid: "t", Method: "signmultisigsession", Params: data}
+	resp = &clisvrcom.CliRpcResponse{}
+	SignMultiSigSession(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_OK, resp.ErrorCode)
+	info = resp.Result.(*MultiSigSessionRsp)
+	assert.Equal(t, 2, len(info.Signed))
+	assert.Equal(t, MULTISIG_SESSION_BROADCAST_FAILED, info.Status)
+	assert.NotNil(t, sentTx)
+
+	//retry broadcast
+	resp = &clisvrcom.CliRpcResponse{}
+	SignMultiSigSession(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_OK, resp.ErrorCode)
+	info = resp.Result.(*MultiSigSessionRsp)
+	assert.Equal(t, MULTISIG_SESSION_BROADCASTED, info.Status)
+	assert.Equal(t, sessionId, sentTx.Hash().ToHexString())
+	assert.Equal(t, 1, len(sentTx.Sigs))
+	assert.Equal(t, 2, len(sentTx.Sigs[0].SigData))
+
+	data, _ = json.Marshal(&MultiSigSessionReq{SessionId: sessionId})
+	req = &clisvrcom.CliRpcRequest{Qid: "t", Method: "getmultisigsession", Params: data}
+	resp = &clisvrcom.CliRpcResponse{}
+	GetMultiSigSession(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_OK, resp.ErrorCode)
+	assert.Equal(t, MULTISIG_SESSION_BROADCASTED, resp.Result.(*MultiSigSessionRsp).Status)
+
+	data, _ = json.Marshal(&MultiSigSessionReq{SessionId: "unknown"})
+	req = &clisvrcom.CliRpcRequest{Qid: "t", Method: "getmultisigsession", Params: data}
+	resp = &clisvrcom.CliRpcResponse{}
+	GetMultiSigSession(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_SESSION_NOT_FOUND, resp.ErrorCode)
+}
+
+func TestMultiSigSessionEmptyPayer(t *testing.T) {
+	acc1, err := clisvrcom.DefWalletStore.NewAccountData(keypair.PK_ECDSA, keypair.P256, signature.SHA256withECDSA, pwd)
+	assert.Nil(t, err)
+	clisvrcom.DefWalletStore.AddAccountData(acc1)
+	acc2 := account.NewAccount("")
+	acc2PubKey := hex.EncodeToString(keypair.SerializePublicKey(acc2.PublicKey))
+
+	pubKeys := make([]keypair.PublicKey, 0, 2)
+	for _, pkStr := range []string{acc1.PubKey, acc2PubKey} {
+		pk, err := parsePubKey(pkStr)
+		assert.Nil(t, err)
+		pubKeys = append(pubKeys, pk)
+	}
+	fromAddr, err := types.AddressFromMultiPubKeys(pubKeys, 2)
+	assert.Nil(t, err)
+	tx, err := utils.TransferTx(0, 0, "ont", fromAddr.ToBase58(), acc1.Address, 20)
+	assert.Nil(t, err)
+	assert.Equal(t, common.ADDRESS_EMPTY, tx.Payer)
+	immut, err := tx.IntoImmutable()
+	assert.Nil(t, err)
+	sink := common.ZeroCopySink{}
+	err = immut.Serialization(&sink)
+	assert.Nil(t, err)
+
+	data, _ := json.Marshal(&CreateMultiSigSessionReq{
+		RawTx:   hex.EncodeToString(sink.Bytes()),
+		M:       2,
+		PubKeys: []string{acc1.PubKey, acc2PubKey},
+	})
+	req := &clisvrcom.CliRpcRequest{Qid: "t", Method: "createmultisigsession", Params: data}
+	resp := &clisvrcom.CliRpcResponse{}
+	CreateMultiSigSession(req, resp)
+	if !assert.Equal(t, clisvrcom.CLIERR_OK, resp.ErrorCode) {
+		return
+	}
+	sessionId := resp.Result.(*MultiSigSessionRsp).SessionId
+	sessionTx := DefMultiSigSessionMgr.getTx(sessionId)
+	if !assert.NotNil(t, sessionTx) {
+		return
+	}
+	//payer is set to the multi-signature address at creation
+	assert.Equal(t, fromAddr, sessionTx.Payer)
+	assert.Equal(t, sessionId, sessionTx.Hash().ToHexString())
+
+	//failed sign leaves session unchanged
+	_, err = DefMultiSigSessionMgr.Sign(sessionId, &presignedSigner{pubKey: acc2.PublicKey, sigData: []byte("invalid")})
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, len(DefMultiSigSessionMgr.getTx(sessionId).Sigs))
+
+	//signing keeps the session id
+	data, _ = json.Marshal(&SignMultiSigSessionReq{SessionId: sessionId})
+	req = &clisvrcom.CliRpcRequest{Qid: "t", Method: "signmultisigsession", Params: data, Account: acc1.Address, Pwd: string(pwd)}
+	resp = &clisvrcom.CliRpcResponse{}
+	SignMultiSigSession(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_OK, resp.ErrorCode)
+	assert.Equal(t, sessionId, resp.Result.(*MultiSigSessionRsp).SessionId)
+	assert.Equal(t, sessionId, DefMultiSigSessionMgr.getTx(sessionId).Hash().ToHexString())
+}
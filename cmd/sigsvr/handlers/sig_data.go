@@ -52,6 +52,13 @@ func SigData(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
 		resp.ErrorCode = clisvrcom.CLIERR_ACCOUNT_UNLOCK
 		return
 	}
+	err = req.CheckDataPolicy()
+	if err != nil {
+		log.Infof("Cli Qid:%s SigData CheckDataPolicy:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_POLICY_REJECTED
+		resp.ErrorInfo = err.Error()
+		return
+	}
 	sigData, err := cliutil.Sign(rawData, signer)
 	if err != nil {
 		log.Infof("Cli Qid:%s SigData Sign error:%s", req.Qid, err)
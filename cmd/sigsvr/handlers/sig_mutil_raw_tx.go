@@ -95,6 +95,13 @@ func SigMutilRawTransaction(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpc
 		resp.ErrorCode = clisvrcom.CLIERR_ACCOUNT_UNLOCK
 		return
 	}
+	err = req.CheckTxPolicy(mutTx)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigMutilRawTransaction CheckTxPolicy:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_POLICY_REJECTED
+		resp.ErrorInfo = err.Error()
+		return
+	}
 	err = cliutil.MultiSigTransaction(mutTx, uint16(rawReq.M), pubKeys, signer)
 	if err != nil {
 		log.Infof("Cli Qid:%s SigMutilRawTransaction MultiSigTransaction error:%s", req.Qid, err)
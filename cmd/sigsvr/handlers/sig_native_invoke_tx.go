@@ -89,6 +89,13 @@ func SigNativeInvokeTx(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcRespo
 		resp.ErrorCode = clisvrcom.CLIERR_ACCOUNT_UNLOCK
 		return
 	}
+	err = req.CheckTxPolicy(tx)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigNativeInvokeTx CheckTxPolicy:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_POLICY_REJECTED
+		resp.ErrorInfo = err.Error()
+		return
+	}
 	err = cliutil.SignTransaction(signer, tx)
 	if err != nil {
 		log.Infof("Cli Qid:%s SigNativeInvokeTx SignTransaction error:%s", req.Qid, err)
@@ -82,6 +82,13 @@ func SigNeoVMInvokeTx(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcRespon
 		resp.ErrorCode = clisvrcom.CLIERR_ACCOUNT_UNLOCK
 		return
 	}
+	err = req.CheckTxPolicy(mutable)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigNeoVMInvokeTx CheckTxPolicy:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_POLICY_REJECTED
+		resp.ErrorInfo = err.Error()
+		return
+	}
 	err = cliutil.SignTransaction(signer, mutable)
 	if err != nil {
 		log.Infof("Cli Qid:%s SigNeoVMInvokeTx SignTransaction error:%s", req.Qid, err)
@@ -94,6 +94,13 @@ func SigNeoVMInvokeAbiTx(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcRes
 		resp.ErrorCode = clisvrcom.CLIERR_ACCOUNT_UNLOCK
 		return
 	}
+	err = req.CheckTxPolicy(mutable)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigNeoVMInvokeAbiTx CheckTxPolicy:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_POLICY_REJECTED
+		resp.ErrorInfo = err.Error()
+		return
+	}
 	err = cliutil.SignTransaction(signer, mutable)
 	if err != nil {
 		log.Infof("Cli Qid:%s SigNeoVMInvokeAbiTx SignTransaction error:%s", req.Qid, err)
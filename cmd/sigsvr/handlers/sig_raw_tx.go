@@ -73,6 +73,13 @@ func SigRawTransaction(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcRespo
 		mutable.Payer = signer.Address
 	}
 
+	err = req.CheckTxPolicy(mutable)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigRawTransaction CheckTxPolicy:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_POLICY_REJECTED
+		resp.ErrorInfo = err.Error()
+		return
+	}
 	txHash := mutable.Hash()
 	sigData, err := cliutil.Sign(txHash.ToArray(), signer)
 	if err != nil {
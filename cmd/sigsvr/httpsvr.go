@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/ontio/ontology/cmd/sigsvr/common"
+	"github.com/ontio/ontology/cmd/sigsvr/policy"
 	"github.com/ontio/ontology/common/log"
 	"io/ioutil"
 	"net/http"
@@ -33,13 +34,17 @@ type CliRpcServer struct {
 	address    string
 	port       uint
 	handlers   map[string]func(req *common.CliRpcRequest, resp *common.CliRpcResponse)
+	httpHdls   map[string]http.Handler
 	httpSvr    *http.Server
 	httpSvtMux *http.ServeMux
+	certFile   string
+	keyFile    string
 }
 
 func NewCliRpcServer() *CliRpcServer {
 	return &CliRpcServer{
 		handlers: make(map[string]func(req *common.CliRpcRequest, resp *common.CliRpcResponse)),
+		httpHdls: make(map[string]http.Handler),
 	}
 }
 
@@ -52,7 +57,15 @@ func (this *CliRpcServer) Start(address string, port uint) {
 		Handler: this.httpSvtMux,
 	}
 	this.httpSvtMux.HandleFunc("/cli", this.Handler)
-	err := this.httpSvr.ListenAndServe()
+	for pattern, handler := range this.httpHdls {
+		this.httpSvtMux.Handle(pattern, handler)
+	}
+	var err error
+	if this.certFile != "" {
+		err = this.httpSvr.ListenAndServeTLS(this.certFile, this.keyFile)
+	} else {
+		err = this.httpSvr.ListenAndServe()
+	}
 	if err != nil {
 		if err == http.ErrServerClosed {
 			return
@@ -65,6 +78,22 @@ func (this *CliRpcServer) RegHandler(method string, handler func(req *common.Cli
 	this.handlers[method] = handler
 }
 
+//SetTls serve https with the certificate and key files, should be called before Start
+func (this *CliRpcServer) SetTls(certFile, keyFile string) {
+	this.certFile = certFile
+	this.keyFile = keyFile
+}
+
+//IsTls return whether the server serves https
+func (this *CliRpcServer) IsTls() bool {
+	return this.certFile != ""
+}
+
+//RegHttpHandler register http handler of pattern, should be called before Start
+func (this *CliRpcServer) RegHttpHandler(pattern string, handler http.Handler) {
+	this.httpHdls[pattern] = handler
+}
+
 func (this *CliRpcServer) GetHandler(method string) func(req *common.CliRpcRequest, resp *common.CliRpcResponse) {
 	handler, ok := this.handlers[method]
 	if !ok {
@@ -74,6 +103,7 @@ func (this *CliRpcServer) GetHandler(method string) func(req *common.CliRpcReque
 }
 
 func (this *CliRpcServer) Handler(w http.ResponseWriter, r *http.Request) {
+	req := &common.CliRpcRequest{}
 	resp := &common.CliRpcResponse{}
 	defer func() {
 		w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
@@ -84,6 +114,24 @@ func (this *CliRpcServer) Handler(w http.ResponseWriter, r *http.Request) {
 		if resp.ErrorInfo == "" {
 			resp.ErrorInfo = common.GetCLIErrorDesc(resp.ErrorCode)
 		}
+		if common.DefAuditLog != nil {
+			err := common.DefAuditLog.Append(&policy.AuditEntry{
+				Qid:       req.Qid,
+				Method:    req.Method,
+				Account:   req.Account,
+				Params:    req.Params,
+				Decision:  req.Decision,
+				ErrorCode: resp.ErrorCode,
+				ErrorInfo: resp.ErrorInfo,
+			})
+			if err != nil {
+				//result cannot be released without audit
+				log.Errorf("CliRpcServer audit log error:%s", err)
+				resp.Result = nil
+				resp.ErrorCode = common.CLIERR_INTERNAL_ERR
+				resp.ErrorInfo = "audit log error"
+			}
+		}
 		data, err := json.Marshal(resp)
 		if err != nil {
 			log.Error("CliRpcServer json.Marshal JsonRpcResponse:%+v error:%s", resp, err)
@@ -110,7 +158,6 @@ func (this *CliRpcServer) Handler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
-	req := &common.CliRpcRequest{}
 	err = json.Unmarshal(data, req)
 	if err != nil {
 		log.Errorf("CliRpcServer json.Unmarshal JsonRpcRequest error:%s", err)
@@ -118,12 +165,13 @@ func (this *CliRpcServer) Handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pwd := req.Pwd
+	pwd, params := req.Pwd, req.Params
 	req.Pwd = "*"
+	req.Params = policy.RedactParams(params)
 	logData, _ := json.Marshal(req)
 	log.Infof("[CliRpcRequest]%s", logData)
 
-	req.Pwd = pwd
+	req.Pwd, req.Params = pwd, params
 	resp.Method = req.Method
 	resp.Qid = req.Qid
 
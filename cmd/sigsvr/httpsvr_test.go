@@ -0,0 +1,65 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sigsvr
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/cmd/sigsvr/common"
+	"github.com/ontio/ontology/cmd/sigsvr/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCliRpcServerAuditRedact(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clirpcaudit")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+	auditLog, _, err := policy.OpenAuditLog(path, account.NewAccount(""))
+	assert.Nil(t, err)
+	defer auditLog.Close()
+	common.DefAuditLog = auditLog
+	defer func() {
+		common.DefAuditLog = nil
+	}()
+
+	server := NewCliRpcServer()
+	server.RegHandler("importaccount", func(req *common.CliRpcRequest, resp *common.CliRpcResponse) {})
+	wif := "L4shZ7B4NFQw2eqKncuUViJdFRq6uk1QUb6HjiuedxN4Q2CaRQKW"
+	body := `{"qid":"1","method":"importaccount","pwd":"123","params":{"wif":"` + wif + `","label":"imported"}}`
+	w := httptest.NewRecorder()
+	server.Handler(w, httptest.NewRequest(http.MethodPost, "/cli", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(string(data), wif))
+	entries, err := policy.ReadAuditLog(path)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, "importaccount", entries[0].Method)
+	assert.Equal(t, `{"label":"imported","wif":"*"}`, string(entries[0].Params))
+}
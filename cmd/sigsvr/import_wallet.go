@@ -54,30 +54,23 @@ func importWallet(ctx *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("NewWalletStore dir path:%s error:%s", walletDirPath, err)
 	}
+	defer walletStore.Close()
 	wallet, err := account.Open(walletFilePath)
 	if err != nil {
 		return fmt.Errorf("open wallet:%s error:%s", walletFilePath, err)
 	}
 	walletData := wallet.GetWalletData()
-	if *walletStore.WalletScrypt != *walletData.Scrypt {
-		return fmt.Errorf("import account failed, wallet scrypt:%+v != %+v", walletData.Scrypt, walletStore.WalletScrypt)
-	}
-	addNum := 0
-	updateNum := 0
-	for i := 0; i < len(walletData.Accounts); i++ {
-		ok, err := walletStore.AddAccountData(walletData.Accounts[i])
-		if err != nil {
-			return fmt.Errorf("import account address:%s error:%s", walletData.Accounts[i].Address, err)
-		}
-		if ok {
-			addNum++
-		} else {
-			updateNum++
-		}
+	addNum, updateNum, addIdNum, err := walletStore.ImportWalletData(walletData)
+	if err != nil {
+		return fmt.Errorf("import account failed, %s", err)
 	}
 	cmd.PrintInfoMsg("Import account success.")
 	cmd.PrintInfoMsg("Total account number:%d", len(walletData.Accounts))
 	cmd.PrintInfoMsg("Add account number:%d", addNum)
 	cmd.PrintInfoMsg("Update account number:%d", updateNum)
+	if len(walletData.Identities) > 0 {
+		cmd.PrintInfoMsg("Total identity number:%d", len(walletData.Identities))
+		cmd.PrintInfoMsg("Add identity number:%d", addIdNum)
+	}
 	return nil
 }
@@ -0,0 +1,252 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package policy
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/core/signature"
+)
+
+//AUDIT_SECRET_KEYS are sub strings of param keys which are redacted in audit log
+var AUDIT_SECRET_KEYS = []string{"pwd", "password", "private", "mnemonic", "passphrase", "wif", "seed", "secret", "key"}
+
+//AUDIT_PUBLIC_KEYS are param keys matching AUDIT_SECRET_KEYS, which are not secret and kept in audit log
+var AUDIT_PUBLIC_KEYS = []string{"pub_key", "pub_keys", "public_key"}
+
+//AuditEntry is a line of audit log. Entries are chained by hash, and each hash is signed by audit account
+type AuditEntry struct {
+	Seq       uint64          `json:"seq"`
+	Time      int64           `json:"time"`
+	Qid       string          `json:"qid"`
+	Method    string          `json:"method"`
+	Account   string          `json:"account"`
+	Params    json.RawMessage `json:"params,omitempty"`
+	Decision  *Decision       `json:"decision,omitempty"`
+	ErrorCode int             `json:"error_code"`
+	ErrorInfo string          `json:"error_info,omitempty"`
+	PrevHash  string          `json:"prev_hash"`
+	Hash      string          `json:"hash"`
+	Signature string          `json:"signature"`
+}
+
+func (this *AuditEntry) digest() ([]byte, error) {
+	entry := *this
+	entry.Hash = ""
+	entry.Signature = ""
+	data, err := json.Marshal(&entry)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(data)
+	return hash[:], nil
+}
+
+//AuditLog is an append-only audit log file of json lines
+type AuditLog struct {
+	file     *os.File
+	signer   account.Signer
+	seq      uint64
+	prevHash string
+	lock     sync.Mutex
+}
+
+//OpenAuditLog open audit log file signed by signer, and return the existing entries
+func OpenAuditLog(path string, signer account.Signer) (*AuditLog, []*AuditEntry, error) {
+	entries, err := ReadAuditLog(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+	err = VerifyAuditEntries(entries, signer.PubKey())
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open audit log error:%s", err)
+	}
+	auditLog := &AuditLog{
+		file:   file,
+		signer: signer,
+	}
+	if len(entries) > 0 {
+		last := entries[len(entries)-1]
+		auditLog.seq = last.Seq
+		auditLog.prevHash = last.Hash
+	}
+	return auditLog, entries, nil
+}
+
+//Append sign and write entry to audit log, entry is persisted when returned
+func (this *AuditLog) Append(entry *AuditEntry) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	entry.Seq = this.seq + 1
+	entry.Time = time.Now().Unix()
+	entry.PrevHash = this.prevHash
+	entry.Params = RedactParams(entry.Params)
+	hash, err := entry.digest()
+	if err != nil {
+		return fmt.Errorf("audit entry digest error:%s", err)
+	}
+	sigData, err := this.signer.Sign(hash)
+	if err != nil {
+		return fmt.Errorf("sign audit entry error:%s", err)
+	}
+	entry.Hash = hex.EncodeToString(hash)
+	entry.Signature = hex.EncodeToString(sigData)
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("audit entry json.Marshal error:%s", err)
+	}
+	_, err = this.file.Write(append(data, '\n'))
+	if err != nil {
+		return fmt.Errorf("write audit log error:%s", err)
+	}
+	err = this.file.Sync()
+	if err != nil {
+		return fmt.Errorf("sync audit log error:%s", err)
+	}
+	this.seq = entry.Seq
+	this.prevHash = entry.Hash
+	return nil
+}
+
+func (this *AuditLog) Close() error {
+	return this.file.Close()
+}
+
+//ReadAuditLog read all entries of audit log file
+func ReadAuditLog(path string) ([]*AuditEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	entries := make([]*AuditEntry, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		entry := &AuditEntry{}
+		err = json.Unmarshal(line, entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid audit entry:%d error:%s", len(entries)+1, err)
+		}
+		entries = append(entries, entry)
+	}
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("read audit log error:%s", err)
+	}
+	return entries, nil
+}
+
+//VerifyAuditEntries check the sequence, hash chain and signatures of entries
+func VerifyAuditEntries(entries []*AuditEntry, pubKey keypair.PublicKey) error {
+	prevHash := ""
+	for i, entry := range entries {
+		if entry.Seq != uint64(i+1) {
+			return fmt.Errorf("audit entry:%d has invalid seq:%d", i+1, entry.Seq)
+		}
+		if entry.PrevHash != prevHash {
+			return fmt.Errorf("audit entry:%d has invalid prev hash", entry.Seq)
+		}
+		hash, err := entry.digest()
+		if err != nil {
+			return fmt.Errorf("audit entry:%d digest error:%s", entry.Seq, err)
+		}
+		if entry.Hash != hex.EncodeToString(hash) {
+			return fmt.Errorf("audit entry:%d has invalid hash", entry.Seq)
+		}
+		sigData, err := hex.DecodeString(entry.Signature)
+		if err != nil {
+			return fmt.Errorf("audit entry:%d has invalid signature", entry.Seq)
+		}
+		err = signature.Verify(pubKey, hash, sigData)
+		if err != nil {
+			return fmt.Errorf("audit entry:%d signature verification failed", entry.Seq)
+		}
+		prevHash = entry.Hash
+	}
+	return nil
+}
+
+//RedactParams replace the values of secret keys in params
+func RedactParams(params json.RawMessage) json.RawMessage {
+	if len(params) == 0 {
+		return params
+	}
+	var value interface{}
+	err := json.Unmarshal(params, &value)
+	if err != nil {
+		return params
+	}
+	data, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return params
+	}
+	return data
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isSecretKey(key) {
+				v[key] = "*"
+				continue
+			}
+			v[key] = redactValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, public := range AUDIT_PUBLIC_KEYS {
+		if key == public {
+			return false
+		}
+	}
+	for _, secret := range AUDIT_SECRET_KEYS {
+		if strings.Contains(key, secret) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package policy
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ontio/ontology/account"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+	acc := account.NewAccount("")
+
+	auditLog, entries, err := OpenAuditLog(path, acc)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(entries))
+	err = auditLog.Append(&AuditEntry{
+		Qid:     "1",
+		Method:  "sigrawtx",
+		Account: acc.Address.ToBase58(),
+		Params:  json.RawMessage(`{"raw_tx":"00","new_pwd":"secret"}`),
+		Decision: &Decision{
+			Allow:  false,
+			Reason: "contract is not allowed",
+		},
+	})
+	assert.Nil(t, err)
+	err = auditLog.Append(&AuditEntry{Qid: "2", Method: "sigdata"})
+	assert.Nil(t, err)
+	auditLog.Close()
+
+	auditLog, entries, err = OpenAuditLog(path, acc)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, `{"new_pwd":"*","raw_tx":"00"}`, string(entries[0].Params))
+	assert.Equal(t, entries[0].Hash, entries[1].PrevHash)
+	err = auditLog.Append(&AuditEntry{Qid: "3", Method: "sigdata"})
+	assert.Nil(t, err)
+	auditLog.Close()
+
+	entries, err = ReadAuditLog(path)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(entries))
+	assert.Equal(t, uint64(3), entries[2].Seq)
+	assert.Nil(t, VerifyAuditEntries(entries, acc.PublicKey))
+	assert.NotNil(t, VerifyAuditEntries(entries, account.NewAccount("").PublicKey))
+	assert.NotNil(t, VerifyAuditEntries(entries[1:], acc.PublicKey))
+
+	//tamper decision
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	data = []byte(strings.Replace(string(data), `"allow":false`, `"allow":true`, 1))
+	err = ioutil.WriteFile(path, data, 0600)
+	assert.Nil(t, err)
+	entries, err = ReadAuditLog(path)
+	assert.Nil(t, err)
+	assert.NotNil(t, VerifyAuditEntries(entries, acc.PublicKey))
+	_, _, err = OpenAuditLog(path, acc)
+	assert.NotNil(t, err)
+}
+
+func TestRedactParams(t *testing.T) {
+	params := RedactParams(json.RawMessage(`{"wif":"L4sh","private_key":"00","seed":"01","pub_keys":["02"],"m":1}`))
+	assert.Equal(t, `{"m":1,"private_key":"*","pub_keys":["02"],"seed":"*","wif":"*"}`, string(params))
+}
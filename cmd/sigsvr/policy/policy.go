@@ -0,0 +1,401 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package policy evaluate signing policies of sigsvr accounts, and keep audit log of requests
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	cliutil "github.com/ontio/ontology/cmd/utils"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/types"
+	cutils "github.com/ontio/ontology/core/utils"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+const (
+	//POLICY_DEFAULT_ACCOUNT is the policy key of accounts without own policy
+	POLICY_DEFAULT_ACCOUNT = "*"
+	//POLICY_ANY_METHOD allow all methods of contract
+	POLICY_ANY_METHOD = "*"
+	POLICY_DAY_FORMAT = "2006-01-02"
+)
+
+//AccountPolicy is the signing policy of an account. Unset field means no restriction,
+//amount of ONT and ONG is in the minimum unit of contract
+type AccountPolicy struct {
+	AllowSignData bool                `json:"allow_sign_data"`
+	Contracts     map[string][]string `json:"contracts,omitempty"`
+	MaxOntPerTx   *uint64             `json:"max_ont_per_tx,omitempty"`
+	MaxOngPerTx   *uint64             `json:"max_ong_per_tx,omitempty"`
+	MaxOntPerDay  *uint64             `json:"max_ont_per_day,omitempty"`
+	MaxOngPerDay  *uint64             `json:"max_ong_per_day,omitempty"`
+	Destinations  []string            `json:"destinations,omitempty"`
+
+	contracts    map[common.Address]map[string]bool
+	destinations map[common.Address]bool
+}
+
+type PolicyConfig struct {
+	Accounts map[string]*AccountPolicy `json:"accounts"`
+}
+
+//Transfer is an ONT or ONG transfer or approval decoded from transaction
+type Transfer struct {
+	Asset string `json:"asset"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Value uint64 `json:"value"`
+}
+
+//Decision is the result of policy evaluation
+type Decision struct {
+	Allow     bool        `json:"allow"`
+	Reason    string      `json:"reason,omitempty"`
+	Contract  string      `json:"contract,omitempty"`
+	Method    string      `json:"method,omitempty"`
+	Transfers []*Transfer `json:"transfers,omitempty"`
+	//Fee is the max ONG fee of transaction paid by the account
+	Fee uint64 `json:"fee,omitempty"`
+}
+
+type dayUsage struct {
+	day    string
+	amount uint64
+}
+
+type PolicyEngine struct {
+	policies map[string]*AccountPolicy
+	usages   map[string]*dayUsage //account+asset -> usage of the day
+	lock     sync.Mutex
+}
+
+//LoadPolicyEngine load policy config from json file
+func LoadPolicyEngine(file string) (*PolicyEngine, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read policy file error:%s", err)
+	}
+	cfg := &PolicyConfig{}
+	err = json.Unmarshal(data, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy file error:%s", err)
+	}
+	return NewPolicyEngine(cfg)
+}
+
+func NewPolicyEngine(cfg *PolicyConfig) (*PolicyEngine, error) {
+	engine := &PolicyEngine{
+		policies: make(map[string]*AccountPolicy),
+		usages:   make(map[string]*dayUsage),
+	}
+	for account, policy := range cfg.Accounts {
+		if account != POLICY_DEFAULT_ACCOUNT {
+			_, err := common.AddressFromBase58(account)
+			if err != nil {
+				return nil, fmt.Errorf("invalid policy account:%s", account)
+			}
+		}
+		if policy.Contracts != nil {
+			policy.contracts = make(map[common.Address]map[string]bool)
+			for contract, methods := range policy.Contracts {
+				addr, err := parseAddress(contract)
+				if err != nil {
+					return nil, fmt.Errorf("invalid contract:%s of account:%s", contract, account)
+				}
+				policy.contracts[addr] = make(map[string]bool)
+				for _, method := range methods {
+					policy.contracts[addr][method] = true
+				}
+			}
+		}
+		if policy.Destinations != nil {
+			policy.destinations = make(map[common.Address]bool)
+			for _, dest := range policy.Destinations {
+				addr, err := common.AddressFromBase58(dest)
+				if err != nil {
+					return nil, fmt.Errorf("invalid destination:%s of account:%s", dest, account)
+				}
+				policy.destinations[addr] = true
+			}
+		}
+		engine.policies[account] = policy
+	}
+	return engine, nil
+}
+
+//parseAddress parse contract address in base58 or hex
+func parseAddress(addr string) (common.Address, error) {
+	address, err := common.AddressFromBase58(addr)
+	if err == nil {
+		return address, nil
+	}
+	return common.AddressFromHexString(addr)
+}
+
+func (this *PolicyEngine) getPolicy(account string) *AccountPolicy {
+	policy, ok := this.policies[account]
+	if ok {
+		return policy
+	}
+	return this.policies[POLICY_DEFAULT_ACCOUNT]
+}
+
+//CheckData evaluate the policy of signing raw data, which cannot be decoded
+func (this *PolicyEngine) CheckData(account string) *Decision {
+	policy := this.getPolicy(account)
+	if policy == nil {
+		return &Decision{Reason: "no policy of account"}
+	}
+	if !policy.AllowSignData {
+		return &Decision{Reason: "signing raw data is not allowed"}
+	}
+	return &Decision{Allow: true}
+}
+
+//CheckTx evaluate the policy of signing transaction, the spending of allowed transaction is counted
+//in daily limit. Fee paid by the account is counted as ONG spending, invoking contract other than
+//ONT and ONG must be allowed explicitly in restricted policy
+func (this *PolicyEngine) CheckTx(account string, tx *types.MutableTransaction) *Decision {
+	policy := this.getPolicy(account)
+	if policy == nil {
+		return &Decision{Reason: "no policy of account"}
+	}
+	decision := &Decision{}
+	if tx.Payer.ToBase58() == account {
+		fee, overflow := common.SafeMul(tx.GasPrice, tx.GasLimit)
+		if overflow {
+			decision.Reason = "transaction fee overflow"
+			return decision
+		}
+		decision.Fee = fee
+	}
+	restricted := policy.contracts != nil || policy.destinations != nil || policy.MaxOntPerTx != nil ||
+		policy.MaxOngPerTx != nil || policy.MaxOntPerDay != nil || policy.MaxOngPerDay != nil
+	switch pl := tx.Payload.(type) {
+	case *payload.DeployCode:
+		decision.Contract = pl.Address().ToHexString()
+		if policy.contracts != nil {
+			decision.Reason = "deploying contract is not allowed"
+			return decision
+		}
+	case *payload.InvokeCode:
+		info, err := cutils.DecodeInvokeCode(pl.Code)
+		if err != nil {
+			if restricted {
+				decision.Reason = fmt.Sprintf("cannot decode invoke code:%s", err)
+				return decision
+			}
+			decision.Allow = true
+			return decision
+		}
+		decision.Contract = info.Contract.ToHexString()
+		decision.Method = info.Method
+		if policy.contracts != nil {
+			methods, ok := policy.contracts[info.Contract]
+			if !ok {
+				decision.Reason = fmt.Sprintf("contract:%s is not allowed", decision.Contract)
+				return decision
+			}
+			if !methods[POLICY_ANY_METHOD] && !methods[info.Method] {
+				decision.Reason = fmt.Sprintf("method:%s of contract:%s is not allowed", info.Method, decision.Contract)
+				return decision
+			}
+		}
+		if info.Native && (info.Contract == utils.OntContractAddress || info.Contract == utils.OngContractAddress) {
+			decision.Transfers, err = decodeTransfers(info)
+			if err != nil {
+				decision.Reason = err.Error()
+				return decision
+			}
+		} else if restricted && policy.contracts == nil {
+			//spending by other contracts cannot be counted
+			decision.Reason = fmt.Sprintf("contract:%s is not allowed", decision.Contract)
+			return decision
+		}
+	default:
+		decision.Reason = "unsupported transaction type"
+		return decision
+	}
+	return this.checkTransfers(account, policy, decision)
+}
+
+func (this *PolicyEngine) checkTransfers(account string, policy *AccountPolicy, decision *Decision) *Decision {
+	total := make(map[string]uint64)
+	for _, transfer := range decision.Transfers {
+		if policy.destinations != nil {
+			to, _ := common.AddressFromBase58(transfer.To)
+			if !policy.destinations[to] {
+				decision.Reason = fmt.Sprintf("destination:%s is not allowed", transfer.To)
+				return decision
+			}
+		}
+		sum, overflow := common.SafeAdd(total[transfer.Asset], transfer.Value)
+		if overflow {
+			decision.Reason = "transfer amount overflow"
+			return decision
+		}
+		total[transfer.Asset] = sum
+	}
+	if decision.Fee != 0 {
+		sum, overflow := common.SafeAdd(total[cliutil.ASSET_ONG], decision.Fee)
+		if overflow {
+			decision.Reason = "transfer amount overflow"
+			return decision
+		}
+		total[cliutil.ASSET_ONG] = sum
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	day := time.Now().UTC().Format(POLICY_DAY_FORMAT)
+	for asset, amount := range total {
+		maxPerTx, maxPerDay := policy.MaxOntPerTx, policy.MaxOntPerDay
+		if asset == cliutil.ASSET_ONG {
+			maxPerTx, maxPerDay = policy.MaxOngPerTx, policy.MaxOngPerDay
+		}
+		if maxPerTx != nil && amount > *maxPerTx {
+			decision.Reason = fmt.Sprintf("%s amount:%d exceeds limit per tx:%d", asset, amount, *maxPerTx)
+			return decision
+		}
+		if maxPerDay != nil {
+			used := this.usedOfDay(account, asset, day)
+			sum, overflow := common.SafeAdd(used, amount)
+			if overflow || sum > *maxPerDay {
+				decision.Reason = fmt.Sprintf("%s amount:%d exceeds limit per day:%d, used:%d", asset, amount, *maxPerDay, used)
+				return decision
+			}
+		}
+	}
+	for asset, amount := range total {
+		this.addUsage(account, asset, day, amount)
+	}
+	decision.Allow = true
+	return decision
+}
+
+func (this *PolicyEngine) usedOfDay(account, asset, day string) uint64 {
+	usage, ok := this.usages[account+asset]
+	if !ok || usage.day != day {
+		return 0
+	}
+	return usage.amount
+}
+
+func (this *PolicyEngine) addUsage(account, asset, day string, amount uint64) {
+	usage, ok := this.usages[account+asset]
+	if !ok || usage.day != day {
+		usage = &dayUsage{day: day}
+		this.usages[account+asset] = usage
+	}
+	usage.amount += amount
+}
+
+//Replay count the allowed transfers and fees of today in audit log, so that daily limit survives restart
+func (this *PolicyEngine) Replay(entries []*AuditEntry) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	day := time.Now().UTC().Format(POLICY_DAY_FORMAT)
+	for _, entry := range entries {
+		if entry.Decision == nil || !entry.Decision.Allow {
+			continue
+		}
+		if time.Unix(entry.Time, 0).UTC().Format(POLICY_DAY_FORMAT) != day {
+			continue
+		}
+		for _, transfer := range entry.Decision.Transfers {
+			this.addUsage(entry.Account, transfer.Asset, day, transfer.Value)
+		}
+		if entry.Decision.Fee != 0 {
+			this.addUsage(entry.Account, cliutil.ASSET_ONG, day, entry.Decision.Fee)
+		}
+	}
+}
+
+//decodeTransfers decode transfer, transferFrom and approve of ONT and ONG contract
+func decodeTransfers(info *cutils.InvokeCodeInfo) ([]*Transfer, error) {
+	asset := cliutil.ASSET_ONT
+	if info.Contract == utils.OngContractAddress {
+		asset = cliutil.ASSET_ONG
+	}
+	var states [][]interface{}
+	var fromIndex int
+	switch info.Method {
+	case cliutil.CONTRACT_TRANSFER:
+		//[[from, to, value]...]
+		if len(info.Params) != 1 {
+			return nil, fmt.Errorf("invalid %s params", info.Method)
+		}
+		items, ok := info.Params[0].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid %s params", info.Method)
+		}
+		for _, item := range items {
+			state, ok := item.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid %s params", info.Method)
+			}
+			states = append(states, state)
+		}
+	case cliutil.CONTRACT_TRANSFER_FROM, cliutil.CONTRACT_APPROVE:
+		//transferFrom [sender, from, to, value], approve [from, to, value]
+		if len(info.Params) != 1 {
+			return nil, fmt.Errorf("invalid %s params", info.Method)
+		}
+		state, ok := info.Params[0].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid %s params", info.Method)
+		}
+		states = append(states, state)
+		if info.Method == cliutil.CONTRACT_TRANSFER_FROM {
+			fromIndex = 1
+		}
+	default:
+		return nil, nil
+	}
+	transfers := make([]*Transfer, 0, len(states))
+	for _, state := range states {
+		if len(state) != fromIndex+3 {
+			return nil, fmt.Errorf("invalid %s params", info.Method)
+		}
+		from, err := cutils.ParamToAddress(state[fromIndex])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s from:%s", info.Method, err)
+		}
+		to, err := cutils.ParamToAddress(state[fromIndex+1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s to:%s", info.Method, err)
+		}
+		value, err := cutils.ParamToBigInt(state[fromIndex+2])
+		if err != nil || value.Sign() < 0 || !value.IsUint64() {
+			return nil, fmt.Errorf("invalid %s value", info.Method)
+		}
+		transfers = append(transfers, &Transfer{
+			Asset: asset,
+			From:  from.ToBase58(),
+			To:    to.ToBase58(),
+			Value: value.Uint64(),
+		})
+	}
+	return transfers, nil
+}
@@ -0,0 +1,208 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology/account"
+	cliutil "github.com/ontio/ontology/cmd/utils"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
+	cutils "github.com/ontio/ontology/core/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTransferTx(t *testing.T, asset, from, to string, amount uint64) *types.MutableTransaction {
+	tx, err := cliutil.TransferTx(0, 20000, asset, from, to, amount)
+	assert.Nil(t, err)
+	return tx
+}
+
+func TestPolicyEngine(t *testing.T) {
+	acc := account.NewAccount("").Address.ToBase58()
+	dest := account.NewAccount("").Address.ToBase58()
+	other := account.NewAccount("").Address.ToBase58()
+	maxPerTx := uint64(100)
+	maxPerDay := uint64(150)
+	engine, err := NewPolicyEngine(&PolicyConfig{
+		Accounts: map[string]*AccountPolicy{
+			acc: {
+				Contracts: map[string][]string{
+					"0100000000000000000000000000000000000000": {"transfer"},
+				},
+				MaxOntPerTx:  &maxPerTx,
+				MaxOntPerDay: &maxPerDay,
+				Destinations: []string{dest},
+			},
+		},
+	})
+	assert.Nil(t, err)
+
+	decision := engine.CheckTx(acc, newTestTransferTx(t, "ont", acc, dest, 100))
+	assert.True(t, decision.Allow, decision.Reason)
+	assert.Equal(t, "transfer", decision.Method)
+	assert.Equal(t, []*Transfer{{Asset: "ont", From: acc, To: dest, Value: 100}}, decision.Transfers)
+
+	decision = engine.CheckTx(acc, newTestTransferTx(t, "ont", acc, dest, 101))
+	assert.False(t, decision.Allow)
+	decision = engine.CheckTx(acc, newTestTransferTx(t, "ont", acc, dest, 60))
+	assert.False(t, decision.Allow)
+	decision = engine.CheckTx(acc, newTestTransferTx(t, "ont", acc, dest, 50))
+	assert.True(t, decision.Allow, decision.Reason)
+
+	decision = engine.CheckTx(acc, newTestTransferTx(t, "ont", acc, other, 0))
+	assert.False(t, decision.Allow)
+	decision = engine.CheckTx(acc, newTestTransferTx(t, "ong", acc, dest, 1))
+	assert.False(t, decision.Allow)
+	decision = engine.CheckTx(acc, cliutil.NewInvokeTransaction(0, 20000, []byte{0x51, 0x93}))
+	assert.False(t, decision.Allow)
+
+	assert.False(t, engine.CheckData(acc).Allow)
+	assert.False(t, engine.CheckTx(other, newTestTransferTx(t, "ont", other, dest, 1)).Allow)
+}
+
+func TestPolicyEngineDefault(t *testing.T) {
+	acc := account.NewAccount("").Address.ToBase58()
+	dest := account.NewAccount("").Address.ToBase58()
+	maxPerDay := uint64(10)
+	engine, err := NewPolicyEngine(&PolicyConfig{
+		Accounts: map[string]*AccountPolicy{
+			POLICY_DEFAULT_ACCOUNT: {
+				AllowSignData: true,
+				MaxOngPerDay:  &maxPerDay,
+			},
+		},
+	})
+	assert.Nil(t, err)
+	assert.True(t, engine.CheckData(acc).Allow)
+
+	engine.Replay([]*AuditEntry{
+		{
+			Time:     time.Now().Unix(),
+			Account:  acc,
+			Decision: &Decision{Allow: true, Transfers: []*Transfer{{Asset: "ong", From: acc, To: dest, Value: 8}}},
+		},
+		{
+			Time:     time.Now().Add(-48 * time.Hour).Unix(),
+			Account:  acc,
+			Decision: &Decision{Allow: true, Transfers: []*Transfer{{Asset: "ong", From: acc, To: dest, Value: 8}}},
+		},
+	})
+	assert.False(t, engine.CheckTx(acc, newTestTransferTx(t, "ong", acc, dest, 3)).Allow)
+	assert.True(t, engine.CheckTx(acc, newTestTransferTx(t, "ong", acc, dest, 2)).Allow)
+	assert.True(t, engine.CheckTx(acc, newTestTransferTx(t, "ont", acc, dest, 1000)).Allow)
+
+	_, err = NewPolicyEngine(&PolicyConfig{
+		Accounts: map[string]*AccountPolicy{"invalid": {}},
+	})
+	assert.NotNil(t, err)
+}
+
+func TestPolicyEngineContract(t *testing.T) {
+	acc := account.NewAccount("").Address.ToBase58()
+	maxPerDay := uint64(10)
+	engine, err := NewPolicyEngine(&PolicyConfig{
+		Accounts: map[string]*AccountPolicy{
+			POLICY_DEFAULT_ACCOUNT: {MaxOngPerDay: &maxPerDay},
+		},
+	})
+	assert.Nil(t, err)
+
+	contract := account.NewAccount("").Address
+	code, err := cutils.BuildNeoVMInvokeCode(contract, []interface{}{"transfer", []interface{}{[]byte("to"), 100}})
+	assert.Nil(t, err)
+	decision := engine.CheckTx(acc, cliutil.NewInvokeTransaction(0, 20000, code))
+	assert.False(t, decision.Allow)
+	assert.Equal(t, contract.ToHexString(), decision.Contract)
+
+	engine, err = NewPolicyEngine(&PolicyConfig{
+		Accounts: map[string]*AccountPolicy{
+			POLICY_DEFAULT_ACCOUNT: {
+				Contracts:    map[string][]string{contract.ToHexString(): {POLICY_ANY_METHOD}},
+				MaxOngPerDay: &maxPerDay,
+			},
+		},
+	})
+	assert.Nil(t, err)
+	decision = engine.CheckTx(acc, cliutil.NewInvokeTransaction(0, 20000, code))
+	assert.True(t, decision.Allow, decision.Reason)
+}
+
+func TestPolicyEngineFee(t *testing.T) {
+	acc := account.NewAccount("").Address
+	dest := account.NewAccount("").Address.ToBase58()
+	maxPerTx := uint64(30)
+	maxPerDay := uint64(50)
+	engine, err := NewPolicyEngine(&PolicyConfig{
+		Accounts: map[string]*AccountPolicy{
+			POLICY_DEFAULT_ACCOUNT: {
+				MaxOngPerTx:  &maxPerTx,
+				MaxOngPerDay: &maxPerDay,
+			},
+		},
+	})
+	assert.Nil(t, err)
+
+	newTx := func(gasPrice, gasLimit, amount uint64) *types.MutableTransaction {
+		tx, err := cliutil.TransferTx(gasPrice, gasLimit, "ong", acc.ToBase58(), dest, amount)
+		assert.Nil(t, err)
+		tx.Payer = acc
+		return tx
+	}
+	decision := engine.CheckTx(acc.ToBase58(), newTx(1, 21, 10))
+	assert.False(t, decision.Allow)
+	decision = engine.CheckTx(acc.ToBase58(), newTx(1, 20, 10))
+	assert.True(t, decision.Allow, decision.Reason)
+	assert.Equal(t, uint64(20), decision.Fee)
+	decision = engine.CheckTx(acc.ToBase58(), newTx(1, 20, 1))
+	assert.False(t, decision.Allow)
+	decision = engine.CheckTx(acc.ToBase58(), newTx(1, 20, 0))
+	assert.True(t, decision.Allow, decision.Reason)
+
+	//fee of transaction paid by others is not counted
+	tx := newTx(1, 20, 10)
+	tx.Payer = common.ADDRESS_EMPTY
+	decision = engine.CheckTx(acc.ToBase58(), tx)
+	assert.False(t, decision.Allow)
+	tx = newTx(1, 20, 0)
+	tx.Payer = common.ADDRESS_EMPTY
+	decision = engine.CheckTx(acc.ToBase58(), tx)
+	assert.True(t, decision.Allow, decision.Reason)
+
+	decision = engine.CheckTx(acc.ToBase58(), newTx(2, 1<<63, 0))
+	assert.False(t, decision.Allow)
+
+	engine, err = NewPolicyEngine(&PolicyConfig{
+		Accounts: map[string]*AccountPolicy{
+			POLICY_DEFAULT_ACCOUNT: {MaxOngPerDay: &maxPerDay},
+		},
+	})
+	assert.Nil(t, err)
+	engine.Replay([]*AuditEntry{
+		{
+			Time:     time.Now().Unix(),
+			Account:  acc.ToBase58(),
+			Decision: &Decision{Allow: true, Fee: 45},
+		},
+	})
+	assert.False(t, engine.CheckTx(acc.ToBase58(), newTx(1, 6, 0)).Allow)
+	assert.True(t, engine.CheckTx(acc.ToBase58(), newTx(1, 5, 0)).Allow)
+}
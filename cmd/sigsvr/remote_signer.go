@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sigsvr
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/cmd/sigsvr/common"
+	"github.com/ontio/ontology/cmd/sigsvr/policy"
+	"github.com/ontio/ontology/common/log"
+)
+
+//REMOTE_SIGNER_AUDIT_METHOD prefix the method of remote signer requests in audit log, e.g. remotesigner/sign
+const REMOTE_SIGNER_AUDIT_METHOD = "remotesigner"
+
+//RemoteSignerHandler serve remote signer of account under the signing policy, sign and vrf requests
+//are checked as signing raw data and kept in audit log like cli requests. Error code of audit entry
+//is the http status of failed request
+type RemoteSignerHandler struct {
+	account string
+	token   string
+	handler http.Handler
+}
+
+func NewRemoteSignerHandler(acc *account.Account, token string) *RemoteSignerHandler {
+	return &RemoteSignerHandler{
+		account: acc.Address.ToBase58(),
+		token:   token,
+		handler: account.NewRemoteSignerHandler(acc, ""),
+	}
+}
+
+func (this *RemoteSignerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if this.token != "" {
+		auth := r.Header.Get("Authorization")
+		if subtle.ConstantTimeCompare([]byte(auth), []byte("Bearer "+this.token)) != 1 {
+			writeRemoteSignerError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+	}
+	if r.Method != http.MethodPost {
+		//public key is not sensitive
+		this.handler.ServeHTTP(w, r)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, account.REMOTE_SIGNER_MAX_BODY))
+	if err != nil {
+		writeRemoteSignerError(w, http.StatusBadRequest, "read request error")
+		return
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	var decision *policy.Decision
+	rsp := httptest.NewRecorder()
+	if common.DefPolicyEngine != nil {
+		decision = common.DefPolicyEngine.CheckData(this.account)
+	}
+	if decision != nil && !decision.Allow {
+		writeRemoteSignerError(rsp, http.StatusForbidden, "policy rejected:"+decision.Reason)
+	} else {
+		this.handler.ServeHTTP(rsp, r)
+	}
+	if common.DefAuditLog != nil {
+		entry := &policy.AuditEntry{
+			Method:   REMOTE_SIGNER_AUDIT_METHOD + r.URL.Path[strings.LastIndex(r.URL.Path, "/"):],
+			Account:  this.account,
+			Decision: decision,
+		}
+		if json.Valid(body) {
+			entry.Params = body
+		}
+		if rsp.Code != http.StatusOK {
+			entry.ErrorCode = rsp.Code
+			entry.ErrorInfo = strings.TrimSpace(rsp.Body.String())
+		}
+		err = common.DefAuditLog.Append(entry)
+		if err != nil {
+			//result cannot be released without audit
+			log.Errorf("RemoteSignerHandler audit log error:%s", err)
+			writeRemoteSignerError(w, http.StatusInternalServerError, "audit log error")
+			return
+		}
+	}
+	for key, values := range rsp.Header() {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(rsp.Code)
+	w.Write(rsp.Body.Bytes())
+}
+
+func writeRemoteSignerError(w http.ResponseWriter, status int, desc string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": desc})
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sigsvr
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/cmd/sigsvr/common"
+	"github.com/ontio/ontology/cmd/sigsvr/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteSignerHandler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remotesigner")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+	acc := account.NewAccount("")
+	auditLog, _, err := policy.OpenAuditLog(path, account.NewAccount(""))
+	assert.Nil(t, err)
+	defer auditLog.Close()
+	engine, err := policy.NewPolicyEngine(&policy.PolicyConfig{
+		Accounts: map[string]*policy.AccountPolicy{
+			acc.Address.ToBase58(): {AllowSignData: true},
+		},
+	})
+	assert.Nil(t, err)
+	common.DefAuditLog = auditLog
+	common.DefPolicyEngine = engine
+	defer func() {
+		common.DefAuditLog = nil
+		common.DefPolicyEngine = nil
+	}()
+
+	server := httptest.NewServer(NewRemoteSignerHandler(acc, "token"))
+	defer server.Close()
+	_, err = account.NewRemoteSigner(server.URL+"/signer", "invalid")
+	assert.NotNil(t, err)
+	signer, err := account.NewRemoteSigner(server.URL+"/signer", "token")
+	assert.Nil(t, err)
+	_, err = signer.Sign([]byte("hello"))
+	assert.Nil(t, err)
+
+	//account without policy cannot sign
+	other := account.NewAccount("")
+	otherServer := httptest.NewServer(NewRemoteSignerHandler(other, "token"))
+	defer otherServer.Close()
+	signer, err = account.NewRemoteSigner(otherServer.URL+"/signer", "token")
+	assert.Nil(t, err)
+	_, err = signer.Sign([]byte("hello"))
+	assert.NotNil(t, err)
+
+	entries, err := policy.ReadAuditLog(path)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, "remotesigner/sign", entries[0].Method)
+	assert.Equal(t, acc.Address.ToBase58(), entries[0].Account)
+	assert.True(t, entries[0].Decision.Allow)
+	assert.Equal(t, 0, entries[0].ErrorCode)
+	assert.Contains(t, string(entries[0].Params), hex.EncodeToString([]byte("hello")))
+	assert.Equal(t, other.Address.ToBase58(), entries[1].Account)
+	assert.False(t, entries[1].Decision.Allow)
+	assert.Equal(t, 403, entries[1].ErrorCode)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 	"sync"
 )
 
@@ -239,10 +240,19 @@ func (this *WalletStore) NewAccountData(typeCode keypair.KeyType, curveCode byte
 	if len(passwd) == 0 {
 		return nil, fmt.Errorf("password cannot empty")
 	}
-	prvkey, pubkey, err := keypair.GenerateKeyPair(typeCode, curveCode)
+	prvkey, _, err := keypair.GenerateKeyPair(typeCode, curveCode)
 	if err != nil {
 		return nil, fmt.Errorf("generateKeyPair error:%s", err)
 	}
+	return this.NewAccountDataFromPrivateKey(prvkey, sigScheme, passwd)
+}
+
+//NewAccountDataFromPrivateKey return account data of imported private key encrypted by passwd
+func (this *WalletStore) NewAccountDataFromPrivateKey(prvkey keypair.PrivateKey, sigScheme s.SignatureScheme, passwd []byte) (*account.AccountData, error) {
+	if len(passwd) == 0 {
+		return nil, fmt.Errorf("password cannot empty")
+	}
+	pubkey := prvkey.Public()
 	address := types.AddressFromPubKey(pubkey)
 	addressBase58 := address.ToBase58()
 	prvSecret, err := keypair.EncryptWithCustomScrypt(prvkey, addressBase58, passwd, this.WalletScrypt)
@@ -414,3 +424,284 @@ func (this *WalletStore) GetAccountNumber() (uint32, error) {
 	}
 	return accNum, nil
 }
+
+//GetAccountDatas return all accounts in index order
+func (this *WalletStore) GetAccountDatas() ([]*account.AccountData, error) {
+	accDatas := make([]*account.AccountData, 0)
+	nextIndex := this.GetNextAccountIndex()
+	for i := uint32(0); i < nextIndex; i++ {
+		accData, err := this.GetAccountDataByIndex(i)
+		if err != nil {
+			return nil, fmt.Errorf("GetAccountDataByIndex:%d error:%s", i, err)
+		}
+		if accData == nil {
+			continue
+		}
+		accDatas = append(accDatas, accData)
+	}
+	return accDatas, nil
+}
+
+//UpdateAccountData overwrite data of an existing account
+func (this *WalletStore) UpdateAccountData(accData *account.AccountData) error {
+	isExist, err := this.IsAccountExist(accData.Address)
+	if err != nil {
+		return err
+	}
+	if !isExist {
+		return fmt.Errorf("cannot find account by %s", accData.Address)
+	}
+	data, err := json.Marshal(accData)
+	if err != nil {
+		return err
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.db.Put(GetAccountKey(accData.Address), data, nil)
+}
+
+//ChangeAccountPassword re-encrypt private key of account with new password
+func (this *WalletStore) ChangeAccountPassword(address string, oldPasswd, newPasswd []byte) error {
+	if len(newPasswd) == 0 {
+		return fmt.Errorf("password cannot empty")
+	}
+	accData, err := this.GetAccountDataByAddress(address)
+	if err != nil {
+		return err
+	}
+	if accData == nil {
+		return fmt.Errorf("cannot find account by %s", address)
+	}
+	prvkey, err := keypair.DecryptWithCustomScrypt(&accData.ProtectedKey, oldPasswd, this.WalletScrypt)
+	if err != nil {
+		return fmt.Errorf("decrypt PrivateKey error:%s", err)
+	}
+	prvSecret, err := keypair.EncryptWithCustomScrypt(prvkey, address, newPasswd, this.WalletScrypt)
+	if err != nil {
+		return fmt.Errorf("encryptPrivateKey error:%s", err)
+	}
+	accData.SetKeyPair(prvSecret)
+	return this.UpdateAccountData(accData)
+}
+
+//DeleteAccountData delete account, if the default account is deleted, the first account left becomes default
+func (this *WalletStore) DeleteAccountData(address string) (bool, error) {
+	isExist, err := this.IsAccountExist(address)
+	if err != nil {
+		return false, err
+	}
+	if !isExist {
+		return false, nil
+	}
+	accData, err := this.GetAccountDataByAddress(address)
+	if err != nil {
+		return false, err
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	accountNum, err := this.GetAccountNumber()
+	if err != nil {
+		return false, fmt.Errorf("GetAccountNumber error:%s", err)
+	}
+	batch := &leveldb.Batch{}
+	batch.Delete(GetAccountKey(address))
+	var newDefault *account.AccountData
+	for i := uint32(0); i < this.nextAccountIndex; i++ {
+		addr, err := this.GetAccountAddress(i)
+		if err != nil {
+			return false, fmt.Errorf("GetAccountAddress Index:%d error:%s", i, err)
+		}
+		if addr == "" {
+			continue
+		}
+		if addr == address {
+			batch.Delete(GetAccountIndexKey(i))
+			continue
+		}
+		if accData.IsDefault && newDefault == nil {
+			newDefault, err = this.GetAccountDataByAddress(addr)
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+	if newDefault != nil {
+		newDefault.IsDefault = true
+		data, err := json.Marshal(newDefault)
+		if err != nil {
+			return false, err
+		}
+		batch.Put(GetAccountKey(newDefault.Address), data)
+	}
+	if accountNum > 0 {
+		accountNum--
+	}
+	data := make([]byte, 4, 4)
+	binary.LittleEndian.PutUint32(data, accountNum)
+	batch.Put(GetWalletAccountNumberKey(), data)
+
+	err = this.db.Write(batch, nil)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+//NewIdentity create an ONT ID controlled by a new P-256 key encrypted by passwd
+func (this *WalletStore) NewIdentity(label string, passwd []byte) (*account.Identity, error) {
+	if len(passwd) == 0 {
+		return nil, fmt.Errorf("password cannot empty")
+	}
+	ontid, err := account.GenerateID()
+	if err != nil {
+		return nil, err
+	}
+	prvkey, pubkey, err := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	if err != nil {
+		return nil, fmt.Errorf("generateKeyPair error:%s", err)
+	}
+	address := types.AddressFromPubKey(pubkey)
+	prvSecret, err := keypair.EncryptWithCustomScrypt(prvkey, address.ToBase58(), passwd, this.WalletScrypt)
+	if err != nil {
+		return nil, fmt.Errorf("encryptPrivateKey error:%s", err)
+	}
+	identity := &account.Identity{
+		ID:    ontid,
+		Label: label,
+		Control: []account.Controller{{
+			ID:           "1",
+			Public:       hex.EncodeToString(keypair.SerializePublicKey(pubkey)),
+			ProtectedKey: *prvSecret,
+		}},
+	}
+	return identity, nil
+}
+
+//AddIdentity add or update an ONT ID, return true if added
+func (this *WalletStore) AddIdentity(identity *account.Identity) (bool, error) {
+	if !account.VerifyID(identity.ID) {
+		return false, fmt.Errorf("invalid ONT ID:%s", identity.ID)
+	}
+	old, err := this.GetIdentity(identity.ID)
+	if err != nil {
+		return false, err
+	}
+	data, err := json.Marshal(identity)
+	if err != nil {
+		return false, err
+	}
+	err = this.db.Put(GetIdentityKey(identity.ID), data, nil)
+	if err != nil {
+		return false, err
+	}
+	return old == nil, nil
+}
+
+func (this *WalletStore) GetIdentity(ontid string) (*account.Identity, error) {
+	data, err := this.db.Get(GetIdentityKey(ontid), nil)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	identity := &account.Identity{}
+	err = json.Unmarshal(data, identity)
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+//GetIdentities return all ONT IDs in order of id
+func (this *WalletStore) GetIdentities() ([]*account.Identity, error) {
+	identities := make([]*account.Identity, 0)
+	iter := this.db.NewIterator(util.BytesPrefix([]byte{WALLET_IDENTITY_PREFIX}), nil)
+	defer iter.Release()
+	for iter.Next() {
+		identity := &account.Identity{}
+		err := json.Unmarshal(iter.Value(), identity)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+	return identities, iter.Error()
+}
+
+//CheckIdentityPassword check passwd by decrypting the keys of ONT ID controllers
+func (this *WalletStore) CheckIdentityPassword(identity *account.Identity, passwd []byte) error {
+	for i := range identity.Control {
+		_, err := keypair.DecryptWithCustomScrypt(&identity.Control[i].ProtectedKey, passwd, this.WalletScrypt)
+		if err != nil {
+			return fmt.Errorf("decrypt key of controller:%s error:%s", identity.Control[i].ID, err)
+		}
+	}
+	return nil
+}
+
+func (this *WalletStore) DeleteIdentity(ontid string) error {
+	return this.db.Delete(GetIdentityKey(ontid), nil)
+}
+
+//ExportWalletData return wallet data of all accounts and ONT IDs in wallet store
+func (this *WalletStore) ExportWalletData() (*account.WalletData, error) {
+	accDatas, err := this.GetAccountDatas()
+	if err != nil {
+		return nil, fmt.Errorf("GetAccountDatas error:%s", err)
+	}
+	identities, err := this.GetIdentities()
+	if err != nil {
+		return nil, fmt.Errorf("GetIdentities error:%s", err)
+	}
+	scrypt := *this.WalletScrypt
+	walletData := &account.WalletData{
+		Name:     this.WalletName,
+		Version:  this.WalletVersion,
+		Scrypt:   &scrypt,
+		Accounts: accDatas,
+		Extra:    this.WalletExtra,
+	}
+	for _, identity := range identities {
+		walletData.Identities = append(walletData.Identities, *identity)
+	}
+	return walletData, nil
+}
+
+//ImportWalletData add or update accounts and ONT IDs of wallet data, scrypt param of wallet data should be the same as wallet store.
+//Return number of added accounts, updated accounts and added ONT IDs
+func (this *WalletStore) ImportWalletData(walletData *account.WalletData) (int, int, int, error) {
+	if walletData.Scrypt == nil || *walletData.Scrypt != *this.WalletScrypt {
+		return 0, 0, 0, fmt.Errorf("wallet scrypt:%+v != %+v", walletData.Scrypt, this.WalletScrypt)
+	}
+	addNum := 0
+	updateNum := 0
+	for _, accData := range walletData.Accounts {
+		ok, err := this.AddAccountData(accData)
+		if err != nil {
+			return addNum, updateNum, 0, fmt.Errorf("import account address:%s error:%s", accData.Address, err)
+		}
+		if ok {
+			addNum++
+		} else {
+			updateNum++
+		}
+	}
+	addIdNum := 0
+	for i := range walletData.Identities {
+		ok, err := this.AddIdentity(&walletData.Identities[i])
+		if err != nil {
+			return addNum, updateNum, addIdNum, fmt.Errorf("import identity:%s error:%s", walletData.Identities[i].ID, err)
+		}
+		if ok {
+			addIdNum++
+		}
+	}
+	return addNum, updateNum, addIdNum, nil
+}
+
+func (this *WalletStore) Close() error {
+	return this.db.Close()
+}
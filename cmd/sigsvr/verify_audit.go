@@ -0,0 +1,84 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sigsvr
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/cmd"
+	"github.com/ontio/ontology/cmd/sigsvr/policy"
+	"github.com/ontio/ontology/cmd/sigsvr/store"
+	"github.com/ontio/ontology/cmd/utils"
+	"github.com/urfave/cli"
+)
+
+var VerifyAuditCommand = cli.Command{
+	Name:      "verifyaudit",
+	Usage:     "Verify hash chain and signatures of audit log",
+	ArgsUsage: "",
+	Action:    verifyAudit,
+	Flags: []cli.Flag{
+		utils.CliWalletDirFlag,
+		utils.CliAuditLogFlag,
+		utils.CliAuditAccountFlag,
+	},
+	Description: "",
+}
+
+func verifyAudit(ctx *cli.Context) error {
+	walletDirPath := ctx.String(utils.GetFlagName(utils.CliWalletDirFlag))
+	auditFile := ctx.String(utils.GetFlagName(utils.CliAuditLogFlag))
+	auditAddress := ctx.String(utils.GetFlagName(utils.CliAuditAccountFlag))
+	if walletDirPath == "" || auditFile == "" || auditAddress == "" {
+		cmd.PrintErrorMsg("Missing %s, %s or %s flag.", utils.CliWalletDirFlag.Name, utils.CliAuditLogFlag.Name, utils.CliAuditAccountFlag.Name)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	walletStore, err := store.NewWalletStore(walletDirPath)
+	if err != nil {
+		return fmt.Errorf("NewWalletStore dir path:%s error:%s", walletDirPath, err)
+	}
+	accData, err := walletStore.GetAccountDataByAddress(auditAddress)
+	if err != nil {
+		return fmt.Errorf("get account:%s error:%s", auditAddress, err)
+	}
+	if accData == nil {
+		return fmt.Errorf("cannot find account by %s", auditAddress)
+	}
+	pkData, err := hex.DecodeString(accData.PubKey)
+	if err != nil {
+		return fmt.Errorf("invalid public key of account:%s", auditAddress)
+	}
+	pubKey, err := keypair.DeserializePublicKey(pkData)
+	if err != nil {
+		return fmt.Errorf("invalid public key of account:%s", auditAddress)
+	}
+	entries, err := policy.ReadAuditLog(auditFile)
+	if err != nil {
+		return fmt.Errorf("read audit log:%s error:%s", auditFile, err)
+	}
+	err = policy.VerifyAuditEntries(entries, pubKey)
+	if err != nil {
+		return err
+	}
+	cmd.PrintInfoMsg("Verify audit log success.")
+	cmd.PrintInfoMsg("Total entry number:%d", len(entries))
+	return nil
+}
@@ -20,9 +20,11 @@ package cmd
 import (
 	"encoding/hex"
 	"fmt"
+	"github.com/ontio/ontology/cmd/abi"
 	cmdcom "github.com/ontio/ontology/cmd/common"
 	"github.com/ontio/ontology/cmd/utils"
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
 	nutils "github.com/ontio/ontology/smartcontract/service/native/utils"
 	"github.com/urfave/cli"
 	"strconv"
@@ -87,6 +89,26 @@ var TxCommond = cli.Command{
 	Description: "Build transaction",
 }
 
+var TxToolCommand = cli.Command{
+	Name:  "tx",
+	Usage: "Offline transaction tools",
+	Subcommands: []cli.Command{
+		DecodeTxCommand,
+	},
+	Description: "Offline transaction tools",
+}
+
+var DecodeTxCommand = cli.Command{
+	Name:        "decode",
+	Usage:       "Decode raw transaction offline",
+	Description: "Decode raw transaction offline, show payer, fee, signatures with signer address and payload. Native invoke params are decoded by native abi, NeoVM invoke code is disassembled.",
+	ArgsUsage:   "<rawtx>",
+	Action:      decodeTx,
+	Flags: []cli.Flag{
+		utils.CliABIPathFlag,
+	},
+}
+
 var TransferTxCommond = cli.Command{
 	Name:        "transfer",
 	Usage:       "Build transfer transaction",
@@ -493,3 +515,22 @@ func withdrawONGTx(ctx *cli.Context) error {
 	PrintInfoMsg(hex.EncodeToString(sink.Bytes()))
 	return nil
 }
+
+func decodeTx(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		PrintErrorMsg("Missing raw tx argument.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	txData, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("RawTx hex decode error:%s", err)
+	}
+	tx, err := types.TransactionFromRawBytes(txData)
+	if err != nil {
+		return fmt.Errorf("TransactionFromRawBytes error:%s", err)
+	}
+	abi.DefAbiMgr.Init(ctx.String(utils.GetFlagName(utils.CliABIPathFlag)))
+	PrintJsonObject(utils.DecodeTransaction(tx))
+	return nil
+}
@@ -100,6 +100,8 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.ConfigFlag,
 			utils.LogLevelFlag,
 			utils.DisableEventLogFlag,
+			utils.EnableAddressIndexFlag,
+			utils.EnableContractAbiFlag,
 			utils.DataDirFlag,
 		},
 	},
@@ -109,6 +111,12 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.WalletFileFlag,
 			utils.AccountAddressFlag,
 			utils.AccountPassFlag,
+			utils.SignerFlag,
+			utils.Pkcs11ModuleFlag,
+			utils.Pkcs11SlotFlag,
+			utils.Pkcs11LabelFlag,
+			utils.RemoteSignerUrlFlag,
+			utils.RemoteSignerTokenFileFlag,
 			utils.AccountDefaultFlag,
 			utils.AccountKeylenFlag,
 			utils.AccountSetDefaultFlag,
@@ -124,6 +132,9 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.AccountMultiMFlag,
 			utils.AccountMultiPubKeyFlag,
 			utils.IdentityFlag,
+			utils.AccountHDFlag,
+			utils.AccountBIP39PassphraseFlag,
+			utils.CliWalletDirFlag,
 		},
 	},
 	{
@@ -155,6 +166,8 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.MaxConnInBoundFlag,
 			utils.MaxConnOutBoundFlag,
 			utils.MaxConnInBoundForSingleIPFlag,
+			utils.P2PEncryptFlag,
+			utils.NodeKeyFileFlag,
 		},
 	},
 	{
@@ -164,6 +177,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.RPCPortFlag,
 			utils.RPCLocalEnableFlag,
 			utils.RPCLocalProtFlag,
+			utils.RPCLegacyResponseFlag,
 		},
 	},
 	{
@@ -181,11 +195,37 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.WsPortFlag,
 		},
 	},
+	{
+		Name: "GRAPHQL",
+		Flags: []cli.Flag{
+			utils.GraphQLEnableFlag,
+			utils.GraphQLPortFlag,
+			utils.GraphQLMaxDepthFlag,
+			utils.GraphQLMaxCostFlag,
+		},
+	},
+	{
+		Name: "API ACCESS",
+		Flags: []cli.Flag{
+			utils.ApiAuthConfigFlag,
+			utils.ApiRateLimitFlag,
+			utils.ApiRateBurstFlag,
+			utils.ApiMaxRequestBytesFlag,
+		},
+	},
+	{
+		Name: "METRICS",
+		Flags: []cli.Flag{
+			utils.MetricsEnableFlag,
+			utils.MetricsPortFlag,
+		},
+	},
 	{
 		Name: "TEST MODE",
 		Flags: []cli.Flag{
 			utils.EnableTestModeFlag,
 			utils.TestModeGenBlockTimeFlag,
+			utils.TestModeGenBlockOnDemandFlag,
 		},
 	},
 	{
@@ -27,10 +27,11 @@ import (
 )
 
 const (
-	DEFAULT_EXPORT_FILE   = "./OntBlocks.dat"
-	DEFAULT_ABI_PATH      = "./abi"
-	DEFAULT_EXPORT_HEIGHT = 0
-	DEFAULT_WALLET_PATH   = "./wallet_data"
+	DEFAULT_EXPORT_FILE     = "./OntBlocks.dat"
+	DEFAULT_ABI_PATH        = "./abi"
+	DEFAULT_EXPORT_HEIGHT   = 0
+	DEFAULT_WALLET_PATH     = "./wallet_data"
+	REMOTE_SIGNER_TOKEN_ENV = "ONTOLOGY_REMOTE_SIGNER_TOKEN" //Env of remote signer token, so that it is not in process args
 )
 
 var (
@@ -48,6 +49,14 @@ var (
 		Name:  "disable-event-log",
 		Usage: "Discard event log output by smart contract execution",
 	}
+	EnableAddressIndexFlag = cli.BoolFlag{
+		Name:  "enable-address-index",
+		Usage: "Index transactions by the addresses they touch",
+	}
+	EnableContractAbiFlag = cli.BoolFlag{
+		Name:  "enable-contract-abi",
+		Usage: "Store NeoVM contract ABIs from deploy description or registry, to decode contract events",
+	}
 	WalletFileFlag = cli.StringFlag{
 		Name:  "wallet,w",
 		Value: config.DEFAULT_WALLET_FILE_NAME,
@@ -100,6 +109,10 @@ var (
 		Usage: "Block-out `<time>`(s) in test mode.",
 		Value: config.DEFAULT_GEN_BLOCK_TIME,
 	}
+	TestModeGenBlockOnDemandFlag = cli.BoolFlag{
+		Name:  "testmode-gen-block-ondemand",
+		Usage: "Block-out as soon as a transaction enters the tx pool in test mode, instead of by block-out time.",
+	}
 
 	//P2P setting
 	ReservedPeersOnlyFlag = cli.BoolFlag{
@@ -145,6 +158,15 @@ var (
 		Usage: "Max connection `<number>` in bound for single ip",
 		Value: config.DEFAULT_MAX_CONN_IN_BOUND_FOR_SINGLE_IP,
 	}
+	P2PEncryptFlag = cli.BoolFlag{
+		Name:  "p2p-encrypt",
+		Usage: "Encrypt and authenticate P2P links by node key",
+	}
+	NodeKeyFileFlag = cli.StringFlag{
+		Name:  "nodekey",
+		Usage: "Node key file `<path>` of encrypted P2P links, a new key is generated if not exist",
+		Value: config.DEFAULT_NODE_KEY_FILE,
+	}
 	// RPC settings
 	RPCDisabledFlag = cli.BoolFlag{
 		Name:  "disable-rpc",
@@ -164,6 +186,10 @@ var (
 		Usage: "Json rpc local server listening port `<number>`",
 		Value: config.DEFAULT_RPC_LOCAL_PORT,
 	}
+	RPCLegacyResponseFlag = cli.BoolFlag{
+		Name:  "rpc-legacy-response",
+		Usage: "Respond rpc requests in the legacy format with error code and desc instead of JSON-RPC 2.0",
+	}
 
 	//Websocket setting
 	WsEnabledFlag = cli.BoolFlag{
@@ -176,6 +202,57 @@ var (
 		Value: config.DEFAULT_WS_PORT,
 	}
 
+	//GraphQL setting
+	GraphQLEnableFlag = cli.BoolFlag{
+		Name:  "graphql",
+		Usage: "Enable graphql query server",
+	}
+	GraphQLPortFlag = cli.UintFlag{
+		Name:  "graphqlport",
+		Usage: "Graphql server listening port `<number>`",
+		Value: config.DEFAULT_GRAPHQL_PORT,
+	}
+	GraphQLMaxDepthFlag = cli.UintFlag{
+		Name:  "graphql-max-depth",
+		Usage: "Max depth `<number>` of nested fields in a graphql query",
+		Value: config.DEFAULT_GRAPHQL_MAX_DEPTH,
+	}
+	GraphQLMaxCostFlag = cli.UintFlag{
+		Name:  "graphql-max-cost",
+		Usage: "Max ledger lookups `<number>` of a graphql query",
+		Value: config.DEFAULT_GRAPHQL_MAX_COST,
+	}
+
+	//Api access setting
+	ApiAuthConfigFlag = cli.StringFlag{
+		Name:  "api-auth-config",
+		Usage: "Enable authentication of rpc, restful and websocket servers with the api keys and jwt secret in json `<file>`",
+	}
+	ApiRateLimitFlag = cli.UintFlag{
+		Name:  "api-rate-limit",
+		Usage: "Max requests `<number>` per second of a client to rpc, restful and websocket servers. 0 means no limit",
+	}
+	ApiRateBurstFlag = cli.UintFlag{
+		Name:  "api-rate-burst",
+		Usage: "Max burst requests `<number>` of a client. 0 means same as api-rate-limit",
+	}
+	ApiMaxRequestBytesFlag = cli.UintFlag{
+		Name:  "api-max-request-bytes",
+		Usage: "Max body size `<number>` of a request to rpc, restful and websocket servers. 0 means no limit",
+		Value: config.DEFAULT_API_MAX_REQUEST_BYTES,
+	}
+
+	//Metrics setting
+	MetricsEnableFlag = cli.BoolFlag{
+		Name:  "metrics",
+		Usage: "Enable prometheus metrics server",
+	}
+	MetricsPortFlag = cli.UintFlag{
+		Name:  "metricsport",
+		Usage: "Metrics server listening port `<number>`",
+		Value: config.DEFAULT_METRICS_PORT,
+	}
+
 	//Restful setting
 	RestfulEnableFlag = cli.BoolFlag{
 		Name:  "rest",
@@ -198,6 +275,31 @@ var (
 		Hidden: true,
 		Usage:  "Account `<password>` when Ontology node starts.",
 	}
+	SignerFlag = cli.StringFlag{
+		Name:  "signer",
+		Value: "wallet",
+		Usage: "Signer `<type>` of consensus account: wallet, pkcs11 or remote. Key of pkcs11 or remote signer never touches node's disk",
+	}
+	Pkcs11ModuleFlag = cli.StringFlag{
+		Name:  "pkcs11-module",
+		Usage: "PKCS#11 module `<file>` of HSM, eg. /usr/lib/softhsm/libsofthsm2.so. The PIN is input as password",
+	}
+	Pkcs11SlotFlag = cli.UintFlag{
+		Name:  "pkcs11-slot",
+		Usage: "PKCS#11 `<slot>` id of the token keeping consensus key",
+	}
+	Pkcs11LabelFlag = cli.StringFlag{
+		Name:  "pkcs11-label",
+		Usage: "`<label>` of ECDSA P-256 key pair in PKCS#11 token",
+	}
+	RemoteSignerUrlFlag = cli.StringFlag{
+		Name:  "remote-signer-url",
+		Usage: "`<url>` of remote signer, eg. https://10.0.0.2:20000/signer",
+	}
+	RemoteSignerTokenFileFlag = cli.StringFlag{
+		Name:  "remote-signer-token-file",
+		Usage: "`<file>` of bearer token of remote signer. If not set, the token is read from env " + REMOTE_SIGNER_TOKEN_ENV,
+	}
 	AccountAddressFlag = cli.StringFlag{
 		Name:  "account,a",
 		Usage: "Account `<address>` when the Ontology node starts. If not specific, using default account instead",
@@ -268,6 +370,14 @@ var (
 		Name:  "ontid",
 		Usage: "create an ONT ID instead of account",
 	}
+	AccountHDFlag = cli.BoolFlag{
+		Name:  "hd",
+		Usage: "Derive account from the mnemonic seed of wallet, a new mnemonic is generated if wallet has no seed",
+	}
+	AccountBIP39PassphraseFlag = cli.BoolFlag{
+		Name:  "passphrase",
+		Usage: "Input an optional BIP39 passphrase protecting the mnemonic",
+	}
 
 	//SmartContract setting
 	ContractAddrFlag = cli.StringFlag{
@@ -321,6 +431,18 @@ var (
 		Name:  "return",
 		Usage: "Return `<type>` of contract. bytearray(hexstring), string, integer, boolean",
 	}
+	ContractAbiFlag = cli.StringFlag{
+		Name:  "abi",
+		Usage: "ABI json `<file>` of contract. Invoke method with named arguments, and decode return value and notify by ABI",
+	}
+	ContractMethodFlag = cli.StringFlag{
+		Name:  "method",
+		Usage: "`<name>` of method defined in ABI to invoke",
+	}
+	ContractArgsFlag = cli.StringFlag{
+		Name:  "args",
+		Usage: "Named arguments `<json>` of method, such as {\"to\":\"AXK2KtCfcJnSMyRzSwTuwTKgNrtx5aXfFX\",\"amount\":100}",
+	}
 
 	//information cmd settings
 	BlockHashInfoFlag = cli.StringFlag{
@@ -440,6 +562,30 @@ var (
 		Usage: "Abi `<file>` path",
 		Value: DEFAULT_ABI_PATH,
 	}
+	CliRemoteSignerFlag = cli.StringFlag{
+		Name:  "remote-signer-account",
+		Usage: "Serve remote signer protocol at /signer/ with account `<address>`, the password is input when starting",
+	}
+	CliTlsCertFlag = cli.StringFlag{
+		Name:  "tls-cert",
+		Usage: "TLS certificate `<file>` of rpc, required by remote signer on non-loopback address",
+	}
+	CliTlsKeyFlag = cli.StringFlag{
+		Name:  "tls-key",
+		Usage: "TLS private key `<file>` of rpc",
+	}
+	CliPolicyFlag = cli.StringFlag{
+		Name:  "policy",
+		Usage: "Signing policy json `<file>` of accounts. If not set, any request of unlocked account is signed",
+	}
+	CliAuditLogFlag = cli.StringFlag{
+		Name:  "audit-log",
+		Usage: "Append-only audit log `<file>` of every request and decision",
+	}
+	CliAuditAccountFlag = cli.StringFlag{
+		Name:  "audit-account",
+		Usage: "`<address>` of account signing audit log entries, the password is input when starting",
+	}
 	CliWalletDirFlag = cli.StringFlag{
 		Name:  "walletdir",
 		Usage: "Wallet data `<path>`",
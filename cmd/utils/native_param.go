@@ -25,6 +25,7 @@ import (
 	"github.com/ontio/ontology/cmd/abi"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/core/types"
+	cutils "github.com/ontio/ontology/core/utils"
 	httpcom "github.com/ontio/ontology/http/base/common"
 	svrneovm "github.com/ontio/ontology/smartcontract/service/neovm"
 	"github.com/ontio/ontology/vm/neovm"
@@ -252,3 +253,103 @@ func ParseNativeParamAddress(builder *neovm.ParamsBuilder, param string) error {
 	builder.EmitPushByteArray(addr[:])
 	return nil
 }
+
+//DecodeNativeFuncParams decode params of native invoke code by function abi, the reverse of ParseNativeFuncParam.
+//Result is keyed by param name
+func DecodeNativeFuncParams(params []interface{}, funcAbi *abi.NativeContractFunctionAbi) (map[string]interface{}, error) {
+	paramsAbi := funcAbi.Parameters
+	if len(paramsAbi) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	if len(params) != 1 {
+		return nil, fmt.Errorf("abi unmatch")
+	}
+	if len(paramsAbi) == 1 {
+		value, err := DecodeNativeParam(params[0], paramsAbi[0])
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{paramsAbi[0].Name: value}, nil
+	}
+	//If more than one param in func, params are in struct
+	value, err := DecodeNativeParam(params[0], &abi.NativeContractParamAbi{
+		Name:    "root",
+		Type:    abi.NATIVE_PARAM_TYPE_STRUCT,
+		SubType: paramsAbi,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string]interface{}), nil
+}
+
+//DecodeNativeParam decode param of native invoke code by param abi. Struct is decoded to map keyed by field name,
+//address to base58, int and byte to *big.Int, bytearray and uint256 to hex string
+func DecodeNativeParam(param interface{}, paramAbi *abi.NativeContractParamAbi) (interface{}, error) {
+	switch strings.ToLower(paramAbi.Type) {
+	case abi.NATIVE_PARAM_TYPE_STRUCT:
+		items, ok := param.([]interface{})
+		if !ok || len(items) != len(paramAbi.SubType) {
+			return nil, fmt.Errorf("param:%s struct abi not match", paramAbi.Name)
+		}
+		fields := make(map[string]interface{}, len(items))
+		for i, item := range items {
+			value, err := DecodeNativeParam(item, paramAbi.SubType[i])
+			if err != nil {
+				return nil, err
+			}
+			fields[paramAbi.SubType[i].Name] = value
+		}
+		return fields, nil
+	case abi.NATIVE_PARAM_TYPE_ARRAY:
+		items, ok := param.([]interface{})
+		if !ok || len(paramAbi.SubType) == 0 {
+			return nil, fmt.Errorf("param:%s array abi not match", paramAbi.Name)
+		}
+		values := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			value, err := DecodeNativeParam(item, paramAbi.SubType[0])
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case abi.NATIVE_PARAM_TYPE_INTEGER, abi.NATIVE_PARAM_TYPE_BYTE:
+		value, err := cutils.ParamToBigInt(param)
+		if err != nil {
+			return nil, fmt.Errorf("param:%s error:%s", paramAbi.Name, err)
+		}
+		return value, nil
+	case abi.NATIVE_PARAM_TYPE_BOOL:
+		value, err := cutils.ParamToBigInt(param)
+		if err != nil {
+			return nil, fmt.Errorf("param:%s error:%s", paramAbi.Name, err)
+		}
+		return value.Sign() != 0, nil
+	}
+	data, ok := param.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("param:%s is not %s", paramAbi.Name, paramAbi.Type)
+	}
+	switch strings.ToLower(paramAbi.Type) {
+	case abi.NATIVE_PARAM_TYPE_ADDRESS:
+		addr, err := common.AddressParseFromBytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("param:%s error:%s", paramAbi.Name, err)
+		}
+		return addr.ToBase58(), nil
+	case abi.NATIVE_PARAM_TYPE_STRING:
+		return string(data), nil
+	case abi.NATIVE_PARAM_TYPE_BYTEARRAY:
+		return hex.EncodeToString(data), nil
+	case abi.NATIVE_PARAM_TYPE_UINT256:
+		hash, err := common.Uint256ParseFromBytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("param:%s error:%s", paramAbi.Name, err)
+		}
+		return hash.ToHexString(), nil
+	default:
+		return nil, fmt.Errorf("unknown param type:%s", paramAbi.Type)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/ontio/ontology/cmd/abi"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/vm/neovm"
+	"math/big"
 	"testing"
 )
 
@@ -103,3 +104,51 @@ func TestParseNativeParam(t *testing.T) {
 		return
 	}
 }
+
+func TestDecodeNativeFuncParams(t *testing.T) {
+	funcAbi := &abi.NativeContractFunctionAbi{
+		Name: "transferFrom",
+		Parameters: []*abi.NativeContractParamAbi{
+			{
+				Name: "sender",
+				Type: "Address",
+			},
+			{
+				Name: "from",
+				Type: "Address",
+			},
+			{
+				Name: "to",
+				Type: "Address",
+			},
+			{
+				Name: "value",
+				Type: "Int",
+			},
+		},
+	}
+	from := common.Address{1}
+	to := common.Address{2}
+	params := []interface{}{
+		[]interface{}{from[:], from[:], to[:], big.NewInt(100)},
+	}
+	values, err := DecodeNativeFuncParams(params, funcAbi)
+	if err != nil {
+		t.Errorf("DecodeNativeFuncParams error:%s", err)
+		return
+	}
+	if values["from"] != from.ToBase58() || values["to"] != to.ToBase58() {
+		t.Errorf("DecodeNativeFuncParams address unmatch:%v", values)
+		return
+	}
+	if values["value"].(*big.Int).Int64() != 100 {
+		t.Errorf("DecodeNativeFuncParams value:%v != 100", values["value"])
+		return
+	}
+
+	_, err = DecodeNativeFuncParams([]interface{}{[]interface{}{from[:]}}, funcAbi)
+	if err == nil {
+		t.Errorf("DecodeNativeFuncParams should fail on unmatch struct")
+		return
+	}
+}
@@ -21,7 +21,8 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"github.com/ontio/ontology/cmd/abi"
+	"github.com/ontio/ontology/core/txbuilder"
+	"github.com/ontio/ontology/smartcontract/abi"
 	"strconv"
 	"strings"
 )
@@ -36,27 +37,14 @@ func NewNeovmContractAbi(abiData []byte) (*abi.NeovmContractAbi, error) {
 }
 
 func ParseNeovmFunc(rawParams []string, funcAbi *abi.NeovmContractFunctionAbi) ([]interface{}, error) {
-	res := make([]interface{}, 0)
-	funcName := convertNeovmFuncName(funcAbi.Name)
-	res = append(res, funcName)
 	params, err := ParseNeovmParam(rawParams, funcAbi.Parameters)
 	if err != nil {
 		return nil, err
 	}
-	res = append(res, params)
-	return res, nil
-}
-
-//Neovm func name in Camel-Case. For example: transfer, transferFrom
-func convertNeovmFuncName(name string) string {
-	if name == "" {
-		return name
-	}
-	data := []byte(name)
-	data[0] = strings.ToLower(string(data[0]))[0]
-	return string(data)
+	return txbuilder.NeoVMAbiParams(funcAbi, params)
 }
 
+//ParseNeovmParam parse cli arguments to NeoVM params by ABI, arguments are converted by txbuilder
 func ParseNeovmParam(params []string, paramsAbi []*abi.NeovmContractParamsAbi) ([]interface{}, error) {
 	if len(params) != len(paramsAbi) {
 		return nil, fmt.Errorf("abi param not match")
@@ -68,14 +56,10 @@ func ParseNeovmParam(params []string, paramsAbi []*abi.NeovmContractParamsAbi) (
 		var res interface{}
 		var err error
 		switch strings.ToLower(paramAbi.Type) {
-		case abi.NEOVM_PARAM_TYPE_INTEGER:
-			res, err = ParseNeovmParamInteger(rawParam)
 		case abi.NEOVM_PARAM_TYPE_BOOL:
 			res, err = ParseNeovmParamBoolean(rawParam)
-		case abi.NEOVM_PARAM_TYPE_STRING:
-			res, err = ParseNeovmParamString(rawParam)
-		case abi.NEOVM_PARAM_TYPE_BYTE_ARRAY:
-			res, err = ParseNeovmParamByteArray(rawParam)
+		case abi.NEOVM_PARAM_TYPE_INTEGER, abi.NEOVM_PARAM_TYPE_STRING, abi.NEOVM_PARAM_TYPE_BYTE_ARRAY:
+			res, err = txbuilder.NeoVMAbiParam(paramAbi.Type, rawParam)
 		default:
 			return nil, fmt.Errorf("unknown param type:%s", paramAbi.Type)
 		}
@@ -122,3 +106,30 @@ func ParseNeovmParamByteArray(param string) (interface{}, error) {
 	}
 	return res, nil
 }
+
+//ParseNeovmNamedArgs parse json object of named arguments to arguments in order of function ABI parameters.
+//Numbers are decoded as json.Number, so that big integers don't lose precision.
+func ParseNeovmNamedArgs(rawArgs string, funcAbi *abi.NeovmContractFunctionAbi) ([]interface{}, error) {
+	namedArgs := make(map[string]interface{})
+	if strings.TrimSpace(rawArgs) != "" {
+		decoder := json.NewDecoder(strings.NewReader(rawArgs))
+		decoder.UseNumber()
+		err := decoder.Decode(&namedArgs)
+		if err != nil {
+			return nil, fmt.Errorf("json decode args error:%s", err)
+		}
+	}
+	args := make([]interface{}, 0, len(funcAbi.Parameters))
+	for _, paramAbi := range funcAbi.Parameters {
+		arg, ok := namedArgs[paramAbi.Name]
+		if !ok {
+			return nil, fmt.Errorf("method:%s missing argument:%s", funcAbi.Name, paramAbi.Name)
+		}
+		args = append(args, arg)
+		delete(namedArgs, paramAbi.Name)
+	}
+	for name := range namedArgs {
+		return nil, fmt.Errorf("method:%s unknown argument:%s", funcAbi.Name, name)
+	}
+	return args, nil
+}
@@ -18,7 +18,9 @@
 package utils
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/ontio/ontology/smartcontract/abi"
 	"testing"
 )
 
@@ -76,3 +78,33 @@ func TestParseNeovmFunc(t *testing.T) {
 	}
 	fmt.Printf("TestParseNeovmFunc %v\n", params)
 }
+
+func TestParseNeovmNamedArgs(t *testing.T) {
+	funcAbi := &abi.NeovmContractFunctionAbi{
+		Name: "Add",
+		Parameters: []*abi.NeovmContractParamsAbi{
+			{Name: "a", Type: "Integer"},
+			{Name: "b", Type: "Integer"},
+		},
+		ReturnType: "Integer",
+	}
+	args, err := ParseNeovmNamedArgs(`{"b":34,"a":"12"}`, funcAbi)
+	if err != nil {
+		t.Errorf("TestParseNeovmNamedArgs ParseNeovmNamedArgs error:%s", err)
+		return
+	}
+	if len(args) != 2 || args[0] != "12" || args[1] != json.Number("34") {
+		t.Errorf("TestParseNeovmNamedArgs args:%v not in order of abi", args)
+		return
+	}
+	_, err = ParseNeovmNamedArgs(`{"a":12}`, funcAbi)
+	if err == nil {
+		t.Errorf("TestParseNeovmNamedArgs should failed on missing argument")
+		return
+	}
+	_, err = ParseNeovmNamedArgs(`{"a":12,"b":34,"c":56}`, funcAbi)
+	if err == nil {
+		t.Errorf("TestParseNeovmNamedArgs should failed on unknown argument")
+		return
+	}
+}
@@ -25,13 +25,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/ontio/ontology-crypto/keypair"
-	sig "github.com/ontio/ontology-crypto/signature"
 	"github.com/ontio/ontology/account"
 	"github.com/ontio/ontology/common"
-	"github.com/ontio/ontology/common/constants"
 	"github.com/ontio/ontology/common/serialization"
 	"github.com/ontio/ontology/core/payload"
-	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/txbuilder"
 	"github.com/ontio/ontology/core/types"
 	cutils "github.com/ontio/ontology/core/utils"
 	httpcom "github.com/ontio/ontology/http/base/common"
@@ -42,7 +40,6 @@ import (
 	cstates "github.com/ontio/ontology/smartcontract/states"
 	"github.com/ontio/ontology/vm/wasmvm/exec"
 	"math/rand"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -301,138 +298,19 @@ func NewInvokeTransaction(gasPrice, gasLimit uint64, invokeCode []byte) *types.M
 	return tx
 }
 
-func SignTransaction(signer *account.Account, tx *types.MutableTransaction) error {
-	if tx.Payer == common.ADDRESS_EMPTY {
-		tx.Payer = signer.Address
-	}
-	txHash := tx.Hash()
-	sigData, err := Sign(txHash.ToArray(), signer)
-	if err != nil {
-		return fmt.Errorf("sign error:%s", err)
-	}
-	hasSig := false
-	for i, sig := range tx.Sigs {
-		if len(sig.PubKeys) == 1 && pubKeysEqual(sig.PubKeys, []keypair.PublicKey{signer.PublicKey}) {
-			if hasAlreadySig(txHash.ToArray(), signer.PublicKey, sig.SigData) {
-				//has already signed
-				return nil
-			}
-			hasSig = true
-			//replace
-			tx.Sigs[i].SigData = [][]byte{sigData}
-		}
-	}
-	if !hasSig {
-		tx.Sigs = append(tx.Sigs, types.Sig{
-			PubKeys: []keypair.PublicKey{signer.PublicKey},
-			M:       1,
-			SigData: [][]byte{sigData},
-		})
-	}
-	return nil
+func SignTransaction(signer account.Signer, tx *types.MutableTransaction) error {
+	return txbuilder.SignTransaction(tx, signer)
 }
 
-func MultiSigTransaction(mutTx *types.MutableTransaction, m uint16, pubKeys []keypair.PublicKey, signer *account.Account) error {
-	pkSize := len(pubKeys)
-	if m == 0 || int(m) > pkSize || pkSize > constants.MULTI_SIG_MAX_PUBKEY_SIZE {
-		return fmt.Errorf("invalid params")
-	}
-	validPubKey := false
-	for _, pk := range pubKeys {
-		if keypair.ComparePublicKey(pk, signer.PublicKey) {
-			validPubKey = true
-			break
-		}
-	}
-	if !validPubKey {
-		return fmt.Errorf("invalid signer")
-	}
-	if mutTx.Payer == common.ADDRESS_EMPTY {
-		payer, err := types.AddressFromMultiPubKeys(pubKeys, int(m))
-		if err != nil {
-			return fmt.Errorf("AddressFromMultiPubKeys error:%s", err)
-		}
-		mutTx.Payer = payer
-	}
-
-	if len(mutTx.Sigs) == 0 {
-		mutTx.Sigs = make([]types.Sig, 0)
-	}
-
-	txHash := mutTx.Hash()
-	sigData, err := Sign(txHash.ToArray(), signer)
-	if err != nil {
-		return fmt.Errorf("sign error:%s", err)
-	}
-
-	hasMutilSig := false
-	for i, sigs := range mutTx.Sigs {
-		if !pubKeysEqual(sigs.PubKeys, pubKeys) {
-			continue
-		}
-		hasMutilSig = true
-		if hasAlreadySig(txHash.ToArray(), signer.PublicKey, sigs.SigData) {
-			break
-		}
-		sigs.SigData = append(sigs.SigData, sigData)
-		mutTx.Sigs[i] = sigs
-		break
-	}
-	if !hasMutilSig {
-		mutTx.Sigs = append(mutTx.Sigs, types.Sig{
-			PubKeys: pubKeys,
-			M:       uint16(m),
-			SigData: [][]byte{sigData},
-		})
-	}
-	return nil
+func MultiSigTransaction(mutTx *types.MutableTransaction, m uint16, pubKeys []keypair.PublicKey, signer account.Signer) error {
+	return txbuilder.MultiSignTransaction(mutTx, m, pubKeys, signer)
 }
 
-func hasAlreadySig(data []byte, pk keypair.PublicKey, sigDatas [][]byte) bool {
-	for _, sigData := range sigDatas {
-		err := signature.Verify(pk, data, sigData)
-		if err == nil {
-			return true
-		}
-	}
-	return false
-}
-
-func pubKeysEqual(pks1, pks2 []keypair.PublicKey) bool {
-	if len(pks1) != len(pks2) {
-		return false
-	}
-	size := len(pks1)
-	if size == 0 {
-		return true
-	}
-	pkstr1 := make([]string, 0, size)
-	for _, pk := range pks1 {
-		pkstr1 = append(pkstr1, hex.EncodeToString(keypair.SerializePublicKey(pk)))
-	}
-	pkstr2 := make([]string, 0, size)
-	for _, pk := range pks2 {
-		pkstr2 = append(pkstr2, hex.EncodeToString(keypair.SerializePublicKey(pk)))
-	}
-	sort.Strings(pkstr1)
-	sort.Strings(pkstr2)
-	for i := 0; i < size; i++ {
-		if pkstr1[i] != pkstr2[i] {
-			return false
-		}
-	}
-	return true
-}
-
-//Sign sign return the signature to the data of private key
-func Sign(data []byte, signer *account.Account) ([]byte, error) {
-	s, err := sig.Sign(signer.SigScheme, signer.PrivateKey, data, nil)
+//Sign sign return the signature to the data by signer
+func Sign(data []byte, signer account.Signer) ([]byte, error) {
+	sigData, err := signer.Sign(data)
 	if err != nil {
-		return nil, err
-	}
-	sigData, err := sig.Serialize(s)
-	if err != nil {
-		return nil, fmt.Errorf("sig.Serialize error:%s", err)
+		return nil, fmt.Errorf("signer.Sign error:%s", err)
 	}
 	return sigData, nil
 }
@@ -461,6 +339,44 @@ func SendRawTransactionData(txData string) (string, error) {
 	return hexHash, nil
 }
 
+//EstimateGas return the gas limit of transaction estimated by node
+func EstimateGas(tx *types.Transaction) (uint64, error) {
+	var buffer bytes.Buffer
+	err := tx.Serialize(&buffer)
+	if err != nil {
+		return 0, fmt.Errorf("serialize error:%s", err)
+	}
+	data, ontErr := sendRpcRequest("estimategas", []interface{}{hex.EncodeToString(buffer.Bytes())})
+	if ontErr != nil {
+		return 0, ontErr.Error
+	}
+	var gasLimit uint64
+	err = json.Unmarshal(data, &gasLimit)
+	if err != nil {
+		return 0, fmt.Errorf("json.Unmarshal gas limit:%s error:%s", data, err)
+	}
+	return gasLimit, nil
+}
+
+//estimateGasLimit set the gas limit of transaction estimated by node. The transaction is signed before
+//estimating, so that the witness check of contract passes in pre-execution
+func estimateGasLimit(signer *account.Account, tx *types.MutableTransaction) error {
+	err := SignTransaction(signer, tx)
+	if err != nil {
+		return fmt.Errorf("SignTransaction error:%s", err)
+	}
+	immut, err := tx.IntoImmutable()
+	if err != nil {
+		return err
+	}
+	gasLimit, err := EstimateGas(immut)
+	if err != nil {
+		return fmt.Errorf("EstimateGas error:%s", err)
+	}
+	tx.GasLimit = gasLimit
+	return nil
+}
+
 func PrepareSendRawTransaction(txData string) (*cstates.PreExecResult, error) {
 	data, ontErr := sendRpcRequest("sendrawtransaction", []interface{}{txData, 1})
 	if ontErr != nil {
@@ -592,6 +508,7 @@ func GetTxHeight(txHash string) (uint32, error) {
 	return height, nil
 }
 
+//DeployContract deploy the contract, the gas limit is estimated by node if gasLimit is 0
 func DeployContract(
 	gasPrice,
 	gasLimit uint64,
@@ -609,6 +526,12 @@ func DeployContract(
 		return "", fmt.Errorf("hex.DecodeString error:%s", err)
 	}
 	mutable := NewDeployCodeTransaction(gasPrice, gasLimit, c, needStorage, cname, cversion, cauthor, cemail, cdesc)
+	if gasLimit == 0 {
+		err = estimateGasLimit(signer, mutable)
+		if err != nil {
+			return "", err
+		}
+	}
 
 	err = SignTransaction(signer, mutable)
 	if err != nil {
@@ -703,8 +626,14 @@ func InvokeNeoVMContract(
 	return InvokeSmartContract(signer, tx)
 }
 
-//InvokeSmartContract is low level method to invoke contact.
+//InvokeSmartContract is low level method to invoke contact. The gas limit is estimated by node if it is 0
 func InvokeSmartContract(signer *account.Account, tx *types.MutableTransaction) (string, error) {
+	if tx.GasLimit == 0 {
+		err := estimateGasLimit(signer, tx)
+		if err != nil {
+			return "", err
+		}
+	}
 	err := SignTransaction(signer, tx)
 	if err != nil {
 		return "", fmt.Errorf("SignTransaction error:%s", err)
@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+//withRpcServer point the rpc requests of cli to a test server answering estimategas with rsp
+func withRpcServer(t *testing.T, rsp string, fn func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &JsonRpcRequest{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(req))
+		assert.Equal(t, "estimategas", req.Method)
+		raw, err := common.HexToBytes(req.Params[0].(string))
+		assert.Nil(t, err)
+		tx, err := types.TransactionFromRawBytes(raw)
+		assert.Nil(t, err)
+		//signed before estimating, so that the witness check passes in pre-execution
+		assert.Equal(t, 1, len(tx.Sigs))
+		fmt.Fprint(w, rsp)
+	}))
+	defer server.Close()
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	assert.Nil(t, err)
+	p, err := strconv.Atoi(port)
+	assert.Nil(t, err)
+	old := config.DefConfig.Rpc.HttpJsonPort
+	config.DefConfig.Rpc.HttpJsonPort = uint(p)
+	defer func() { config.DefConfig.Rpc.HttpJsonPort = old }()
+	fn()
+}
+
+func TestEstimateGasLimit(t *testing.T) {
+	signer := account.NewAccount("")
+	to := account.NewAccount("")
+	tx, err := TransferTx(0, 0, "ont", signer.Address.ToBase58(), to.Address.ToBase58(), 1)
+	assert.Nil(t, err)
+	tx.Payer = signer.Address
+
+	withRpcServer(t, `{"jsonrpc":"2.0","result":22000,"id":"cli"}`, func() {
+		assert.Nil(t, estimateGasLimit(signer, tx))
+		assert.Equal(t, uint64(22000), tx.GasLimit)
+	})
+
+	tx.Sigs = nil
+	withRpcServer(t, `{"jsonrpc":"2.0","error":{"code":47001,"message":"SMARTCODE EXEC ERROR","data":"pre-execute transaction failed"},"id":"cli"}`, func() {
+		assert.NotNil(t, estimateGasLimit(signer, tx))
+		assert.Equal(t, uint64(22000), tx.GasLimit)
+	})
+}
@@ -63,13 +63,38 @@ type JsonRpcRequest struct {
 	Params  []interface{} `json:"params"`
 }
 
-//JsonRpcResponse object response for JsonRpcRequest
+//JsonRpcResponse object response for JsonRpcRequest, error is the error code
+//in legacy response, and the error object in JSON-RPC 2.0 response
 type JsonRpcResponse struct {
-	Error  int64           `json:"error"`
+	Error  json.RawMessage `json:"error"`
 	Desc   string          `json:"desc"`
 	Result json.RawMessage `json:"result"`
 }
 
+//errorCode return the ontology error code of response
+func (this *JsonRpcResponse) errorCode() (int64, error) {
+	if len(this.Error) == 0 || string(this.Error) == "null" {
+		return ERROR_ONTOLOGY_SUCCESS, nil
+	}
+	var code int64
+	if err := json.Unmarshal(this.Error, &code); err == nil {
+		return code, nil
+	}
+	rpcErr := &rpcerr.JsonRpcError{}
+	if err := json.Unmarshal(this.Error, rpcErr); err != nil {
+		return 0, err
+	}
+	switch rpcErr.Code {
+	case rpcerr.JSON_RPC_INVALID_PARAMS:
+		return rpcerr.INVALID_PARAMS, nil
+	case rpcerr.JSON_RPC_METHOD_NOT_FOUND:
+		return rpcerr.INVALID_METHOD, nil
+	case ERROR_ONTOLOGY_SUCCESS:
+		return ERROR_ONTOLOGY_COMMON, nil
+	}
+	return rpcErr.Code, nil
+}
+
 func sendRpcRequest(method string, params []interface{}) ([]byte, *OntologyError) {
 	rpcReq := &JsonRpcRequest{
 		Version: JSON_RPC_VERSION,
@@ -98,8 +123,12 @@ func sendRpcRequest(method string, params []interface{}) ([]byte, *OntologyError
 	if err != nil {
 		return nil, NewOntologyError(fmt.Errorf("json.Unmarshal JsonRpcResponse:%s error:%s", body, err))
 	}
-	if rpcRsp.Error != 0 {
-		return nil, NewOntologyError(fmt.Errorf("\n %s ", string(body)), rpcRsp.Error)
+	errCode, err := rpcRsp.errorCode()
+	if err != nil {
+		return nil, NewOntologyError(fmt.Errorf("json.Unmarshal JsonRpcResponse:%s error:%s", body, err))
+	}
+	if errCode != ERROR_ONTOLOGY_SUCCESS {
+		return nil, NewOntologyError(fmt.Errorf("\n %s ", string(body)), errCode)
 	}
 	return rpcRsp.Result, nil
 }
@@ -0,0 +1,232 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/cmd/abi"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+	cutils "github.com/ontio/ontology/core/utils"
+	"math/big"
+)
+
+//TX_DECODE_FEE_OVERFLOW is the max fee of transaction whose gas price * gas limit overflows
+const TX_DECODE_FEE_OVERFLOW = "overflow"
+
+//DecodedTransaction is the human readable form of raw transaction, decoded offline
+type DecodedTransaction struct {
+	Hash     string
+	Version  byte
+	TxType   string
+	Nonce    uint32
+	Payer    string
+	GasPrice uint64
+	GasLimit uint64
+	MaxFee   string
+	Sigs     []*DecodedSig
+	Invoke   *DecodedInvoke `json:",omitempty"`
+	Deploy   *DecodedDeploy `json:",omitempty"`
+}
+
+//DecodedSig is signature of transaction with signer address recovered from verify script
+type DecodedSig struct {
+	Signer   string
+	PubKeys  []string
+	M        uint16
+	SigData  []string
+	Verified bool
+	Error    string `json:",omitempty"`
+}
+
+//DecodedInvoke is payload of invoke transaction
+type DecodedInvoke struct {
+	Code        string
+	Native      bool
+	Contract    string      `json:",omitempty"`
+	Version     byte        `json:",omitempty"`
+	Method      string      `json:",omitempty"`
+	Params      interface{} `json:",omitempty"`
+	DecodeError string      `json:",omitempty"`
+	Disassembly []string    `json:",omitempty"`
+}
+
+//DecodedDeploy is payload of deploy transaction
+type DecodedDeploy struct {
+	Address     string
+	CodeSize    int
+	NeedStorage bool
+	Name        string
+	Version     string
+	Author      string
+	Email       string
+	Description string
+}
+
+//DecodeTransaction decode transaction without node. Native params are decoded by abi of DefAbiMgr,
+//so abi.DefAbiMgr should be initialized before
+func DecodeTransaction(tx *types.Transaction) *DecodedTransaction {
+	txHash := tx.Hash()
+	decoded := &DecodedTransaction{
+		Hash:     txHash.ToHexString(),
+		Version:  tx.Version,
+		Nonce:    tx.Nonce,
+		Payer:    tx.Payer.ToBase58(),
+		GasPrice: tx.GasPrice,
+		GasLimit: tx.GasLimit,
+		MaxFee:   formatMaxFee(tx.GasPrice, tx.GasLimit),
+		Sigs:     make([]*DecodedSig, 0, len(tx.Sigs)),
+	}
+	for _, rawSig := range tx.Sigs {
+		decoded.Sigs = append(decoded.Sigs, decodeTxSig(txHash, rawSig))
+	}
+	switch pl := tx.Payload.(type) {
+	case *payload.InvokeCode:
+		decoded.TxType = "Invoke"
+		decoded.Invoke = decodeInvokePayload(pl.Code)
+	case *payload.DeployCode:
+		decoded.TxType = "Deploy"
+		decoded.Deploy = &DecodedDeploy{
+			Address:     pl.Address().ToBase58(),
+			CodeSize:    len(pl.Code),
+			NeedStorage: pl.NeedStorage,
+			Name:        pl.Name,
+			Version:     pl.Version,
+			Author:      pl.Author,
+			Email:       pl.Email,
+			Description: pl.Description,
+		}
+	default:
+		decoded.TxType = fmt.Sprintf("Unknown(%x)", byte(tx.TxType))
+	}
+	return decoded
+}
+
+//formatMaxFee return gas price * gas limit in ONG, or TX_DECODE_FEE_OVERFLOW if a crafted transaction overflows it
+func formatMaxFee(gasPrice, gasLimit uint64) string {
+	fee, overflow := common.SafeMul(gasPrice, gasLimit)
+	if overflow {
+		return TX_DECODE_FEE_OVERFLOW
+	}
+	return FormatOng(fee)
+}
+
+func decodeTxSig(txHash common.Uint256, rawSig types.RawSig) *DecodedSig {
+	decoded := &DecodedSig{
+		Signer: common.AddressFromVmCode(rawSig.Verify).ToBase58(),
+	}
+	sig, err := rawSig.GetSig()
+	if err != nil {
+		decoded.Error = fmt.Sprintf("GetSig error:%s", err)
+		return decoded
+	}
+	decoded.M = sig.M
+	for _, pubKey := range sig.PubKeys {
+		decoded.PubKeys = append(decoded.PubKeys, hex.EncodeToString(keypair.SerializePublicKey(pubKey)))
+	}
+	for _, sigData := range sig.SigData {
+		decoded.SigData = append(decoded.SigData, hex.EncodeToString(sigData))
+	}
+	m := int(sig.M)
+	if m <= 0 || m > len(sig.PubKeys) || len(sig.SigData) < m {
+		decoded.Error = "wrong sig param length"
+		return decoded
+	}
+	if len(sig.PubKeys) == 1 {
+		err = signature.Verify(sig.PubKeys[0], txHash[:], sig.SigData[0])
+	} else {
+		err = signature.VerifyMultiSignature(txHash[:], sig.PubKeys, m, sig.SigData)
+	}
+	if err != nil {
+		decoded.Error = fmt.Sprintf("verify signature error:%s", err)
+		return decoded
+	}
+	decoded.Verified = true
+	return decoded
+}
+
+func decodeInvokePayload(code []byte) *DecodedInvoke {
+	decoded := &DecodedInvoke{
+		Code: hex.EncodeToString(code),
+	}
+	info, err := cutils.DecodeInvokeCode(code)
+	if err == nil {
+		decoded.Native = info.Native
+		decoded.Contract = info.Contract.ToHexString()
+		decoded.Version = info.Version
+		decoded.Method = info.Method
+		decoded.Params, err = decodeInvokeParams(info)
+		if err != nil {
+			decoded.DecodeError = err.Error()
+		}
+	} else {
+		decoded.DecodeError = err.Error()
+	}
+	if decoded.Native {
+		return decoded
+	}
+	instructions, err := cutils.DisassembleNeoVMCode(code)
+	if err != nil {
+		decoded.DecodeError = fmt.Sprintf("DisassembleNeoVMCode error:%s", err)
+	}
+	for _, ins := range instructions {
+		decoded.Disassembly = append(decoded.Disassembly, ins.String())
+	}
+	return decoded
+}
+
+func decodeInvokeParams(info *cutils.InvokeCodeInfo) (interface{}, error) {
+	if info.Native {
+		nativeAbi := abi.DefAbiMgr.GetNativeAbi(info.Contract.ToHexString())
+		if nativeAbi != nil {
+			funcAbi := nativeAbi.GetFunc(info.Method)
+			if funcAbi == nil {
+				return ToDecodedParams(info.Params), fmt.Errorf("cannot find abi of method:%s", info.Method)
+			}
+			params, err := DecodeNativeFuncParams(info.Params, funcAbi)
+			if err != nil {
+				return ToDecodedParams(info.Params), fmt.Errorf("DecodeNativeFuncParams error:%s", err)
+			}
+			return params, nil
+		}
+	}
+	return ToDecodedParams(info.Params), nil
+}
+
+//ToDecodedParams convert params of InvokeCodeInfo to printable value, bytes are hex encoded
+func ToDecodedParams(params []interface{}) []interface{} {
+	decoded := make([]interface{}, 0, len(params))
+	for _, param := range params {
+		switch v := param.(type) {
+		case []byte:
+			decoded = append(decoded, hex.EncodeToString(v))
+		case *big.Int:
+			decoded = append(decoded, v.String())
+		case []interface{}:
+			decoded = append(decoded, ToDecodedParams(v))
+		default:
+			decoded = append(decoded, v)
+		}
+	}
+	return decoded
+}
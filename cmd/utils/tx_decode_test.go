@@ -0,0 +1,36 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeTransactionMaxFee(t *testing.T) {
+	tx, err := NewInvokeTransaction(500, 20000, []byte{0x51}).IntoImmutable()
+	assert.Nil(t, err)
+	assert.Equal(t, "0.01", DecodeTransaction(tx).MaxFee)
+
+	tx, err = NewInvokeTransaction(math.MaxUint64, 2, []byte{0x51}).IntoImmutable()
+	assert.Nil(t, err)
+	assert.Equal(t, TX_DECODE_FEE_OVERFLOW, DecodeTransaction(tx).MaxFee)
+}
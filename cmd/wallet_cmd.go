@@ -0,0 +1,294 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	cmdcom "github.com/ontio/ontology/cmd/common"
+	"github.com/ontio/ontology/cmd/sigsvr/store"
+	"github.com/ontio/ontology/cmd/utils"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/password"
+	"github.com/urfave/cli"
+	"strings"
+)
+
+var WalletCommand = cli.Command{
+	Action:    cli.ShowSubcommandHelp,
+	Name:      "wallet",
+	Usage:     "Migrate, convert, backup and restore wallet",
+	ArgsUsage: "[arguments...]",
+	Description: `Wallet tool commands can be used to upgrade wallet file to current version, convert wallet between wallet file and
+wallet data of sigsvr, and backup wallet into an encrypted file.`,
+	Subcommands: []cli.Command{
+		{
+			Action:    walletMigrate,
+			Name:      "migrate",
+			Usage:     "Upgrade wallet file to current version",
+			ArgsUsage: "[sub-command options]",
+			Flags: []cli.Flag{
+				utils.WalletFileFlag,
+				utils.AccountLowSecurityFlag,
+			},
+			Description: `Upgrade wallet file to current version. Missing fields of old version are filled, and keys not encrypted in
+   current format are re-encrypted with the default scrypt param, or the low security param with --low-security.
+   Password of each key to re-encrypt is required, password is not changed.`,
+		},
+		{
+			Action:    walletToStore,
+			Name:      "tostore",
+			Usage:     "Import wallet file to wallet data of sigsvr",
+			ArgsUsage: "[sub-command options]",
+			Flags: []cli.Flag{
+				utils.WalletFileFlag,
+				utils.CliWalletDirFlag,
+			},
+			Description: `Import accounts and ONT IDs of wallet file to wallet data of sigsvr. If scrypt param of wallet file is different
+   from wallet data, keys are re-encrypted with scrypt param of wallet data. Hd seed is not supported by wallet data of sigsvr.`,
+		},
+		{
+			Action:    walletFromStore,
+			Name:      "fromstore",
+			Usage:     "Export wallet data of sigsvr to wallet file",
+			ArgsUsage: "[sub-command options]",
+			Flags: []cli.Flag{
+				utils.WalletFileFlag,
+				utils.CliWalletDirFlag,
+			},
+			Description: `Export accounts and ONT IDs of wallet data of sigsvr to a new wallet file.`,
+		},
+		{
+			Action:    walletBackup,
+			Name:      "backup",
+			Usage:     "Backup wallet file into an encrypted file",
+			ArgsUsage: "[sub-command options] <filename>",
+			Flags: []cli.Flag{
+				utils.WalletFileFlag,
+			},
+			Description: `Backup the whole wallet file into a single file encrypted by a backup password, with checksum of integrity.`,
+		},
+		{
+			Action:    walletRestore,
+			Name:      "restore",
+			Usage:     "Restore wallet file from an encrypted backup file",
+			ArgsUsage: "[sub-command options] <filename>",
+			Flags: []cli.Flag{
+				utils.WalletFileFlag,
+			},
+			Description: `Restore wallet file from backup file. The wallet file should not exist.`,
+		},
+	},
+}
+
+func loadWalletData(path string) (*account.WalletData, error) {
+	if !common.FileExisted(path) {
+		return nil, fmt.Errorf("wallet file:%s does not exist", path)
+	}
+	walletData := &account.WalletData{}
+	err := walletData.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("load wallet:%s error:%s", path, err)
+	}
+	return walletData, nil
+}
+
+//migrateWallet migrate wallet data with password of user input
+func migrateWallet(walletData *account.WalletData, param *keypair.ScryptParam) (*account.MigrateInfo, error) {
+	passwds := make([][]byte, 0)
+	defer func() {
+		for _, passwd := range passwds {
+			cmdcom.ClearPasswd(passwd)
+		}
+	}()
+	return walletData.Migrate(param, func(id string) ([]byte, error) {
+		switch {
+		case id == "":
+			PrintInfoMsg("HD seed:")
+		case strings.HasPrefix(id, "did:ont:"):
+			PrintInfoMsg("ONT ID %s:", id)
+		default:
+			PrintInfoMsg("Account %s:", id)
+		}
+		passwd, err := password.GetPassword()
+		if err != nil {
+			return nil, err
+		}
+		passwds = append(passwds, passwd)
+		return passwd, nil
+	})
+}
+
+func walletMigrate(ctx *cli.Context) error {
+	walletFile := ctx.String(utils.GetFlagName(utils.WalletFileFlag))
+	walletData, err := loadWalletData(walletFile)
+	if err != nil {
+		return err
+	}
+	param := keypair.GetScryptParameters()
+	if ctx.Bool(utils.GetFlagName(utils.AccountLowSecurityFlag)) {
+		param = account.GetLowSecurityScryptParam()
+	}
+	info, err := migrateWallet(walletData, param)
+	if err != nil {
+		return fmt.Errorf("migrate wallet error:%s", err)
+	}
+	err = walletData.Save(walletFile)
+	if err != nil {
+		return fmt.Errorf("save wallet file error:%s", err)
+	}
+	PrintInfoMsg("Migrate wallet success.")
+	PrintInfoMsg("Version:%s => %s", info.FromVersion, info.ToVersion)
+	PrintInfoMsg("Re-encrypted keys:%d", info.Reencrypted)
+	PrintInfoMsg("Fixed accounts:%d", info.Fixed)
+	return nil
+}
+
+func walletToStore(ctx *cli.Context) error {
+	walletFile := ctx.String(utils.GetFlagName(utils.WalletFileFlag))
+	walletDir := ctx.String(utils.GetFlagName(utils.CliWalletDirFlag))
+	walletData, err := loadWalletData(walletFile)
+	if err != nil {
+		return err
+	}
+	walletStore, err := store.NewWalletStore(walletDir)
+	if err != nil {
+		return fmt.Errorf("NewWalletStore dir path:%s error:%s", walletDir, err)
+	}
+	defer walletStore.Close()
+	if walletData.HDSeed != nil {
+		PrintWarnMsg("Hd seed of wallet is not imported, please keep the mnemonic or wallet file.")
+	}
+	if walletData.Scrypt == nil || *walletData.Scrypt != *walletStore.WalletScrypt {
+		PrintInfoMsg("Scrypt param of wallet is different from wallet data, keys will be re-encrypted.")
+		walletData.HDSeed = nil
+		_, err = migrateWallet(walletData, walletStore.WalletScrypt)
+		if err != nil {
+			return fmt.Errorf("migrate wallet error:%s", err)
+		}
+	}
+	addNum, updateNum, addIdNum, err := walletStore.ImportWalletData(walletData)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Import wallet to %s success.", walletDir)
+	PrintInfoMsg("Add account number:%d", addNum)
+	PrintInfoMsg("Update account number:%d", updateNum)
+	PrintInfoMsg("Add identity number:%d", addIdNum)
+	return nil
+}
+
+func walletFromStore(ctx *cli.Context) error {
+	walletFile := ctx.String(utils.GetFlagName(utils.WalletFileFlag))
+	walletDir := ctx.String(utils.GetFlagName(utils.CliWalletDirFlag))
+	if common.FileExisted(walletFile) {
+		return fmt.Errorf("wallet file:%s already exist", walletFile)
+	}
+	if !common.FileExisted(walletDir) {
+		return fmt.Errorf("wallet data:%s does not exist", walletDir)
+	}
+	walletStore, err := store.NewWalletStore(walletDir)
+	if err != nil {
+		return fmt.Errorf("NewWalletStore dir path:%s error:%s", walletDir, err)
+	}
+	defer walletStore.Close()
+	walletData, err := walletStore.ExportWalletData()
+	if err != nil {
+		return err
+	}
+	err = walletData.Save(walletFile)
+	if err != nil {
+		return fmt.Errorf("save wallet file error:%s", err)
+	}
+	PrintInfoMsg("Export wallet data to %s success.", walletFile)
+	PrintInfoMsg("Account number:%d", len(walletData.Accounts))
+	PrintInfoMsg("Identity number:%d", len(walletData.Identities))
+	return nil
+}
+
+func walletBackup(ctx *cli.Context) error {
+	if ctx.NArg() <= 0 {
+		PrintErrorMsg("Missing backup file argument.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	backupFile := ctx.Args().First()
+	if common.FileExisted(backupFile) {
+		return fmt.Errorf("backup file:%s already exist", backupFile)
+	}
+	walletData, err := loadWalletData(ctx.String(utils.GetFlagName(utils.WalletFileFlag)))
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Please input a password to encrypt the backup file")
+	passwd, err := password.GetConfirmedPassword()
+	if err != nil {
+		return err
+	}
+	defer cmdcom.ClearPasswd(passwd)
+	backup, err := account.NewWalletBackup(walletData, passwd, nil)
+	if err != nil {
+		return fmt.Errorf("backup wallet error:%s", err)
+	}
+	err = backup.Save(backupFile)
+	if err != nil {
+		return fmt.Errorf("save backup file error:%s", err)
+	}
+	PrintInfoMsg("Backup wallet to %s success.", backupFile)
+	PrintInfoMsg("Checksum:%s", backup.Checksum)
+	return nil
+}
+
+func walletRestore(ctx *cli.Context) error {
+	if ctx.NArg() <= 0 {
+		PrintErrorMsg("Missing backup file argument.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	walletFile := ctx.String(utils.GetFlagName(utils.WalletFileFlag))
+	if common.FileExisted(walletFile) {
+		return fmt.Errorf("wallet file:%s already exist", walletFile)
+	}
+	backup, err := account.LoadWalletBackup(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	err = backup.Verify()
+	if err != nil {
+		return err
+	}
+	passwd, err := password.GetPassword()
+	if err != nil {
+		return err
+	}
+	defer cmdcom.ClearPasswd(passwd)
+	walletData, err := backup.Restore(passwd)
+	if err != nil {
+		return err
+	}
+	err = walletData.Save(walletFile)
+	if err != nil {
+		return fmt.Errorf("save wallet file error:%s", err)
+	}
+	PrintInfoMsg("Restore wallet to %s success.", walletFile)
+	PrintInfoMsg("Account number:%d", len(walletData.Accounts))
+	PrintInfoMsg("Identity number:%d", len(walletData.Identities))
+	return nil
+}
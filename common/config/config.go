@@ -57,6 +57,11 @@ const (
 	DEFAULT_RPC_LOCAL_PORT                  = uint(20337)
 	DEFAULT_REST_PORT                       = uint(20334)
 	DEFAULT_WS_PORT                         = uint(20335)
+	DEFAULT_GRAPHQL_PORT                    = uint(20340)
+	DEFAULT_GRAPHQL_MAX_DEPTH               = uint(10)
+	DEFAULT_GRAPHQL_MAX_COST                = uint(1000)
+	DEFAULT_METRICS_PORT                    = uint(20341)
+	DEFAULT_API_MAX_REQUEST_BYTES           = uint(4 * 1024 * 1024)
 	DEFAULT_REST_MAX_CONN                   = uint(1024)
 	DEFAULT_MAX_CONN_IN_BOUND               = uint(1024)
 	DEFAULT_MAX_CONN_OUT_BOUND              = uint(1024)
@@ -73,6 +78,7 @@ const (
 
 	DEFAULT_DATA_DIR      = "./Chain"
 	DEFAULT_RESERVED_FILE = "./peers.rsv"
+	DEFAULT_NODE_KEY_FILE = "./nodekey"
 )
 
 const (
@@ -477,18 +483,21 @@ type DBFTConfig struct {
 }
 
 type SOLOConfig struct {
-	GenBlockTime uint
-	Bookkeepers  []string
+	GenBlockTime     uint
+	GenBlockOnDemand bool
+	Bookkeepers      []string
 }
 
 type CommonConfig struct {
-	LogLevel       uint
-	NodeType       string
-	EnableEventLog bool
-	SystemFee      map[string]int64
-	GasLimit       uint64
-	GasPrice       uint64
-	DataDir        string
+	LogLevel           uint
+	NodeType           string
+	EnableEventLog     bool
+	EnableAddressIndex bool
+	EnableContractAbi  bool
+	SystemFee          map[string]int64
+	GasLimit           uint64
+	GasPrice           uint64
+	DataDir            string
 }
 
 type ConsensusConfig struct {
@@ -514,6 +523,8 @@ type P2PNodeConfig struct {
 	CertPath                  string
 	KeyPath                   string
 	CAPath                    string
+	IsEncrypted               bool
+	NodeKeyPath               string
 	HttpInfoPort              uint
 	MaxHdrSyncReqs            uint
 	MaxConnInBound            uint
@@ -522,9 +533,10 @@ type P2PNodeConfig struct {
 }
 
 type RpcConfig struct {
-	EnableHttpJsonRpc bool
-	HttpJsonPort      uint
-	HttpLocalPort     uint
+	EnableHttpJsonRpc    bool
+	HttpJsonPort         uint
+	HttpLocalPort        uint
+	EnableLegacyResponse bool
 }
 
 type RestfulConfig struct {
@@ -542,6 +554,31 @@ type WebSocketConfig struct {
 	HttpKeyPath  string
 }
 
+type GraphQLConfig struct {
+	EnableGraphQL bool
+	GraphQLPort   uint
+	MaxQueryDepth uint //Max depth of nested fields in a query
+	MaxQueryCost  uint //Max ledger lookups of a query
+}
+
+type MetricsConfig struct {
+	EnableMetrics bool
+	MetricsPort   uint
+}
+
+//AuthConfig guards the rpc, restful and websocket servers
+type AuthConfig struct {
+	EnableAuth      bool
+	PublicRead      bool     //Allow read methods without credential
+	ReadKeys        []string //Api keys of read group
+	SubmitKeys      []string //Api keys of submit group, also granted read
+	AdminKeys       []string //Api keys of admin group, granted all methods
+	JwtSecret       string   //HMAC secret of HS256 jwt, whose scope claim is the group
+	RateLimit       uint     //Requests per second of a client, 0 means no limit
+	RateBurst       uint     //Burst requests of a client, 0 means same as RateLimit
+	MaxRequestBytes uint     //Max body size of a request, 0 means no limit
+}
+
 type OntologyConfig struct {
 	Genesis   *GenesisConfig
 	Common    *CommonConfig
@@ -550,6 +587,9 @@ type OntologyConfig struct {
 	Rpc       *RpcConfig
 	Restful   *RestfulConfig
 	Ws        *WebSocketConfig
+	GraphQL   *GraphQLConfig
+	Metrics   *MetricsConfig
+	Auth      *AuthConfig
 }
 
 func NewOntologyConfig() *OntologyConfig {
@@ -579,6 +619,8 @@ func NewOntologyConfig() *OntologyConfig {
 			CertPath:                  "",
 			KeyPath:                   "",
 			CAPath:                    "",
+			IsEncrypted:               false,
+			NodeKeyPath:               DEFAULT_NODE_KEY_FILE,
 			HttpInfoPort:              DEFAULT_HTTP_INFO_PORT,
 			MaxHdrSyncReqs:            DEFAULT_MAX_SYNC_HEADER,
 			MaxConnInBound:            DEFAULT_MAX_CONN_IN_BOUND,
@@ -598,6 +640,20 @@ func NewOntologyConfig() *OntologyConfig {
 			EnableHttpWs: true,
 			HttpWsPort:   DEFAULT_WS_PORT,
 		},
+		GraphQL: &GraphQLConfig{
+			EnableGraphQL: false,
+			GraphQLPort:   DEFAULT_GRAPHQL_PORT,
+			MaxQueryDepth: DEFAULT_GRAPHQL_MAX_DEPTH,
+			MaxQueryCost:  DEFAULT_GRAPHQL_MAX_COST,
+		},
+		Metrics: &MetricsConfig{
+			EnableMetrics: false,
+			MetricsPort:   DEFAULT_METRICS_PORT,
+		},
+		Auth: &AuthConfig{
+			EnableAuth:      false,
+			MaxRequestBytes: DEFAULT_API_MAX_REQUEST_BYTES,
+		},
 	}
 }
 
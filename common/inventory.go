@@ -21,9 +21,10 @@ package common
 type InventoryType byte
 
 const (
-	TRANSACTION InventoryType = 0x01
-	BLOCK       InventoryType = 0x02
-	CONSENSUS   InventoryType = 0xe0
+	TRANSACTION   InventoryType = 0x01
+	BLOCK         InventoryType = 0x02
+	COMPACT_BLOCK InventoryType = 0x03
+	CONSENSUS     InventoryType = 0xe0
 )
 
 //TODO: temp inventory
@@ -0,0 +1,64 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package metrics holds the prometheus collectors updated inside the node
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//NAMESPACE is the prefix of all metric names of node
+const NAMESPACE = "ontology"
+
+var (
+	//BlockExecuteTime observe the seconds of executing a block in ledger
+	BlockExecuteTime = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "ledger",
+		Name:      "block_execute_seconds",
+		Help:      "Time of executing a block in ledger.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 15),
+	})
+	//ConsensusRounds count the vbft rounds started
+	ConsensusRounds = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "vbft",
+		Name:      "rounds_total",
+		Help:      "Count of vbft rounds started.",
+	})
+	//ConsensusViewChanges count the rounds whose proposer timed out
+	ConsensusViewChanges = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "vbft",
+		Name:      "view_changes_total",
+		Help:      "Count of vbft rounds handed over to backup proposers after proposal timeout.",
+	})
+	//RpcRequestTime observe the seconds of handling a json rpc request by method
+	RpcRequestTime = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "rpc",
+		Name:      "request_seconds",
+		Help:      "Time of handling a json rpc request.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+)
+
+func init() {
+	prometheus.MustRegister(BlockExecuteTime, ConsensusRounds, ConsensusViewChanges, RpcRequestTime)
+}
@@ -65,6 +65,16 @@ func GetConfirmedPassword() ([]byte, error) {
 	return first, nil
 }
 
+// GetHiddenInput gets secret input such as mnemonic from user without echo
+func GetHiddenInput(prompt string) ([]byte, error) {
+	fmt.Print(prompt)
+	input, err := gopass.GetPasswd()
+	if err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
 // GetPassword gets node's wallet password from command line or user input
 func GetAccountPassword() ([]byte, error) {
 	var passwd []byte
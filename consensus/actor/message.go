@@ -28,3 +28,19 @@ type TimeOut struct{}
 type BlockCompleted struct {
 	Block *types.Block
 }
+
+//solo test mode Message
+type GenBlocks struct {
+	Count uint32
+}
+type GenBlocksRsp struct {
+	Height uint32
+	Error  error
+}
+type IncreaseTime struct {
+	Seconds uint32
+}
+type IncreaseTimeRsp struct {
+	Offset uint32
+	Error  error
+}
@@ -67,6 +67,9 @@ func (self *BlockSignatures) Deserialization(source *common.ZeroCopySource) erro
 		if irregular {
 			return common.ErrIrregularData
 		}
+		if eof {
+			return io.ErrUnexpectedEOF
+		}
 
 		sig.Index, eof = source.NextUint16()
 		if eof {
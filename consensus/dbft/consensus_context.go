@@ -26,6 +26,8 @@ import (
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/core/ledger"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/store"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/core/vote"
 	msg "github.com/ontio/ontology/p2pserver/message/types"
@@ -50,7 +52,8 @@ type ConsensusContext struct {
 	Signatures      [][]byte
 	ExpectedView    []byte
 
-	header *types.Block
+	header     *types.Block
+	execResult *store.ExecuteResult
 
 	isBookkeeperChanged bool
 	nmChangedblkHeight  uint32
@@ -68,22 +71,32 @@ func NewConsensusContext() *ConsensusContext {
 
 func (ctx *ConsensusContext) ChangeView(viewNum byte) {
 	log.Debug()
-	p := (ctx.Height - uint32(viewNum)) % uint32(len(ctx.Bookkeepers))
 	ctx.State &= SignatureSent
 	ctx.ViewNumber = viewNum
-	if p >= 0 {
-		ctx.PrimaryIndex = uint32(p)
-	} else {
-		ctx.PrimaryIndex = uint32(p) + uint32(len(ctx.Bookkeepers))
-	}
+	ctx.PrimaryIndex = GetPrimaryIndex(ctx.Height, viewNum, len(ctx.Bookkeepers))
 
 	if ctx.State == Initial {
 		ctx.Transactions = nil
 		ctx.Signatures = make([][]byte, len(ctx.Bookkeepers))
 		ctx.header = nil
+		ctx.execResult = nil
 	}
 }
 
+// GetPrimaryIndex returns the index of the speaker for the given height and view,
+// the speaker moves backward by one bookkeeper on every view change.
+func GetPrimaryIndex(height uint32, viewNum byte, bookkeeperLen int) uint32 {
+	if bookkeeperLen <= 0 {
+		return 0
+	}
+	n := int64(bookkeeperLen)
+	p := (int64(height) - int64(viewNum)) % n
+	if p < 0 {
+		p += n
+	}
+	return uint32(p)
+}
+
 func (ctx *ConsensusContext) MakeChangeView() *msg.ConsensusPayload {
 	log.Debug()
 	cv := &ChangeView{
@@ -119,6 +132,47 @@ func (ctx *ConsensusContext) MakeHeader() *types.Block {
 	return ctx.header
 }
 
+// MakeProposal returns the proposed block with the transactions of current context,
+// the header is shared with MakeHeader so the block hash is the one signed by bookkeepers.
+func (ctx *ConsensusContext) MakeProposal() *types.Block {
+	header := ctx.MakeHeader()
+	return &types.Block{
+		Header:       header.Header,
+		Transactions: ctx.Transactions,
+	}
+}
+
+// ExecuteAndSignProposal executes the proposal with execute, and only if the execution
+// succeeds signs the block hash into the signature slot of this bookkeeper.
+func (ctx *ConsensusContext) ExecuteAndSignProposal(execute func(*types.Block) (store.ExecuteResult, error),
+	signer *account.Account) error {
+	ctx.Signatures[ctx.BookkeeperIndex] = nil
+	block := ctx.MakeProposal()
+	result, err := execute(block)
+	if err != nil {
+		return fmt.Errorf("ExecuteBlock Height:%d error:%s", block.Header.Height, err)
+	}
+	ctx.SetExecuteResult(&result)
+	blockHash := block.Hash()
+	sig, err := signature.Sign(signer, blockHash[:])
+	if err != nil {
+		return fmt.Errorf("sign block Height:%d error:%s", block.Header.Height, err)
+	}
+	ctx.Signatures[ctx.BookkeeperIndex] = sig
+	return nil
+}
+
+// SetExecuteResult caches the execution result of the proposal, it will be
+// submitted to ledger when enough signatures are collected.
+func (ctx *ConsensusContext) SetExecuteResult(result *store.ExecuteResult) {
+	ctx.execResult = result
+}
+
+// GetExecuteResult returns the cached execution result of current proposal
+func (ctx *ConsensusContext) GetExecuteResult() *store.ExecuteResult {
+	return ctx.execResult
+}
+
 func (ctx *ConsensusContext) MakePayload(message ConsensusMessage) *msg.ConsensusPayload {
 	log.Debug()
 	message.ConsensusMessageData().ViewNumber = ctx.ViewNumber
@@ -212,9 +266,10 @@ func (ctx *ConsensusContext) Reset(bkAccount *account.Account) {
 	ctx.BookkeeperIndex = -1
 	ctx.NextBookkeepers = nil
 	bookkeeperLen := len(ctx.Bookkeepers)
-	ctx.PrimaryIndex = ctx.Height % uint32(bookkeeperLen)
+	ctx.PrimaryIndex = GetPrimaryIndex(ctx.Height, 0, bookkeeperLen)
 	ctx.Transactions = nil
 	ctx.header = nil
+	ctx.execResult = nil
 	ctx.Signatures = make([][]byte, bookkeeperLen)
 	ctx.ExpectedView = make([]byte, bookkeeperLen)
 
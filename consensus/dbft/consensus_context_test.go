@@ -0,0 +1,147 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package dbft
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/core/store"
+	"github.com/ontio/ontology/core/types"
+)
+
+func newTestContext(n int) *ConsensusContext {
+	ctx := NewConsensusContext()
+	for i := 0; i < n; i++ {
+		acc := account.NewAccount("SHA256withECDSA")
+		ctx.Bookkeepers = append(ctx.Bookkeepers, acc.PublicKey)
+	}
+	ctx.Signatures = make([][]byte, n)
+	ctx.ExpectedView = make([]byte, n)
+	return ctx
+}
+
+func TestConsensusContextM(t *testing.T) {
+	expected := map[int]int{1: 1, 4: 3, 5: 4, 7: 5, 10: 7}
+	for n, m := range expected {
+		ctx := &ConsensusContext{Bookkeepers: make([]keypair.PublicKey, n)}
+		if ctx.M() != m {
+			t.Errorf("bookkeepers %d, expected M %d, got %d", n, m, ctx.M())
+		}
+	}
+}
+
+func TestGetPrimaryIndex(t *testing.T) {
+	if idx := GetPrimaryIndex(10, 0, 4); idx != 2 {
+		t.Errorf("expected primary 2, got %d", idx)
+	}
+	if idx := GetPrimaryIndex(10, 3, 4); idx != 3 {
+		t.Errorf("expected primary 3, got %d", idx)
+	}
+	// view number larger than height must not underflow
+	if idx := GetPrimaryIndex(1, 3, 4); idx != 2 {
+		t.Errorf("expected primary 2, got %d", idx)
+	}
+	if idx := GetPrimaryIndex(1, 3, 0); idx != 0 {
+		t.Errorf("expected primary 0 without bookkeepers, got %d", idx)
+	}
+}
+
+func TestChangeView(t *testing.T) {
+	ctx := newTestContext(4)
+	ctx.Height = 2
+	ctx.State = Backup | RequestReceived
+	ctx.Signatures[1] = []byte{1}
+	ctx.SetExecuteResult(&store.ExecuteResult{})
+
+	ctx.ChangeView(3)
+	if ctx.ViewNumber != 3 || ctx.PrimaryIndex != 3 {
+		t.Fatalf("unexpected view %d primary %d", ctx.ViewNumber, ctx.PrimaryIndex)
+	}
+	if ctx.State != Initial || ctx.GetSignaturesCount() != 0 || ctx.GetExecuteResult() != nil {
+		t.Fatalf("context should be cleared after view change: %s", ctx.GetStateDetail())
+	}
+
+	// signed proposal is kept across view change
+	ctx.State = Backup | SignatureSent
+	ctx.Signatures[1] = []byte{1}
+	ctx.SetExecuteResult(&store.ExecuteResult{})
+	ctx.ChangeView(4)
+	if ctx.State != SignatureSent || ctx.GetSignaturesCount() != 1 || ctx.GetExecuteResult() == nil {
+		t.Fatalf("signed proposal should be kept after view change: %s", ctx.GetStateDetail())
+	}
+}
+
+func TestExecuteAndSignProposal(t *testing.T) {
+	ctx := newTestContext(4)
+	ctx.Height = 2
+	ctx.BookkeeperIndex = 1
+	//preset header, so proposal is made without ledger
+	ctx.header = &types.Block{Header: &types.Header{Height: ctx.Height}}
+	acc := account.NewAccount("SHA256withECDSA")
+
+	failed := func(block *types.Block) (store.ExecuteResult, error) {
+		return store.ExecuteResult{}, errors.New("execute failed")
+	}
+	if err := ctx.ExecuteAndSignProposal(failed, acc); err == nil {
+		t.Fatalf("execute failure should be returned")
+	}
+	if ctx.Signatures[1] != nil || ctx.GetExecuteResult() != nil {
+		t.Fatalf("proposal should not be signed when execution fails")
+	}
+
+	succeed := func(block *types.Block) (store.ExecuteResult, error) {
+		return store.ExecuteResult{}, nil
+	}
+	if err := ctx.ExecuteAndSignProposal(succeed, acc); err != nil {
+		t.Fatalf("ExecuteAndSignProposal error:%s", err)
+	}
+	if ctx.Signatures[1] == nil || ctx.GetExecuteResult() == nil {
+		t.Fatalf("proposal should be signed after execution")
+	}
+}
+
+func TestConsensusStateFlag(t *testing.T) {
+	state := Primary | RequestSent
+	if !state.HasFlag(Primary) || !state.HasFlag(RequestSent) {
+		t.Fatalf("missing state flag")
+	}
+	if state.HasFlag(Backup) || state.HasFlag(BlockGenerated) {
+		t.Fatalf("unexpected state flag")
+	}
+}
+
+func TestGetViewTimeout(t *testing.T) {
+	genBlockTime := 6 * time.Second
+	if timeout := GetViewTimeout(genBlockTime, 0); timeout != 12*time.Second {
+		t.Errorf("view 0 expected 12s, got %v", timeout)
+	}
+	if timeout := GetViewTimeout(genBlockTime, 2); timeout != 48*time.Second {
+		t.Errorf("view 2 expected 48s, got %v", timeout)
+	}
+	max := genBlockTime << MaxViewBackoff
+	for _, view := range []byte{MaxViewBackoff, 63, 255} {
+		if timeout := GetViewTimeout(genBlockTime, view); timeout != max {
+			t.Errorf("view %d expected %v, got %v", view, max, timeout)
+		}
+	}
+}
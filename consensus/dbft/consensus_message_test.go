@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package dbft
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+)
+
+func serializeMessage(t *testing.T, msg ConsensusMessage) []byte {
+	sink := common.NewZeroCopySink(nil)
+	if err := msg.Serialization(sink); err != nil {
+		t.Fatalf("serialize message failed: %s", err)
+	}
+	return sink.Bytes()
+}
+
+func TestChangeViewSerialization(t *testing.T) {
+	cv := &ChangeView{NewViewNumber: 3}
+	cv.msgData.Type = ChangeViewMsg
+	cv.msgData.ViewNumber = 2
+
+	msg, err := DeserializeMessage(serializeMessage(t, cv))
+	if err != nil {
+		t.Fatalf("DeserializeMessage failed: %s", err)
+	}
+	cv2, ok := msg.(*ChangeView)
+	if !ok {
+		t.Fatalf("unexpected message type %d", msg.Type())
+	}
+	if cv2.ViewNumber() != 2 || cv2.NewViewNumber != 3 {
+		t.Fatalf("unmatched change view: view %d, new view %d", cv2.ViewNumber(), cv2.NewViewNumber)
+	}
+}
+
+func TestPrepareRequestSerialization(t *testing.T) {
+	pr := &PrepareRequest{
+		Nonce:          123456,
+		NextBookkeeper: common.Address{1, 2, 3},
+		Signature:      []byte{4, 5, 6},
+	}
+	pr.msgData.Type = PrepareRequestMsg
+	pr.msgData.ViewNumber = 1
+
+	buf := serializeMessage(t, pr)
+	msg, err := DeserializeMessage(buf)
+	if err != nil {
+		t.Fatalf("DeserializeMessage failed: %s", err)
+	}
+	pr2, ok := msg.(*PrepareRequest)
+	if !ok {
+		t.Fatalf("unexpected message type %d", msg.Type())
+	}
+	if pr2.Nonce != pr.Nonce || pr2.NextBookkeeper != pr.NextBookkeeper || pr2.ViewNumber() != 1 {
+		t.Fatalf("unmatched prepare request")
+	}
+	if !bytes.Equal(pr2.Signature, pr.Signature) || len(pr2.Transactions) != 0 {
+		t.Fatalf("unmatched prepare request signature or transactions")
+	}
+
+	for i := 2; i < len(buf); i++ {
+		if _, err := DeserializeMessage(buf[:i]); err == nil {
+			t.Fatalf("truncated prepare request of length %d should fail", i)
+		}
+	}
+}
+
+func TestPrepareResponseSerialization(t *testing.T) {
+	pres := &PrepareResponse{Signature: []byte{1, 2, 3}}
+	pres.msgData.Type = PrepareResponseMsg
+
+	msg, err := DeserializeMessage(serializeMessage(t, pres))
+	if err != nil {
+		t.Fatalf("DeserializeMessage failed: %s", err)
+	}
+	pres2, ok := msg.(*PrepareResponse)
+	if !ok {
+		t.Fatalf("unexpected message type %d", msg.Type())
+	}
+	if !bytes.Equal(pres2.Signature, pres.Signature) {
+		t.Fatalf("unmatched prepare response signature")
+	}
+}
+
+func TestBlockSignaturesSerialization(t *testing.T) {
+	sigs := &BlockSignatures{
+		Signatures: []SignaturesData{
+			{Signature: []byte{1, 2}, Index: 0},
+			{Signature: []byte{3, 4}, Index: 3},
+		},
+	}
+	sigs.msgData.Type = BlockSignaturesMsg
+
+	buf := serializeMessage(t, sigs)
+	msg, err := DeserializeMessage(buf)
+	if err != nil {
+		t.Fatalf("DeserializeMessage failed: %s", err)
+	}
+	sigs2, ok := msg.(*BlockSignatures)
+	if !ok {
+		t.Fatalf("unexpected message type %d", msg.Type())
+	}
+	if len(sigs2.Signatures) != 2 || sigs2.Signatures[1].Index != 3 || !bytes.Equal(sigs2.Signatures[1].Signature, []byte{3, 4}) {
+		t.Fatalf("unmatched block signatures: %v", sigs2.Signatures)
+	}
+
+	if _, err := DeserializeMessage(buf[:len(buf)-1]); err == nil {
+		t.Fatalf("truncated block signatures should fail")
+	}
+}
+
+func TestDeserializeInvalidMessage(t *testing.T) {
+	if _, err := DeserializeMessage(nil); err == nil {
+		t.Fatalf("empty message should fail")
+	}
+	if _, err := DeserializeMessage([]byte{0xff, 0}); err == nil {
+		t.Fatalf("unknown message type should fail")
+	}
+}
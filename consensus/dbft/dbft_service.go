@@ -221,14 +221,16 @@ func (ds *DbftService) CheckSignatures() error {
 			return err
 		}
 		if !isExist {
-			// save block
-			result, err := ds.ledger.ExecuteBlock(block)
-			if err != nil {
-				return fmt.Errorf("CheckSignatures DefLedgerPid.RequestFuture Height:%d error:%s", block.Header.Height, err)
+			// the proposal has been executed when the prepare request was made or
+			// accepted, only execute it here if that result is missing
+			if ds.context.GetExecuteResult() == nil {
+				if err := ds.executeProposal(); err != nil {
+					return fmt.Errorf("CheckSignatures %s", err)
+				}
 			}
-			err = ds.ledger.SubmitBlock(block, result)
+			err = ds.ledger.SubmitBlock(block, *ds.context.GetExecuteResult())
 			if err != nil {
-				return fmt.Errorf("CheckSignatures DefLedgerPid.RequestFuture Height:%d error:%s", block.Header.Height, err)
+				return fmt.Errorf("CheckSignatures SubmitBlock Height:%d error:%s", block.Header.Height, err)
 			}
 
 			ds.context.State |= BlockGenerated
@@ -260,6 +262,7 @@ func (ds *DbftService) halt() error {
 
 	if ds.started {
 		ds.sub.Unsubscribe(message.TOPIC_SAVE_BLOCK_COMPLETE)
+		ds.started = false
 	}
 	return nil
 }
@@ -305,7 +308,7 @@ func (ds *DbftService) InitializeConsensus(viewNum byte) error {
 		ds.timeView = viewNum
 
 		ds.timer.Stop()
-		ds.timer.Reset(genesis.GenBlockTime << (viewNum + 1))
+		ds.timer.Reset(GetViewTimeout(genesis.GenBlockTime, viewNum))
 	}
 	return nil
 }
@@ -485,6 +488,13 @@ func (ds *DbftService) PrepareRequestReceived(payload *p2pmsg.ConsensusPayload,
 		return
 	}
 
+	if err := ds.executeProposal(); err != nil {
+		ds.context = backupContext
+		ds.RequestChangeView()
+		log.Errorf("[PrepareRequestReceived] %s", err)
+		return
+	}
+
 	log.Info("send prepare response")
 	ds.context.State |= SignatureSent
 
@@ -585,6 +595,18 @@ func (ds *DbftService) BlockSignaturesReceived(payload *p2pmsg.ConsensusPayload,
 	log.Info("BlockSignatures finished")
 }
 
+// executeProposal executes the block proposed in current context and caches the
+// result, so the block can be submitted to ledger without executing it again.
+func (ds *DbftService) executeProposal() error {
+	block := ds.context.MakeProposal()
+	result, err := ds.ledger.ExecuteBlock(block)
+	if err != nil {
+		return fmt.Errorf("ExecuteBlock Height:%d error:%s", block.Header.Height, err)
+	}
+	ds.context.SetExecuteResult(&result)
+	return nil
+}
+
 func (ds *DbftService) RefreshPolicy() {
 }
 
@@ -592,7 +614,9 @@ func (ds *DbftService) RequestChangeView() {
 	if ds.context.State.HasFlag(BlockGenerated) {
 		return
 	}
-	// FIXME if there is no save block notifcation, when the timeout call this function it will crash
+	if ds.context.BookkeeperIndex < 0 || ds.context.BookkeeperIndex >= len(ds.context.ExpectedView) {
+		return
+	}
 	if ds.context.ViewNumber > ds.context.ExpectedView[ds.context.BookkeeperIndex] {
 		ds.context.ExpectedView[ds.context.BookkeeperIndex] = ds.context.ViewNumber + 1
 	} else {
@@ -602,7 +626,7 @@ func (ds *DbftService) RequestChangeView() {
 		ds.context.ViewNumber, ds.context.ExpectedView[ds.context.BookkeeperIndex], ds.context.GetStateDetail()))
 
 	ds.timer.Stop()
-	ds.timer.Reset(genesis.GenBlockTime << (ds.context.ExpectedView[ds.context.BookkeeperIndex] + 1))
+	ds.timer.Reset(GetViewTimeout(genesis.GenBlockTime, ds.context.ExpectedView[ds.context.BookkeeperIndex]))
 
 	ds.SignAndRelay(ds.context.MakeChangeView())
 	ds.CheckExpectedView(ds.context.ExpectedView[ds.context.BookkeeperIndex])
@@ -699,10 +723,12 @@ func (ds *DbftService) Timeout() {
 				return
 			}
 			ds.context.header = nil
-			//build block and sign
-			block := ds.context.MakeHeader()
-			blockHash := block.Hash()
-			ds.context.Signatures[ds.context.BookkeeperIndex], _ = signature.Sign(ds.Account, blockHash[:])
+			//build block, execute it and sign only if the execution succeeds
+			if err := ds.context.ExecuteAndSignProposal(ds.ledger.ExecuteBlock, ds.Account); err != nil {
+				log.Errorf("[Timeout] %s", err)
+				ds.RequestChangeView()
+				return
+			}
 		}
 		payload := ds.context.MakePrepareRequest()
 		ds.SignAndRelay(payload)
@@ -710,7 +736,7 @@ func (ds *DbftService) Timeout() {
 		ds.blockReceivedTime = time.Now()
 
 		ds.timer.Stop()
-		ds.timer.Reset(genesis.GenBlockTime << (ds.timeView + 1))
+		ds.timer.Reset(GetViewTimeout(genesis.GenBlockTime, ds.timeView))
 	} else if (ds.context.State.HasFlag(Primary) && ds.context.State.HasFlag(RequestSent)) || ds.context.State.HasFlag(Backup) {
 		ds.RequestChangeView()
 	}
@@ -66,12 +66,18 @@ func (pr *PrepareRequest) Deserialization(source *common.ZeroCopySource) error {
 	}
 	pr.Nonce = nonce
 	pr.NextBookkeeper, eof = source.NextAddress()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
 
 	var length uint64
 	length, _, irregular, eof = source.NextVarUint()
 	if irregular {
 		return common.ErrIrregularData
 	}
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
 
 	for i := 0; i < int(length); i++ {
 		var t types.Transaction
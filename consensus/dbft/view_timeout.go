@@ -0,0 +1,37 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package dbft
+
+import (
+	"time"
+)
+
+// MaxViewBackoff limits the exponential growth of the view change timeout,
+// the timeout of view v is genBlockTime << min(v+1, MaxViewBackoff).
+const MaxViewBackoff = 6
+
+// GetViewTimeout returns how long a bookkeeper waits in the given view before
+// requesting a view change.
+func GetViewTimeout(genBlockTime time.Duration, viewNum byte) time.Duration {
+	shift := uint(viewNum) + 1
+	if shift > MaxViewBackoff {
+		shift = MaxViewBackoff
+	}
+	return genBlockTime << shift
+}
@@ -20,6 +20,7 @@ package solo
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"time"
 
@@ -49,6 +50,9 @@ type SoloService struct {
 	incrValidator    *increment.IncrementValidator
 	existCh          chan interface{}
 	genBlockInterval time.Duration
+	genBlockOnDemand bool   // seal a block as soon as a transaction enters the tx pool
+	timeOffset       uint32 // seconds added to the timestamp of new blocks
+	started          bool
 	pid              *actor.PID
 	sub              *events.ActorSubscriber
 }
@@ -59,6 +63,7 @@ func NewSoloService(bkAccount *account.Account, txpool *actor.PID) (*SoloService
 		poolActor:        &actorTypes.TxPoolActor{Pool: txpool},
 		incrValidator:    increment.NewIncrementValidator(20),
 		genBlockInterval: time.Duration(config.DefConfig.Genesis.SOLO.GenBlockTime) * time.Second,
+		genBlockOnDemand: config.DefConfig.Genesis.SOLO.GenBlockOnDemand,
 	}
 
 	props := actor.FromProducer(func() actor.Actor {
@@ -85,12 +90,18 @@ func (self *SoloService) Receive(context actor.Context) {
 	case *actor.Restart:
 		log.Info("solo actor restart")
 	case *actorTypes.StartConsensus:
-		if self.existCh != nil {
+		if self.started {
 			log.Info("consensus have started")
 			return
 		}
+		self.started = true
 
 		self.sub.Subscribe(message.TOPIC_SAVE_BLOCK_COMPLETE)
+		if self.genBlockOnDemand {
+			log.Info("solo generates block on demand")
+			self.sub.Subscribe(message.TOPIC_NEW_TRANSACTION)
+			return
+		}
 
 		timer := time.NewTicker(self.genBlockInterval)
 		self.existCh = make(chan interface{})
@@ -107,16 +118,68 @@ func (self *SoloService) Receive(context actor.Context) {
 			}
 		}()
 	case *actorTypes.StopConsensus:
+		if !self.started {
+			return
+		}
+		self.started = false
 		if self.existCh != nil {
 			close(self.existCh)
 			self.existCh = nil
-			self.incrValidator.Clean()
-			self.sub.Unsubscribe(message.TOPIC_SAVE_BLOCK_COMPLETE)
 		}
+		if self.genBlockOnDemand {
+			self.sub.Unsubscribe(message.TOPIC_NEW_TRANSACTION)
+		}
+		self.incrValidator.Clean()
+		self.sub.Unsubscribe(message.TOPIC_SAVE_BLOCK_COMPLETE)
 	case *message.SaveBlockCompleteMsg:
 		log.Infof("solo actor receives block complete event. block height=%d txnum=%d", msg.Block.Header.Height, len(msg.Block.Transactions))
 		self.incrValidator.AddBlock(msg.Block)
 
+	case *message.NewTransactionMsg:
+		// the transaction may have been sealed together with a previous one
+		if exist, _ := ledger.DefLedger.IsContainTransaction(msg.Tx.Hash()); exist {
+			return
+		}
+		transactions := self.getTransactions()
+		if len(transactions) == 0 {
+			return
+		}
+		block, err := self.makeBlock(transactions)
+		if err == nil {
+			err = self.saveBlock(block)
+		}
+		if err != nil {
+			log.Errorf("Solo genBlock error %s", err)
+		}
+
+	case *actorTypes.GenBlocks:
+		rsp := &actorTypes.GenBlocksRsp{}
+		for i := uint32(0); i < msg.Count; i++ {
+			if err := self.genEmptyBlock(); err != nil {
+				rsp.Error = err
+				break
+			}
+		}
+		rsp.Height = ledger.DefLedger.GetCurrentBlockHeight()
+		if sender := context.Sender(); sender != nil {
+			sender.Request(rsp, context.Self())
+		}
+
+	case *actorTypes.IncreaseTime:
+		rsp := &actorTypes.IncreaseTimeRsp{}
+		offset, err := increaseTimeOffset(uint32(time.Now().Unix()), self.timeOffset, msg.Seconds)
+		if err != nil {
+			log.Errorf("solo increase time error:%s", err)
+			rsp.Error = err
+		} else {
+			self.timeOffset = offset
+			log.Infof("solo block timestamp offset increased to %ds", self.timeOffset)
+		}
+		rsp.Offset = self.timeOffset
+		if sender := context.Sender(); sender != nil {
+			sender.Request(rsp, context.Self())
+		}
+
 	case *actorTypes.TimeOut:
 		err := self.genBlock()
 		if err != nil {
@@ -142,11 +205,23 @@ func (self *SoloService) Halt() error {
 }
 
 func (self *SoloService) genBlock() error {
-	block, err := self.makeBlock()
+	block, err := self.makeBlock(self.getTransactions())
 	if err != nil {
 		return fmt.Errorf("makeBlock error %s", err)
 	}
+	return self.saveBlock(block)
+}
+
+// genEmptyBlock seals a block without transactions, the tx pool is left untouched.
+func (self *SoloService) genEmptyBlock() error {
+	block, err := self.makeBlock(nil)
+	if err != nil {
+		return fmt.Errorf("makeBlock error %s", err)
+	}
+	return self.saveBlock(block)
+}
 
+func (self *SoloService) saveBlock(block *types.Block) error {
 	result, err := ledger.DefLedger.ExecuteBlock(block)
 	if err != nil {
 		return fmt.Errorf("genBlock DefLedgerPid.RequestFuture Height:%d error:%s", block.Header.Height, err)
@@ -158,16 +233,8 @@ func (self *SoloService) genBlock() error {
 	return nil
 }
 
-func (self *SoloService) makeBlock() (*types.Block, error) {
-	log.Debug()
-	owner := self.Account.PublicKey
-	nextBookkeeper, err := types.AddressFromBookkeepers([]keypair.PublicKey{owner})
-	if err != nil {
-		return nil, fmt.Errorf("GetBookkeeperAddress error:%s", err)
-	}
-	prevHash := ledger.DefLedger.GetCurrentBlockHash()
+func (self *SoloService) getTransactions() []*types.Transaction {
 	height := ledger.DefLedger.GetCurrentBlockHeight()
-
 	validHeight := height
 
 	start, end := self.incrValidator.BlockRange()
@@ -190,6 +257,64 @@ func (self *SoloService) makeBlock() (*types.Block, error) {
 			transactions = append(transactions, txEntry.Tx)
 		}
 	}
+	return transactions
+}
+
+// getBlockTimestamp returns the timestamp of next block, which is shifted by the
+// configured offset and always later than the previous block.
+func (self *SoloService) getBlockTimestamp(prevHash common.Uint256) (uint32, error) {
+	prevHeader, err := ledger.DefLedger.GetHeaderByHash(prevHash)
+	if err != nil {
+		return 0, fmt.Errorf("GetHeaderByHash error:%s", err)
+	}
+	prevTimestamp := uint32(0)
+	if prevHeader != nil {
+		prevTimestamp = prevHeader.Timestamp
+	}
+	return nextBlockTimestamp(uint32(time.Now().Unix()), self.timeOffset, prevTimestamp)
+}
+
+// increaseTimeOffset adds seconds to offset, and rejects the increase if the shifted
+// timestamp of now no longer fits in uint32.
+func increaseTimeOffset(now, offset, seconds uint32) (uint32, error) {
+	if seconds > math.MaxUint32-offset {
+		return offset, fmt.Errorf("time offset %d plus %d overflows", offset, seconds)
+	}
+	if offset+seconds > math.MaxUint32-now {
+		return offset, fmt.Errorf("block timestamp %d plus offset %d overflows", now, offset+seconds)
+	}
+	return offset + seconds, nil
+}
+
+// nextBlockTimestamp returns now shifted by offset, and at least one second after the
+// previous block. Timestamps overflowing uint32 are rejected rather than wrapped.
+func nextBlockTimestamp(now, offset, prevTimestamp uint32) (uint32, error) {
+	if offset > math.MaxUint32-now {
+		return 0, fmt.Errorf("block timestamp %d plus offset %d overflows", now, offset)
+	}
+	timestamp := now + offset
+	if timestamp <= prevTimestamp {
+		if prevTimestamp == math.MaxUint32 {
+			return 0, fmt.Errorf("previous block timestamp %d reaches the maximum", prevTimestamp)
+		}
+		timestamp = prevTimestamp + 1
+	}
+	return timestamp, nil
+}
+
+func (self *SoloService) makeBlock(transactions []*types.Transaction) (*types.Block, error) {
+	log.Debug()
+	owner := self.Account.PublicKey
+	nextBookkeeper, err := types.AddressFromBookkeepers([]keypair.PublicKey{owner})
+	if err != nil {
+		return nil, fmt.Errorf("GetBookkeeperAddress error:%s", err)
+	}
+	prevHash := ledger.DefLedger.GetCurrentBlockHash()
+	height := ledger.DefLedger.GetCurrentBlockHeight()
+	timestamp, err := self.getBlockTimestamp(prevHash)
+	if err != nil {
+		return nil, err
+	}
 
 	txHash := []common.Uint256{}
 	for _, t := range transactions {
@@ -203,7 +328,7 @@ func (self *SoloService) makeBlock() (*types.Block, error) {
 		PrevBlockHash:    prevHash,
 		TransactionsRoot: txRoot,
 		BlockRoot:        blockRoot,
-		Timestamp:        uint32(time.Now().Unix()),
+		Timestamp:        timestamp,
 		Height:           height + 1,
 		ConsensusData:    common.GetNonce(),
 		NextBookkeeper:   nextBookkeeper,
@@ -0,0 +1,150 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package solo
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/ontio/ontology-eventbus/eventhub"
+	actorTypes "github.com/ontio/ontology/consensus/actor"
+	"github.com/ontio/ontology/events"
+	"github.com/ontio/ontology/events/message"
+	"github.com/ontio/ontology/validator/increment"
+)
+
+//testContext delivers message to SoloService.Receive without sender
+type testContext struct {
+	actor.Context
+	msg interface{}
+}
+
+func (this *testContext) Message() interface{} {
+	return this.msg
+}
+
+func (this *testContext) Sender() *actor.PID {
+	return nil
+}
+
+func TestNextBlockTimestamp(t *testing.T) {
+	timestamp, err := nextBlockTimestamp(1000, 50, 900)
+	if err != nil || timestamp != 1050 {
+		t.Fatalf("expected 1050, got %d error:%v", timestamp, err)
+	}
+	//never earlier than previous block
+	timestamp, err = nextBlockTimestamp(1000, 50, 2000)
+	if err != nil || timestamp != 2001 {
+		t.Fatalf("expected 2001, got %d error:%v", timestamp, err)
+	}
+	if _, err = nextBlockTimestamp(math.MaxUint32-10, 11, 0); err == nil {
+		t.Fatalf("timestamp overflow should be rejected")
+	}
+	if _, err = nextBlockTimestamp(1000, 0, math.MaxUint32); err == nil {
+		t.Fatalf("previous timestamp overflow should be rejected")
+	}
+}
+
+func TestIncreaseTimeOffset(t *testing.T) {
+	offset, err := increaseTimeOffset(1000, 10, 20)
+	if err != nil || offset != 30 {
+		t.Fatalf("expected 30, got %d error:%v", offset, err)
+	}
+	if offset, err = increaseTimeOffset(1000, math.MaxUint32-5, 10); err == nil || offset != math.MaxUint32-5 {
+		t.Fatalf("offset overflow should be rejected, got %d", offset)
+	}
+	if offset, err = increaseTimeOffset(math.MaxUint32-100, 50, 60); err == nil || offset != 50 {
+		t.Fatalf("timestamp overflow should be rejected, got %d", offset)
+	}
+}
+
+func TestReceiveIncreaseTime(t *testing.T) {
+	service := &SoloService{}
+	service.Receive(&testContext{msg: &actorTypes.IncreaseTime{Seconds: 3600}})
+	if service.timeOffset != 3600 {
+		t.Fatalf("expected offset 3600, got %d", service.timeOffset)
+	}
+	service.Receive(&testContext{msg: &actorTypes.IncreaseTime{Seconds: math.MaxUint32}})
+	if service.timeOffset != 3600 {
+		t.Fatalf("overflowed increase should keep offset 3600, got %d", service.timeOffset)
+	}
+}
+
+func newTestService(onDemand bool) (*SoloService, chan interface{}) {
+	received := make(chan interface{}, 10)
+	pid := actor.Spawn(actor.FromFunc(func(context actor.Context) {
+		if msg, ok := context.Message().(*message.NewTransactionMsg); ok {
+			received <- msg
+		}
+	}))
+	service := &SoloService{
+		incrValidator:    increment.NewIncrementValidator(20),
+		genBlockInterval: time.Hour,
+		genBlockOnDemand: onDemand,
+		pid:              pid,
+		sub:              events.NewActorSubscriber(pid, eventhub.GlobalEventHub),
+	}
+	return service, received
+}
+
+func publishNewTx(received chan interface{}) bool {
+	publisher := events.NewActorPublisher(actor.Spawn(actor.FromFunc(func(actor.Context) {})), eventhub.GlobalEventHub)
+	publisher.Publish(message.TOPIC_NEW_TRANSACTION, &message.NewTransactionMsg{})
+	select {
+	case <-received:
+		return true
+	case <-time.After(200 * time.Millisecond):
+		return false
+	}
+}
+
+func TestGenBlockOnDemand(t *testing.T) {
+	service, received := newTestService(true)
+	service.Receive(&testContext{msg: &actorTypes.StartConsensus{}})
+	if !service.started || service.existCh != nil {
+		t.Fatalf("on demand solo should not start block timer")
+	}
+	if !publishNewTx(received) {
+		t.Fatalf("on demand solo should subscribe new transaction")
+	}
+	service.Receive(&testContext{msg: &actorTypes.StopConsensus{}})
+	if service.started {
+		t.Fatalf("solo should be stopped")
+	}
+	if publishNewTx(received) {
+		t.Fatalf("stopped solo should unsubscribe new transaction")
+	}
+}
+
+func TestGenBlockByInterval(t *testing.T) {
+	service, received := newTestService(false)
+	service.Receive(&testContext{msg: &actorTypes.StartConsensus{}})
+	if !service.started || service.existCh == nil {
+		t.Fatalf("solo should start block timer")
+	}
+	if publishNewTx(received) {
+		t.Fatalf("solo should not subscribe new transaction without on demand")
+	}
+	service.Receive(&testContext{msg: &actorTypes.StopConsensus{}})
+	if service.started || service.existCh != nil {
+		t.Fatalf("solo block timer should be stopped")
+	}
+}
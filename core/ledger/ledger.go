@@ -26,6 +26,7 @@ import (
 	"github.com/ontio/ontology/core/payload"
 	"github.com/ontio/ontology/core/states"
 	"github.com/ontio/ontology/core/store"
+	scom "github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/store/ledgerstore"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/smartcontract/event"
@@ -176,6 +177,15 @@ func (self *Ledger) PreExecuteContract(tx *types.Transaction) (*cstate.PreExecRe
 	return self.ldgStore.PreExecuteContract(tx)
 }
 
+func (self *Ledger) DebugExecuteContract(tx *types.Transaction, height uint32,
+	overrides *cstate.StateOverrides) (*cstate.DebugExecResult, error) {
+	return self.ldgStore.DebugExecuteContract(tx, height, overrides)
+}
+
+func (self *Ledger) TraceTransaction(txHash common.Uint256) (*cstate.TxTraceResult, error) {
+	return self.ldgStore.TraceTransaction(txHash)
+}
+
 func (self *Ledger) GetEventNotifyByTx(tx common.Uint256) (*event.ExecuteNotify, error) {
 	return self.ldgStore.GetEventNotifyByTx(tx)
 }
@@ -184,6 +194,32 @@ func (self *Ledger) GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNotify
 	return self.ldgStore.GetEventNotifyByBlock(height)
 }
 
+func (self *Ledger) GetEventNotifyByContract(contract common.Address, topic string, startHeight, endHeight uint32,
+	offset, limit uint32) ([]*event.ContractEventNotify, error) {
+	return self.ldgStore.GetEventNotifyByContract(contract, topic, startHeight, endHeight, offset, limit)
+}
+
+func (self *Ledger) GetAddressTxs(addr common.Address, startHeight, endHeight uint32,
+	offset, limit uint32) ([]*scom.AddressTx, error) {
+	return self.ldgStore.GetAddressTxs(addr, startHeight, endHeight, offset, limit)
+}
+
+func (self *Ledger) GetIndexStartHeight(prefix scom.DataEntryPrefix) (uint32, error) {
+	return self.ldgStore.GetIndexStartHeight(prefix)
+}
+
+func (self *Ledger) GetContractAbi(contract common.Address) ([]byte, error) {
+	return self.ldgStore.GetContractAbi(contract)
+}
+
+func (self *Ledger) PutContractAbi(contract common.Address, abiData []byte) error {
+	return self.ldgStore.PutContractAbi(contract, abiData)
+}
+
+func (self *Ledger) DeleteContractAbi(contract common.Address) error {
+	return self.ldgStore.DeleteContractAbi(contract)
+}
+
 func (self *Ledger) Close() error {
 	return self.ldgStore.Close()
 }
@@ -24,12 +24,13 @@ import (
 )
 
 // Signer is the abstract interface of user's information(Keys) for signing data.
+// The private key may be kept in a HSM or remote signer, see account.Signer.
 type Signer interface {
-	//get signer's private key
-	PrivKey() keypair.PrivateKey
-
 	//get signer's public key
 	PubKey() keypair.PublicKey
 
 	Scheme() signature.SignatureScheme
+
+	//sign data and return the serialized signature
+	Sign(data []byte) ([]byte, error)
 }
@@ -44,5 +44,10 @@ const (
 	SYS_BLOCK_MERKLE_TREE  DataEntryPrefix = 0x13 // Block merkle tree root key prefix
 	SYS_STATE_MERKLE_TREE  DataEntryPrefix = 0x20 // state merkle tree root key prefix
 
-	EVENT_NOTIFY DataEntryPrefix = 0x14 //Event notify key prefix
+	EVENT_NOTIFY             DataEntryPrefix = 0x14 //Event notify key prefix
+	EVENT_NOTIFY_BY_CONTRACT DataEntryPrefix = 0x15 //Contract address + height + tx hash + event index => event notify index key prefix
+	EVENT_NOTIFY_BY_TOPIC    DataEntryPrefix = 0x16 //Contract address + topic hash + height + tx hash + event index => event notify index key prefix
+	IX_ADDRESS_TX            DataEntryPrefix = 0x17 //Address + height + tx hash => address transaction index key prefix
+	CONTRACT_ABI             DataEntryPrefix = 0x18 //Contract address => NeoVM contract abi key prefix
+	SYS_INDEX_START_HEIGHT   DataEntryPrefix = 0x19 //Index key prefix => height from which the index is built
 )
@@ -52,6 +52,8 @@ type PersistStore interface {
 	BatchCommit() error                      //Commit batch to store
 	Close() error                            //Close store
 	NewIterator(prefix []byte) StoreIterator //Return the iterator of store
+	//Return the iterator of store with the key prefix, starting from the first key not less than start
+	NewSeekIterator(prefix []byte, start []byte) StoreIterator
 }
 
 //StateStore save result of smart contract execution, before commit to store
@@ -90,6 +92,8 @@ type EventStore interface {
 	SaveEventNotifyByBlock(height uint32, txHashs []common.Uint256) error
 	//GetEventNotifyByTx return event notify by transaction hash
 	GetEventNotifyByTx(txHash common.Uint256) (*event.ExecuteNotify, error)
+	//SaveEventNotifyIndex save the index of event notify by contract address and topic
+	SaveEventNotifyIndex(height uint32, notify *event.ExecuteNotify)
 	//Commit event notify to store
 	CommitTo() error
 }
@@ -116,3 +120,9 @@ func (e *StateItem) Copy() *StateItem {
 	c := *e
 	return &c
 }
+
+//Transaction touching an address, found by address index
+type AddressTx struct {
+	Height uint32         //Block height of transaction
+	TxHash common.Uint256 //Transaction hash
+}
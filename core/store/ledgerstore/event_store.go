@@ -20,6 +20,7 @@ package ledgerstore
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
@@ -29,12 +30,27 @@ import (
 	scom "github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/store/leveldbstore"
 	"github.com/ontio/ontology/smartcontract/event"
+	"sync"
 )
 
+//length of height + tx hash + event index in event notify index key
+const EVENT_INDEX_SUFFIX_LEN = 4 + common.UINT256_SIZE + 4
+
+//INDEX_QUERY_MAX_SCAN bound the index records scanned by a query of event notifies or address transactions,
+//including the records skipped by offset
+const INDEX_QUERY_MAX_SCAN = 100000
+
+//CONTRACT_ABI_MAX_MISSES bound the cached contracts without abi, so that querying random addresses cannot
+//exhaust memory. They are dropped from cache when reaching it
+const CONTRACT_ABI_MAX_MISSES = 10000
+
 //Saving event notifies gen by smart contract execution
 type EventStore struct {
-	dbDir string                     //Store path
-	store *leveldbstore.LevelDBStore //Store handler
+	dbDir     string                     //Store path
+	store     *leveldbstore.LevelDBStore //Store handler
+	abiLock   sync.Mutex
+	abis      map[common.Address][]byte //Cached abi of contracts, nil for contract without abi
+	abiMisses int
 }
 
 //NewEventStore return event store instance
@@ -46,6 +62,7 @@ func NewEventStore(dbDir string) (*EventStore, error) {
 	return &EventStore{
 		dbDir: dbDir,
 		store: store,
+		abis:  make(map[common.Address][]byte),
 	}, nil
 }
 
@@ -134,6 +151,229 @@ func (this *EventStore) GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNo
 	return evtNotifies, nil
 }
 
+//SaveEventNotifyIndex persist the index of event notify by contract address and topic
+func (this *EventStore) SaveEventNotifyIndex(height uint32, notify *event.ExecuteNotify) {
+	for index, notifyInfo := range notify.Notify {
+		key := this.getEventNotifyByContractKey(notifyInfo.ContractAddress, height, notify.TxHash, uint32(index))
+		this.store.BatchPut(key, []byte{})
+		topic := notifyInfo.Topic()
+		if topic == "" {
+			continue
+		}
+		key = this.getEventNotifyByTopicKey(notifyInfo.ContractAddress, topic, height, notify.TxHash, uint32(index))
+		this.store.BatchPut(key, []byte{})
+	}
+}
+
+//GetEventNotifyByContract return the event notifies of contract in height range [startHeight, endHeight],
+//filter by topic if topic is not empty. The first offset event notifies are skipped, and at most limit
+//event notifies are returned. The index is read from startHeight, and the query fails when more than
+//INDEX_QUERY_MAX_SCAN records are scanned
+func (this *EventStore) GetEventNotifyByContract(contract common.Address, topic string, startHeight, endHeight uint32,
+	offset, limit uint32) ([]*event.ContractEventNotify, error) {
+	var prefix []byte
+	if topic == "" {
+		prefix = this.getEventNotifyByContractKey(contract, 0, common.UINT256_EMPTY, 0)
+	} else {
+		prefix = this.getEventNotifyByTopicKey(contract, topic, 0, common.UINT256_EMPTY, 0)
+	}
+	prefix = prefix[:len(prefix)-EVENT_INDEX_SUFFIX_LEN]
+
+	evtNotifies := make([]*event.ContractEventNotify, 0)
+	execNotifies := make(map[common.Uint256]*event.ExecuteNotify)
+	iter := this.store.NewSeekIterator(prefix, appendHeight(prefix, startHeight))
+	defer iter.Release()
+	scanned := 0
+	for has := iter.First(); has && uint32(len(evtNotifies)) < limit; has = iter.Next() {
+		scanned++
+		if scanned > INDEX_QUERY_MAX_SCAN {
+			return nil, fmt.Errorf("more than %d event notifies scanned, narrow the height range", INDEX_QUERY_MAX_SCAN)
+		}
+		key := iter.Key()
+		if len(key) != len(prefix)+EVENT_INDEX_SUFFIX_LEN {
+			continue
+		}
+		suffix := key[len(prefix):]
+		height := binary.BigEndian.Uint32(suffix[:4])
+		if height > endHeight {
+			break
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		var txHash common.Uint256
+		copy(txHash[:], suffix[4:4+common.UINT256_SIZE])
+		index := binary.BigEndian.Uint32(suffix[4+common.UINT256_SIZE:])
+
+		execNotify, ok := execNotifies[txHash]
+		if !ok {
+			var err error
+			execNotify, err = this.GetEventNotifyByTx(txHash)
+			if err != nil {
+				return nil, fmt.Errorf("GetEventNotifyByTx %s error %s", txHash.ToHexString(), err)
+			}
+			execNotifies[txHash] = execNotify
+		}
+		if int(index) >= len(execNotify.Notify) {
+			return nil, fmt.Errorf("event index %d of tx %s out of range", index, txHash.ToHexString())
+		}
+		evtNotifies = append(evtNotifies, &event.ContractEventNotify{
+			Height:     height,
+			TxHash:     txHash,
+			EventIndex: index,
+			Notify:     execNotify.Notify[index],
+		})
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return evtNotifies, nil
+}
+
+//SaveAddressTxIndex persist the index of transaction by the addresses it touches
+func (this *EventStore) SaveAddressTxIndex(height uint32, txHash common.Uint256, addrs []common.Address) {
+	for _, addr := range addrs {
+		this.store.BatchPut(this.getAddressTxKey(addr, height, txHash), []byte{})
+	}
+}
+
+//SaveContractAbi persist the NeoVM abi of contract in batch, used when saving block. The abi is cached
+//before batch committed, so that it is not shadowed by contract without abi cached meanwhile
+func (this *EventStore) SaveContractAbi(contract common.Address, abiData []byte) {
+	this.abiLock.Lock()
+	defer this.abiLock.Unlock()
+	this.store.BatchPut(this.getContractAbiKey(contract), abiData)
+	this.cacheContractAbi(contract, abiData)
+}
+
+//PutContractAbi persist the NeoVM abi of contract immediately, used by abi registry
+func (this *EventStore) PutContractAbi(contract common.Address, abiData []byte) error {
+	this.abiLock.Lock()
+	defer this.abiLock.Unlock()
+	err := this.store.Put(this.getContractAbiKey(contract), abiData)
+	if err != nil {
+		return err
+	}
+	this.cacheContractAbi(contract, abiData)
+	return nil
+}
+
+//GetContractAbi return the NeoVM abi json of contract, scom.ErrNotFound if contract has no abi
+func (this *EventStore) GetContractAbi(contract common.Address) ([]byte, error) {
+	this.abiLock.Lock()
+	defer this.abiLock.Unlock()
+	abiData, ok := this.abis[contract]
+	if !ok {
+		var err error
+		abiData, err = this.store.Get(this.getContractAbiKey(contract))
+		if err != nil && err != scom.ErrNotFound {
+			return nil, err
+		}
+		this.cacheContractAbi(contract, abiData)
+	}
+	if abiData == nil {
+		return nil, scom.ErrNotFound
+	}
+	return abiData, nil
+}
+
+//DeleteContractAbi delete the NeoVM abi of contract
+func (this *EventStore) DeleteContractAbi(contract common.Address) error {
+	this.abiLock.Lock()
+	defer this.abiLock.Unlock()
+	err := this.store.Delete(this.getContractAbiKey(contract))
+	if err != nil {
+		return err
+	}
+	this.cacheContractAbi(contract, nil)
+	return nil
+}
+
+//cacheContractAbi cache abi of contract, nil for contract without abi. Should be called under abiLock
+func (this *EventStore) cacheContractAbi(contract common.Address, abiData []byte) {
+	old, ok := this.abis[contract]
+	if ok && old == nil {
+		this.abiMisses--
+	}
+	if abiData == nil {
+		if this.abiMisses >= CONTRACT_ABI_MAX_MISSES {
+			for addr, cached := range this.abis {
+				if cached == nil {
+					delete(this.abis, addr)
+				}
+			}
+			this.abiMisses = 0
+		}
+		this.abiMisses++
+	}
+	this.abis[contract] = abiData
+}
+
+//GetAddressTxs return the transactions touching address in height range [startHeight, endHeight]. The first
+//offset transactions are skipped, and at most limit transactions are returned. The index is read from
+//startHeight, and the query fails when more than INDEX_QUERY_MAX_SCAN records are scanned
+func (this *EventStore) GetAddressTxs(addr common.Address, startHeight, endHeight uint32,
+	offset, limit uint32) ([]*scom.AddressTx, error) {
+	prefix := this.getAddressTxKey(addr, 0, common.UINT256_EMPTY)
+	prefix = prefix[:len(prefix)-4-common.UINT256_SIZE]
+
+	txs := make([]*scom.AddressTx, 0)
+	iter := this.store.NewSeekIterator(prefix, appendHeight(prefix, startHeight))
+	defer iter.Release()
+	scanned := 0
+	for has := iter.First(); has && uint32(len(txs)) < limit; has = iter.Next() {
+		scanned++
+		if scanned > INDEX_QUERY_MAX_SCAN {
+			return nil, fmt.Errorf("more than %d transactions scanned, narrow the height range", INDEX_QUERY_MAX_SCAN)
+		}
+		key := iter.Key()
+		if len(key) != len(prefix)+4+common.UINT256_SIZE {
+			continue
+		}
+		height := binary.BigEndian.Uint32(key[len(prefix):])
+		if height > endHeight {
+			break
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		addrTx := &scom.AddressTx{Height: height}
+		copy(addrTx.TxHash[:], key[len(prefix)+4:])
+		txs = append(txs, addrTx)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+//SaveIndexStartHeight persist the height from which the index of prefix is built, in batch
+func (this *EventStore) SaveIndexStartHeight(prefix scom.DataEntryPrefix, height uint32) {
+	value := make([]byte, 4)
+	binary.BigEndian.PutUint32(value, height)
+	this.store.BatchPut(this.getIndexStartHeightKey(prefix), value)
+}
+
+//GetIndexStartHeight return the height from which the index of prefix is built, return
+//scom.ErrNotFound if the index has not been built
+func (this *EventStore) GetIndexStartHeight(prefix scom.DataEntryPrefix) (uint32, error) {
+	value, err := this.store.Get(this.getIndexStartHeightKey(prefix))
+	if err != nil {
+		return 0, err
+	}
+	if len(value) != 4 {
+		return 0, fmt.Errorf("invalid index start height length %d", len(value))
+	}
+	return binary.BigEndian.Uint32(value), nil
+}
+
+//DeleteIndexStartHeight delete the start height of index in batch, used when the index is disabled
+func (this *EventStore) DeleteIndexStartHeight(prefix scom.DataEntryPrefix) {
+	this.store.BatchDelete(this.getIndexStartHeightKey(prefix))
+}
+
 //CommitTo event store batch to store
 func (this *EventStore) CommitTo() error {
 	return this.store.BatchCommit()
@@ -207,3 +447,54 @@ func (this *EventStore) getEventNotifyByTxKey(txHash common.Uint256) []byte {
 	copy(key[1:], data)
 	return key
 }
+
+func (this *EventStore) getEventNotifyByContractKey(contract common.Address, height uint32, txHash common.Uint256, index uint32) []byte {
+	key := make([]byte, 0, 1+common.ADDR_LEN+EVENT_INDEX_SUFFIX_LEN)
+	key = append(key, byte(scom.EVENT_NOTIFY_BY_CONTRACT))
+	key = append(key, contract[:]...)
+	return appendEventIndexSuffix(key, height, txHash, index)
+}
+
+func (this *EventStore) getEventNotifyByTopicKey(contract common.Address, topic string, height uint32, txHash common.Uint256, index uint32) []byte {
+	topicHash := sha256.Sum256([]byte(topic))
+	key := make([]byte, 0, 1+common.ADDR_LEN+len(topicHash)+EVENT_INDEX_SUFFIX_LEN)
+	key = append(key, byte(scom.EVENT_NOTIFY_BY_TOPIC))
+	key = append(key, contract[:]...)
+	key = append(key, topicHash[:]...)
+	return appendEventIndexSuffix(key, height, txHash, index)
+}
+
+//appendEventIndexSuffix append height, tx hash and event index, height is big endian to keep keys in height order
+func appendEventIndexSuffix(key []byte, height uint32, txHash common.Uint256, index uint32) []byte {
+	key = appendHeight(key, height)
+	key = append(key, txHash[:]...)
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], index)
+	return append(key, buf[:]...)
+}
+
+//appendHeight return a copy of key with the big endian height appended, which is also the first index key
+//of the height under the key prefix
+func appendHeight(key []byte, height uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], height)
+	return append(append([]byte(nil), key...), buf[:]...)
+}
+
+func (this *EventStore) getIndexStartHeightKey(prefix scom.DataEntryPrefix) []byte {
+	return []byte{byte(scom.SYS_INDEX_START_HEIGHT), byte(prefix)}
+}
+
+func (this *EventStore) getContractAbiKey(contract common.Address) []byte {
+	key := make([]byte, 0, 1+common.ADDR_LEN)
+	key = append(key, byte(scom.CONTRACT_ABI))
+	return append(key, contract[:]...)
+}
+
+func (this *EventStore) getAddressTxKey(addr common.Address, height uint32, txHash common.Uint256) []byte {
+	key := make([]byte, 0, 1+common.ADDR_LEN+4+common.UINT256_SIZE)
+	key = append(key, byte(scom.IX_ADDRESS_TX))
+	key = append(key, addr[:]...)
+	key = appendHeight(key, height)
+	return append(key, txHash[:]...)
+}
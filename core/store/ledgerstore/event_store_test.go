@@ -0,0 +1,263 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	scom "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/smartcontract/event"
+)
+
+func TestEventNotifyByContract(t *testing.T) {
+	eventStore, err := NewEventStore("test/event")
+	if err != nil {
+		t.Fatalf("NewEventStore error %s", err)
+	}
+	defer eventStore.Close()
+
+	contract := common.Address{1}
+	other := common.Address{2}
+	eventStore.NewBatch()
+	for height := uint32(1); height <= 5; height++ {
+		notify := &event.ExecuteNotify{
+			TxHash: common.Uint256{byte(height)},
+			State:  event.CONTRACT_STATE_SUCCESS,
+			Notify: []*event.NotifyEventInfo{
+				{ContractAddress: contract, States: []interface{}{"transfer", height}},
+				{ContractAddress: other, States: []interface{}{"transfer", height}},
+				{ContractAddress: contract, States: "approve"},
+			},
+		}
+		if err := eventStore.SaveEventNotifyByTx(notify.TxHash, notify); err != nil {
+			t.Fatalf("SaveEventNotifyByTx error %s", err)
+		}
+		eventStore.SaveEventNotifyIndex(height, notify)
+	}
+	if err := eventStore.CommitTo(); err != nil {
+		t.Fatalf("CommitTo error %s", err)
+	}
+
+	evts, err := eventStore.GetEventNotifyByContract(contract, "", 0, math.MaxUint32, 0, 100)
+	if err != nil {
+		t.Fatalf("GetEventNotifyByContract error %s", err)
+	}
+	if len(evts) != 10 {
+		t.Fatalf("event count %d != 10", len(evts))
+	}
+
+	evts, err = eventStore.GetEventNotifyByContract(contract, "transfer", 2, 4, 0, 100)
+	if err != nil {
+		t.Fatalf("GetEventNotifyByContract error %s", err)
+	}
+	if len(evts) != 3 {
+		t.Fatalf("event count %d != 3", len(evts))
+	}
+	for i, evt := range evts {
+		if evt.Height != uint32(i+2) || evt.EventIndex != 0 || evt.Notify.ContractAddress != contract {
+			t.Fatalf("unexpected event %+v", evt)
+		}
+	}
+
+	evts, err = eventStore.GetEventNotifyByContract(contract, "approve", 0, math.MaxUint32, 1, 2)
+	if err != nil {
+		t.Fatalf("GetEventNotifyByContract error %s", err)
+	}
+	if len(evts) != 2 || evts[0].Height != 2 || evts[1].Height != 3 || evts[0].EventIndex != 2 {
+		t.Fatalf("unexpected page %+v", evts)
+	}
+
+	evts, err = eventStore.GetEventNotifyByContract(common.Address{3}, "", 0, math.MaxUint32, 0, 100)
+	if err != nil {
+		t.Fatalf("GetEventNotifyByContract error %s", err)
+	}
+	if len(evts) != 0 {
+		t.Fatalf("unexpected events of unknown contract")
+	}
+}
+
+func TestAddressTxs(t *testing.T) {
+	eventStore, err := NewEventStore("test/addresstx")
+	if err != nil {
+		t.Fatalf("NewEventStore error %s", err)
+	}
+	defer eventStore.Close()
+
+	addr := common.Address{1}
+	eventStore.NewBatch()
+	for height := uint32(1); height <= 5; height++ {
+		eventStore.SaveAddressTxIndex(height, common.Uint256{byte(height)}, []common.Address{addr, {2}})
+	}
+	if err := eventStore.CommitTo(); err != nil {
+		t.Fatalf("CommitTo error %s", err)
+	}
+
+	txs, err := eventStore.GetAddressTxs(addr, 2, 5, 1, 2)
+	if err != nil {
+		t.Fatalf("GetAddressTxs error %s", err)
+	}
+	if len(txs) != 2 || txs[0].Height != 3 || txs[1].Height != 4 || txs[0].TxHash != (common.Uint256{3}) {
+		t.Fatalf("unexpected page %+v", txs)
+	}
+	txs, err = eventStore.GetAddressTxs(common.Address{3}, 0, math.MaxUint32, 0, 100)
+	if err != nil {
+		t.Fatalf("GetAddressTxs error %s", err)
+	}
+	if len(txs) != 0 {
+		t.Fatalf("unexpected txs of unknown address")
+	}
+}
+
+func TestContractAbi(t *testing.T) {
+	eventStore, err := NewEventStore("test/contractabi")
+	if err != nil {
+		t.Fatalf("NewEventStore error %s", err)
+	}
+	defer eventStore.Close()
+
+	contract := common.Address{1}
+	eventStore.NewBatch()
+	eventStore.SaveContractAbi(contract, []byte(`{"functions":[]}`))
+	if err := eventStore.CommitTo(); err != nil {
+		t.Fatalf("CommitTo error %s", err)
+	}
+	abiData, err := eventStore.GetContractAbi(contract)
+	if err != nil || string(abiData) != `{"functions":[]}` {
+		t.Fatalf("GetContractAbi %s error %v", abiData, err)
+	}
+	if err := eventStore.PutContractAbi(contract, []byte(`{"events":[]}`)); err != nil {
+		t.Fatalf("PutContractAbi error %s", err)
+	}
+	abiData, err = eventStore.GetContractAbi(contract)
+	if err != nil || string(abiData) != `{"events":[]}` {
+		t.Fatalf("GetContractAbi %s error %v", abiData, err)
+	}
+	if err := eventStore.DeleteContractAbi(contract); err != nil {
+		t.Fatalf("DeleteContractAbi error %s", err)
+	}
+	if _, err := eventStore.GetContractAbi(contract); err == nil {
+		t.Fatalf("GetContractAbi of deleted abi should fail")
+	}
+}
+
+func TestIndexStartHeight(t *testing.T) {
+	eventStore, err := NewEventStore("test/indexstart")
+	if err != nil {
+		t.Fatalf("NewEventStore error %s", err)
+	}
+	defer eventStore.Close()
+
+	if _, err := eventStore.GetIndexStartHeight(scom.EVENT_NOTIFY_BY_CONTRACT); err != scom.ErrNotFound {
+		t.Fatalf("index start height should not be found, error %v", err)
+	}
+	ledgerStore := &LedgerStoreImp{
+		eventStore:        eventStore,
+		indexStartHeights: make(map[scom.DataEntryPrefix]uint32),
+	}
+	if err := ledgerStore.checkIndexStartHeight(scom.EVENT_NOTIFY_BY_CONTRACT, 0); err == nil {
+		t.Fatalf("query of index not built should fail")
+	}
+
+	//index enabled from height 10, later blocks keep the start height
+	eventStore.NewBatch()
+	ledgerStore.saveIndexStartHeight(scom.EVENT_NOTIFY_BY_CONTRACT, 10, true)
+	ledgerStore.saveIndexStartHeight(scom.EVENT_NOTIFY_BY_CONTRACT, 11, true)
+	if err := eventStore.CommitTo(); err != nil {
+		t.Fatalf("CommitTo error %s", err)
+	}
+	height, err := eventStore.GetIndexStartHeight(scom.EVENT_NOTIFY_BY_CONTRACT)
+	if err != nil || height != 10 {
+		t.Fatalf("GetIndexStartHeight %d error %v", height, err)
+	}
+	if err := ledgerStore.checkIndexStartHeight(scom.EVENT_NOTIFY_BY_CONTRACT, 9); err == nil {
+		t.Fatalf("query below index start height should fail")
+	}
+	if err := ledgerStore.checkIndexStartHeight(scom.EVENT_NOTIFY_BY_CONTRACT, 10); err != nil {
+		t.Fatalf("checkIndexStartHeight error %s", err)
+	}
+
+	//disabled index forgets the start height
+	eventStore.NewBatch()
+	ledgerStore.saveIndexStartHeight(scom.EVENT_NOTIFY_BY_CONTRACT, 12, false)
+	if err := eventStore.CommitTo(); err != nil {
+		t.Fatalf("CommitTo error %s", err)
+	}
+	if _, err := eventStore.GetIndexStartHeight(scom.EVENT_NOTIFY_BY_CONTRACT); err != scom.ErrNotFound {
+		t.Fatalf("index start height should be deleted, error %v", err)
+	}
+
+	//address index is kept apart from event index
+	eventStore.NewBatch()
+	ledgerStore.saveIndexStartHeight(scom.IX_ADDRESS_TX, 20, true)
+	if err := eventStore.CommitTo(); err != nil {
+		t.Fatalf("CommitTo error %s", err)
+	}
+	if err := ledgerStore.checkIndexStartHeight(scom.IX_ADDRESS_TX, 19); err == nil {
+		t.Fatalf("address query below index start height should fail")
+	}
+	if err := ledgerStore.checkIndexStartHeight(scom.EVENT_NOTIFY_BY_CONTRACT, 20); err == nil {
+		t.Fatalf("event index should not be built")
+	}
+}
+
+func TestContractAbiCache(t *testing.T) {
+	eventStore, err := NewEventStore("test/abi")
+	if err != nil {
+		t.Fatalf("NewEventStore error %s", err)
+	}
+	defer eventStore.Close()
+
+	contract := common.Address{1}
+	if _, err := eventStore.GetContractAbi(contract); err != scom.ErrNotFound {
+		t.Fatalf("GetContractAbi of contract without abi error %v", err)
+	}
+	if abiData, ok := eventStore.abis[contract]; !ok || abiData != nil || eventStore.abiMisses != 1 {
+		t.Fatalf("contract without abi is not cached")
+	}
+
+	//abi saved in batch is returned before commit
+	eventStore.NewBatch()
+	eventStore.SaveContractAbi(contract, []byte("abi"))
+	abiData, err := eventStore.GetContractAbi(contract)
+	if err != nil || string(abiData) != "abi" || eventStore.abiMisses != 0 {
+		t.Fatalf("GetContractAbi of saved abi %s error %v", abiData, err)
+	}
+	if err := eventStore.CommitTo(); err != nil {
+		t.Fatalf("CommitTo error %s", err)
+	}
+
+	if err := eventStore.DeleteContractAbi(contract); err != nil {
+		t.Fatalf("DeleteContractAbi error %s", err)
+	}
+	if _, err := eventStore.GetContractAbi(contract); err != scom.ErrNotFound || eventStore.abiMisses != 1 {
+		t.Fatalf("GetContractAbi of deleted abi error %v", err)
+	}
+
+	//contracts without abi are dropped when reaching limit
+	eventStore.abiMisses = CONTRACT_ABI_MAX_MISSES
+	other := common.Address{2}
+	if _, err := eventStore.GetContractAbi(other); err != scom.ErrNotFound {
+		t.Fatalf("GetContractAbi of contract without abi error %v", err)
+	}
+	if _, ok := eventStore.abis[contract]; ok || eventStore.abiMisses != 1 {
+		t.Fatalf("contracts without abi are not dropped")
+	}
+}
@@ -21,6 +21,7 @@ package ledgerstore
 import (
 	"bytes"
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"hash"
 	"math"
@@ -35,6 +36,7 @@ import (
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/common/metrics"
 	"github.com/ontio/ontology/common/serialization"
 	"github.com/ontio/ontology/consensus/vbft/config"
 	"github.com/ontio/ontology/core/payload"
@@ -48,9 +50,12 @@ import (
 	"github.com/ontio/ontology/events"
 	"github.com/ontio/ontology/events/message"
 	"github.com/ontio/ontology/smartcontract"
+	"github.com/ontio/ontology/smartcontract/abi"
 	scommon "github.com/ontio/ontology/smartcontract/common"
+	"github.com/ontio/ontology/smartcontract/context"
 	"github.com/ontio/ontology/smartcontract/event"
 	"github.com/ontio/ontology/smartcontract/service/native/global_params"
+	"github.com/ontio/ontology/smartcontract/service/native/ont"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
 	"github.com/ontio/ontology/smartcontract/service/neovm"
 	sstate "github.com/ontio/ontology/smartcontract/states"
@@ -85,6 +90,9 @@ type LedgerStoreImp struct {
 	vbftPeerInfoblock    map[string]uint32 //pubInfo save pubkey,peerindex
 	lock                 sync.RWMutex
 	stateHashCheckHeight uint32
+	indexStartHeights    map[scom.DataEntryPrefix]uint32 //Index key prefix => height from which the index is built
+	parentState          *overlaydb.MemDB                //State before current block of the keys written by it, nil if not kept
+	parentStateHeight    uint32                          //Height of the block whose parent state is kept
 }
 
 //NewLedgerStore return LedgerStoreImp instance
@@ -96,6 +104,7 @@ func NewLedgerStore(dataDir string, stateHashHeight uint32) (*LedgerStoreImp, er
 		vbftPeerInfoblock:    make(map[string]uint32),
 		savingBlockSemaphore: make(chan bool, 1),
 		stateHashCheckHeight: stateHashHeight,
+		indexStartHeights:    make(map[scom.DataEntryPrefix]uint32),
 	}
 
 	blockStore, err := NewBlockStore(fmt.Sprintf("%s%s%s", dataDir, string(os.PathSeparator), DBDirBlock), true)
@@ -241,6 +250,10 @@ func (this *LedgerStoreImp) init() error {
 	if err != nil {
 		return fmt.Errorf("loadHeaderIndexList error %s", err)
 	}
+	err = this.loadIndexStartHeights()
+	if err != nil {
+		return fmt.Errorf("loadIndexStartHeights error %s", err)
+	}
 	err = this.recoverStore()
 	if err != nil {
 		return fmt.Errorf("recoverStore error %s", err)
@@ -259,6 +272,22 @@ func (this *LedgerStoreImp) loadCurrentBlock() error {
 	return nil
 }
 
+//loadIndexStartHeights load the heights from which the indexes are built. Blocks saved before
+//an index is enabled are not indexed, so queries below the start height are rejected
+func (this *LedgerStoreImp) loadIndexStartHeights() error {
+	for _, prefix := range []scom.DataEntryPrefix{scom.EVENT_NOTIFY_BY_CONTRACT, scom.IX_ADDRESS_TX} {
+		height, err := this.eventStore.GetIndexStartHeight(prefix)
+		if err == scom.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		this.indexStartHeights[prefix] = height
+	}
+	return nil
+}
+
 func (this *LedgerStoreImp) loadHeaderIndexList() error {
 	currBlockHeight := this.GetCurrentBlockHeight()
 	headerIndex, err := this.blockStore.GetHeaderIndexList()
@@ -313,6 +342,8 @@ func (this *LedgerStoreImp) recoverStore() error {
 		if err != nil {
 			return fmt.Errorf("save to event store height:%d error:%s", i, err)
 		}
+		this.saveBlockToAddressIndex(block, result)
+		this.saveBlockToContractAbi(result)
 		err = this.eventStore.CommitTo()
 		if err != nil {
 			return fmt.Errorf("eventStore.CommitTo height:%d error %s", i, err)
@@ -372,6 +403,33 @@ func (this *LedgerStoreImp) setCurrentBlock(height uint32, blockHash common.Uint
 	return
 }
 
+//getParentState return the values before block of the keys written by block, the keys not exist are deleted.
+//Return nil if state store failed
+func (this *LedgerStoreImp) getParentState(result store.ExecuteResult) *overlaydb.MemDB {
+	origin := this.stateStore.NewOverlayDB()
+	parent := overlaydb.NewMemDB(0, result.WriteSet.Len())
+	result.WriteSet.ForEach(func(key, val []byte) {
+		before, _ := origin.Get(key)
+		if len(before) == 0 {
+			parent.Delete(key)
+		} else {
+			parent.Put(key, before)
+		}
+	})
+	if err := origin.Error(); err != nil {
+		log.Warnf("get parent state error:%s", err)
+		return nil
+	}
+	return parent
+}
+
+func (this *LedgerStoreImp) setParentState(height uint32, parent *overlaydb.MemDB) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.parentState = parent
+	this.parentStateHeight = height
+}
+
 //GetCurrentBlock return the current block height, and block hash.
 //Current block means the latest block in store.
 func (this *LedgerStoreImp) GetCurrentBlock() (uint32, common.Uint256) {
@@ -625,6 +683,10 @@ func (this *LedgerStoreImp) saveBlockToBlockStore(block *types.Block) error {
 }
 
 func (this *LedgerStoreImp) executeBlock(block *types.Block) (result store.ExecuteResult, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.BlockExecuteTime.Observe(time.Since(start).Seconds())
+	}()
 	overlay := this.stateStore.NewOverlayDB()
 	if block.Header.Height != 0 {
 		config := &smartcontract.Config{
@@ -706,7 +768,7 @@ func (this *LedgerStoreImp) saveBlockToStateStore(block *types.Block, result sto
 	blockHeight := block.Header.Height
 
 	for _, notify := range result.Notify {
-		SaveNotify(this.eventStore, notify.TxHash, notify)
+		SaveNotify(this.eventStore, blockHeight, notify.TxHash, notify)
 	}
 
 	err := this.stateStore.AddStateMerkleTreeRoot(blockHeight, result.Hash)
@@ -751,6 +813,7 @@ func (this *LedgerStoreImp) saveBlockToEventStore(block *types.Block) error {
 			return fmt.Errorf("SaveEventNotifyByBlock error %s", err)
 		}
 	}
+	this.saveIndexStartHeight(scom.EVENT_NOTIFY_BY_CONTRACT, blockHeight, config.DefConfig.Common.EnableEventLog)
 	err := this.eventStore.SaveCurrentBlock(blockHeight, blockHash)
 	if err != nil {
 		return fmt.Errorf("SaveCurrentBlock error %s", err)
@@ -758,6 +821,133 @@ func (this *LedgerStoreImp) saveBlockToEventStore(block *types.Block) error {
 	return nil
 }
 
+//saveIndexStartHeight record height as the start of index if the index has not been built, or
+//forget the start height if the index is disabled, so a later enabled index starts afresh
+func (this *LedgerStoreImp) saveIndexStartHeight(prefix scom.DataEntryPrefix, height uint32, enabled bool) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	_, ok := this.indexStartHeights[prefix]
+	if enabled && !ok {
+		this.eventStore.SaveIndexStartHeight(prefix, height)
+		this.indexStartHeights[prefix] = height
+	} else if !enabled && ok {
+		this.eventStore.DeleteIndexStartHeight(prefix)
+		delete(this.indexStartHeights, prefix)
+	}
+}
+
+//checkIndexStartHeight return error if the index is not built from startHeight
+func (this *LedgerStoreImp) checkIndexStartHeight(prefix scom.DataEntryPrefix, startHeight uint32) error {
+	indexHeight, err := this.GetIndexStartHeight(prefix)
+	if err != nil {
+		return err
+	}
+	if startHeight < indexHeight {
+		return fmt.Errorf("index is built from height %d, blocks before it are not indexed", indexHeight)
+	}
+	return nil
+}
+
+//saveBlockToAddressIndex index the transactions of block by payer, signer addresses and the addresses in
+//ONT/ONG transfer notifies
+func (this *LedgerStoreImp) saveBlockToAddressIndex(block *types.Block, result store.ExecuteResult) {
+	this.saveIndexStartHeight(scom.IX_ADDRESS_TX, block.Header.Height, config.DefConfig.Common.EnableAddressIndex)
+	if !config.DefConfig.Common.EnableAddressIndex {
+		return
+	}
+	notifies := make(map[common.Uint256]*event.ExecuteNotify, len(result.Notify))
+	for _, notify := range result.Notify {
+		notifies[notify.TxHash] = notify
+	}
+	for _, tx := range block.Transactions {
+		txHash := tx.Hash()
+		addrs := getTxAddresses(tx, notifies[txHash])
+		this.eventStore.SaveAddressTxIndex(block.Header.Height, txHash, addrs)
+	}
+}
+
+//saveBlockToContractAbi save the abi in description of NeoVM contracts deployed or migrated in block. The abi
+//of contract already saved is not overwritten, use abi registry to update it
+func (this *LedgerStoreImp) saveBlockToContractAbi(result store.ExecuteResult) {
+	if !config.DefConfig.Common.EnableContractAbi || result.WriteSet == nil {
+		return
+	}
+	result.WriteSet.ForEach(func(key, val []byte) {
+		if len(key) != 1+common.ADDR_LEN || key[0] != byte(scom.ST_CONTRACT) || len(val) == 0 {
+			return
+		}
+		deploy := &payload.DeployCode{}
+		if err := deploy.Deserialization(common.NewZeroCopySource(val)); err != nil {
+			return
+		}
+		abiData, ok := getDeployContractAbi(deploy)
+		if !ok {
+			return
+		}
+		address := deploy.Address()
+		if _, err := this.eventStore.GetContractAbi(address); err != scom.ErrNotFound {
+			return
+		}
+		this.eventStore.SaveContractAbi(address, abiData)
+	})
+}
+
+//getDeployContractAbi return the NeoVM abi json in description of deploy code, with hash set to contract address
+func getDeployContractAbi(deploy *payload.DeployCode) ([]byte, bool) {
+	contractAbi, err := abi.ParseNeovmContractAbi(deploy.Address(), []byte(deploy.Description))
+	if err != nil {
+		return nil, false
+	}
+	abiData, err := json.Marshal(contractAbi)
+	if err != nil {
+		return nil, false
+	}
+	return abiData, true
+}
+
+//getTxAddresses return the addresses touched by transaction
+func getTxAddresses(tx *types.Transaction, notify *event.ExecuteNotify) []common.Address {
+	addrMap := make(map[common.Address]bool)
+	addrs := make([]common.Address, 0)
+	addAddr := func(addr common.Address) {
+		if addr == common.ADDRESS_EMPTY || addrMap[addr] {
+			return
+		}
+		addrMap[addr] = true
+		addrs = append(addrs, addr)
+	}
+	addAddr(tx.Payer)
+	sigAddrs, err := tx.GetSignatureAddresses()
+	if err != nil {
+		log.Warnf("get signature addresses of tx %s error %s", tx.Hash().ToHexString(), err)
+	}
+	for _, addr := range sigAddrs {
+		addAddr(addr)
+	}
+	if notify == nil {
+		return addrs
+	}
+	for _, notifyInfo := range notify.Notify {
+		if notifyInfo.ContractAddress != utils.OntContractAddress && notifyInfo.ContractAddress != utils.OngContractAddress {
+			continue
+		}
+		states, ok := notifyInfo.States.([]interface{})
+		if !ok || len(states) < 3 || notifyInfo.Topic() != "transfer" {
+			continue
+		}
+		for _, state := range states[1:3] {
+			str, ok := state.(string)
+			if !ok {
+				continue
+			}
+			if addr, err := common.AddressFromBase58(str); err == nil {
+				addAddr(addr)
+			}
+		}
+	}
+	return addrs
+}
+
 func (this *LedgerStoreImp) tryGetSavingBlockLock() (hasLocked bool) {
 	select {
 	case this.savingBlockSemaphore <- true:
@@ -790,6 +980,8 @@ func (this *LedgerStoreImp) submitBlock(block *types.Block, result store.Execute
 			block.Header.Height, blockRoot.ToHexString(), block.Header.BlockRoot.ToHexString())
 	}
 
+	//read before the write set is committed
+	parentState := this.getParentState(result)
 	this.blockStore.NewBatch()
 	this.stateStore.NewBatch()
 	this.eventStore.NewBatch()
@@ -805,6 +997,8 @@ func (this *LedgerStoreImp) submitBlock(block *types.Block, result store.Execute
 	if err != nil {
 		return fmt.Errorf("save to event store height:%d error:%s", blockHeight, err)
 	}
+	this.saveBlockToAddressIndex(block, result)
+	this.saveBlockToContractAbi(result)
 	err = this.blockStore.CommitTo()
 	if err != nil {
 		return fmt.Errorf("blockStore.CommitTo height:%d error %s", blockHeight, err)
@@ -819,6 +1013,7 @@ func (this *LedgerStoreImp) submitBlock(block *types.Block, result store.Execute
 		return fmt.Errorf("stateStore.CommitTo height:%d error %s", blockHeight, err)
 	}
 	this.setCurrentBlock(blockHeight, blockHash)
+	this.setParentState(blockHeight, parentState)
 
 	if events.DefActorPublisher != nil {
 		events.DefActorPublisher.Publish(
@@ -1009,6 +1204,51 @@ func (this *LedgerStoreImp) GetEventNotifyByBlock(height uint32) ([]*event.Execu
 	return this.eventStore.GetEventNotifyByBlock(height)
 }
 
+//GetEventNotifyByContract return the event notifies of contract by topic and height range. Wrap function of EventStore.GetEventNotifyByContract
+func (this *LedgerStoreImp) GetEventNotifyByContract(contract common.Address, topic string, startHeight, endHeight uint32,
+	offset, limit uint32) ([]*event.ContractEventNotify, error) {
+	if err := this.checkIndexStartHeight(scom.EVENT_NOTIFY_BY_CONTRACT, startHeight); err != nil {
+		return nil, err
+	}
+	return this.eventStore.GetEventNotifyByContract(contract, topic, startHeight, endHeight, offset, limit)
+}
+
+//GetAddressTxs return the transactions touching address in height range. Wrap function of EventStore.GetAddressTxs
+func (this *LedgerStoreImp) GetAddressTxs(addr common.Address, startHeight, endHeight uint32,
+	offset, limit uint32) ([]*scom.AddressTx, error) {
+	if err := this.checkIndexStartHeight(scom.IX_ADDRESS_TX, startHeight); err != nil {
+		return nil, err
+	}
+	return this.eventStore.GetAddressTxs(addr, startHeight, endHeight, offset, limit)
+}
+
+//GetIndexStartHeight return the height from which the index of prefix is built. Blocks saved before
+//the index is enabled are not indexed
+func (this *LedgerStoreImp) GetIndexStartHeight(prefix scom.DataEntryPrefix) (uint32, error) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	height, ok := this.indexStartHeights[prefix]
+	if !ok {
+		return 0, fmt.Errorf("index has not been built")
+	}
+	return height, nil
+}
+
+//GetContractAbi return the NeoVM abi json of contract. Wrap function of EventStore.GetContractAbi
+func (this *LedgerStoreImp) GetContractAbi(contract common.Address) ([]byte, error) {
+	return this.eventStore.GetContractAbi(contract)
+}
+
+//PutContractAbi persist the NeoVM abi json of contract. Wrap function of EventStore.PutContractAbi
+func (this *LedgerStoreImp) PutContractAbi(contract common.Address, abiData []byte) error {
+	return this.eventStore.PutContractAbi(contract, abiData)
+}
+
+//DeleteContractAbi delete the NeoVM abi of contract. Wrap function of EventStore.DeleteContractAbi
+func (this *LedgerStoreImp) DeleteContractAbi(contract common.Address) error {
+	return this.eventStore.DeleteContractAbi(contract)
+}
+
 //PreExecuteContract return the result of smart contract execution without commit to store
 func (this *LedgerStoreImp) PreExecuteContract(tx *types.Transaction) (*sstate.PreExecResult, error) {
 	height := this.GetCurrentBlockHeight()
@@ -1063,6 +1303,214 @@ func (this *LedgerStoreImp) PreExecuteContract(tx *types.Transaction) (*sstate.P
 	}
 }
 
+//DebugExecuteContract dry-run the transaction on the state of current height with the state overrides, and
+//return the execution trace. Only the latest state is kept by ledger, so any other height is rejected
+func (this *LedgerStoreImp) DebugExecuteContract(tx *types.Transaction, height uint32,
+	overrides *sstate.StateOverrides) (*sstate.DebugExecResult, error) {
+	currentHeight := this.GetCurrentBlockHeight()
+	if height != currentHeight {
+		return nil, fmt.Errorf("unsupported height %d, state of history height is not kept, only current height %d is supported",
+			height, currentHeight)
+	}
+	config := &smartcontract.Config{
+		Time:      uint32(time.Now().Unix()),
+		Height:    height + 1,
+		Tx:        tx,
+		BlockHash: this.GetBlockHash(height),
+	}
+
+	overlay := this.stateStore.NewOverlayDB()
+	if overrides != nil {
+		for _, item := range overrides.Storage {
+			key := append([]byte{byte(scom.ST_STORAGE)}, item.Contract[:]...)
+			key = append(key, item.Key...)
+			if len(item.Value) == 0 {
+				overlay.Delete(key)
+			} else {
+				overlay.Put(key, states.GenRawStorageItem(item.Value))
+			}
+		}
+		for _, item := range overrides.Balances {
+			key := append([]byte{byte(scom.ST_STORAGE)}, ont.GenBalanceKey(item.Asset, item.Account)...)
+			overlay.Put(key, utils.GenUInt64StorageItem(item.Balance).ToArray())
+		}
+	}
+	cache := storage.NewCacheDB(overlay)
+	preGas, err := this.getPreGas(config, cache)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &sstate.DebugExecResult{State: event.CONTRACT_STATE_FAIL, GasDetail: make(map[string]uint64)}
+	switch tx.TxType {
+	case types.Invoke:
+		invoke := tx.Payload.(*payload.InvokeCode)
+		codeGas := calcGasByCodeLen(len(invoke.Code), preGas[neovm.UINT_INVOKE_CODE_LEN_NAME])
+		tracer := smartcontract.NewExecTracer()
+		sc := smartcontract.SmartContract{
+			Config:  config,
+			Store:   this,
+			CacheDB: cache,
+			Gas:     math.MaxUint64 - codeGas,
+			PreExec: true,
+			Tracer:  tracer,
+		}
+		engine, err := sc.NewExecuteEngine(invoke.Code)
+		if err != nil {
+			return nil, fmt.Errorf("NewExecuteEngine error:%s", err)
+		}
+		ret, err := engine.Invoke()
+		if err != nil {
+			result.Error = err.Error()
+		} else if cv, err := scommon.ConvertNeoVmTypeHexString(ret); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.State = event.CONTRACT_STATE_SUCCESS
+			result.Result = cv
+		}
+		result.Gas = math.MaxUint64 - sc.Gas
+		if result.Gas < neovm.MIN_TRANSACTION_GAS {
+			result.Gas = neovm.MIN_TRANSACTION_GAS
+		}
+		result.Notify = sc.Notifications
+		result.GasDetail = tracer.GasDetail
+		result.GasDetail[neovm.UINT_INVOKE_CODE_LEN_NAME] = codeGas
+		result.Steps = tracer.Steps
+		result.Truncated = tracer.Truncated
+		cache.ForEachStorage(func(key, val []byte) {
+			write := &sstate.StorageWrite{Key: append([]byte(nil), key...)}
+			if len(val) != 0 {
+				write.Value = getStorageItemValue(val)
+			}
+			result.StorageWrites = append(result.StorageWrites, write)
+		})
+	case types.Deploy:
+		deploy := tx.Payload.(*payload.DeployCode)
+		result.State = event.CONTRACT_STATE_SUCCESS
+		result.GasDetail[neovm.CONTRACT_CREATE_NAME] = preGas[neovm.CONTRACT_CREATE_NAME]
+		result.GasDetail[neovm.UINT_DEPLOY_CODE_LEN_NAME] = calcGasByCodeLen(len(deploy.Code),
+			preGas[neovm.UINT_DEPLOY_CODE_LEN_NAME])
+		result.Gas = preGas[neovm.CONTRACT_CREATE_NAME] + result.GasDetail[neovm.UINT_DEPLOY_CODE_LEN_NAME]
+	default:
+		return nil, errors.NewErr("transaction type error")
+	}
+	return result, nil
+}
+
+//TraceTransaction re-execute the mined transaction in a throwaway overlay with tracing. The ledger only keeps
+//the latest state and the state before the current block, so only the transactions of the current block can be
+//traced. The state before the block is rebuilt, and the transactions before the traced one in block are replayed,
+//so the transaction is re-executed on the state it saw when the block was executed
+func (this *LedgerStoreImp) TraceTransaction(txHash common.Uint256) (*sstate.TxTraceResult, error) {
+	tx, height, err := this.GetTransaction(txHash)
+	if err != nil {
+		return nil, err
+	}
+	this.lock.RLock()
+	currentHeight := this.currBlockHeight
+	parentState, parentStateHeight := this.parentState, this.parentStateHeight
+	this.lock.RUnlock()
+	if height != currentHeight {
+		return nil, fmt.Errorf("transaction is in block %d, only transactions of current block %d can be traced",
+			height, currentHeight)
+	}
+	if parentState == nil || parentStateHeight != height || height == 0 {
+		return nil, fmt.Errorf("state before block %d is not kept, the block is saved before the node started", height)
+	}
+	block, err := this.GetBlockByHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("get block of height %d error:%s", height, err)
+	}
+
+	result := &sstate.TxTraceResult{
+		TxHash:      txHash,
+		Height:      height,
+		StateHeight: height - 1,
+	}
+	overlay := this.stateStore.NewOverlayDB()
+	parentState.ForEach(func(key, val []byte) {
+		if len(val) == 0 {
+			overlay.Delete(key)
+		} else {
+			overlay.Put(key, val)
+		}
+	})
+	cache := storage.NewCacheDB(overlay)
+	for _, blkTx := range block.Transactions {
+		if blkTx.Hash() == txHash {
+			break
+		}
+		cache.Reset()
+		if _, err := this.handleTransaction(overlay, cache, block, blkTx); err != nil {
+			return nil, fmt.Errorf("replay transaction %s error:%s", blkTx.Hash().ToHexString(), err)
+		}
+	}
+	//the keys written by transaction are written by block too, keep their values before transaction
+	before := make(map[string][]byte)
+	parentState.ForEach(func(key, val []byte) {
+		if len(key) != 0 && key[0] == byte(scom.ST_STORAGE) {
+			before[string(key)], _ = overlay.Get(key)
+		}
+	})
+
+	cache.Reset()
+	notify := &event.ExecuteNotify{TxHash: txHash, State: event.CONTRACT_STATE_FAIL}
+	switch tx.TxType {
+	case types.Invoke:
+		invoke := tx.Payload.(*payload.InvokeCode)
+		tracer := smartcontract.NewExecTracer()
+		tracer.CaptureEnter(context.CALL_KIND_ENTRY, common.AddressFromVmCode(invoke.Code), "")
+		err = this.stateStore.handleInvokeTransaction(this, overlay, cache, tx, block, notify, tracer)
+		tracer.CaptureExit(err)
+		result.Calls = tracer.Calls
+	case types.Deploy:
+		err = this.stateStore.HandleDeployTransaction(this, overlay, cache, tx, block, notify)
+	default:
+		return nil, errors.NewErr("transaction type error")
+	}
+	if overlay.Error() != nil {
+		return nil, fmt.Errorf("re-execute transaction error:%s", overlay.Error())
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	result.State = notify.State
+	result.GasConsumed = notify.GasConsumed
+	result.Notify = notify.Notify
+
+	parentState.ForEach(func(key, _ []byte) {
+		if len(key) == 0 || key[0] != byte(scom.ST_STORAGE) {
+			return
+		}
+		old := before[string(key)]
+		val, _ := overlay.Get(key)
+		if bytes.Equal(old, val) {
+			return
+		}
+		diff := &sstate.StorageDiff{Key: append([]byte(nil), key[1:]...)}
+		if len(old) != 0 {
+			diff.Before = getStorageItemValue(old)
+		}
+		if len(val) != 0 {
+			diff.After = getStorageItemValue(val)
+		}
+		result.StorageDiffs = append(result.StorageDiffs, diff)
+	})
+	if this.GetCurrentBlockHeight() != height {
+		return nil, fmt.Errorf("block %d saved while tracing, please retry", height+1)
+	}
+	return result, nil
+}
+
+//getStorageItemValue return a copy of the value in raw storage item, or the raw data if it is not a storage item
+func getStorageItemValue(raw []byte) []byte {
+	value, err := states.GetValueFromRawStorageItem(raw)
+	if err != nil {
+		value = raw
+	}
+	return append([]byte(nil), value...)
+}
+
 func (this *LedgerStoreImp) getPreGas(config *smartcontract.Config, cache *storage.CacheDB) (map[string]uint64, error) {
 	bf := new(bytes.Buffer)
 	names := []string{neovm.CONTRACT_CREATE_NAME, neovm.UINT_INVOKE_CODE_LEN_NAME, neovm.UINT_DEPLOY_CODE_LEN_NAME}
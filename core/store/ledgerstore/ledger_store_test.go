@@ -19,12 +19,19 @@
 package ledgerstore
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/core/genesis"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/store"
+	scom "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/core/store/overlaydb"
+	"github.com/ontio/ontology/smartcontract/abi"
 	"os"
 	"testing"
 )
@@ -137,3 +144,103 @@ func TestInitLedgerStoreWithGenesisBlock(t *testing.T) {
 		return
 	}
 }
+
+func TestGetDeployContractAbi(t *testing.T) {
+	deploy := &payload.DeployCode{
+		Code:        []byte{1, 2, 3},
+		Description: `{"hash":"0x00","functions":[{"name":"Main","parameters":[],"returntype":"Void"}]}`,
+	}
+	abiData, ok := getDeployContractAbi(deploy)
+	if !ok {
+		t.Errorf("TestGetDeployContractAbi abi in description not found")
+		return
+	}
+	contractAbi := &abi.NeovmContractAbi{}
+	if err := json.Unmarshal(abiData, contractAbi); err != nil {
+		t.Errorf("TestGetDeployContractAbi json.Unmarshal error %s", err)
+		return
+	}
+	address := deploy.Address()
+	if contractAbi.Address != address.ToHexString() {
+		t.Errorf("TestGetDeployContractAbi abi hash %s != %s", contractAbi.Address, address.ToHexString())
+		return
+	}
+	deploy.Description = "token contract"
+	if _, ok := getDeployContractAbi(deploy); ok {
+		t.Errorf("TestGetDeployContractAbi description is not abi")
+		return
+	}
+	deploy.Description = `{"functions":[],"events":[{"name":"transfer","parameters":[]}]}`
+	if _, ok := getDeployContractAbi(deploy); !ok {
+		t.Errorf("TestGetDeployContractAbi abi with events only not found")
+		return
+	}
+}
+
+func TestSaveBlockToContractAbi(t *testing.T) {
+	config.DefConfig.Common.EnableContractAbi = true
+	defer func() {
+		config.DefConfig.Common.EnableContractAbi = false
+	}()
+	deploy := &payload.DeployCode{
+		Code:        []byte{4, 5, 6},
+		Description: `{"functions":[],"events":[{"name":"transfer","parameters":[]}]}`,
+	}
+	address := deploy.Address()
+	if _, err := testLedgerStore.GetContractAbi(address); err != scom.ErrNotFound {
+		t.Errorf("TestSaveBlockToContractAbi GetContractAbi before deploy error %v", err)
+		return
+	}
+	//contract deployed or migrated in block is in write set
+	sink := common.NewZeroCopySink(nil)
+	if err := deploy.Serialization(sink); err != nil {
+		t.Errorf("TestSaveBlockToContractAbi Serialization error %s", err)
+		return
+	}
+	writeSet := overlaydb.NewMemDB(0, 0)
+	writeSet.Put(append([]byte{byte(scom.ST_CONTRACT)}, address[:]...), sink.Bytes())
+	testLedgerStore.eventStore.NewBatch()
+	testLedgerStore.saveBlockToContractAbi(store.ExecuteResult{WriteSet: writeSet})
+	if err := testLedgerStore.eventStore.CommitTo(); err != nil {
+		t.Errorf("TestSaveBlockToContractAbi CommitTo error %s", err)
+		return
+	}
+	abiData, err := testLedgerStore.GetContractAbi(address)
+	if err != nil {
+		t.Errorf("TestSaveBlockToContractAbi GetContractAbi after deploy error %s", err)
+		return
+	}
+	contractAbi := &abi.NeovmContractAbi{}
+	if err := json.Unmarshal(abiData, contractAbi); err != nil || contractAbi.Address != address.ToHexString() {
+		t.Errorf("TestSaveBlockToContractAbi invalid abi %s", abiData)
+		return
+	}
+}
+
+func TestGetParentState(t *testing.T) {
+	existKey := []byte{byte(scom.ST_STORAGE), 1, 2, 3}
+	newKey := []byte{byte(scom.ST_STORAGE), 4, 5, 6}
+	testLedgerStore.stateStore.NewBatch()
+	testLedgerStore.stateStore.BatchPutRawKeyVal(existKey, []byte("before"))
+	if err := testLedgerStore.stateStore.CommitTo(); err != nil {
+		t.Errorf("TestGetParentState CommitTo error %s", err)
+		return
+	}
+	writeSet := overlaydb.NewMemDB(0, 0)
+	writeSet.Put(existKey, []byte("after"))
+	writeSet.Put(newKey, []byte("new"))
+	parent := testLedgerStore.getParentState(store.ExecuteResult{WriteSet: writeSet})
+	if parent == nil {
+		t.Errorf("TestGetParentState parent state is nil")
+		return
+	}
+	if val, unknown := parent.Get(existKey); unknown || string(val) != "before" {
+		t.Errorf("TestGetParentState value of exist key %s != before", val)
+		return
+	}
+	//key not exist before block is deleted in parent state
+	if val, unknown := parent.Get(newKey); unknown || len(val) != 0 {
+		t.Errorf("TestGetParentState value of new key %s should be deleted", val)
+		return
+	}
+}
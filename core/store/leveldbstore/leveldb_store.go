@@ -19,6 +19,8 @@
 package leveldbstore
 
 import (
+	"bytes"
+
 	"github.com/ontio/ontology/core/store/common"
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/errors"
@@ -146,3 +148,12 @@ func (self *LevelDBStore) NewIterator(prefix []byte) common.StoreIterator {
 
 	return iter
 }
+
+//NewSeekIterator return a iterator of leveldb with the key prefix, starting from the first key not less than start
+func (self *LevelDBStore) NewSeekIterator(prefix []byte, start []byte) common.StoreIterator {
+	slice := util.BytesPrefix(prefix)
+	if bytes.Compare(start, slice.Start) > 0 {
+		slice.Start = start
+	}
+	return self.db.NewIterator(slice, nil)
+}
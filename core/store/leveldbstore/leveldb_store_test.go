@@ -140,3 +140,36 @@ func TestIterator(t *testing.T) {
 	}
 
 }
+
+func TestSeekIterator(t *testing.T) {
+	for _, key := range []string{"seek1", "seek2", "seek3", "seel1"} {
+		err := testLevelDB.Put([]byte(key), []byte(key))
+		if err != nil {
+			t.Errorf("Put error:%s", err)
+			return
+		}
+	}
+
+	keys := make([]string, 0)
+	iter := testLevelDB.NewSeekIterator([]byte("seek"), []byte("seek2"))
+	for iter.Next() {
+		keys = append(keys, string(iter.Key()))
+	}
+	iter.Release()
+	if len(keys) != 2 || keys[0] != "seek2" || keys[1] != "seek3" {
+		t.Errorf("TestSeekIterator keys:%v != [seek2 seek3]", keys)
+		return
+	}
+
+	//start before prefix is limited to prefix
+	keys = keys[:0]
+	iter = testLevelDB.NewSeekIterator([]byte("seek"), []byte("se"))
+	for iter.Next() {
+		keys = append(keys, string(iter.Key()))
+	}
+	iter.Release()
+	if len(keys) != 3 {
+		t.Errorf("TestSeekIterator keys:%v != [seek1 seek2 seek3]", keys)
+		return
+	}
+}
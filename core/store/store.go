@@ -23,6 +23,7 @@ import (
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/core/payload"
 	"github.com/ontio/ontology/core/states"
+	scom "github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/store/overlaydb"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/smartcontract/event"
@@ -63,6 +64,15 @@ type LedgerStore interface {
 	GetBookkeeperState() (*states.BookkeeperState, error)
 	GetStorageItem(key *states.StorageKey) (*states.StorageItem, error)
 	PreExecuteContract(tx *types.Transaction) (*cstates.PreExecResult, error)
+	DebugExecuteContract(tx *types.Transaction, height uint32, overrides *cstates.StateOverrides) (*cstates.DebugExecResult, error)
+	TraceTransaction(txHash common.Uint256) (*cstates.TxTraceResult, error)
 	GetEventNotifyByTx(tx common.Uint256) (*event.ExecuteNotify, error)
 	GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNotify, error)
+	GetEventNotifyByContract(contract common.Address, topic string, startHeight, endHeight uint32,
+		offset, limit uint32) ([]*event.ContractEventNotify, error)
+	GetAddressTxs(addr common.Address, startHeight, endHeight uint32, offset, limit uint32) ([]*scom.AddressTx, error)
+	GetIndexStartHeight(prefix scom.DataEntryPrefix) (uint32, error)
+	GetContractAbi(contract common.Address) ([]byte, error)
+	PutContractAbi(contract common.Address, abiData []byte) error
+	DeleteContractAbi(contract common.Address) error
 }
@@ -0,0 +1,176 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package txbuilder
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/smartcontract/abi"
+)
+
+//NeoVMAbiParams return NeoVM invoke params of method and arguments, arguments are checked and converted by types of ABI:
+//integer accepts go integers, *big.Int, json.Number, integral float64 and decimal string;
+//boolean accepts bool; string accepts string; bytearray accepts []byte, common.Address, common.Uint256,
+//base58 address or hex string; array accepts []interface{}; any accepts all above
+func NeoVMAbiParams(funcAbi *abi.NeovmContractFunctionAbi, args []interface{}) ([]interface{}, error) {
+	if len(args) != len(funcAbi.Parameters) {
+		return nil, fmt.Errorf("method:%s need %d arguments, got %d", funcAbi.Name, len(funcAbi.Parameters), len(args))
+	}
+	params := make([]interface{}, 0, len(args))
+	for i, paramAbi := range funcAbi.Parameters {
+		param, err := NeoVMAbiParam(paramAbi.Type, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument:%s error:%s", paramAbi.Name, err)
+		}
+		params = append(params, param)
+	}
+	return []interface{}{neoVMFuncName(funcAbi.Name), params}, nil
+}
+
+//NeoVM func name in Camel-Case. For example: transfer, transferFrom
+func neoVMFuncName(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+//NeoVMAbiParam check and convert argument to NeoVM param of ABI type
+func NeoVMAbiParam(paramType string, arg interface{}) (interface{}, error) {
+	switch strings.ToLower(paramType) {
+	case abi.NEOVM_PARAM_TYPE_INTEGER:
+		return toBigInt(arg)
+	case abi.NEOVM_PARAM_TYPE_BOOL:
+		v, ok := arg.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%v is not boolean", arg)
+		}
+		return v, nil
+	case abi.NEOVM_PARAM_TYPE_STRING:
+		v, ok := arg.(string)
+		if !ok {
+			return nil, fmt.Errorf("%v is not string", arg)
+		}
+		return v, nil
+	case abi.NEOVM_PARAM_TYPE_BYTE_ARRAY:
+		return toByteArray(arg)
+	case abi.NEOVM_PARAM_TYPE_ARRAY:
+		v, ok := arg.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%v is not array", arg)
+		}
+		return toAny(v)
+	case abi.NEOVM_PARAM_TYPE_ANY:
+		return toAny(arg)
+	default:
+		return nil, fmt.Errorf("unsupported param type:%s", paramType)
+	}
+}
+
+func toBigInt(arg interface{}) (*big.Int, error) {
+	switch v := arg.(type) {
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int8:
+		return big.NewInt(int64(v)), nil
+	case int16:
+		return big.NewInt(int64(v)), nil
+	case int32:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case uint:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint8:
+		return big.NewInt(int64(v)), nil
+	case uint16:
+		return big.NewInt(int64(v)), nil
+	case uint32:
+		return big.NewInt(int64(v)), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case *big.Int:
+		return v, nil
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > 1<<53 {
+			return nil, fmt.Errorf("%v is not integer", v)
+		}
+		return big.NewInt(int64(v)), nil
+	case json.Number:
+		return toBigInt(string(v))
+	case string:
+		value, ok := new(big.Int).SetString(v, 10)
+		if !ok {
+			return nil, fmt.Errorf("%s is not integer", v)
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("%v is not integer", arg)
+	}
+}
+
+func toByteArray(arg interface{}) ([]byte, error) {
+	switch v := arg.(type) {
+	case []byte:
+		return v, nil
+	case common.Address:
+		return v[:], nil
+	case common.Uint256:
+		return v.ToArray(), nil
+	case string:
+		if addr, err := common.AddressFromBase58(v); err == nil {
+			return addr[:], nil
+		}
+		data, err := hex.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s is neither address nor hex string", v)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("%v is not bytearray", arg)
+	}
+}
+
+//toAny convert json values, which are not supported by NeoVM params
+func toAny(arg interface{}) (interface{}, error) {
+	switch v := arg.(type) {
+	case []interface{}:
+		res := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			param, err := toAny(item)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, param)
+		}
+		return res, nil
+	case float64, json.Number:
+		return toBigInt(v)
+	case nil, map[string]interface{}:
+		return nil, fmt.Errorf("unsupported argument:%v", arg)
+	default:
+		return v, nil
+	}
+}
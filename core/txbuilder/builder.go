@@ -0,0 +1,269 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package txbuilder build, and sign transactions for go applications, without depending on ontology cli
+package txbuilder
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/types"
+	cutils "github.com/ontio/ontology/core/utils"
+	"github.com/ontio/ontology/smartcontract/abi"
+	"github.com/ontio/ontology/smartcontract/service/native/ont"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/ontio/ontology/smartcontract/service/neovm"
+)
+
+const (
+	VERSION_TRANSACTION    = byte(0)
+	VERSION_CONTRACT_ONT   = byte(0)
+	VERSION_CONTRACT_ONG   = byte(0)
+	CONTRACT_TRANSFER      = "transfer"
+	CONTRACT_TRANSFER_FROM = "transferFrom"
+	CONTRACT_APPROVE       = "approve"
+)
+
+func init() {
+	rand.Seed(time.Now().UnixNano())
+}
+
+//multiSigner - signers of a multi-signature address
+type multiSigner struct {
+	m       uint16
+	pubKeys []keypair.PublicKey
+	signers []account.Signer
+}
+
+//TxBuilder build transaction by chained calls. The first error of calls is returned by Build.
+//For example:
+//	tx, err := txbuilder.NewTxBuilder().GasPrice(500).Transfer(utils.OntContractAddress, from, to, 1).Sign(signer).Build()
+type TxBuilder struct {
+	gasPrice uint64
+	gasLimit uint64
+	nonce    uint32
+	payer    common.Address
+	txType   types.TransactionType
+	payload  types.Payload
+	signers  []account.Signer
+	multis   []*multiSigner
+	err      error
+}
+
+//NewTxBuilder return transaction builder with default gas price, gas limit and random nonce
+func NewTxBuilder() *TxBuilder {
+	return &TxBuilder{
+		gasPrice: config.DEFAULT_GAS_PRICE,
+		gasLimit: neovm.MIN_TRANSACTION_GAS,
+		nonce:    rand.Uint32(),
+	}
+}
+
+func (this *TxBuilder) setErr(err error) *TxBuilder {
+	if this.err == nil {
+		this.err = err
+	}
+	return this
+}
+
+func (this *TxBuilder) GasPrice(gasPrice uint64) *TxBuilder {
+	this.gasPrice = gasPrice
+	return this
+}
+
+func (this *TxBuilder) GasLimit(gasLimit uint64) *TxBuilder {
+	this.gasLimit = gasLimit
+	return this
+}
+
+func (this *TxBuilder) Nonce(nonce uint32) *TxBuilder {
+	this.nonce = nonce
+	return this
+}
+
+//Payer set payer of transaction fee. If not set, payer is the first signer, or the first multi-signature address
+func (this *TxBuilder) Payer(payer common.Address) *TxBuilder {
+	this.payer = payer
+	return this
+}
+
+func (this *TxBuilder) setPayload(txType types.TransactionType, pl types.Payload) *TxBuilder {
+	if this.payload != nil {
+		return this.setErr(fmt.Errorf("payload of transaction is already set"))
+	}
+	this.txType = txType
+	this.payload = pl
+	return this
+}
+
+//InvokeCode set invoke code of transaction
+func (this *TxBuilder) InvokeCode(code []byte) *TxBuilder {
+	return this.setPayload(types.Invoke, &payload.InvokeCode{Code: code})
+}
+
+//NativeInvoke invoke method of native contract with params
+func (this *TxBuilder) NativeInvoke(contract common.Address, version byte, method string, params ...interface{}) *TxBuilder {
+	code, err := cutils.BuildNativeInvokeCode(contract, version, method, params)
+	if err != nil {
+		return this.setErr(fmt.Errorf("build native invoke code error:%s", err))
+	}
+	return this.InvokeCode(code)
+}
+
+//NeoVMInvoke invoke NeoVM contract with raw params, which are usually method name and array of arguments
+func (this *TxBuilder) NeoVMInvoke(contract common.Address, params ...interface{}) *TxBuilder {
+	code, err := cutils.BuildNeoVMInvokeCode(contract, params)
+	if err != nil {
+		return this.setErr(fmt.Errorf("build neovm invoke code error:%s", err))
+	}
+	return this.InvokeCode(code)
+}
+
+//NeoVMInvokeAbi invoke method of NeoVM contract, arguments are checked and converted by ABI of contract.
+//Contract address is the hash of ABI if contract is empty address
+func (this *TxBuilder) NeoVMInvokeAbi(contract common.Address, contractAbi *abi.NeovmContractAbi, method string, args ...interface{}) *TxBuilder {
+	if contract == common.ADDRESS_EMPTY {
+		addr, err := common.AddressFromHexString(strings.TrimPrefix(contractAbi.Address, "0x"))
+		if err != nil {
+			return this.setErr(fmt.Errorf("invalid contract address:%s of abi", contractAbi.Address))
+		}
+		contract = addr
+	}
+	funcAbi := contractAbi.GetFunc(method)
+	if funcAbi == nil {
+		return this.setErr(fmt.Errorf("method:%s not found in abi", method))
+	}
+	params, err := NeoVMAbiParams(funcAbi, args)
+	if err != nil {
+		return this.setErr(err)
+	}
+	return this.NeoVMInvoke(contract, params...)
+}
+
+//Deploy deploy NeoVM contract
+func (this *TxBuilder) Deploy(code []byte, needStorage bool, name, version, author, email, desc string) *TxBuilder {
+	return this.setPayload(types.Deploy, &payload.DeployCode{
+		Code:        code,
+		NeedStorage: needStorage,
+		Name:        name,
+		Version:     version,
+		Author:      author,
+		Email:       email,
+		Description: desc,
+	})
+}
+
+//Transfer transfer amount of ONT or ONG contract from an address to another
+func (this *TxBuilder) Transfer(contract, from, to common.Address, amount uint64) *TxBuilder {
+	if contract != utils.OntContractAddress && contract != utils.OngContractAddress {
+		return this.setErr(fmt.Errorf("unsupported asset contract:%s", contract.ToHexString()))
+	}
+	return this.NativeInvoke(contract, VERSION_CONTRACT_ONT, CONTRACT_TRANSFER, []*ont.State{{From: from, To: to, Value: amount}})
+}
+
+//Approve approve amount of ONT or ONG to be transferred by another address
+func (this *TxBuilder) Approve(contract, from, to common.Address, amount uint64) *TxBuilder {
+	if contract != utils.OntContractAddress && contract != utils.OngContractAddress {
+		return this.setErr(fmt.Errorf("unsupported asset contract:%s", contract.ToHexString()))
+	}
+	return this.NativeInvoke(contract, VERSION_CONTRACT_ONT, CONTRACT_APPROVE, &ont.State{From: from, To: to, Value: amount})
+}
+
+//TransferFrom transfer approved amount of ONT or ONG by sender
+func (this *TxBuilder) TransferFrom(contract, sender, from, to common.Address, amount uint64) *TxBuilder {
+	if contract != utils.OntContractAddress && contract != utils.OngContractAddress {
+		return this.setErr(fmt.Errorf("unsupported asset contract:%s", contract.ToHexString()))
+	}
+	return this.NativeInvoke(contract, VERSION_CONTRACT_ONT, CONTRACT_TRANSFER_FROM,
+		&ont.TransferFrom{Sender: sender, From: from, To: to, Value: amount})
+}
+
+//Sign add a signer, transaction is signed by signers in order when Build
+func (this *TxBuilder) Sign(signer account.Signer) *TxBuilder {
+	this.signers = append(this.signers, signer)
+	return this
+}
+
+//MultiSign add signers of an m of n multi-signature address. Signers can be part of pubKeys,
+//the transaction can be signed by other signers later by MultiSignTransaction
+func (this *TxBuilder) MultiSign(m uint16, pubKeys []keypair.PublicKey, signers ...account.Signer) *TxBuilder {
+	this.multis = append(this.multis, &multiSigner{
+		m:       m,
+		pubKeys: pubKeys,
+		signers: signers,
+	})
+	return this
+}
+
+//Build return the transaction signed by signers
+func (this *TxBuilder) Build() (*types.MutableTransaction, error) {
+	if this.err != nil {
+		return nil, this.err
+	}
+	if this.payload == nil {
+		return nil, fmt.Errorf("payload of transaction is not set")
+	}
+	tx := &types.MutableTransaction{
+		Version:  VERSION_TRANSACTION,
+		TxType:   this.txType,
+		Nonce:    this.nonce,
+		GasPrice: this.gasPrice,
+		GasLimit: this.gasLimit,
+		Payer:    this.payer,
+		Payload:  this.payload,
+		Sigs:     make([]types.Sig, 0),
+	}
+	if tx.Payer == common.ADDRESS_EMPTY {
+		if len(this.signers) > 0 {
+			tx.Payer = types.AddressFromPubKey(this.signers[0].PubKey())
+		} else if len(this.multis) > 0 {
+			payer, err := types.AddressFromMultiPubKeys(this.multis[0].pubKeys, int(this.multis[0].m))
+			if err != nil {
+				return nil, fmt.Errorf("AddressFromMultiPubKeys error:%s", err)
+			}
+			tx.Payer = payer
+		}
+	}
+	for _, signer := range this.signers {
+		err := SignTransaction(tx, signer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, multi := range this.multis {
+		err := checkMultiSigParam(multi.m, multi.pubKeys)
+		if err != nil {
+			return nil, err
+		}
+		for _, signer := range multi.signers {
+			err := MultiSignTransaction(tx, multi.m, multi.pubKeys, signer)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return tx, nil
+}
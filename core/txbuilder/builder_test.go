@@ -0,0 +1,163 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package txbuilder
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+	cutils "github.com/ontio/ontology/core/utils"
+	"github.com/ontio/ontology/smartcontract/abi"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildTransfer(t *testing.T) {
+	acc := account.NewAccount("")
+	to := account.NewAccount("")
+	tx, err := NewTxBuilder().GasPrice(0).GasLimit(30000).Nonce(1).
+		Transfer(utils.OntContractAddress, acc.Address, to.Address, 10).Sign(acc).Build()
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, types.Invoke, tx.TxType)
+	assert.Equal(t, uint64(0), tx.GasPrice)
+	assert.Equal(t, uint64(30000), tx.GasLimit)
+	assert.Equal(t, uint32(1), tx.Nonce)
+	assert.Equal(t, acc.Address, tx.Payer)
+	if !assert.Equal(t, 1, len(tx.Sigs)) {
+		return
+	}
+	txHash := tx.Hash()
+	assert.Nil(t, signature.Verify(acc.PublicKey, txHash.ToArray(), tx.Sigs[0].SigData[0]))
+	_, err = tx.IntoImmutable()
+	assert.Nil(t, err)
+
+	info, err := cutils.DecodeInvokeCode(tx.Payload.(*payload.InvokeCode).Code)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, info.Native)
+	assert.Equal(t, utils.OntContractAddress, info.Contract)
+	assert.Equal(t, CONTRACT_TRANSFER, info.Method)
+}
+
+func TestBuildError(t *testing.T) {
+	acc := account.NewAccount("")
+	_, err := NewTxBuilder().Transfer(common.ADDRESS_EMPTY, acc.Address, acc.Address, 1).Sign(acc).Build()
+	assert.NotNil(t, err)
+
+	_, err = NewTxBuilder().InvokeCode([]byte{0}).Deploy([]byte{0}, false, "", "", "", "", "").Build()
+	assert.NotNil(t, err)
+
+	_, err = NewTxBuilder().Sign(acc).Build()
+	assert.NotNil(t, err)
+
+	_, err = NewTxBuilder().InvokeCode([]byte{0}).MultiSign(3, []keypair.PublicKey{acc.PublicKey}, acc).Build()
+	assert.NotNil(t, err)
+}
+
+func TestBuildMultiSign(t *testing.T) {
+	accs := []*account.Account{account.NewAccount(""), account.NewAccount(""), account.NewAccount("")}
+	pubKeys := []keypair.PublicKey{accs[0].PublicKey, accs[1].PublicKey, accs[2].PublicKey}
+	multiAddr, err := types.AddressFromMultiPubKeys(pubKeys, 2)
+	if !assert.Nil(t, err) {
+		return
+	}
+	tx, err := NewTxBuilder().Deploy([]byte{1, 2, 3}, true, "name", "1.0", "author", "email", "desc").
+		MultiSign(2, pubKeys, accs[0]).Build()
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, types.Deploy, tx.TxType)
+	assert.Equal(t, multiAddr, tx.Payer)
+	assert.Equal(t, 1, len(tx.Sigs[0].SigData))
+
+	err = MultiSignTransaction(tx, 2, pubKeys, accs[2])
+	assert.Nil(t, err)
+	err = MultiSignTransaction(tx, 2, pubKeys, accs[2])
+	assert.Nil(t, err)
+	if !assert.Equal(t, 1, len(tx.Sigs)) {
+		return
+	}
+	assert.Equal(t, 2, len(tx.Sigs[0].SigData))
+	txHash := tx.Hash()
+	assert.Nil(t, signature.VerifyMultiSignature(txHash.ToArray(), pubKeys, 2, tx.Sigs[0].SigData))
+
+	other := account.NewAccount("")
+	err = MultiSignTransaction(tx, 2, pubKeys, other)
+	assert.NotNil(t, err)
+}
+
+const testAbi = `{"hash":"0xe827bf96529b5780ad0702757b8bad315e2bb8ce","entrypoint":"Main",
+"functions":[{"name":"Transfer","parameters":[{"name":"from","type":"ByteArray"},{"name":"to","type":"ByteArray"},
+{"name":"value","type":"Integer"},{"name":"memo","type":"String"}],"returntype":"Boolean"}],"events":[]}`
+
+func TestBuildNeoVMInvokeAbi(t *testing.T) {
+	contractAbi := &abi.NeovmContractAbi{}
+	err := json.Unmarshal([]byte(testAbi), contractAbi)
+	if !assert.Nil(t, err) {
+		return
+	}
+	from := account.NewAccount("")
+	to := account.NewAccount("")
+	tx, err := NewTxBuilder().NeoVMInvokeAbi(common.ADDRESS_EMPTY, contractAbi, "transfer",
+		from.Address.ToBase58(), to.Address, json.Number("100"), "memo").Sign(from).Build()
+	if !assert.Nil(t, err) {
+		return
+	}
+	contract, _ := common.AddressFromHexString("e827bf96529b5780ad0702757b8bad315e2bb8ce")
+	code, err := cutils.BuildNeoVMInvokeCode(contract, []interface{}{"transfer",
+		[]interface{}{from.Address[:], to.Address[:], big.NewInt(100), "memo"}})
+	assert.Nil(t, err)
+	assert.Equal(t, code, tx.Payload.(*payload.InvokeCode).Code)
+
+	_, err = NewTxBuilder().NeoVMInvokeAbi(contract, contractAbi, "transfer",
+		from.Address, to.Address, "abc", "memo").Build()
+	assert.NotNil(t, err)
+	_, err = NewTxBuilder().NeoVMInvokeAbi(contract, contractAbi, "transfer", from.Address).Build()
+	assert.NotNil(t, err)
+	_, err = NewTxBuilder().NeoVMInvokeAbi(contract, contractAbi, "unknown").Build()
+	assert.NotNil(t, err)
+}
+
+func TestNeoVMAbiParam(t *testing.T) {
+	v, err := NeoVMAbiParam("integer", 1.5)
+	assert.NotNil(t, err)
+	v, err = NeoVMAbiParam("integer", float64(7))
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(7), v)
+	v, err = NeoVMAbiParam("bytearray", "0a0b")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{10, 11}, v)
+	_, err = NeoVMAbiParam("boolean", "true")
+	assert.NotNil(t, err)
+	v, err = NeoVMAbiParam("array", []interface{}{float64(1), "a", []interface{}{true}})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{big.NewInt(1), "a", []interface{}{true}}, v)
+	_, err = NeoVMAbiParam("any", map[string]interface{}{})
+	assert.NotNil(t, err)
+}
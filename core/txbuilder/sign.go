@@ -0,0 +1,172 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package txbuilder
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/constants"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+)
+
+//SignTransaction sign transaction by signer. Payer is set to signer if transaction has no payer
+func SignTransaction(tx *types.MutableTransaction, signer account.Signer) error {
+	if tx.Payer == common.ADDRESS_EMPTY {
+		tx.Payer = types.AddressFromPubKey(signer.PubKey())
+	}
+	txHash := tx.Hash()
+	sigData, err := signer.Sign(txHash.ToArray())
+	if err != nil {
+		return fmt.Errorf("sign error:%s", err)
+	}
+	hasSig := false
+	for i, sig := range tx.Sigs {
+		if len(sig.PubKeys) == 1 && pubKeysEqual(sig.PubKeys, []keypair.PublicKey{signer.PubKey()}) {
+			if hasAlreadySig(txHash.ToArray(), signer.PubKey(), sig.SigData) {
+				//has already signed
+				return nil
+			}
+			hasSig = true
+			//replace
+			tx.Sigs[i].SigData = [][]byte{sigData}
+		}
+	}
+	if !hasSig {
+		if len(tx.Sigs) >= constants.TX_MAX_SIG_SIZE {
+			return fmt.Errorf("too many signatures of transaction")
+		}
+		tx.Sigs = append(tx.Sigs, types.Sig{
+			PubKeys: []keypair.PublicKey{signer.PubKey()},
+			M:       1,
+			SigData: [][]byte{sigData},
+		})
+	}
+	return nil
+}
+
+func checkMultiSigParam(m uint16, pubKeys []keypair.PublicKey) error {
+	pkSize := len(pubKeys)
+	if m == 0 || int(m) > pkSize || pkSize > constants.MULTI_SIG_MAX_PUBKEY_SIZE {
+		return fmt.Errorf("invalid params")
+	}
+	return nil
+}
+
+//MultiSignTransaction sign transaction by signer of an m of n multi-signature address.
+//Payer is set to the multi-signature address if transaction has no payer
+func MultiSignTransaction(tx *types.MutableTransaction, m uint16, pubKeys []keypair.PublicKey, signer account.Signer) error {
+	err := checkMultiSigParam(m, pubKeys)
+	if err != nil {
+		return err
+	}
+	validPubKey := false
+	for _, pk := range pubKeys {
+		if keypair.ComparePublicKey(pk, signer.PubKey()) {
+			validPubKey = true
+			break
+		}
+	}
+	if !validPubKey {
+		return fmt.Errorf("invalid signer")
+	}
+	if tx.Payer == common.ADDRESS_EMPTY {
+		payer, err := types.AddressFromMultiPubKeys(pubKeys, int(m))
+		if err != nil {
+			return fmt.Errorf("AddressFromMultiPubKeys error:%s", err)
+		}
+		tx.Payer = payer
+	}
+
+	if len(tx.Sigs) == 0 {
+		tx.Sigs = make([]types.Sig, 0)
+	}
+
+	txHash := tx.Hash()
+	sigData, err := signer.Sign(txHash.ToArray())
+	if err != nil {
+		return fmt.Errorf("sign error:%s", err)
+	}
+
+	hasMutilSig := false
+	for i, sigs := range tx.Sigs {
+		if !pubKeysEqual(sigs.PubKeys, pubKeys) {
+			continue
+		}
+		hasMutilSig = true
+		if hasAlreadySig(txHash.ToArray(), signer.PubKey(), sigs.SigData) {
+			break
+		}
+		sigs.SigData = append(sigs.SigData, sigData)
+		tx.Sigs[i] = sigs
+		break
+	}
+	if !hasMutilSig {
+		if len(tx.Sigs) >= constants.TX_MAX_SIG_SIZE {
+			return fmt.Errorf("too many signatures of transaction")
+		}
+		tx.Sigs = append(tx.Sigs, types.Sig{
+			PubKeys: pubKeys,
+			M:       m,
+			SigData: [][]byte{sigData},
+		})
+	}
+	return nil
+}
+
+func hasAlreadySig(data []byte, pk keypair.PublicKey, sigDatas [][]byte) bool {
+	for _, sigData := range sigDatas {
+		err := signature.Verify(pk, data, sigData)
+		if err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func pubKeysEqual(pks1, pks2 []keypair.PublicKey) bool {
+	if len(pks1) != len(pks2) {
+		return false
+	}
+	size := len(pks1)
+	if size == 0 {
+		return true
+	}
+	pkstr1 := make([]string, 0, size)
+	for _, pk := range pks1 {
+		pkstr1 = append(pkstr1, hex.EncodeToString(keypair.SerializePublicKey(pk)))
+	}
+	pkstr2 := make([]string, 0, size)
+	for _, pk := range pks2 {
+		pkstr2 = append(pkstr2, hex.EncodeToString(keypair.SerializePublicKey(pk)))
+	}
+	sort.Strings(pkstr1)
+	sort.Strings(pkstr2)
+	for i := 0; i < size; i++ {
+		if pkstr1[i] != pkstr2[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/ontio/ontology/common"
+	vm "github.com/ontio/ontology/vm/neovm"
+)
+
+//Instruction is a NeoVM instruction disassembled from code
+type Instruction struct {
+	Offset  int
+	OpCode  vm.OpCode
+	Name    string
+	Operand string //hex of pushed data, jump target, contract address of APPCALL or name of SYSCALL
+}
+
+func (this *Instruction) String() string {
+	if this.Operand == "" {
+		return fmt.Sprintf("%04x %s", this.Offset, this.Name)
+	}
+	return fmt.Sprintf("%04x %s %s", this.Offset, this.Name, this.Operand)
+}
+
+//DisassembleNeoVMCode disassemble NeoVM code to instructions
+func DisassembleNeoVMCode(code []byte) ([]*Instruction, error) {
+	source := common.NewZeroCopySource(code)
+	instrs := make([]*Instruction, 0)
+	for source.Len() > 0 {
+		offset := int(source.Pos())
+		b, _ := source.NextByte()
+		op := vm.OpCode(b)
+		instr := &Instruction{
+			Offset: offset,
+			OpCode: op,
+			Name:   opCodeName(op),
+		}
+		var eof bool
+		switch {
+		case op >= vm.PUSHBYTES1 && op <= vm.PUSHBYTES75:
+			var data []byte
+			data, eof = source.NextBytes(uint64(op))
+			instr.Operand = hex.EncodeToString(data)
+		case op == vm.PUSHDATA1 || op == vm.PUSHDATA2 || op == vm.PUSHDATA4:
+			var size uint64
+			switch op {
+			case vm.PUSHDATA1:
+				l, e := source.NextUint8()
+				size, eof = uint64(l), e
+			case vm.PUSHDATA2:
+				l, e := source.NextUint16()
+				size, eof = uint64(l), e
+			default:
+				l, e := source.NextUint32()
+				size, eof = uint64(l), e
+			}
+			if !eof {
+				var data []byte
+				data, eof = source.NextBytes(size)
+				instr.Operand = hex.EncodeToString(data)
+			}
+		case op == vm.JMP || op == vm.JMPIF || op == vm.JMPIFNOT || op == vm.CALL:
+			var num int16
+			num, eof = source.NextInt16()
+			//jump offset is relative to the jump instruction
+			instr.Operand = fmt.Sprintf("%04x", offset+int(num))
+		case op == vm.APPCALL || op == vm.TAILCALL:
+			var addr common.Address
+			addr, eof = source.NextAddress()
+			instr.Operand = addr.ToHexString()
+		case op == vm.SYSCALL:
+			var irregular bool
+			instr.Operand, _, irregular, eof = source.NextString()
+			if irregular {
+				return nil, fmt.Errorf("irregular syscall name at offset:%d", offset)
+			}
+		}
+		if eof {
+			return nil, fmt.Errorf("operand of %s out of code at offset:%d", instr.Name, offset)
+		}
+		instrs = append(instrs, instr)
+	}
+	return instrs, nil
+}
+
+func opCodeName(op vm.OpCode) string {
+	if name := vm.OpExecList[op].Name; name != "" {
+		return name
+	}
+	if op >= vm.PUSHBYTES1 && op <= vm.PUSHBYTES75 {
+		return fmt.Sprintf("PUSHBYTES%d", op)
+	}
+	return fmt.Sprintf("UNKNOWN(%02x)", byte(op))
+}
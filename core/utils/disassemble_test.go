@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	vm "github.com/ontio/ontology/vm/neovm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisassembleNeoVMCode(t *testing.T) {
+	contract := common.Address{1, 2, 3}
+	code := []byte{3, 'a', 'b', 'c', byte(vm.PUSH0), byte(vm.PACK), byte(vm.JMP), 0x05, 0x00, byte(vm.PUSH0)}
+	code = append(code, byte(vm.APPCALL))
+	code = append(code, contract[:]...)
+	code = append(code, byte(vm.SYSCALL), 3, 'f', 'o', 'o')
+
+	instrs, err := DisassembleNeoVMCode(code)
+	assert.Nil(t, err)
+	lines := make([]string, 0, len(instrs))
+	for _, instr := range instrs {
+		lines = append(lines, instr.String())
+	}
+	assert.Equal(t, []string{
+		"0000 PUSHBYTES3 616263",
+		"0004 PUSH0",
+		"0005 PACK",
+		"0006 JMP 000b",
+		"0009 PUSH0",
+		"000a APPCALL " + contract.ToHexString(),
+		"001f SYSCALL foo",
+	}, lines)
+
+	_, err = DisassembleNeoVMCode([]byte{3, 1, 2})
+	assert.NotNil(t, err)
+	_, err = DisassembleNeoVMCode([]byte{byte(vm.APPCALL), 1})
+	assert.NotNil(t, err)
+}
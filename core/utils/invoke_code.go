@@ -0,0 +1,247 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ontio/ontology/common"
+	neovm "github.com/ontio/ontology/smartcontract/service/neovm"
+	vm "github.com/ontio/ontology/vm/neovm"
+)
+
+//InvokeCodeInfo is the contract invocation decoded from invoke code
+type InvokeCodeInfo struct {
+	Native   bool
+	Contract common.Address
+	Version  byte
+	Method   string
+	//Params item is []byte, *big.Int, or []interface{} of array and struct
+	Params []interface{}
+}
+
+//invokeArray is array or struct on stack, which may be appended after pushed
+type invokeArray struct {
+	items []interface{}
+}
+
+//DecodeInvokeCode decode invoke code built by BuildNativeInvokeCode or neovm param builder with APPCALL.
+//Only push, PACK and struct building opcodes are accepted before the call
+func DecodeInvokeCode(code []byte) (*InvokeCodeInfo, error) {
+	source := common.NewZeroCopySource(code)
+	stack := make([]interface{}, 0)
+	altStack := make([]interface{}, 0)
+	pop := func() (interface{}, error) {
+		if len(stack) == 0 {
+			return nil, fmt.Errorf("stack is empty")
+		}
+		item := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return item, nil
+	}
+	for {
+		b, eof := source.NextByte()
+		if eof {
+			return nil, fmt.Errorf("no contract call in code")
+		}
+		op := vm.OpCode(b)
+		switch {
+		case op == vm.PUSH0:
+			stack = append(stack, []byte{})
+		case op >= vm.PUSHBYTES1 && op <= vm.PUSHBYTES75:
+			data, eof := source.NextBytes(uint64(op))
+			if eof {
+				return nil, fmt.Errorf("push bytes out of code")
+			}
+			stack = append(stack, data)
+		case op == vm.PUSHDATA1 || op == vm.PUSHDATA2 || op == vm.PUSHDATA4:
+			var size uint64
+			switch op {
+			case vm.PUSHDATA1:
+				l, e := source.NextUint8()
+				size, eof = uint64(l), e
+			case vm.PUSHDATA2:
+				l, e := source.NextUint16()
+				size, eof = uint64(l), e
+			default:
+				l, e := source.NextUint32()
+				size, eof = uint64(l), e
+			}
+			if eof {
+				return nil, fmt.Errorf("push data out of code")
+			}
+			data, eof := source.NextBytes(size)
+			if eof {
+				return nil, fmt.Errorf("push data out of code")
+			}
+			stack = append(stack, data)
+		case op == vm.PUSHM1:
+			stack = append(stack, big.NewInt(-1))
+		case op >= vm.PUSH1 && op <= vm.PUSH16:
+			stack = append(stack, big.NewInt(int64(op)-int64(vm.PUSH1)+1))
+		case op == vm.NEWSTRUCT:
+			item, err := pop()
+			if err != nil {
+				return nil, err
+			}
+			//builders create empty struct and append items, a struct pre-filled with false items is not decoded
+			n, err := ParamToBigInt(item)
+			if err != nil || n.Sign() != 0 {
+				return nil, fmt.Errorf("unsupported struct size")
+			}
+			stack = append(stack, &invokeArray{})
+		case op == vm.PACK:
+			item, err := pop()
+			if err != nil {
+				return nil, err
+			}
+			n, err := ParamToBigInt(item)
+			if err != nil || n.Sign() < 0 || n.Int64() > int64(len(stack)) {
+				return nil, fmt.Errorf("invalid pack size")
+			}
+			array := &invokeArray{}
+			for i := int64(0); i < n.Int64(); i++ {
+				item, _ := pop()
+				array.items = append(array.items, item)
+			}
+			stack = append(stack, array)
+		case op == vm.APPEND:
+			item, err := pop()
+			if err != nil {
+				return nil, err
+			}
+			top, err := pop()
+			if err != nil {
+				return nil, err
+			}
+			array, ok := top.(*invokeArray)
+			if !ok {
+				return nil, fmt.Errorf("append to non array")
+			}
+			array.items = append(array.items, item)
+		case op == vm.TOALTSTACK:
+			item, err := pop()
+			if err != nil {
+				return nil, err
+			}
+			altStack = append(altStack, item)
+		case op == vm.DUPFROMALTSTACK || op == vm.FROMALTSTACK:
+			if len(altStack) == 0 {
+				return nil, fmt.Errorf("alt stack is empty")
+			}
+			stack = append(stack, altStack[len(altStack)-1])
+			if op == vm.FROMALTSTACK {
+				altStack = altStack[:len(altStack)-1]
+			}
+		case op == vm.SYSCALL:
+			name, _, irregular, eof := source.NextString()
+			if irregular || eof || name != neovm.NATIVE_INVOKE_NAME || source.Len() != 0 {
+				return nil, fmt.Errorf("unsupported syscall")
+			}
+			if len(stack) < 3 {
+				return nil, fmt.Errorf("invalid native invoke code")
+			}
+			version, err := ParamToBigInt(stack[len(stack)-1])
+			if err != nil || version.Sign() < 0 || version.Int64() > 0xff {
+				return nil, fmt.Errorf("invalid native contract version")
+			}
+			addr, err := ParamToAddress(stack[len(stack)-2])
+			if err != nil {
+				return nil, err
+			}
+			method, ok := stack[len(stack)-3].([]byte)
+			if !ok {
+				return nil, fmt.Errorf("invalid native contract method")
+			}
+			params := make([]interface{}, 0, len(stack)-3)
+			for _, item := range stack[:len(stack)-3] {
+				params = append(params, toInvokeParam(item))
+			}
+			return &InvokeCodeInfo{
+				Native:   true,
+				Contract: addr,
+				Version:  byte(version.Int64()),
+				Method:   string(method),
+				Params:   params,
+			}, nil
+		case op == vm.APPCALL:
+			addr, eof := source.NextAddress()
+			if eof || source.Len() != 0 {
+				return nil, fmt.Errorf("invalid appcall")
+			}
+			//first param is on the top of stack
+			params := make([]interface{}, 0, len(stack))
+			for i := len(stack) - 1; i >= 0; i-- {
+				params = append(params, toInvokeParam(stack[i]))
+			}
+			info := &InvokeCodeInfo{
+				Contract: addr,
+				Params:   params,
+			}
+			if len(params) > 0 {
+				if method, ok := params[0].([]byte); ok {
+					info.Method = string(method)
+					info.Params = params[1:]
+				}
+			}
+			if len(info.Params) == 1 {
+				//neovm contract takes method and args array
+				if args, ok := info.Params[0].([]interface{}); ok {
+					info.Params = args
+				}
+			}
+			return info, nil
+		default:
+			return nil, fmt.Errorf("unsupported opcode:%x", byte(op))
+		}
+	}
+}
+
+func toInvokeParam(item interface{}) interface{} {
+	array, ok := item.(*invokeArray)
+	if !ok {
+		return item
+	}
+	params := make([]interface{}, 0, len(array.items))
+	for _, item := range array.items {
+		params = append(params, toInvokeParam(item))
+	}
+	return params
+}
+
+//ParamToBigInt convert decoded invoke param to integer
+func ParamToBigInt(param interface{}) (*big.Int, error) {
+	switch v := param.(type) {
+	case *big.Int:
+		return v, nil
+	case []byte:
+		return common.BigIntFromNeoBytes(v), nil
+	}
+	return nil, fmt.Errorf("param is not integer")
+}
+
+//ParamToAddress convert decoded invoke param to address
+func ParamToAddress(param interface{}) (common.Address, error) {
+	data, ok := param.([]byte)
+	if !ok {
+		return common.ADDRESS_EMPTY, fmt.Errorf("param is not address")
+	}
+	return common.AddressParseFromBytes(data)
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	vm "github.com/ontio/ontology/vm/neovm"
+	"github.com/stretchr/testify/assert"
+)
+
+type testState struct {
+	From  common.Address
+	To    common.Address
+	Value uint64
+}
+
+func TestDecodeNativeInvokeCode(t *testing.T) {
+	contract := common.AddressFromVmCode([]byte("contract"))
+	from := common.AddressFromVmCode([]byte("from"))
+	to := common.AddressFromVmCode([]byte("to"))
+	sts := []*testState{{From: from, To: to, Value: 10}, {From: from, To: to, Value: 1000000}}
+	code, err := BuildNativeInvokeCode(contract, 0, "transfer", []interface{}{sts})
+	assert.Nil(t, err)
+
+	info, err := DecodeInvokeCode(code)
+	assert.Nil(t, err)
+	assert.True(t, info.Native)
+	assert.Equal(t, contract, info.Contract)
+	assert.Equal(t, byte(0), info.Version)
+	assert.Equal(t, "transfer", info.Method)
+	assert.Equal(t, 1, len(info.Params))
+	states := info.Params[0].([]interface{})
+	assert.Equal(t, 2, len(states))
+	for i, item := range states {
+		state := item.([]interface{})
+		assert.Equal(t, 3, len(state))
+		addr, err := ParamToAddress(state[0])
+		assert.Nil(t, err)
+		assert.Equal(t, from, addr)
+		addr, err = ParamToAddress(state[1])
+		assert.Nil(t, err)
+		assert.Equal(t, to, addr)
+		value, err := ParamToBigInt(state[2])
+		assert.Nil(t, err)
+		assert.Equal(t, sts[i].Value, value.Uint64())
+	}
+}
+
+func TestDecodeNeoVMInvokeCode(t *testing.T) {
+	contract := common.AddressFromVmCode([]byte("contract"))
+	builder := vm.NewParamsBuilder(new(bytes.Buffer))
+	err := BuildNeoVMParam(builder, []interface{}{"put", []interface{}{"key", 100, true}})
+	assert.Nil(t, err)
+	builder.EmitPushCall(contract[:])
+
+	info, err := DecodeInvokeCode(builder.ToArray())
+	assert.Nil(t, err)
+	assert.False(t, info.Native)
+	assert.Equal(t, contract, info.Contract)
+	assert.Equal(t, "put", info.Method)
+	assert.Equal(t, []interface{}{[]byte("key"), []byte{100}, big.NewInt(1)}, info.Params)
+
+	_, err = DecodeInvokeCode([]byte{byte(vm.PUSH1), byte(vm.ADD)})
+	assert.NotNil(t, err)
+	_, err = DecodeInvokeCode([]byte{byte(vm.PUSH1)})
+	assert.NotNil(t, err)
+}
+
+func TestDecodeInvokeCodeNewStruct(t *testing.T) {
+	contract := common.AddressFromVmCode([]byte("contract"))
+	builder := vm.NewParamsBuilder(new(bytes.Buffer))
+	builder.Emit(vm.PUSH0)
+	builder.Emit(vm.NEWSTRUCT)
+	builder.Emit(vm.TOALTSTACK)
+	builder.Emit(vm.DUPFROMALTSTACK)
+	builder.EmitPushInteger(big.NewInt(5))
+	builder.Emit(vm.APPEND)
+	builder.Emit(vm.FROMALTSTACK)
+	builder.EmitPushByteArray([]byte("put"))
+	builder.EmitPushCall(contract[:])
+	info, err := DecodeInvokeCode(builder.ToArray())
+	assert.Nil(t, err)
+	assert.Equal(t, "put", info.Method)
+	assert.Equal(t, []interface{}{big.NewInt(5)}, info.Params)
+
+	//struct of non-zero size is rejected instead of losing its items
+	builder = vm.NewParamsBuilder(new(bytes.Buffer))
+	builder.Emit(vm.PUSH2)
+	builder.Emit(vm.NEWSTRUCT)
+	builder.EmitPushCall(contract[:])
+	_, err = DecodeInvokeCode(builder.ToArray())
+	assert.NotNil(t, err)
+}
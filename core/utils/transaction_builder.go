@@ -93,6 +93,17 @@ func BuildNativeInvokeCode(contractAddress common.Address, version byte, method
 	return builder.ToArray(), nil
 }
 
+//BuildNeoVMInvokeCode build NeoVM Invoke code for params
+func BuildNeoVMInvokeCode(contractAddress common.Address, params []interface{}) ([]byte, error) {
+	builder := vm.NewParamsBuilder(new(bytes.Buffer))
+	err := BuildNeoVMParam(builder, params)
+	if err != nil {
+		return nil, err
+	}
+	builder.EmitPushCall(contractAddress[:])
+	return builder.ToArray(), nil
+}
+
 //buildNeoVMParamInter build neovm invoke param code
 func BuildNeoVMParam(builder *vm.ParamsBuilder, smartContractParams []interface{}) error {
 	//VM load params in reverse order
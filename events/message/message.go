@@ -29,6 +29,16 @@ const (
 	TOPIC_NODE_DISCONNECT           = "noddis"
 	TOPIC_NODE_CONSENSUS_DISCONNECT = "nodcnsdis"
 	TOPIC_SMART_CODE_EVENT          = "scevt"
+	TOPIC_NEW_TRANSACTION           = "newtx"
+	TOPIC_TX_STATUS                 = "txstatus"
+)
+
+//status of transaction in its lifecycle, verified is published by TOPIC_NEW_TRANSACTION
+const (
+	TX_STATUS_VERIFIED = "verified" //Entered tx pool after verification
+	TX_STATUS_REJECTED = "rejected" //Refused by tx pool
+	TX_STATUS_EVICTED  = "evicted"  //Dropped from tx pool without being included
+	TX_STATUS_INCLUDED = "included" //Saved in a block, which is final
 )
 
 type SaveBlockCompleteMsg struct {
@@ -43,6 +53,16 @@ type SmartCodeEventMsg struct {
 	Event *types.SmartCodeEvent
 }
 
+type NewTransactionMsg struct {
+	Tx *types.Transaction
+}
+
+type TxStatusMsg struct {
+	Tx     *types.Transaction
+	Status string
+	Reason string
+}
+
 type BlockConsensusComplete struct {
 	Block *types.Block
 }
@@ -19,6 +19,9 @@
 package actor
 
 import (
+	"errors"
+	"time"
+
 	"github.com/ontio/ontology-eventbus/actor"
 	cactor "github.com/ontio/ontology/consensus/actor"
 )
@@ -44,3 +47,38 @@ func ConsensusSrvHalt() error {
 	}
 	return nil
 }
+
+//generate empty blocks by consensus actor, only supported by solo consensus
+func ConsensusGenBlocks(count uint32) (uint32, error) {
+	if consensusSrvPid == nil {
+		return 0, errors.New("consensus service not started")
+	}
+	timeout := time.Duration(REQ_TIMEOUT+count) * time.Second
+	future := consensusSrvPid.RequestFuture(&cactor.GenBlocks{Count: count}, timeout)
+	result, err := future.Result()
+	if err != nil {
+		return 0, err
+	}
+	rsp, ok := result.(*cactor.GenBlocksRsp)
+	if !ok {
+		return 0, errors.New("fail")
+	}
+	return rsp.Height, rsp.Error
+}
+
+//increase the timestamp of following blocks, only supported by solo consensus
+func ConsensusIncreaseTime(seconds uint32) (uint32, error) {
+	if consensusSrvPid == nil {
+		return 0, errors.New("consensus service not started")
+	}
+	future := consensusSrvPid.RequestFuture(&cactor.IncreaseTime{Seconds: seconds}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		return 0, err
+	}
+	rsp, ok := result.(*cactor.IncreaseTimeRsp)
+	if !ok {
+		return 0, errors.New("fail")
+	}
+	return rsp.Offset, rsp.Error
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/core/ledger"
 	"github.com/ontio/ontology/core/payload"
+	scom "github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/smartcontract/event"
 	cstate "github.com/ontio/ontology/smartcontract/states"
@@ -62,6 +63,11 @@ func GetCurrentBlockHeight() uint32 {
 	return ledger.DefLedger.GetCurrentBlockHeight()
 }
 
+//GetCurrentHeaderHeight from ledger
+func GetCurrentHeaderHeight() uint32 {
+	return ledger.DefLedger.GetCurrentHeaderHeight()
+}
+
 //GetTransaction from ledger
 func GetTransaction(hash common.Uint256) (*types.Transaction, error) {
 	return ledger.DefLedger.GetTransaction(hash)
@@ -89,6 +95,17 @@ func PreExecuteContract(tx *types.Transaction) (*cstate.PreExecResult, error) {
 	return ledger.DefLedger.PreExecuteContract(tx)
 }
 
+//DebugExecuteContract from ledger
+func DebugExecuteContract(tx *types.Transaction, height uint32,
+	overrides *cstate.StateOverrides) (*cstate.DebugExecResult, error) {
+	return ledger.DefLedger.DebugExecuteContract(tx, height, overrides)
+}
+
+//TraceTransaction from ledger
+func TraceTransaction(txHash common.Uint256) (*cstate.TxTraceResult, error) {
+	return ledger.DefLedger.TraceTransaction(txHash)
+}
+
 //GetEventNotifyByTxHash from ledger
 func GetEventNotifyByTxHash(txHash common.Uint256) (*event.ExecuteNotify, error) {
 	return ledger.DefLedger.GetEventNotifyByTx(txHash)
@@ -99,6 +116,37 @@ func GetEventNotifyByHeight(height uint32) ([]*event.ExecuteNotify, error) {
 	return ledger.DefLedger.GetEventNotifyByBlock(height)
 }
 
+//GetEventNotifyByContract from ledger
+func GetEventNotifyByContract(contract common.Address, topic string, startHeight, endHeight uint32,
+	offset, limit uint32) ([]*event.ContractEventNotify, error) {
+	return ledger.DefLedger.GetEventNotifyByContract(contract, topic, startHeight, endHeight, offset, limit)
+}
+
+//GetAddressTxs from ledger
+func GetAddressTxs(addr common.Address, startHeight, endHeight uint32, offset, limit uint32) ([]*scom.AddressTx, error) {
+	return ledger.DefLedger.GetAddressTxs(addr, startHeight, endHeight, offset, limit)
+}
+
+//GetIndexStartHeight from ledger
+func GetIndexStartHeight(prefix scom.DataEntryPrefix) (uint32, error) {
+	return ledger.DefLedger.GetIndexStartHeight(prefix)
+}
+
+//GetContractAbi from ledger
+func GetContractAbi(contract common.Address) ([]byte, error) {
+	return ledger.DefLedger.GetContractAbi(contract)
+}
+
+//PutContractAbi to ledger
+func PutContractAbi(contract common.Address, abiData []byte) error {
+	return ledger.DefLedger.PutContractAbi(contract, abiData)
+}
+
+//DeleteContractAbi from ledger
+func DeleteContractAbi(contract common.Address) error {
+	return ledger.DefLedger.DeleteContractAbi(contract)
+}
+
 //GetMerkleProof from ledger
 func GetMerkleProof(proofHeight uint32, rootHeight uint32) ([]common.Uint256, error) {
 	return ledger.DefLedger.GetMerkleProof(proofHeight, rootHeight)
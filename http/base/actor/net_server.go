@@ -26,6 +26,7 @@ import (
 	"github.com/ontio/ontology/common/log"
 	ac "github.com/ontio/ontology/p2pserver/actor/server"
 	"github.com/ontio/ontology/p2pserver/common"
+	"github.com/ontio/ontology/p2pserver/peer"
 )
 
 var netServerPid *actor.PID
@@ -204,3 +205,80 @@ func GetNodeType() (uint64, error) {
 	}
 	return r.NodeType, nil
 }
+
+//GetPeerInfos from netSever actor
+func GetPeerInfos() ([]*ac.PeerInfo, error) {
+	if netServerPid == nil {
+		return []*ac.PeerInfo{}, nil
+	}
+	future := netServerPid.RequestFuture(&ac.GetPeerInfosReq{}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, err
+	}
+	r, ok := result.(*ac.GetPeerInfosRsp)
+	if !ok {
+		return nil, errors.New("fail")
+	}
+	return r.Peers, nil
+}
+
+//GetBanList from netSever actor
+func GetBanList() ([]*peer.BanEntry, error) {
+	if netServerPid == nil {
+		return []*peer.BanEntry{}, nil
+	}
+	future := netServerPid.RequestFuture(&ac.GetBanListReq{}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, err
+	}
+	r, ok := result.(*ac.GetBanListRsp)
+	if !ok {
+		return nil, errors.New("fail")
+	}
+	return r.List, nil
+}
+
+//BanPeer ban peer id or ip by netSever actor
+func BanPeer(id uint64, ip string, duration time.Duration, reason string) error {
+	if netServerPid == nil {
+		return errors.New("net server not started")
+	}
+	req := &ac.BanPeerReq{
+		Id:       id,
+		Ip:       ip,
+		Duration: duration,
+		Reason:   reason,
+	}
+	future := netServerPid.RequestFuture(req, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return err
+	}
+	if _, ok := result.(*ac.BanPeerRsp); !ok {
+		return errors.New("fail")
+	}
+	return nil
+}
+
+//UnbanPeer unban peer id or ip by netSever actor
+func UnbanPeer(id uint64, ip string) (bool, error) {
+	if netServerPid == nil {
+		return false, errors.New("net server not started")
+	}
+	future := netServerPid.RequestFuture(&ac.UnbanPeerReq{Id: id, Ip: ip}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return false, err
+	}
+	r, ok := result.(*ac.UnbanPeerRsp)
+	if !ok {
+		return false, errors.New("fail")
+	}
+	return r.Unbanned, nil
+}
@@ -0,0 +1,182 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package auth privides authentication, rate limiting and request size limit of http servers
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ontio/ontology/common/config"
+)
+
+//Group is the permission level of methods, a higher group is granted all lower groups
+type Group byte
+
+const (
+	GROUP_NONE   Group = iota //No permission
+	GROUP_READ                //Query ledger and node state
+	GROUP_SUBMIT              //Send transactions
+	GROUP_ADMIN               //Debug and node management
+)
+
+const (
+	AUTH_HEADER   = "Authorization"
+	BEARER_PREFIX = "Bearer "
+	TOKEN_QUERY   = "token" //Query parameter of token, for websocket clients which cannot set headers
+
+	PREEXEC_METHOD = "preexectransaction" //Method name to authorize sendrawtransaction with pre-execution
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+//methodGroups is the group of methods, keyed by rpc method or restful/websocket action name. Methods executing
+//contract code are admin, as they are expensive, and methods not listed are admin too
+var methodGroups = map[string]Group{
+	"getallowance":              GROUP_READ,
+	"getaddresstxs":             GROUP_READ,
+	"getbalance":                GROUP_READ,
+	"getbestblockhash":          GROUP_READ,
+	"getblock":                  GROUP_READ,
+	"getblockbyhash":            GROUP_READ,
+	"getblockbyheight":          GROUP_READ,
+	"getblockcount":             GROUP_READ,
+	"getblockhash":              GROUP_READ,
+	"getblockheight":            GROUP_READ,
+	"getblockheightbytxhash":    GROUP_READ,
+	"getblocktxsbyheight":       GROUP_READ,
+	"getconnectioncount":        GROUP_READ,
+	"getcontract":               GROUP_READ,
+	"getcontractabi":            GROUP_READ,
+	"getcontractstate":          GROUP_READ,
+	"getevents":                 GROUP_READ,
+	"getgasprice":               GROUP_READ,
+	"getgrantong":               GROUP_READ,
+	"getmempooltxcount":         GROUP_READ,
+	"getmempooltxstate":         GROUP_READ,
+	"getmerkleproof":            GROUP_READ,
+	"getnetworkid":              GROUP_READ,
+	"getrawmempool":             GROUP_READ,
+	"getrawtransaction":         GROUP_READ,
+	"getsmartcodeevent":         GROUP_READ,
+	"getsmartcodeeventbyhash":   GROUP_READ,
+	"getsmartcodeeventbyheight": GROUP_READ,
+	"getstorage":                GROUP_READ,
+	"gettransaction":            GROUP_READ,
+	"getunboundong":             GROUP_READ,
+	"getversion":                GROUP_READ,
+	"graphql":                   GROUP_READ,
+	"heartbeat":                 GROUP_READ,
+	"subscribe":                 GROUP_READ,
+	"sendrawtransaction":        GROUP_SUBMIT,
+	PREEXEC_METHOD:              GROUP_ADMIN,
+	"estimategas":               GROUP_ADMIN,
+	"dryruntransaction":         GROUP_ADMIN,
+	"tracetransaction":          GROUP_ADMIN,
+	"getsessioncount":           GROUP_ADMIN,
+	"setcontractabi":            GROUP_ADMIN,
+	"deletecontractabi":         GROUP_ADMIN,
+}
+
+//MethodGroup return the group required by method
+func MethodGroup(method string) Group {
+	if group, ok := methodGroups[method]; ok {
+		return group
+	}
+	return GROUP_ADMIN
+}
+
+//Authenticate return the group granted to the credential of request
+func Authenticate(r *http.Request) (Group, error) {
+	cfg := config.DefConfig.Auth
+	if !cfg.EnableAuth {
+		return GROUP_ADMIN, nil
+	}
+	token := getToken(r)
+	if token == "" {
+		if cfg.PublicRead {
+			return GROUP_READ, nil
+		}
+		return GROUP_NONE, nil
+	}
+	if group := keyGroup(cfg, token); group != GROUP_NONE {
+		return group, nil
+	}
+	if cfg.JwtSecret != "" && strings.Count(token, ".") == 2 {
+		group, err := parseJwt(token, []byte(cfg.JwtSecret))
+		if err != nil {
+			return GROUP_NONE, err
+		}
+		return group, nil
+	}
+	return GROUP_NONE, ErrInvalidToken
+}
+
+//Authorize check the group granted to request is enough for method
+func Authorize(granted Group, method string) error {
+	if granted < MethodGroup(method) {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+func getToken(r *http.Request) string {
+	header := r.Header.Get(AUTH_HEADER)
+	if strings.HasPrefix(header, BEARER_PREFIX) {
+		return strings.TrimSpace(header[len(BEARER_PREFIX):])
+	}
+	return r.URL.Query().Get(TOKEN_QUERY)
+}
+
+func keyGroup(cfg *config.AuthConfig, token string) Group {
+	groups := []struct {
+		keys  []string
+		group Group
+	}{
+		{cfg.AdminKeys, GROUP_ADMIN},
+		{cfg.SubmitKeys, GROUP_SUBMIT},
+		{cfg.ReadKeys, GROUP_READ},
+	}
+	for _, g := range groups {
+		for _, key := range g.keys {
+			if key != "" && subtle.ConstantTimeCompare([]byte(key), []byte(token)) == 1 {
+				return g.group
+			}
+		}
+	}
+	return GROUP_NONE
+}
+
+//GroupFromName parse the group name used in jwt scope
+func GroupFromName(name string) Group {
+	switch name {
+	case "read":
+		return GROUP_READ
+	case "submit":
+		return GROUP_SUBMIT
+	case "admin":
+		return GROUP_ADMIN
+	}
+	return GROUP_NONE
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package auth
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology/common/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func newJwt(secret string, header, claims string) string {
+	input := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(claims))
+	return input + "." + base64.RawURLEncoding.EncodeToString(signJwt(input, []byte(secret)))
+}
+
+func TestAuthenticate(t *testing.T) {
+	cfg := config.DefConfig.Auth
+	cfg.EnableAuth = true
+	cfg.ReadKeys = []string{"r1"}
+	cfg.SubmitKeys = []string{"s1"}
+	cfg.AdminKeys = []string{"a1"}
+	cfg.JwtSecret = "secret"
+	defer func() {
+		*cfg = config.AuthConfig{MaxRequestBytes: config.DEFAULT_API_MAX_REQUEST_BYTES}
+	}()
+
+	authenticate := func(token string) (Group, error) {
+		r := httptest.NewRequest("POST", "/", nil)
+		if token != "" {
+			r.Header.Set(AUTH_HEADER, BEARER_PREFIX+token)
+		}
+		return Authenticate(r)
+	}
+	group, err := authenticate("")
+	assert.Nil(t, err)
+	assert.Equal(t, GROUP_NONE, group)
+	cfg.PublicRead = true
+	group, _ = authenticate("")
+	assert.Equal(t, GROUP_READ, group)
+
+	group, _ = authenticate("r1")
+	assert.Equal(t, GROUP_READ, group)
+	group, _ = authenticate("s1")
+	assert.Equal(t, GROUP_SUBMIT, group)
+	group, _ = authenticate("a1")
+	assert.Equal(t, GROUP_ADMIN, group)
+	_, err = authenticate("x1")
+	assert.Equal(t, ErrInvalidToken, err)
+
+	header := `{"alg":"HS256","typ":"JWT"}`
+	exp := time.Now().Add(time.Hour).Unix()
+	group, err = authenticate(newJwt("secret", header, fmt.Sprintf(`{"scope":"submit","exp":%d}`, exp)))
+	assert.Nil(t, err)
+	assert.Equal(t, GROUP_SUBMIT, group)
+	_, err = authenticate(newJwt("other", header, fmt.Sprintf(`{"scope":"submit","exp":%d}`, exp)))
+	assert.NotNil(t, err)
+	_, err = authenticate(newJwt("secret", header, `{"scope":"admin","exp":1}`))
+	assert.NotNil(t, err)
+	_, err = authenticate(newJwt("secret", `{"alg":"none"}`, `{"scope":"admin"}`))
+	assert.NotNil(t, err)
+
+	r := httptest.NewRequest("GET", "/?"+TOKEN_QUERY+"=a1", nil)
+	group, _ = Authenticate(r)
+	assert.Equal(t, GROUP_ADMIN, group)
+}
+
+func TestAuthorize(t *testing.T) {
+	assert.Nil(t, Authorize(GROUP_READ, "getblock"))
+	assert.Equal(t, ErrUnauthorized, Authorize(GROUP_NONE, "getblock"))
+	assert.Equal(t, ErrUnauthorized, Authorize(GROUP_READ, "sendrawtransaction"))
+	assert.Nil(t, Authorize(GROUP_SUBMIT, "sendrawtransaction"))
+	assert.Equal(t, ErrUnauthorized, Authorize(GROUP_SUBMIT, "tracetransaction"))
+	assert.Nil(t, Authorize(GROUP_ADMIN, "tracetransaction"))
+}
+
+func TestLimiter(t *testing.T) {
+	limiter := NewLimiter(1, 2)
+	assert.True(t, limiter.Allow("a"))
+	assert.True(t, limiter.Allow("a"))
+	assert.False(t, limiter.Allow("a"))
+	assert.True(t, limiter.Allow("b"))
+
+	limiter.buckets["a"].last = time.Now().Add(-time.Second)
+	assert.True(t, limiter.Allow("a"))
+	assert.False(t, limiter.Allow("a"))
+
+	limiter.buckets["b"].last = time.Now().Add(-time.Hour)
+	limiter.sweep(time.Now())
+	_, ok := limiter.buckets["b"]
+	assert.False(t, ok)
+
+	unlimited := NewLimiter(0, 0)
+	for i := 0; i < 100; i++ {
+		assert.True(t, unlimited.Allow("a"))
+	}
+}
+
+func TestLimiterAllowN(t *testing.T) {
+	limiter := NewLimiter(1, 5)
+	assert.True(t, limiter.AllowN("a", 3))
+	assert.False(t, limiter.AllowN("a", 3))
+	//a rejected request takes nothing
+	assert.True(t, limiter.AllowN("a", 2))
+	assert.False(t, limiter.Allow("a"))
+	assert.False(t, limiter.AllowN("b", 6))
+	assert.True(t, limiter.AllowN("b", 0))
+}
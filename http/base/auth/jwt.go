@@ -0,0 +1,94 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+}
+
+type jwtClaims struct {
+	Scope     string `json:"scope"`
+	ExpiresAt int64  `json:"exp"`
+	NotBefore int64  `json:"nbf"`
+}
+
+//parseJwt verify the HS256 jwt and return the group of its scope claim
+func parseJwt(token string, secret []byte) (Group, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return GROUP_NONE, ErrInvalidToken
+	}
+	header := &jwtHeader{}
+	if err := decodeJwtPart(parts[0], header); err != nil {
+		return GROUP_NONE, err
+	}
+	if header.Alg != "HS256" {
+		return GROUP_NONE, fmt.Errorf("unsupported jwt alg:%s", header.Alg)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return GROUP_NONE, ErrInvalidToken
+	}
+	if !hmac.Equal(sig, signJwt(parts[0]+"."+parts[1], secret)) {
+		return GROUP_NONE, ErrInvalidToken
+	}
+	claims := &jwtClaims{}
+	if err := decodeJwtPart(parts[1], claims); err != nil {
+		return GROUP_NONE, err
+	}
+	now := time.Now().Unix()
+	if claims.ExpiresAt != 0 && now >= claims.ExpiresAt {
+		return GROUP_NONE, fmt.Errorf("jwt expired")
+	}
+	if claims.NotBefore != 0 && now < claims.NotBefore {
+		return GROUP_NONE, fmt.Errorf("jwt not valid yet")
+	}
+	group := GroupFromName(claims.Scope)
+	if group == GROUP_NONE {
+		return GROUP_NONE, fmt.Errorf("invalid jwt scope:%s", claims.Scope)
+	}
+	return group, nil
+}
+
+func decodeJwtPart(part string, obj interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	if err := json.Unmarshal(data, obj); err != nil {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+func signJwt(signingInput string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
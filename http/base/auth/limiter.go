@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package auth
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ontio/ontology/common/config"
+)
+
+const LIMITER_SWEEP_INTERVAL = time.Minute //Interval to drop the buckets of idle clients
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+//Limiter is a token bucket rate limiter per client
+type Limiter struct {
+	lock      sync.Mutex
+	rate      float64
+	burst     float64
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+//NewLimiter return a limiter allowing rate requests per second and burst requests at once
+func NewLimiter(rate, burst uint) *Limiter {
+	if burst == 0 {
+		burst = rate
+	}
+	return &Limiter{
+		rate:      float64(rate),
+		burst:     float64(burst),
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+//Allow take a token of client, return false if client run out of tokens
+func (this *Limiter) Allow(client string) bool {
+	return this.AllowN(client, 1)
+}
+
+//AllowN take n tokens of client at once, return false and take nothing if client has less than n tokens
+func (this *Limiter) AllowN(client string, n int) bool {
+	if this.rate == 0 || n <= 0 {
+		return true
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	now := time.Now()
+	if now.Sub(this.lastSweep) > LIMITER_SWEEP_INTERVAL {
+		this.sweep(now)
+	}
+	b, ok := this.buckets[client]
+	if !ok {
+		b = &bucket{tokens: this.burst, last: now}
+		this.buckets[client] = b
+	} else {
+		b.tokens += now.Sub(b.last).Seconds() * this.rate
+		if b.tokens > this.burst {
+			b.tokens = this.burst
+		}
+		b.last = now
+	}
+	if b.tokens < float64(n) {
+		return false
+	}
+	b.tokens -= float64(n)
+	return true
+}
+
+//sweep drop the buckets already refilled, they are same as new ones
+func (this *Limiter) sweep(now time.Time) {
+	for client, b := range this.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*this.rate >= this.burst {
+			delete(this.buckets, client)
+		}
+	}
+	this.lastSweep = now
+}
+
+var defLimiter *Limiter
+var defLimiterOnce sync.Once
+
+//AllowRequest take a token of the client of request from the limiter of config
+func AllowRequest(r *http.Request) bool {
+	return AllowRequestN(r, 1)
+}
+
+//AllowRequestN take n tokens of the client of request from the limiter of config, for requests counted as n calls
+func AllowRequestN(r *http.Request, n int) bool {
+	defLimiterOnce.Do(func() {
+		cfg := config.DefConfig.Auth
+		defLimiter = NewLimiter(cfg.RateLimit, cfg.RateBurst)
+	})
+	return defLimiter.AllowN(clientIP(r), n)
+}
+
+//Guard apply the rate limit and request size limit, write the http error and return false if rejected
+func Guard(w http.ResponseWriter, r *http.Request) bool {
+	if !AllowRequest(r) {
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+		return false
+	}
+	maxBytes := int64(config.DefConfig.Auth.MaxRequestBytes)
+	if maxBytes > 0 && r.Body != nil {
+		if r.ContentLength > maxBytes {
+			http.Error(w, "request too large", http.StatusRequestEntityTooLarge)
+			return false
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+	}
+	return true
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
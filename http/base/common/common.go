@@ -25,25 +25,32 @@ import (
 	"fmt"
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/constants"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/common/serialization"
 	"github.com/ontio/ontology/core/ledger"
 	"github.com/ontio/ontology/core/payload"
+	scom "github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/types"
 	cutils "github.com/ontio/ontology/core/utils"
 	ontErrors "github.com/ontio/ontology/errors"
 	bactor "github.com/ontio/ontology/http/base/actor"
+	"github.com/ontio/ontology/smartcontract/abi"
 	"github.com/ontio/ontology/smartcontract/event"
 	"github.com/ontio/ontology/smartcontract/service/native/ont"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	svrneovm "github.com/ontio/ontology/smartcontract/service/neovm"
 	cstate "github.com/ontio/ontology/smartcontract/states"
 	"github.com/ontio/ontology/vm/neovm"
+	"math"
 	"strings"
 	"time"
 )
 
 const MAX_SEARCH_HEIGHT uint32 = 100
+const MAX_EVENT_QUERY_LIMIT uint32 = 100
+const GAS_ESTIMATE_MARGIN uint64 = 10 //Percent of safety margin added to the estimated gas of invoke transaction
 
 type BalanceOfRsp struct {
 	Ont string `json:"ont"`
@@ -79,9 +86,94 @@ type PreExecuteResult struct {
 	Notify []NotifyEventInfo
 }
 
+type DebugExecuteResult struct {
+	State         byte
+	Gas           uint64
+	Result        interface{}
+	Error         string
+	Notify        []NotifyEventInfo
+	GasDetail     map[string]uint64
+	Steps         []ExecStep
+	Truncated     bool
+	StorageWrites []StorageWrite
+}
+
+type ExecStep struct {
+	ContractAddress string
+	PC              int
+	Op              string
+	Gas             uint64
+}
+
+type StorageWrite struct {
+	ContractAddress string
+	Key             string
+	Value           string
+	Deleted         bool
+}
+
+type TxTraceResult struct {
+	TxHash       string
+	Height       uint32
+	StateHeight  uint32
+	State        byte
+	GasConsumed  uint64
+	Error        string
+	Notify       []NotifyEventInfo
+	Calls        []*CallFrame
+	StorageDiffs []StorageDiff
+}
+
+type CallFrame struct {
+	Kind            string
+	ContractAddress string
+	Method          string
+	Error           string
+	Calls           []*CallFrame
+}
+
+type StorageDiff struct {
+	ContractAddress string
+	Key             string
+	Before          string
+	After           string
+}
+
+type StateOverrides struct {
+	Storage  []StorageOverride
+	Balances []BalanceOverride
+}
+
+type StorageOverride struct {
+	ContractAddress string
+	Key             string
+	Value           string
+}
+
+type BalanceOverride struct {
+	Asset   string
+	Address string
+	Balance uint64
+}
+
 type NotifyEventInfo struct {
 	ContractAddress string
 	States          interface{}
+	Event           *abi.NeovmEventValue `json:",omitempty"` //decoded by contract abi, if abi is stored
+}
+
+type AddressTx struct {
+	TxHash string
+	Height uint32
+}
+
+type ContractEvent struct {
+	TxHash          string
+	Height          uint32
+	EventIndex      uint32
+	ContractAddress string
+	States          interface{}
+	Event           *abi.NeovmEventValue `json:",omitempty"`
 }
 
 type TxAttributeInfo struct {
@@ -159,6 +251,15 @@ type ConsensusInfo struct {
 	// TODO
 }
 
+type TxStatusInfo struct {
+	TxHash   string
+	Status   string
+	Reason   string `json:",omitempty"`
+	Height   uint32 `json:",omitempty"`
+	Payer    string
+	Contract string `json:",omitempty"`
+}
+
 type TXNAttrInfo struct {
 	Height  uint32
 	Type    int
@@ -176,25 +277,171 @@ func GetLogEvent(obj *event.LogEventArgs) (map[string]bool, LogEventArgs) {
 	return contractAddrs, LogEventArgs{hash.ToHexString(), addr, obj.Message}
 }
 
+//ConvertNotifyEventInfo convert notify to json format, with event decoded by contract abi
+func ConvertNotifyEventInfo(notify *event.NotifyEventInfo) NotifyEventInfo {
+	return NotifyEventInfo{
+		ContractAddress: notify.ContractAddress.ToHexString(),
+		States:          notify.States,
+		Event:           DecodeNotifyEvent(notify.ContractAddress, notify.States),
+	}
+}
+
 func GetExecuteNotify(obj *event.ExecuteNotify) (map[string]bool, ExecuteNotify) {
 	evts := []NotifyEventInfo{}
 	var contractAddrs = make(map[string]bool)
 	for _, v := range obj.Notify {
-		evts = append(evts, NotifyEventInfo{v.ContractAddress.ToHexString(), v.States})
+		evts = append(evts, ConvertNotifyEventInfo(v))
 		contractAddrs[v.ContractAddress.ToHexString()] = true
 	}
 	txhash := obj.TxHash.ToHexString()
 	return contractAddrs, ExecuteNotify{txhash, obj.State, obj.GasConsumed, evts}
 }
 
+func GetAddressTxs(addrTxs []*scom.AddressTx) []AddressTx {
+	txs := make([]AddressTx, 0, len(addrTxs))
+	for _, v := range addrTxs {
+		txs = append(txs, AddressTx{TxHash: v.TxHash.ToHexString(), Height: v.Height})
+	}
+	return txs
+}
+
+func GetContractEvents(notifies []*event.ContractEventNotify) []ContractEvent {
+	evts := make([]ContractEvent, 0, len(notifies))
+	for _, v := range notifies {
+		evts = append(evts, ContractEvent{
+			TxHash:          v.TxHash.ToHexString(),
+			Height:          v.Height,
+			EventIndex:      v.EventIndex,
+			ContractAddress: v.Notify.ContractAddress.ToHexString(),
+			States:          v.Notify.States,
+			Event:           DecodeNotifyEvent(v.Notify.ContractAddress, v.Notify.States),
+		})
+	}
+	return evts
+}
+
 func ConvertPreExecuteResult(obj *cstate.PreExecResult) PreExecuteResult {
 	evts := []NotifyEventInfo{}
 	for _, v := range obj.Notify {
-		evts = append(evts, NotifyEventInfo{v.ContractAddress.ToHexString(), v.States})
+		evts = append(evts, ConvertNotifyEventInfo(v))
 	}
 	return PreExecuteResult{obj.State, obj.Gas, obj.Result, evts}
 }
 
+func ConvertDebugExecuteResult(obj *cstate.DebugExecResult) DebugExecuteResult {
+	result := DebugExecuteResult{
+		State:         obj.State,
+		Gas:           obj.Gas,
+		Result:        obj.Result,
+		Error:         obj.Error,
+		Notify:        []NotifyEventInfo{},
+		GasDetail:     obj.GasDetail,
+		Steps:         make([]ExecStep, 0, len(obj.Steps)),
+		Truncated:     obj.Truncated,
+		StorageWrites: make([]StorageWrite, 0, len(obj.StorageWrites)),
+	}
+	for _, v := range obj.Notify {
+		result.Notify = append(result.Notify, ConvertNotifyEventInfo(v))
+	}
+	for _, v := range obj.Steps {
+		result.Steps = append(result.Steps, ExecStep{v.Contract.ToHexString(), v.PC, v.Op, v.Gas})
+	}
+	for _, v := range obj.StorageWrites {
+		if len(v.Key) < common.ADDR_LEN {
+			continue
+		}
+		contract, _ := common.AddressParseFromBytes(v.Key[:common.ADDR_LEN])
+		result.StorageWrites = append(result.StorageWrites, StorageWrite{
+			ContractAddress: contract.ToHexString(),
+			Key:             common.ToHexString(v.Key[common.ADDR_LEN:]),
+			Value:           common.ToHexString(v.Value),
+			Deleted:         v.Value == nil,
+		})
+	}
+	return result
+}
+
+func ConvertTxTraceResult(obj *cstate.TxTraceResult) TxTraceResult {
+	result := TxTraceResult{
+		TxHash:       obj.TxHash.ToHexString(),
+		Height:       obj.Height,
+		StateHeight:  obj.StateHeight,
+		State:        obj.State,
+		GasConsumed:  obj.GasConsumed,
+		Error:        obj.Error,
+		Notify:       []NotifyEventInfo{},
+		Calls:        convertCallFrames(obj.Calls),
+		StorageDiffs: make([]StorageDiff, 0, len(obj.StorageDiffs)),
+	}
+	for _, v := range obj.Notify {
+		result.Notify = append(result.Notify, ConvertNotifyEventInfo(v))
+	}
+	for _, v := range obj.StorageDiffs {
+		if len(v.Key) < common.ADDR_LEN {
+			continue
+		}
+		contract, _ := common.AddressParseFromBytes(v.Key[:common.ADDR_LEN])
+		result.StorageDiffs = append(result.StorageDiffs, StorageDiff{
+			ContractAddress: contract.ToHexString(),
+			Key:             common.ToHexString(v.Key[common.ADDR_LEN:]),
+			Before:          common.ToHexString(v.Before),
+			After:           common.ToHexString(v.After),
+		})
+	}
+	return result
+}
+
+func convertCallFrames(frames []*cstate.CallFrame) []*CallFrame {
+	calls := make([]*CallFrame, 0, len(frames))
+	for _, v := range frames {
+		calls = append(calls, &CallFrame{
+			Kind:            v.Kind,
+			ContractAddress: v.Contract.ToHexString(),
+			Method:          v.Method,
+			Error:           v.Error,
+			Calls:           convertCallFrames(v.Calls),
+		})
+	}
+	return calls
+}
+
+//ParseStateOverrides convert the state overrides of request to ledger
+func ParseStateOverrides(overrides *StateOverrides) (*cstate.StateOverrides, error) {
+	result := &cstate.StateOverrides{}
+	for _, v := range overrides.Storage {
+		contract, err := GetAddress(v.ContractAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid contract address %s", v.ContractAddress)
+		}
+		key, err := common.HexToBytes(v.Key)
+		if err != nil || len(key) == 0 {
+			return nil, fmt.Errorf("invalid storage key %s", v.Key)
+		}
+		value, err := common.HexToBytes(v.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid storage value %s", v.Value)
+		}
+		result.Storage = append(result.Storage, &cstate.StorageOverride{Contract: contract, Key: key, Value: value})
+	}
+	for _, v := range overrides.Balances {
+		var asset common.Address
+		switch strings.ToLower(v.Asset) {
+		case "ont":
+			asset = utils.OntContractAddress
+		case "ong":
+			asset = utils.OngContractAddress
+		default:
+			return nil, fmt.Errorf("unsupport asset %s", v.Asset)
+		}
+		addr, err := common.AddressFromBase58(v.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %s", v.Address)
+		}
+		result.Balances = append(result.Balances, &cstate.BalanceOverride{Asset: asset, Account: addr, Balance: v.Balance})
+	}
+	return result, nil
+}
+
 func TransArryByteToHexString(ptx *types.Transaction) *Transactions {
 	trans := new(Transactions)
 	trans.TxType = ptx.TxType
@@ -411,6 +658,41 @@ func GetGasPrice() (map[string]interface{}, error) {
 	return result, nil
 }
 
+//EstimateGas return the minimal gas limit for the transaction to be executed and accepted by tx pool. The gas of
+//invoke transaction is added a safety margin, as the execution may change with the state when it is packed
+func EstimateGas(tx *types.Transaction) (uint64, error) {
+	result, err := bactor.PreExecuteContract(tx)
+	if err != nil {
+		return 0, err
+	}
+	if result.State == event.CONTRACT_STATE_FAIL {
+		return 0, fmt.Errorf("pre-execute transaction failed")
+	}
+	gas := result.Gas
+	if tx.TxType == types.Invoke {
+		gas, err = addGasMargin(gas)
+		if err != nil {
+			return 0, err
+		}
+	}
+	minGas := svrneovm.MIN_TRANSACTION_GAS
+	if config.DefConfig.Common.GasLimit > minGas {
+		minGas = config.DefConfig.Common.GasLimit
+	}
+	if gas < minGas {
+		gas = minGas
+	}
+	return gas, nil
+}
+
+//addGasMargin add GAS_ESTIMATE_MARGIN percent to gas, rounded down
+func addGasMargin(gas uint64) (uint64, error) {
+	if gas > math.MaxUint64/(100+GAS_ESTIMATE_MARGIN) {
+		return 0, fmt.Errorf("gas %d overflows with estimate margin", gas)
+	}
+	return gas * (100 + GAS_ESTIMATE_MARGIN) / 100, nil
+}
+
 func GetBlockTransactions(block *types.Block) interface{} {
 	trans := make([]string, len(block.Transactions))
 	for i := 0; i < len(block.Transactions); i++ {
@@ -431,6 +713,33 @@ func GetBlockTransactions(block *types.Block) interface{} {
 	return b
 }
 
+//GetTxContract return the address of contract deployed or invoked by the transaction
+func GetTxContract(txn *types.Transaction) (common.Address, bool) {
+	switch pl := txn.Payload.(type) {
+	case *payload.DeployCode:
+		return pl.Address(), true
+	case *payload.InvokeCode:
+		code := pl.Code
+		//native invoke code: PUSHBYTES20 address, PUSH version, SYSCALL, PUSHBYTES "Ontology.Native.Invoke"
+		suffix := append([]byte{byte(neovm.SYSCALL), byte(len(svrneovm.NATIVE_INVOKE_NAME))},
+			[]byte(svrneovm.NATIVE_INVOKE_NAME)...)
+		if bytes.HasSuffix(code, suffix) {
+			end := len(code) - len(suffix) - 1
+			if end-common.ADDR_LEN-1 >= 0 && code[end-common.ADDR_LEN-1] == common.ADDR_LEN {
+				addr, err := common.AddressParseFromBytes(code[end-common.ADDR_LEN : end])
+				return addr, err == nil
+			}
+			return common.ADDRESS_EMPTY, false
+		}
+		//neovm invoke code: APPCALL address
+		if len(code) > common.ADDR_LEN && code[len(code)-common.ADDR_LEN-1] == byte(neovm.APPCALL) {
+			addr, err := common.AddressParseFromBytes(code[len(code)-common.ADDR_LEN:])
+			return addr, err == nil
+		}
+	}
+	return common.ADDRESS_EMPTY, false
+}
+
 //NewNativeInvokeTransaction return native contract invoke transaction
 func NewNativeInvokeTransaction(gasPirce, gasLimit uint64, contractAddress common.Address, version byte,
 	method string, params []interface{}) (*types.MutableTransaction, error) {
@@ -466,14 +775,7 @@ func NewSmartContractTransaction(gasPrice, gasLimit uint64, invokeCode []byte) (
 
 //BuildNeoVMInvokeCode build NeoVM Invoke code for params
 func BuildNeoVMInvokeCode(smartContractAddress common.Address, params []interface{}) ([]byte, error) {
-	builder := neovm.NewParamsBuilder(new(bytes.Buffer))
-	err := cutils.BuildNeoVMParam(builder, params)
-	if err != nil {
-		return nil, err
-	}
-	args := append(builder.ToArray(), 0x67)
-	args = append(args, smartContractAddress[:]...)
-	return args, nil
+	return cutils.BuildNeoVMInvokeCode(smartContractAddress, params)
 }
 
 func GetAddress(str string) (common.Address, error) {
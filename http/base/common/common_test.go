@@ -0,0 +1,139 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/genesis"
+	"github.com/ontio/ontology/core/ledger"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	svrneovm "github.com/ontio/ontology/smartcontract/service/neovm"
+	"github.com/ontio/ontology/vm/neovm"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	log.Init(log.PATH, log.Stdout)
+	dataDir, err := ioutil.TempDir("", "ontology-http-common")
+	if err != nil {
+		return
+	}
+	ledger.DefLedger, err = ledger.NewLedger(dataDir, 0)
+	if err != nil {
+		return
+	}
+	bookKeepers, err := config.DefConfig.GetBookkeepers()
+	if err != nil {
+		return
+	}
+	genesisBlock, err := genesis.BuildGenesisBlock(bookKeepers, config.DefConfig.Genesis)
+	if err != nil {
+		return
+	}
+	ledger.DefLedger.Init(bookKeepers, genesisBlock)
+}
+
+func newOntInvokeTx(t *testing.T, method string) *types.Transaction {
+	mutable, err := NewNativeInvokeTransaction(0, 0, utils.OntContractAddress, 0, method, []interface{}{})
+	assert.Nil(t, err)
+	tx, err := mutable.IntoImmutable()
+	assert.Nil(t, err)
+	return tx
+}
+
+func TestAddGasMargin(t *testing.T) {
+	gas, err := addGasMargin(0)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), gas)
+
+	//the margin of gas below 100 is not rounded away
+	gas, err = addGasMargin(50)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(55), gas)
+
+	gas, err = addGasMargin(20000)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(22000), gas)
+
+	max := math.MaxUint64 / (100 + GAS_ESTIMATE_MARGIN)
+	gas, err = addGasMargin(max)
+	assert.Nil(t, err)
+	assert.Equal(t, max*(100+GAS_ESTIMATE_MARGIN)/100, gas)
+
+	_, err = addGasMargin(max + 1)
+	assert.NotNil(t, err)
+	_, err = addGasMargin(math.MaxUint64)
+	assert.NotNil(t, err)
+}
+
+func TestEstimateGas(t *testing.T) {
+	minGas := svrneovm.MIN_TRANSACTION_GAS
+	if config.DefConfig.Common.GasLimit > minGas {
+		minGas = config.DefConfig.Common.GasLimit
+	}
+	tx := newOntInvokeTx(t, "name")
+	result, err := ledger.DefLedger.PreExecuteContract(tx)
+	assert.Nil(t, err)
+	expect, err := addGasMargin(result.Gas)
+	assert.Nil(t, err)
+	if expect < minGas {
+		expect = minGas
+	}
+	gas, err := EstimateGas(tx)
+	assert.Nil(t, err)
+	assert.Equal(t, expect, gas)
+
+	_, err = EstimateGas(newOntInvokeTx(t, "nosuchmethod"))
+	assert.NotNil(t, err)
+}
+
+func TestGetTxContract(t *testing.T) {
+	addr, ok := GetTxContract(newOntInvokeTx(t, "name"))
+	assert.True(t, ok)
+	assert.Equal(t, utils.OntContractAddress, addr)
+
+	contract := common.AddressFromVmCode([]byte{1, 2, 3})
+	mutable, err := NewNeovmInvokeTransaction(0, 0, contract, []interface{}{"name"})
+	assert.Nil(t, err)
+	tx, err := mutable.IntoImmutable()
+	assert.Nil(t, err)
+	addr, ok = GetTxContract(tx)
+	assert.True(t, ok)
+	assert.Equal(t, contract, addr)
+
+	deploy := &payload.DeployCode{Code: []byte{1, 2, 3}}
+	addr, ok = GetTxContract(&types.Transaction{Payload: deploy})
+	assert.True(t, ok)
+	assert.Equal(t, contract, addr)
+
+	//code calling no contract, and native syscall without address
+	_, ok = GetTxContract(&types.Transaction{Payload: &payload.InvokeCode{Code: []byte{1, 2, 3}}})
+	assert.False(t, ok)
+	code := append([]byte{byte(neovm.SYSCALL), byte(len(svrneovm.NATIVE_INVOKE_NAME))}, svrneovm.NATIVE_INVOKE_NAME...)
+	_, ok = GetTxContract(&types.Transaction{Payload: &payload.InvokeCode{Code: code}})
+	assert.False(t, ok)
+}
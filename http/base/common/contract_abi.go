@@ -0,0 +1,117 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	bactor "github.com/ontio/ontology/http/base/actor"
+	"github.com/ontio/ontology/smartcontract/abi"
+)
+
+//contractAbis cache the NeoVM abi of contracts loaded from ledger. Contracts without abi are cached by ledger
+var contractAbis = struct {
+	sync.RWMutex
+	abis map[common.Address]*abi.NeovmContractAbi
+}{abis: make(map[common.Address]*abi.NeovmContractAbi)}
+
+//GetContractAbi return the NeoVM abi of contract, nil if contract abi is disabled or not found
+func GetContractAbi(contract common.Address) *abi.NeovmContractAbi {
+	if !config.DefConfig.Common.EnableContractAbi {
+		return nil
+	}
+	contractAbis.RLock()
+	contractAbi, ok := contractAbis.abis[contract]
+	contractAbis.RUnlock()
+	if ok {
+		return contractAbi
+	}
+	//load under lock, so that abi set or deleted meanwhile is not overwritten by stale one
+	contractAbis.Lock()
+	defer contractAbis.Unlock()
+	contractAbi, ok = contractAbis.abis[contract]
+	if ok {
+		return contractAbi
+	}
+	abiData, err := bactor.GetContractAbi(contract)
+	if err != nil {
+		return nil
+	}
+	contractAbi, err = abi.ParseNeovmContractAbi(contract, abiData)
+	if err != nil {
+		return nil
+	}
+	contractAbis.abis[contract] = contractAbi
+	return contractAbi
+}
+
+//SetContractAbi validate and save the NeoVM abi json of contract to ledger
+func SetContractAbi(contract common.Address, abiData []byte) error {
+	if !config.DefConfig.Common.EnableContractAbi {
+		return fmt.Errorf("contract abi is disabled")
+	}
+	contractAbi, err := abi.ParseNeovmContractAbi(contract, abiData)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(contractAbi)
+	if err != nil {
+		return fmt.Errorf("json.Marshal NeovmContractAbi error:%s", err)
+	}
+	contractAbis.Lock()
+	defer contractAbis.Unlock()
+	err = bactor.PutContractAbi(contract, data)
+	if err != nil {
+		return err
+	}
+	contractAbis.abis[contract] = contractAbi
+	return nil
+}
+
+//DeleteContractAbi delete the NeoVM abi of contract from ledger
+func DeleteContractAbi(contract common.Address) error {
+	if !config.DefConfig.Common.EnableContractAbi {
+		return fmt.Errorf("contract abi is disabled")
+	}
+	contractAbis.Lock()
+	defer contractAbis.Unlock()
+	err := bactor.DeleteContractAbi(contract)
+	if err != nil {
+		return err
+	}
+	delete(contractAbis.abis, contract)
+	return nil
+}
+
+//DecodeNotifyEvent decode the states of notify by the abi of contract, nil if cannot be decoded
+func DecodeNotifyEvent(contract common.Address, states interface{}) *abi.NeovmEventValue {
+	contractAbi := GetContractAbi(contract)
+	if contractAbi == nil {
+		return nil
+	}
+	evt, err := contractAbi.DecodeEvent(states)
+	if err != nil {
+		return nil
+	}
+	return evt
+}
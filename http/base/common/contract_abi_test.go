@@ -0,0 +1,55 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractAbiCache(t *testing.T) {
+	config.DefConfig.Common.EnableContractAbi = true
+	defer func() {
+		config.DefConfig.Common.EnableContractAbi = false
+	}()
+	contract := common.AddressFromVmCode([]byte("contract abi cache"))
+	assert.Nil(t, GetContractAbi(contract))
+	_, ok := contractAbis.abis[contract]
+	assert.False(t, ok)
+
+	//abi with events only is valid
+	abiData := []byte(`{"functions":[],"events":[{"name":"transfer","parameters":[{"name":"from","type":"ByteArray"}]}]}`)
+	err := SetContractAbi(contract, abiData)
+	assert.Nil(t, err)
+	contractAbi := GetContractAbi(contract)
+	assert.NotNil(t, contractAbi)
+	assert.Equal(t, contract.ToHexString(), contractAbi.Address)
+	assert.Equal(t, contractAbi, contractAbis.abis[contract])
+
+	err = DeleteContractAbi(contract)
+	assert.Nil(t, err)
+	assert.Nil(t, GetContractAbi(contract))
+	_, ok = contractAbis.abis[contract]
+	assert.False(t, ok)
+
+	assert.NotNil(t, SetContractAbi(contract, []byte(`{"functions":[],"events":[]}`)))
+}
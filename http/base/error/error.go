@@ -27,6 +27,7 @@ const (
 	SERVICE_CEILING    int64 = 41002
 	ILLEGAL_DATAFORMAT int64 = 41003
 	INVALID_VERSION    int64 = 41004
+	UNAUTHORIZED       int64 = 41005
 
 	INVALID_METHOD int64 = 42001
 	INVALID_PARAMS int64 = 42002
@@ -51,6 +52,7 @@ var ErrMap = map[int64]string{
 	SERVICE_CEILING:    "SERVICE CEILING",
 	ILLEGAL_DATAFORMAT: "ILLEGAL DATAFORMAT",
 	INVALID_VERSION:    "INVALID VERSION",
+	UNAUTHORIZED:       "UNAUTHORIZED",
 
 	INVALID_METHOD: "INVALID METHOD",
 	INVALID_PARAMS: "INVALID PARAMS",
@@ -83,3 +85,29 @@ var ErrMap = map[int64]string{
 	int64(ontErrors.ErrXmitFail):             "INTERNAL ERROR, ErrXmitFail",
 	int64(ontErrors.ErrNoAccount):            "INTERNAL ERROR, ErrNoAccount",
 }
+
+//error codes defined by JSON-RPC 2.0
+const (
+	JSON_RPC_PARSE_ERROR      int64 = -32700
+	JSON_RPC_INVALID_REQUEST  int64 = -32600
+	JSON_RPC_METHOD_NOT_FOUND int64 = -32601
+	JSON_RPC_INVALID_PARAMS   int64 = -32602
+	JSON_RPC_INTERNAL_ERROR   int64 = -32603
+	JSON_RPC_UNAUTHORIZED     int64 = -32001 //implementation defined server error
+)
+
+var JsonRpcErrMap = map[int64]string{
+	JSON_RPC_PARSE_ERROR:      "Parse error",
+	JSON_RPC_INVALID_REQUEST:  "Invalid Request",
+	JSON_RPC_METHOD_NOT_FOUND: "Method not found",
+	JSON_RPC_INVALID_PARAMS:   "Invalid params",
+	JSON_RPC_INTERNAL_ERROR:   "Internal error",
+	JSON_RPC_UNAUTHORIZED:     "Unauthorized",
+}
+
+//JsonRpcError is the error object of JSON-RPC 2.0 response
+type JsonRpcError struct {
+	Code    int64       `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
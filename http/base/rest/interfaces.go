@@ -20,6 +20,7 @@ package rest
 
 import (
 	"bytes"
+	"fmt"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
@@ -30,6 +31,7 @@ import (
 	bcomn "github.com/ontio/ontology/http/base/common"
 	berr "github.com/ontio/ontology/http/base/error"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"math"
 	"strconv"
 )
 
@@ -287,6 +289,34 @@ func SendRawTransaction(cmd map[string]interface{}) map[string]interface{} {
 	return resp
 }
 
+//estimate the gas limit of transaction
+func EstimateGas(cmd map[string]interface{}) map[string]interface{} {
+	str, ok := cmd["Data"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	bys, err := common.HexToBytes(str)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	txn, err := types.TransactionFromRawBytes(bys)
+	if err != nil {
+		return ResponsePack(berr.INVALID_TRANSACTION)
+	}
+	if txn.TxType != types.Invoke && txn.TxType != types.Deploy {
+		return ResponsePack(berr.INVALID_TRANSACTION)
+	}
+	gas, err := bcomn.EstimateGas(txn)
+	if err != nil {
+		resp := ResponsePack(berr.SMARTCODE_ERROR)
+		resp["Result"] = err.Error()
+		return resp
+	}
+	resp := ResponsePack(berr.SUCCESS)
+	resp["Result"] = gas
+	return resp
+}
+
 //get smartcontract event by height
 func GetSmartCodeEventTxsByHeight(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
@@ -350,6 +380,42 @@ func GetSmartCodeEventByTxHash(cmd map[string]interface{}) map[string]interface{
 	return resp
 }
 
+//get smartcontract events by contract address, topic and height range
+func GetEvents(cmd map[string]interface{}) map[string]interface{} {
+	if !config.DefConfig.Common.EnableEventLog {
+		return ResponsePack(berr.INVALID_METHOD)
+	}
+
+	resp := ResponsePack(berr.SUCCESS)
+	str, ok := cmd["Addr"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	contract, err := bcomn.GetAddress(str)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	topic, _ := cmd["Topic"].(string)
+	indexHeight, err := bactor.GetIndexStartHeight(scom.EVENT_NOTIFY_BY_CONTRACT)
+	if err != nil {
+		resp = ResponsePack(berr.INTERNAL_ERROR)
+		resp["Result"] = err.Error()
+		return resp
+	}
+	startHeight, endHeight, offset, limit, ok := getPageParams(cmd, indexHeight)
+	if !ok {
+		resp = ResponsePack(berr.INVALID_PARAMS)
+		resp["Result"] = fmt.Sprintf("events are indexed from height %d", indexHeight)
+		return resp
+	}
+	notifies, err := bactor.GetEventNotifyByContract(contract, topic, startHeight, endHeight, offset, limit)
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = bcomn.GetContractEvents(notifies)
+	return resp
+}
+
 //get contract state
 func GetContractState(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
@@ -580,3 +646,70 @@ func GetMemPoolTxState(cmd map[string]interface{}) map[string]interface{} {
 	resp["Result"] = bcomn.TXNEntryInfo{attrs}
 	return resp
 }
+
+//get transactions touching address by height range
+func GetAddressTxs(cmd map[string]interface{}) map[string]interface{} {
+	if !config.DefConfig.Common.EnableAddressIndex {
+		return ResponsePack(berr.INVALID_METHOD)
+	}
+
+	resp := ResponsePack(berr.SUCCESS)
+	str, ok := cmd["Addr"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	addr, err := bcomn.GetAddress(str)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	indexHeight, err := bactor.GetIndexStartHeight(scom.IX_ADDRESS_TX)
+	if err != nil {
+		resp = ResponsePack(berr.INTERNAL_ERROR)
+		resp["Result"] = err.Error()
+		return resp
+	}
+	startHeight, endHeight, offset, limit, ok := getPageParams(cmd, indexHeight)
+	if !ok {
+		resp = ResponsePack(berr.INVALID_PARAMS)
+		resp["Result"] = fmt.Sprintf("transactions are indexed from height %d", indexHeight)
+		return resp
+	}
+	txs, err := bactor.GetAddressTxs(addr, startHeight, endHeight, offset, limit)
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = bcomn.GetAddressTxs(txs)
+	return resp
+}
+
+//getPageParams return the start height, end height, offset and limit of paging query,
+//params may be string from url or number from websocket
+func getPageParams(cmd map[string]interface{}, minHeight uint32) (uint32, uint32, uint32, uint32, bool) {
+	nums := []uint32{minHeight, bactor.GetCurrentBlockHeight(), 0, bcomn.MAX_EVENT_QUERY_LIMIT}
+	for i, name := range []string{"StartHeight", "EndHeight", "Offset", "Limit"} {
+		switch param := cmd[name].(type) {
+		case nil:
+		case string:
+			if len(param) == 0 {
+				continue
+			}
+			num, err := strconv.ParseUint(param, 10, 32)
+			if err != nil {
+				return 0, 0, 0, 0, false
+			}
+			nums[i] = uint32(num)
+		case float64:
+			if param < 0 || param > math.MaxUint32 {
+				return 0, 0, 0, 0, false
+			}
+			nums[i] = uint32(param)
+		default:
+			return 0, 0, 0, 0, false
+		}
+	}
+	startHeight, endHeight, offset, limit := nums[0], nums[1], nums[2], nums[3]
+	if startHeight < minHeight || startHeight > endHeight || limit == 0 || limit > bcomn.MAX_EVENT_QUERY_LIMIT {
+		return 0, 0, 0, 0, false
+	}
+	return startHeight, endHeight, offset, limit, true
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rest
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"testing"
+
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/genesis"
+	"github.com/ontio/ontology/core/ledger"
+	bcomn "github.com/ontio/ontology/http/base/common"
+	berr "github.com/ontio/ontology/http/base/error"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	log.Init(log.PATH, log.Stdout)
+	dataDir, err := ioutil.TempDir("", "ontology-http-rest")
+	if err != nil {
+		return
+	}
+	ledger.DefLedger, err = ledger.NewLedger(dataDir, 0)
+	if err != nil {
+		return
+	}
+	bookKeepers, err := config.DefConfig.GetBookkeepers()
+	if err != nil {
+		return
+	}
+	genesisBlock, err := genesis.BuildGenesisBlock(bookKeepers, config.DefConfig.Genesis)
+	if err != nil {
+		return
+	}
+	ledger.DefLedger.Init(bookKeepers, genesisBlock)
+}
+
+func TestEstimateGas(t *testing.T) {
+	mutable, err := bcomn.NewNativeInvokeTransaction(0, 0, utils.OntContractAddress, 0, "name", []interface{}{})
+	assert.Nil(t, err)
+	tx, err := mutable.IntoImmutable()
+	assert.Nil(t, err)
+	expect, err := bcomn.EstimateGas(tx)
+	assert.Nil(t, err)
+
+	resp := EstimateGas(map[string]interface{}{"Data": hex.EncodeToString(tx.ToArray())})
+	assert.Equal(t, berr.SUCCESS, resp["Error"])
+	assert.Equal(t, expect, resp["Result"])
+
+	resp = EstimateGas(map[string]interface{}{})
+	assert.Equal(t, berr.INVALID_PARAMS, resp["Error"])
+	resp = EstimateGas(map[string]interface{}{"Data": "not hex"})
+	assert.Equal(t, berr.INVALID_PARAMS, resp["Error"])
+	resp = EstimateGas(map[string]interface{}{"Data": "00"})
+	assert.Equal(t, berr.INVALID_TRANSACTION, resp["Error"])
+
+	mutable, err = bcomn.NewNativeInvokeTransaction(0, 0, utils.OntContractAddress, 0, "nosuchmethod", []interface{}{})
+	assert.Nil(t, err)
+	tx, err = mutable.IntoImmutable()
+	assert.Nil(t, err)
+	resp = EstimateGas(map[string]interface{}{"Data": hex.EncodeToString(tx.ToArray())})
+	assert.Equal(t, berr.SMARTCODE_ERROR, resp["Error"])
+	assert.NotEmpty(t, resp["Result"])
+}
@@ -21,6 +21,8 @@ package rpc
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
@@ -32,6 +34,8 @@ import (
 	bcomn "github.com/ontio/ontology/http/base/common"
 	berr "github.com/ontio/ontology/http/base/error"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	cstate "github.com/ontio/ontology/smartcontract/states"
+	"math"
 )
 
 //get best block hash
@@ -545,6 +549,32 @@ func GetGasPrice(params []interface{}) map[string]interface{} {
 	return responseSuccess(result)
 }
 
+//estimate the gas limit of transaction
+// A JSON example for estimategas method as following:
+//   {"jsonrpc": "2.0", "method": "estimategas", "params": ["raw transactioin in hex"], "id": 0}
+func EstimateGas(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	raw, err := common.HexToBytes(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	txn, err := types.TransactionFromRawBytes(raw)
+	if err != nil || (txn.TxType != types.Invoke && txn.TxType != types.Deploy) {
+		return responsePack(berr.INVALID_TRANSACTION, "")
+	}
+	gas, err := bcomn.EstimateGas(txn)
+	if err != nil {
+		return responsePack(berr.SMARTCODE_ERROR, err.Error())
+	}
+	return responseSuccess(gas)
+}
+
 // get unbound ong of address
 func GetUnboundOng(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
@@ -585,3 +615,253 @@ func GetGrantOng(params []interface{}) map[string]interface{} {
 	}
 	return responseSuccess(rsp)
 }
+
+//get smartcontract events by contract address, topic and height range
+func GetEvents(params []interface{}) map[string]interface{} {
+	if !config.DefConfig.Common.EnableEventLog {
+		return responsePack(berr.INVALID_METHOD, "")
+	}
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	contract, err := bcomn.GetAddress(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	topic := ""
+	if len(params) > 1 && params[1] != nil {
+		topic, ok = params[1].(string)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	}
+	indexHeight, err := bactor.GetIndexStartHeight(scom.EVENT_NOTIFY_BY_CONTRACT)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	startHeight, endHeight, offset, limit, ok := getPageParams(params, 2, indexHeight)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, fmt.Sprintf("events are indexed from height %d", indexHeight))
+	}
+	notifies, err := bactor.GetEventNotifyByContract(contract, topic, startHeight, endHeight, offset, limit)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(bcomn.GetContractEvents(notifies))
+}
+
+//get transactions touching address by height range
+func GetAddressTxs(params []interface{}) map[string]interface{} {
+	if !config.DefConfig.Common.EnableAddressIndex {
+		return responsePack(berr.INVALID_METHOD, "")
+	}
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	addr, err := bcomn.GetAddress(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	indexHeight, err := bactor.GetIndexStartHeight(scom.IX_ADDRESS_TX)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	startHeight, endHeight, offset, limit, ok := getPageParams(params, 1, indexHeight)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, fmt.Sprintf("transactions are indexed from height %d", indexHeight))
+	}
+	txs, err := bactor.GetAddressTxs(addr, startHeight, endHeight, offset, limit)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(bcomn.GetAddressTxs(txs))
+}
+
+//get the NeoVM abi of contract stored by node
+func GetContractAbi(params []interface{}) map[string]interface{} {
+	if !config.DefConfig.Common.EnableContractAbi {
+		return responsePack(berr.INVALID_METHOD, "")
+	}
+	address, ok := getContractParam(params)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	contractAbi := bcomn.GetContractAbi(address)
+	if contractAbi == nil {
+		return responsePack(berr.UNKNOWN_CONTRACT, "unknow contract abi")
+	}
+	return responseSuccess(contractAbi)
+}
+
+//store the NeoVM abi of contract, used to decode the events of contract. Abi is json object or json string
+// A JSON example for setcontractabi method as following:
+//   {"jsonrpc": "2.0", "method": "setcontractabi", "params": ["contract address", {"functions": [], "events": [{"name": "transfer", "parameters": [{"name": "from", "type": "ByteArray"}]}]}], "id": 0}
+func SetContractAbi(params []interface{}) map[string]interface{} {
+	if !config.DefConfig.Common.EnableContractAbi {
+		return responsePack(berr.INVALID_METHOD, "")
+	}
+	address, ok := getContractParam(params)
+	if !ok || len(params) < 2 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	var abiData []byte
+	switch v := params[1].(type) {
+	case string:
+		abiData = []byte(v)
+	case map[string]interface{}:
+		abiData, _ = json.Marshal(v)
+	default:
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	err := bcomn.SetContractAbi(address, abiData)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, err.Error())
+	}
+	return responseSuccess(true)
+}
+
+//delete the NeoVM abi of contract stored by node
+func DeleteContractAbi(params []interface{}) map[string]interface{} {
+	if !config.DefConfig.Common.EnableContractAbi {
+		return responsePack(berr.INVALID_METHOD, "")
+	}
+	address, ok := getContractParam(params)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	err := bcomn.DeleteContractAbi(address)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(true)
+}
+
+//getContractParam return the contract address of params[0], in hex or base58
+func getContractParam(params []interface{}) (common.Address, bool) {
+	if len(params) < 1 {
+		return common.ADDRESS_EMPTY, false
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return common.ADDRESS_EMPTY, false
+	}
+	address, err := bcomn.GetAddress(str)
+	if err != nil {
+		return common.ADDRESS_EMPTY, false
+	}
+	return address, true
+}
+
+//dry-run transaction on the current state with state overrides, and return the execution trace
+// A JSON example for dryruntransaction method as following:
+//   {"jsonrpc": "2.0", "method": "dryruntransaction", "params": ["raw transactioin in hex", null, {"Balances": [{"Asset": "ong", "Address": "base58", "Balance": 1000}]}], "id": 0}
+func DryRunTransaction(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	raw, err := common.HexToBytes(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	txn, err := types.TransactionFromRawBytes(raw)
+	if err != nil {
+		return responsePack(berr.INVALID_TRANSACTION, "")
+	}
+	height := bactor.GetCurrentBlockHeight()
+	if len(params) > 1 && params[1] != nil {
+		h, ok := params[1].(float64)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		if h != float64(height) {
+			return responsePack(berr.INVALID_PARAMS, fmt.Sprintf("unsupported height %v, only current height %d is supported", h, height))
+		}
+	}
+	var overrides *cstate.StateOverrides
+	if len(params) > 2 && params[2] != nil {
+		data, err := json.Marshal(params[2])
+		if err != nil {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		req := &bcomn.StateOverrides{}
+		if err := json.Unmarshal(data, req); err != nil {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		overrides, err = bcomn.ParseStateOverrides(req)
+		if err != nil {
+			return responsePack(berr.INVALID_PARAMS, err.Error())
+		}
+	}
+	result, err := bactor.DebugExecuteContract(txn, height, overrides)
+	if err != nil {
+		log.Infof("DryRunTransaction: %s", err)
+		return responsePack(berr.SMARTCODE_ERROR, err.Error())
+	}
+	return responseSuccess(bcomn.ConvertDebugExecuteResult(result))
+}
+
+//re-execute the mined transaction and return the call tree, error message and storage diffs
+// A JSON example for tracetransaction method as following:
+//   {"jsonrpc": "2.0", "method": "tracetransaction", "params": ["transaction hash in hex"], "id": 0}
+func TraceTransaction(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	hash, err := common.Uint256FromHexString(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	height, _, err := bactor.GetTxnWithHeightByTxHash(hash)
+	if err != nil {
+		return responsePack(berr.UNKNOWN_TRANSACTION, "")
+	}
+	if currentHeight := bactor.GetCurrentBlockHeight(); height != currentHeight {
+		return responsePack(berr.INVALID_PARAMS, fmt.Sprintf("only transactions of current block %d can be traced", currentHeight))
+	}
+	result, err := bactor.TraceTransaction(hash)
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return responsePack(berr.UNKNOWN_TRANSACTION, "")
+		}
+		log.Infof("TraceTransaction: %s", err)
+		return responsePack(berr.SMARTCODE_ERROR, err.Error())
+	}
+	return responseSuccess(bcomn.ConvertTxTraceResult(result))
+}
+
+//getPageParams return the start height, end height, offset and limit of paging query from
+//params[start:], missing or null params use the default value
+func getPageParams(params []interface{}, start int, minHeight uint32) (uint32, uint32, uint32, uint32, bool) {
+	nums := []uint32{minHeight, bactor.GetCurrentBlockHeight(), 0, bcomn.MAX_EVENT_QUERY_LIMIT}
+	for i := range nums {
+		if len(params) <= start+i || params[start+i] == nil {
+			continue
+		}
+		num, ok := params[start+i].(float64)
+		if !ok || num < 0 || num > math.MaxUint32 {
+			return 0, 0, 0, 0, false
+		}
+		nums[i] = uint32(num)
+	}
+	startHeight, endHeight, offset, limit := nums[0], nums[1], nums[2], nums[3]
+	if startHeight < minHeight || startHeight > endHeight || limit == 0 || limit > bcomn.MAX_EVENT_QUERY_LIMIT {
+		return 0, 0, 0, 0, false
+	}
+	return startHeight, endHeight, offset, limit, true
+}
@@ -19,9 +19,13 @@
 package rpc
 
 import (
+	"net"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
 	bactor "github.com/ontio/ontology/http/base/actor"
 	"github.com/ontio/ontology/http/base/common"
@@ -29,7 +33,10 @@ import (
 )
 
 const (
-	RANDBYTELEN = 4
+	RANDBYTELEN       = 4
+	MAX_GEN_BLOCKS    = 1000
+	MAX_INCREASE_TIME = 10 * 365 * 24 * 3600
+	MAX_BAN_DURATION  = 10 * 365 * 24 * 3600
 )
 
 func getCurrentDirectory() string {
@@ -122,3 +129,139 @@ func SetDebugInfo(params []interface{}) map[string]interface{} {
 	}
 	return responsePack(berr.SUCCESS, true)
 }
+
+//generate empty blocks immediately in test mode
+func GenBlocks(params []interface{}) map[string]interface{} {
+	if config.DefConfig.Genesis.ConsensusType != config.CONSENSUS_TYPE_SOLO {
+		return responsePack(berr.INVALID_METHOD, "")
+	}
+	count := uint32(1)
+	if len(params) >= 1 {
+		switch params[0].(type) {
+		case float64:
+			num := params[0].(float64)
+			if num < 1 || num > MAX_GEN_BLOCKS {
+				return responsePack(berr.INVALID_PARAMS, "")
+			}
+			count = uint32(num)
+		default:
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	}
+	height, err := bactor.ConsensusGenBlocks(count)
+	if err != nil {
+		log.Errorf("GenBlocks error:%s", err)
+		return responsePack(berr.INTERNAL_ERROR, height)
+	}
+	return responseSuccess(height)
+}
+
+//increase the timestamp of following blocks by seconds in test mode
+func IncreaseTime(params []interface{}) map[string]interface{} {
+	if config.DefConfig.Genesis.ConsensusType != config.CONSENSUS_TYPE_SOLO {
+		return responsePack(berr.INVALID_METHOD, "")
+	}
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	var seconds uint32
+	switch params[0].(type) {
+	case float64:
+		num := params[0].(float64)
+		if num < 0 || num > MAX_INCREASE_TIME {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		seconds = uint32(num)
+	default:
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	offset, err := bactor.ConsensusIncreaseTime(seconds)
+	if err != nil {
+		log.Errorf("IncreaseTime error:%s", err)
+		return responsePack(berr.INVALID_PARAMS, err.Error())
+	}
+	return responseSuccess(offset)
+}
+
+//get neighbor peers with penalty score
+func GetPeers(params []interface{}) map[string]interface{} {
+	peers, err := bactor.GetPeerInfos()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(peers)
+}
+
+//get banned peer ids and ips
+func GetBanList(params []interface{}) map[string]interface{} {
+	list, err := bactor.GetBanList()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(list)
+}
+
+//ban peer by id or ip, params: [target, duration in seconds(optional), reason(optional)]
+func BanPeer(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	id, ip, ok := parseBanTarget(params[0])
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	var duration time.Duration
+	if len(params) >= 2 {
+		num, ok := params[1].(float64)
+		if !ok || num < 0 || num > MAX_BAN_DURATION {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		duration = time.Duration(num) * time.Second
+	}
+	reason := "manual"
+	if len(params) >= 3 {
+		str, ok := params[2].(string)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		reason = str
+	}
+	if err := bactor.BanPeer(id, ip, duration, reason); err != nil {
+		log.Errorf("BanPeer error:%s", err)
+		return responsePack(berr.INTERNAL_ERROR, false)
+	}
+	return responseSuccess(true)
+}
+
+//unban peer by id or ip, params: [target]
+func UnbanPeer(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	id, ip, ok := parseBanTarget(params[0])
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	unbanned, err := bactor.UnbanPeer(id, ip)
+	if err != nil {
+		log.Errorf("UnbanPeer error:%s", err)
+		return responsePack(berr.INTERNAL_ERROR, false)
+	}
+	return responseSuccess(unbanned)
+}
+
+//parseBanTarget parse the ban target, which is either an ip or a peer id in decimal string
+func parseBanTarget(param interface{}) (uint64, string, bool) {
+	str, ok := param.(string)
+	if !ok || str == "" {
+		return 0, "", false
+	}
+	if net.ParseIP(str) != nil {
+		return 0, str, true
+	}
+	id, err := strconv.ParseUint(str, 10, 64)
+	if err != nil || id == 0 {
+		return 0, "", false
+	}
+	return id, "", true
+}
@@ -20,36 +20,85 @@
 package rpc
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/common/metrics"
+	"github.com/ontio/ontology/http/base/auth"
 	berr "github.com/ontio/ontology/http/base/error"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"strings"
 	"sync"
+	"time"
 )
 
-func init() {
-	mainMux.m = make(map[string]func([]interface{}) map[string]interface{})
+//JsonRpc version
+const JSON_RPC_VERSION = "2.0"
+
+const MAX_BATCH_SIZE = 100 //Max requests in a batch, each request of batch is counted by rate limit
+
+//jsonRpcRequest object in rpc
+type jsonRpcRequest struct {
+	Version string          `json:"jsonrpc"`
+	Id      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+//jsonRpcResponse object success response for jsonRpcRequest
+type jsonRpcResponse struct {
+	Version string          `json:"jsonrpc"`
+	Result  interface{}     `json:"result"`
+	Id      json.RawMessage `json:"id"`
+}
+
+//jsonRpcErrorResponse object error response for jsonRpcRequest
+type jsonRpcErrorResponse struct {
+	Version string             `json:"jsonrpc"`
+	Error   *berr.JsonRpcError `json:"error"`
+	Id      json.RawMessage    `json:"id"`
 }
 
-//an instance of the multiplexer
-var mainMux ServeMux
+//mainMux serves the public rpc server, and localMux serves the local rpc server only
+var mainMux = NewServeMux()
+var localMux = NewServeMux()
 
 //multiplexer that keeps track of every function to be called on specific rpc call
 type ServeMux struct {
 	sync.RWMutex
 	m               map[string]func([]interface{}) map[string]interface{}
+	params          map[string][]string //param names of functions, by position
 	defaultFunction func(http.ResponseWriter, *http.Request)
 }
 
-//a function to register functions to be called for specific rpc calls
-func HandleFunc(pattern string, handler func([]interface{}) map[string]interface{}) {
-	mainMux.Lock()
-	defer mainMux.Unlock()
-	mainMux.m[pattern] = handler
+func NewServeMux() *ServeMux {
+	return &ServeMux{
+		m:      make(map[string]func([]interface{}) map[string]interface{}),
+		params: make(map[string][]string),
+	}
+}
+
+//a function to register functions to be called for specific rpc calls,
+//params are the names of positional params used to map named params
+func HandleFunc(pattern string, handler func([]interface{}) map[string]interface{}, params ...string) {
+	mainMux.HandleFunc(pattern, handler, params...)
+}
+
+//HandleLocalFunc register functions of local rpc server, which are not served by public rpc server
+func HandleLocalFunc(pattern string, handler func([]interface{}) map[string]interface{}, params ...string) {
+	localMux.HandleFunc(pattern, handler, params...)
+}
+
+//HandleFunc register function of rpc call to the multiplexer
+func (this *ServeMux) HandleFunc(pattern string, handler func([]interface{}) map[string]interface{}, params ...string) {
+	this.Lock()
+	defer this.Unlock()
+	this.m[pattern] = handler
+	this.params[pattern] = params
 }
 
 //a function to be called if the request is not a HTTP JSON RPC call
@@ -60,19 +109,37 @@ func SetDefaultFunc(def func(http.ResponseWriter, *http.Request)) {
 // this is the function that should be called in order to answer an rpc call
 // should be registered like "http.HandleFunc("/", httpjsonrpc.Handle)"
 func Handle(w http.ResponseWriter, r *http.Request) {
-	mainMux.RLock()
-	defer mainMux.RUnlock()
+	mainMux.ServeHTTP(w, r)
+}
+
+//HandleLocal answer the rpc call of local rpc server
+func HandleLocal(w http.ResponseWriter, r *http.Request) {
+	localMux.ServeHTTP(w, r)
+}
+
+//ServeHTTP answer the rpc calls of functions registered to the multiplexer
+func (this *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	this.RLock()
+	defer this.RUnlock()
 	if r.Method == "OPTIONS" {
-		w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Add("Access-Control-Allow-Headers", "Content-Type, Authorization")
 		w.Header().Set("content-type", "application/json;charset=utf-8")
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		return
 	}
+	if !auth.Guard(w, r) {
+		return
+	}
+	granted, err := auth.Authenticate(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
 	//JSON RPC commands should be POSTs
 	if r.Method != "POST" {
-		if mainMux.defaultFunction != nil {
+		if this.defaultFunction != nil {
 			log.Info("HTTP JSON RPC Handle - Method!=\"POST\"")
-			mainMux.defaultFunction(w, r)
+			this.defaultFunction(w, r)
 			return
 		} else {
 			log.Warn("HTTP JSON RPC Handle - Method!=\"POST\"")
@@ -82,9 +149,9 @@ func Handle(w http.ResponseWriter, r *http.Request) {
 
 	//check if there is Request Body to read
 	if r.Body == nil {
-		if mainMux.defaultFunction != nil {
+		if this.defaultFunction != nil {
 			log.Info("HTTP JSON RPC Handle - Request body is nil")
-			mainMux.defaultFunction(w, r)
+			this.defaultFunction(w, r)
 			return
 		} else {
 			log.Warn("HTTP JSON RPC Handle - Request body is nil")
@@ -98,61 +165,256 @@ func Handle(w http.ResponseWriter, r *http.Request) {
 		log.Error("HTTP JSON RPC Handle - ioutil.ReadAll: ", err)
 		return
 	}
-	request := make(map[string]interface{})
-	err = json.Unmarshal(body, &request)
-	if err != nil {
-		log.Error("HTTP JSON RPC Handle - json.Unmarshal: ", err)
-		return
+	legacy := config.DefConfig.Rpc.EnableLegacyResponse
+	var response interface{}
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var batch []json.RawMessage
+		err = json.Unmarshal(body, &batch)
+		if err != nil {
+			log.Error("HTTP JSON RPC Handle - json.Unmarshal: ", err)
+			response = errorResponse(legacy, nil, berr.JSON_RPC_PARSE_ERROR, err.Error())
+		} else if len(batch) == 0 {
+			response = errorResponse(legacy, nil, berr.JSON_RPC_INVALID_REQUEST, "empty batch")
+		} else if len(batch) > MAX_BATCH_SIZE {
+			response = errorResponse(legacy, nil, berr.JSON_RPC_INVALID_REQUEST,
+				fmt.Sprintf("batch exceeds %d requests", MAX_BATCH_SIZE))
+		} else if !auth.AllowRequestN(r, len(batch)-1) {
+			//the first request is taken by guard
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		} else {
+			rsps := make([]interface{}, 0, len(batch))
+			for _, raw := range batch {
+				if rsp := this.handleRequest(legacy, raw, granted); rsp != nil {
+					rsps = append(rsps, rsp)
+				}
+			}
+			if len(rsps) > 0 {
+				response = rsps
+			}
+		}
+	} else {
+		if !json.Valid(body) {
+			log.Error("HTTP JSON RPC Handle - json.Unmarshal: invalid json")
+			response = errorResponse(legacy, nil, berr.JSON_RPC_PARSE_ERROR, "invalid json")
+		} else {
+			response = this.handleRequest(legacy, body, granted)
+		}
 	}
-	if request["method"] == nil {
-		log.Error("HTTP JSON RPC Handle - method not found: ")
+	w.Header().Add("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	w.Header().Set("content-type", "application/json;charset=utf-8")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if response == nil {
+		//only notifications in request
+		w.WriteHeader(http.StatusNoContent)
 		return
 	}
-	method, ok := request["method"].(string)
-	if !ok {
-		log.Error("HTTP JSON RPC Handle - method is not string: ")
+	data, err := json.Marshal(response)
+	if err != nil {
+		log.Error("HTTP JSON RPC Handle - json.Marshal: ", err)
 		return
 	}
+	w.Write(data)
+}
+
+//handleRequest call the function of a single request, return nil for notification
+func (this *ServeMux) handleRequest(legacy bool, raw json.RawMessage, granted auth.Group) interface{} {
+	request := &jsonRpcRequest{}
+	err := json.Unmarshal(raw, request)
+	if err != nil || (request.Version != "" && request.Version != JSON_RPC_VERSION) ||
+		request.Method == "" || !validId(request.Id) {
+		log.Warn("HTTP JSON RPC Handle - invalid request: ", string(raw))
+		return errorResponse(legacy, validIdOrNil(request.Id), berr.JSON_RPC_INVALID_REQUEST, "invalid request")
+	}
+	//legacy clients may omit the id and still expect a response
+	notification := request.Id == nil && !legacy
+	id := request.Id
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+
 	//get the corresponding function
-	function, ok := mainMux.m[method]
-	if ok {
-		response := function(request["params"].([]interface{}))
-		data, err := json.Marshal(map[string]interface{}{
-			"jsonrpc": "2.0",
+	function, ok := this.m[request.Method]
+	if !ok {
+		log.Warn("HTTP JSON RPC Handle - No function to call for ", request.Method)
+		if notification {
+			return nil
+		}
+		if legacy {
+			return map[string]interface{}{
+				"error": berr.INVALID_METHOD,
+				"result": map[string]interface{}{
+					"code":    berr.JSON_RPC_METHOD_NOT_FOUND,
+					"message": "Method not found",
+					"data":    "The called method was not found on the server",
+				},
+				"id": id,
+			}
+		}
+		return errorResponse(legacy, id, berr.JSON_RPC_METHOD_NOT_FOUND, "The called method was not found on the server")
+	}
+	if err := auth.Authorize(granted, request.Method); err != nil {
+		log.Warn("HTTP JSON RPC Handle - unauthorized method: ", request.Method)
+		if notification {
+			return nil
+		}
+		return errorResponse(legacy, id, berr.JSON_RPC_UNAUTHORIZED, "The method is not permitted with the credential")
+	}
+	params, err := this.parseParams(request.Method, request.Params)
+	if err != nil {
+		log.Warn("HTTP JSON RPC Handle - invalid params: ", err)
+		if notification {
+			return nil
+		}
+		return errorResponse(legacy, id, berr.JSON_RPC_INVALID_PARAMS, err.Error())
+	}
+	if isPreExec(request.Method, params) {
+		if err := auth.Authorize(granted, auth.PREEXEC_METHOD); err != nil {
+			log.Warn("HTTP JSON RPC Handle - unauthorized pre-execution")
+			if notification {
+				return nil
+			}
+			return errorResponse(legacy, id, berr.JSON_RPC_UNAUTHORIZED, "The method is not permitted with the credential")
+		}
+	}
+	start := time.Now()
+	response := function(params)
+	metrics.RpcRequestTime.WithLabelValues(request.Method).Observe(time.Since(start).Seconds())
+	if notification {
+		return nil
+	}
+	if legacy {
+		return map[string]interface{}{
+			"jsonrpc": JSON_RPC_VERSION,
 			"error":   response["error"],
 			"desc":    response["desc"],
 			"result":  response["result"],
-			"id":      request["id"],
-		})
+			"id":      id,
+		}
+	}
+	errCode, _ := response["error"].(int64)
+	if errCode == berr.SUCCESS {
+		return &jsonRpcResponse{Version: JSON_RPC_VERSION, Result: response["result"], Id: id}
+	}
+	rpcErr := &berr.JsonRpcError{Code: errCode, Message: berr.ErrMap[errCode]}
+	switch errCode {
+	case berr.INVALID_PARAMS:
+		rpcErr.Code = berr.JSON_RPC_INVALID_PARAMS
+	case berr.INVALID_METHOD:
+		rpcErr.Code = berr.JSON_RPC_METHOD_NOT_FOUND
+	}
+	if rpcErr.Message == "" {
+		rpcErr.Message = "INTERNAL ERROR"
+	}
+	if data, ok := response["result"]; ok && data != nil && data != "" {
+		rpcErr.Data = data
+	}
+	return &jsonRpcErrorResponse{Version: JSON_RPC_VERSION, Error: rpcErr, Id: id}
+}
+
+//parseParams convert positional or named params to the positional params of method
+func (this *ServeMux) parseParams(method string, raw json.RawMessage) ([]interface{}, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return []interface{}{}, nil
+	}
+	switch raw[0] {
+	case '[':
+		params := make([]interface{}, 0)
+		err := json.Unmarshal(raw, &params)
 		if err != nil {
-			log.Error("HTTP JSON RPC Handle - json.Marshal: ", err)
-			return
+			return nil, fmt.Errorf("params json.Unmarshal error:%s", err)
 		}
-		w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
-		w.Header().Set("content-type", "application/json;charset=utf-8")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Write(data)
-	} else {
-		//if the function does not exist
-		log.Warn("HTTP JSON RPC Handle - No function to call for ", request["method"])
-		data, err := json.Marshal(map[string]interface{}{
-			"error": berr.INVALID_METHOD,
-			"result": map[string]interface{}{
-				"code":    -32601,
-				"message": "Method not found",
-				"data":    "The called method was not found on the server",
-			},
-			"id": request["id"],
-		})
+		return params, nil
+	case '{':
+		named := make(map[string]interface{})
+		err := json.Unmarshal(raw, &named)
 		if err != nil {
-			log.Error("HTTP JSON RPC Handle - json.Marshal: ", err)
-			return
+			return nil, fmt.Errorf("params json.Unmarshal error:%s", err)
 		}
-		w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
-		w.Header().Set("content-type", "application/json;charset=utf-8")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Write(data)
+		names := this.params[method]
+		params := make([]interface{}, 0, len(names))
+		for index, name := range names {
+			value, ok := named[name]
+			if !ok {
+				continue
+			}
+			for len(params) < index {
+				params = append(params, nil)
+			}
+			params = append(params, value)
+			delete(named, name)
+		}
+		for name := range named {
+			return nil, fmt.Errorf("unknown param %s of method %s", name, method)
+		}
+		return params, nil
+	default:
+		return nil, fmt.Errorf("params must be array or object")
+	}
+}
+
+//validId check the id is a string, number or null
+func validId(id json.RawMessage) bool {
+	if id == nil {
+		return true
+	}
+	var value interface{}
+	if err := json.Unmarshal(id, &value); err != nil {
+		return false
+	}
+	switch value.(type) {
+	case nil, string, float64:
+		return true
+	}
+	return false
+}
+
+//isPreExec check the request is a sendrawtransaction with pre-execution, which needs the group of pre-execution
+func isPreExec(method string, params []interface{}) bool {
+	if method != "sendrawtransaction" || len(params) < 2 {
+		return false
+	}
+	preExec, ok := params[1].(float64)
+	return ok && preExec == 1
+}
+
+func validIdOrNil(id json.RawMessage) json.RawMessage {
+	if id == nil || !validId(id) {
+		return json.RawMessage("null")
+	}
+	return id
+}
+
+//errorResponse make the error response of the request which can't reach the function
+func errorResponse(legacy bool, id json.RawMessage, code int64, data string) interface{} {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	if legacy {
+		errCode := berr.ILLEGAL_DATAFORMAT
+		switch code {
+		case berr.JSON_RPC_METHOD_NOT_FOUND:
+			errCode = berr.INVALID_METHOD
+		case berr.JSON_RPC_INVALID_PARAMS:
+			errCode = berr.INVALID_PARAMS
+		case berr.JSON_RPC_UNAUTHORIZED:
+			errCode = berr.UNAUTHORIZED
+		}
+		return map[string]interface{}{
+			"jsonrpc": JSON_RPC_VERSION,
+			"error":   errCode,
+			"desc":    berr.ErrMap[errCode],
+			"result":  data,
+			"id":      id,
+		}
+	}
+	rpcErr := &berr.JsonRpcError{Code: code, Message: berr.JsonRpcErrMap[code]}
+	if data != "" {
+		rpcErr.Data = data
 	}
+	return &jsonRpcErrorResponse{Version: JSON_RPC_VERSION, Error: rpcErr, Id: id}
 }
 
 // Call sends RPC request to server
@@ -0,0 +1,192 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ontio/ontology/common/config"
+	berr "github.com/ontio/ontology/http/base/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	HandleFunc("testecho", func(params []interface{}) map[string]interface{} {
+		if len(params) < 1 {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		return responseSuccess(params)
+	}, "first", "second")
+}
+
+func doRequest(t *testing.T, body string) (int, string) {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	Handle(w, req)
+	return w.Code, w.Body.String()
+}
+
+func TestHandleSingle(t *testing.T) {
+	_, rsp := doRequest(t, `{"jsonrpc":"2.0","method":"testecho","params":[1,"a"],"id":1}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","result":[1,"a"],"id":1}`, rsp)
+
+	_, rsp = doRequest(t, `{"jsonrpc":"2.0","method":"testecho","params":{"second":"b","first":2},"id":"x"}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","result":[2,"b"],"id":"x"}`, rsp)
+
+	_, rsp = doRequest(t, `{"jsonrpc":"2.0","method":"testecho","params":{"third":1},"id":2}`)
+	assert.Contains(t, rsp, `"code":-32602`)
+
+	_, rsp = doRequest(t, `{"jsonrpc":"2.0","method":"testecho","params":[],"id":3}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":-32602,"message":"INVALID PARAMS"},"id":3}`, rsp)
+
+	_, rsp = doRequest(t, `{"jsonrpc":"2.0","method":"nosuchmethod","id":4}`)
+	assert.Contains(t, rsp, `"code":-32601`)
+
+	_, rsp = doRequest(t, `{"jsonrpc":"2.0","method":"testecho",`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":-32700,"message":"Parse error","data":"invalid json"},"id":null}`, rsp)
+
+	_, rsp = doRequest(t, `{"jsonrpc":"1.0","method":"testecho","id":5}`)
+	assert.Contains(t, rsp, `"code":-32600`)
+}
+
+func TestHandleNotification(t *testing.T) {
+	code, rsp := doRequest(t, `{"jsonrpc":"2.0","method":"testecho","params":[1]}`)
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, "", rsp)
+}
+
+func TestHandleBatch(t *testing.T) {
+	_, rsp := doRequest(t, `[
+		{"jsonrpc":"2.0","method":"testecho","params":[1],"id":1},
+		{"jsonrpc":"2.0","method":"testecho","params":[2]},
+		{"jsonrpc":"2.0","method":"nosuchmethod","id":2},
+		1
+	]`)
+	var rsps []map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(rsp), &rsps))
+	assert.Equal(t, 3, len(rsps))
+	assert.Equal(t, float64(1), rsps[0]["id"])
+	assert.Equal(t, float64(-32601), rsps[1]["error"].(map[string]interface{})["code"])
+	assert.Equal(t, float64(-32600), rsps[2]["error"].(map[string]interface{})["code"])
+	assert.Nil(t, rsps[2]["id"])
+
+	_, rsp = doRequest(t, `[]`)
+	assert.Contains(t, rsp, `"code":-32600`)
+
+	code, _ := doRequest(t, `[{"jsonrpc":"2.0","method":"testecho","params":[1]}]`)
+	assert.Equal(t, http.StatusNoContent, code)
+}
+
+func TestHandleBatchLimit(t *testing.T) {
+	entries := make([]string, MAX_BATCH_SIZE+1)
+	for i := range entries {
+		entries[i] = fmt.Sprintf(`{"jsonrpc":"2.0","method":"testecho","params":[%d],"id":%d}`, i, i)
+	}
+	_, rsp := doRequest(t, "["+strings.Join(entries, ",")+"]")
+	assert.Contains(t, rsp, `"code":-32600`)
+	assert.Contains(t, rsp, "batch exceeds")
+
+	_, rsp = doRequest(t, "["+strings.Join(entries[:MAX_BATCH_SIZE], ",")+"]")
+	var rsps []map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(rsp), &rsps))
+	assert.Equal(t, MAX_BATCH_SIZE, len(rsps))
+}
+
+func TestHandleLegacy(t *testing.T) {
+	config.DefConfig.Rpc.EnableLegacyResponse = true
+	defer func() { config.DefConfig.Rpc.EnableLegacyResponse = false }()
+
+	_, rsp := doRequest(t, `{"jsonrpc":"2.0","method":"testecho","params":[1],"id":"1"}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":0,"desc":"SUCCESS","result":[1],"id":"1"}`, rsp)
+
+	_, rsp = doRequest(t, `{"jsonrpc":"2.0","method":"testecho","params":[]}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":42002,"desc":"INVALID PARAMS","result":"","id":null}`, rsp)
+}
+
+func TestHandleAuth(t *testing.T) {
+	authCfg := config.DefConfig.Auth
+	authCfg.EnableAuth = true
+	authCfg.ReadKeys = []string{"readkey"}
+	authCfg.SubmitKeys = []string{"submitkey"}
+	defer func() {
+		authCfg.EnableAuth = false
+		authCfg.ReadKeys = nil
+		authCfg.SubmitKeys = nil
+	}()
+	HandleFunc("sendrawtransaction", func(params []interface{}) map[string]interface{} {
+		return responseSuccess(params)
+	}, "tx", "preexec")
+	HandleFunc("getversion", func(params []interface{}) map[string]interface{} {
+		return responseSuccess(params)
+	})
+
+	doAuthRequest := func(token, body string) (int, string) {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		Handle(w, req)
+		return w.Code, w.Body.String()
+	}
+
+	_, rsp := doAuthRequest("", `{"jsonrpc":"2.0","method":"testecho","params":[1],"id":1}`)
+	assert.Contains(t, rsp, `"code":-32001`)
+
+	_, rsp = doAuthRequest("readkey", `{"jsonrpc":"2.0","method":"getversion","params":[1],"id":1}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","result":[1],"id":1}`, rsp)
+
+	//methods not listed in groups need admin
+	_, rsp = doAuthRequest("readkey", `{"jsonrpc":"2.0","method":"testecho","params":[1],"id":1}`)
+	assert.Contains(t, rsp, `"code":-32001`)
+
+	_, rsp = doAuthRequest("readkey", `{"jsonrpc":"2.0","method":"sendrawtransaction","params":["00"],"id":2}`)
+	assert.Contains(t, rsp, `"code":-32001`)
+
+	_, rsp = doAuthRequest("submitkey", `{"jsonrpc":"2.0","method":"sendrawtransaction","params":["00"],"id":2}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","result":["00"],"id":2}`, rsp)
+
+	_, rsp = doAuthRequest("submitkey", `{"jsonrpc":"2.0","method":"sendrawtransaction","params":["00",1],"id":2}`)
+	assert.Contains(t, rsp, `"code":-32001`)
+	_, rsp = doAuthRequest("submitkey", `{"jsonrpc":"2.0","method":"sendrawtransaction","params":{"tx":"00","preexec":1},"id":2}`)
+	assert.Contains(t, rsp, `"code":-32001`)
+
+	code, _ := doAuthRequest("wrongkey", `{"jsonrpc":"2.0","method":"testecho","params":[1],"id":3}`)
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestHandleLocal(t *testing.T) {
+	HandleLocalFunc("testlocal", func(params []interface{}) map[string]interface{} {
+		return responseSuccess(true)
+	})
+	_, rsp := doRequest(t, `{"jsonrpc":"2.0","method":"testlocal","id":1}`)
+	assert.Contains(t, rsp, `"code":-32601`)
+
+	w := httptest.NewRecorder()
+	HandleLocal(w, httptest.NewRequest("POST", "/local", strings.NewReader(`{"jsonrpc":"2.0","method":"testlocal","id":1}`)))
+	assert.JSONEq(t, `{"jsonrpc":"2.0","result":true,"id":1}`, w.Body.String())
+	w = httptest.NewRecorder()
+	HandleLocal(w, httptest.NewRequest("POST", "/local", strings.NewReader(`{"jsonrpc":"2.0","method":"testecho","params":[1],"id":1}`)))
+	assert.Contains(t, w.Body.String(), `"code":-32601`)
+}
@@ -0,0 +1,223 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/genesis"
+	"github.com/ontio/ontology/core/ledger"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	log.Init(log.PATH, log.Stdout)
+	dataDir, err := ioutil.TempDir("", "ontology-http-graphql")
+	if err != nil {
+		return
+	}
+	ledger.DefLedger, err = ledger.NewLedger(dataDir, 0)
+	if err != nil {
+		return
+	}
+	bookKeepers, err := config.DefConfig.GetBookkeepers()
+	if err != nil {
+		return
+	}
+	genesisBlock, err := genesis.BuildGenesisBlock(bookKeepers, config.DefConfig.Genesis)
+	if err != nil {
+		return
+	}
+	ledger.DefLedger.Init(bookKeepers, genesisBlock)
+}
+
+//execQuery run query on the schema and decode the data into result, return the error messages
+func execQuery(t *testing.T, query string, result interface{}) []string {
+	schema, err := NewSchema(10)
+	if !assert.Nil(t, err) {
+		return nil
+	}
+	resp := schema.Exec(context.Background(), query, "", nil)
+	errs := make([]string, 0, len(resp.Errors))
+	for _, e := range resp.Errors {
+		errs = append(errs, e.Message)
+	}
+	if result != nil && len(resp.Data) != 0 {
+		assert.Nil(t, json.Unmarshal(resp.Data, result))
+	}
+	return errs
+}
+
+func TestNewSchema(t *testing.T) {
+	schema, err := NewSchema(2)
+	assert.Nil(t, err)
+
+	//rejected by depth validation before any resolver runs
+	resp := schema.Exec(context.Background(), `{ block(height: 1) { prevBlock { prevBlock { hash } } } }`, "", nil)
+	assert.NotEmpty(t, resp.Errors)
+}
+
+func TestCharge(t *testing.T) {
+	ctx := withCostLimit(context.Background(), 3)
+	assert.Nil(t, charge(ctx, 1))
+	assert.Nil(t, charge(ctx, 2))
+	assert.Equal(t, ErrCostExceeded, charge(ctx, 1))
+
+	unlimited := withCostLimit(context.Background(), 0)
+	assert.Nil(t, charge(unlimited, 1000000))
+	assert.Nil(t, charge(context.Background(), 1))
+}
+
+type testBlock struct {
+	Hash             string
+	Height           int32
+	TransactionCount int32
+	Transactions     []struct{ Hash string }
+}
+
+func TestBlockQuery(t *testing.T) {
+	genesisHash := ledger.DefLedger.GetBlockHash(0)
+
+	result := &struct {
+		BlockHeight int32
+		Block       *testBlock
+	}{}
+	errs := execQuery(t, `{ blockHeight block(height: 0) { hash height transactionCount transactions { hash } } }`, result)
+	assert.Empty(t, errs)
+	assert.Equal(t, int32(0), result.BlockHeight)
+	if assert.NotNil(t, result.Block) {
+		assert.Equal(t, genesisHash.ToHexString(), result.Block.Hash)
+		assert.Equal(t, int32(0), result.Block.Height)
+		assert.NotZero(t, result.Block.TransactionCount)
+		assert.Equal(t, int(result.Block.TransactionCount), len(result.Block.Transactions))
+	}
+
+	result.Block = nil
+	errs = execQuery(t, fmt.Sprintf(`{ block(hash: "%s") { height } }`, genesisHash.ToHexString()), result)
+	assert.Empty(t, errs)
+	if assert.NotNil(t, result.Block) {
+		assert.Equal(t, int32(0), result.Block.Height)
+	}
+
+	//unknown block is null
+	result.Block = &testBlock{}
+	errs = execQuery(t, `{ block(height: 100) { hash } }`, result)
+	assert.Empty(t, errs)
+	assert.Nil(t, result.Block)
+	result.Block = &testBlock{}
+	errs = execQuery(t, fmt.Sprintf(`{ block(hash: "%s") { hash } }`, common.UINT256_EMPTY.ToHexString()), result)
+	assert.Empty(t, errs)
+	assert.Nil(t, result.Block)
+
+	assert.NotEmpty(t, execQuery(t, `{ block(height: -1) { hash } }`, nil))
+	assert.NotEmpty(t, execQuery(t, `{ block(hash: "invalid") { hash } }`, nil))
+}
+
+func TestTransactionQuery(t *testing.T) {
+	block, err := ledger.DefLedger.GetBlockByHeight(0)
+	if !assert.Nil(t, err) || !assert.NotEmpty(t, block.Transactions) {
+		return
+	}
+	txHash := block.Transactions[0].Hash()
+
+	result := &struct {
+		Transaction *struct {
+			Hash      string
+			Height    int32
+			Block     struct{ Height int32 }
+			Execution *struct {
+				State  int32
+				Notify []struct{ Contract string }
+			}
+		}
+	}{}
+	errs := execQuery(t, fmt.Sprintf(`{ transaction(hash: "%s") { hash height block { height } execution { state notify { contract } } } }`,
+		txHash.ToHexString()), result)
+	assert.Empty(t, errs)
+	if assert.NotNil(t, result.Transaction) {
+		assert.Equal(t, txHash.ToHexString(), result.Transaction.Hash)
+		assert.Equal(t, int32(0), result.Transaction.Height)
+		assert.Equal(t, int32(0), result.Transaction.Block.Height)
+		assert.NotNil(t, result.Transaction.Execution)
+	}
+
+	//unknown transaction is null
+	errs = execQuery(t, fmt.Sprintf(`{ transaction(hash: "%s") { hash } }`, common.UINT256_EMPTY.ToHexString()), result)
+	assert.Empty(t, errs)
+	assert.Nil(t, result.Transaction)
+
+	assert.NotEmpty(t, execQuery(t, `{ transaction(hash: "invalid") { hash } }`, nil))
+}
+
+func TestEventsQuery(t *testing.T) {
+	ont := utils.OntContractAddress.ToHexString()
+	result := &struct {
+		Events []struct {
+			TxHash      string
+			Height      int32
+			Contract    string
+			Transaction struct{ Hash string }
+		}
+	}{}
+	//ont is distributed in genesis block
+	errs := execQuery(t, fmt.Sprintf(`{ events(contract: "%s", limit: 1) { txHash height contract transaction { hash } } }`, ont), result)
+	assert.Empty(t, errs)
+	if assert.Equal(t, 1, len(result.Events)) {
+		assert.Equal(t, int32(0), result.Events[0].Height)
+		assert.Equal(t, ont, result.Events[0].Contract)
+		assert.Equal(t, result.Events[0].TxHash, result.Events[0].Transaction.Hash)
+	}
+
+	assert.NotEmpty(t, execQuery(t, `{ events(contract: "invalid") { txHash } }`, nil))
+	assert.NotEmpty(t, execQuery(t, fmt.Sprintf(`{ events(contract: "%s", limit: 0) { txHash } }`, ont), nil))
+	assert.NotEmpty(t, execQuery(t, fmt.Sprintf(`{ events(contract: "%s", startHeight: 2, endHeight: 1) { txHash } }`, ont), nil))
+	assert.NotEmpty(t, execQuery(t, fmt.Sprintf(`{ events(contract: "%s", offset: -1) { txHash } }`, ont), nil))
+
+	config.DefConfig.Common.EnableEventLog = false
+	defer func() {
+		config.DefConfig.Common.EnableEventLog = true
+	}()
+	assert.NotEmpty(t, execQuery(t, fmt.Sprintf(`{ events(contract: "%s") { txHash } }`, ont), nil))
+}
+
+func TestAccountQuery(t *testing.T) {
+	addr := common.AddressFromVmCode([]byte("graphql"))
+	result := &struct {
+		Account struct {
+			Address string
+			Ont     string
+			Ong     string
+		}
+	}{}
+	errs := execQuery(t, fmt.Sprintf(`{ account(address: "%s") { address ont ong } }`, addr.ToBase58()), result)
+	assert.Empty(t, errs)
+	assert.Equal(t, addr.ToBase58(), result.Account.Address)
+	assert.Equal(t, "0", result.Account.Ont)
+	assert.Equal(t, "0", result.Account.Ong)
+
+	assert.NotEmpty(t, execQuery(t, `{ account(address: "invalid") { ont } }`, nil))
+}
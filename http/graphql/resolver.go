@@ -0,0 +1,510 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/core/payload"
+	scom "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/core/types"
+	bactor "github.com/ontio/ontology/http/base/actor"
+	bcomn "github.com/ontio/ontology/http/base/common"
+	"github.com/ontio/ontology/smartcontract/event"
+)
+
+//Resolver is the root resolver of graphql query
+type Resolver struct{}
+
+func (this *Resolver) BlockHeight() int32 {
+	return int32(bactor.GetCurrentBlockHeight())
+}
+
+func (this *Resolver) Block(ctx context.Context, args struct {
+	Height *int32
+	Hash   *string
+}) (*blockResolver, error) {
+	if args.Hash != nil {
+		hash, err := common.Uint256FromHexString(*args.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block hash:%s", *args.Hash)
+		}
+		return getBlockByHash(ctx, hash)
+	}
+	height := bactor.GetCurrentBlockHeight()
+	if args.Height != nil {
+		if *args.Height < 0 {
+			return nil, fmt.Errorf("invalid block height:%d", *args.Height)
+		}
+		height = uint32(*args.Height)
+	}
+	return getBlockByHeight(ctx, height)
+}
+
+func (this *Resolver) Transaction(ctx context.Context, args struct{ Hash string }) (*txResolver, error) {
+	hash, err := common.Uint256FromHexString(args.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction hash:%s", args.Hash)
+	}
+	return getTransaction(ctx, hash)
+}
+
+func (this *Resolver) Account(args struct{ Address string }) (*accountResolver, error) {
+	addr, err := bcomn.GetAddress(args.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address:%s", args.Address)
+	}
+	return &accountResolver{addr: addr}, nil
+}
+
+func (this *Resolver) Contract(ctx context.Context, args struct{ Address string }) (*contractResolver, error) {
+	addr, err := bcomn.GetAddress(args.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract address:%s", args.Address)
+	}
+	if err := charge(ctx, 1); err != nil {
+		return nil, err
+	}
+	contract, err := bactor.GetContractStateFromStore(addr)
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if contract == nil {
+		return nil, nil
+	}
+	return &contractResolver{addr: addr, contract: contract}, nil
+}
+
+func (this *Resolver) Events(ctx context.Context, args struct {
+	Contract    string
+	Topic       *string
+	StartHeight *int32
+	EndHeight   *int32
+	Offset      *int32
+	Limit       *int32
+}) ([]*eventResolver, error) {
+	addr, err := bcomn.GetAddress(args.Contract)
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract address:%s", args.Contract)
+	}
+	return getEvents(ctx, addr, args.Topic, args.StartHeight, args.EndHeight, args.Offset, args.Limit)
+}
+
+type blockResolver struct {
+	block *types.Block
+}
+
+func (this *blockResolver) Hash() string {
+	hash := this.block.Hash()
+	return hash.ToHexString()
+}
+
+func (this *blockResolver) Height() int32 {
+	return int32(this.block.Header.Height)
+}
+
+func (this *blockResolver) Timestamp() int32 {
+	return int32(this.block.Header.Timestamp)
+}
+
+func (this *blockResolver) PrevHash() string {
+	return this.block.Header.PrevBlockHash.ToHexString()
+}
+
+func (this *blockResolver) PrevBlock(ctx context.Context) (*blockResolver, error) {
+	if this.block.Header.Height == 0 {
+		return nil, nil
+	}
+	return getBlockByHash(ctx, this.block.Header.PrevBlockHash)
+}
+
+func (this *blockResolver) TransactionsRoot() string {
+	return this.block.Header.TransactionsRoot.ToHexString()
+}
+
+func (this *blockResolver) TransactionCount() int32 {
+	return int32(len(this.block.Transactions))
+}
+
+func (this *blockResolver) Transactions(ctx context.Context) ([]*txResolver, error) {
+	if err := charge(ctx, uint(len(this.block.Transactions))); err != nil {
+		return nil, err
+	}
+	txs := make([]*txResolver, 0, len(this.block.Transactions))
+	for _, tx := range this.block.Transactions {
+		txs = append(txs, &txResolver{tx: tx, height: this.block.Header.Height})
+	}
+	return txs, nil
+}
+
+type txResolver struct {
+	tx     *types.Transaction
+	height uint32
+}
+
+func (this *txResolver) Hash() string {
+	hash := this.tx.Hash()
+	return hash.ToHexString()
+}
+
+func (this *txResolver) Height() int32 {
+	return int32(this.height)
+}
+
+func (this *txResolver) TxType() int32 {
+	return int32(this.tx.TxType)
+}
+
+func (this *txResolver) Nonce() string {
+	return strconv.FormatUint(uint64(this.tx.Nonce), 10)
+}
+
+func (this *txResolver) GasPrice() string {
+	return strconv.FormatUint(this.tx.GasPrice, 10)
+}
+
+func (this *txResolver) GasLimit() string {
+	return strconv.FormatUint(this.tx.GasLimit, 10)
+}
+
+func (this *txResolver) Payer() *accountResolver {
+	return &accountResolver{addr: this.tx.Payer}
+}
+
+func (this *txResolver) Block(ctx context.Context) (*blockResolver, error) {
+	return getBlockByHeight(ctx, this.height)
+}
+
+func (this *txResolver) Execution(ctx context.Context) (*executionResolver, error) {
+	if err := charge(ctx, 1); err != nil {
+		return nil, err
+	}
+	notify, err := bactor.GetEventNotifyByTxHash(this.tx.Hash())
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if notify == nil {
+		return nil, nil
+	}
+	return &executionResolver{notify: notify}, nil
+}
+
+type executionResolver struct {
+	notify *event.ExecuteNotify
+}
+
+func (this *executionResolver) State() int32 {
+	return int32(this.notify.State)
+}
+
+func (this *executionResolver) GasConsumed() string {
+	return strconv.FormatUint(this.notify.GasConsumed, 10)
+}
+
+func (this *executionResolver) Notify() []*notifyResolver {
+	notifies := make([]*notifyResolver, 0, len(this.notify.Notify))
+	for _, v := range this.notify.Notify {
+		notifies = append(notifies, &notifyResolver{notify: v})
+	}
+	return notifies
+}
+
+type notifyResolver struct {
+	notify *event.NotifyEventInfo
+}
+
+func (this *notifyResolver) Contract() string {
+	return this.notify.ContractAddress.ToHexString()
+}
+
+func (this *notifyResolver) States() (string, error) {
+	return marshalStates(this.notify.States)
+}
+
+type accountResolver struct {
+	addr common.Address
+}
+
+func (this *accountResolver) Address() string {
+	return this.addr.ToBase58()
+}
+
+func (this *accountResolver) Ont(ctx context.Context) (string, error) {
+	balance, err := this.getBalance(ctx)
+	if err != nil {
+		return "", err
+	}
+	return balance.Ont, nil
+}
+
+func (this *accountResolver) Ong(ctx context.Context) (string, error) {
+	balance, err := this.getBalance(ctx)
+	if err != nil {
+		return "", err
+	}
+	return balance.Ong, nil
+}
+
+func (this *accountResolver) getBalance(ctx context.Context) (*bcomn.BalanceOfRsp, error) {
+	if err := charge(ctx, 1); err != nil {
+		return nil, err
+	}
+	return bcomn.GetBalance(this.addr)
+}
+
+func (this *accountResolver) Transactions(ctx context.Context, args struct {
+	StartHeight *int32
+	EndHeight   *int32
+	Offset      *int32
+	Limit       *int32
+}) ([]*txResolver, error) {
+	if !config.DefConfig.Common.EnableAddressIndex {
+		return nil, errors.New("address index is disabled")
+	}
+	indexHeight, err := bactor.GetIndexStartHeight(scom.IX_ADDRESS_TX)
+	if err != nil {
+		return nil, err
+	}
+	startHeight, endHeight, offset, limit, err := getPageArgs(args.StartHeight, args.EndHeight, args.Offset, args.Limit, indexHeight)
+	if err != nil {
+		return nil, err
+	}
+	if err := charge(ctx, 1); err != nil {
+		return nil, err
+	}
+	addrTxs, err := bactor.GetAddressTxs(this.addr, startHeight, endHeight, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err := charge(ctx, uint(len(addrTxs))); err != nil {
+		return nil, err
+	}
+	txs := make([]*txResolver, 0, len(addrTxs))
+	for _, v := range addrTxs {
+		height, tx, err := bactor.GetTxnWithHeightByTxHash(v.TxHash)
+		if err != nil {
+			return nil, fmt.Errorf("get transaction %s error:%s", v.TxHash.ToHexString(), err)
+		}
+		txs = append(txs, &txResolver{tx: tx, height: height})
+	}
+	return txs, nil
+}
+
+type contractResolver struct {
+	addr     common.Address
+	contract *payload.DeployCode
+}
+
+func (this *contractResolver) Address() string {
+	return this.addr.ToHexString()
+}
+
+func (this *contractResolver) Name() string {
+	return this.contract.Name
+}
+
+func (this *contractResolver) Version() string {
+	return this.contract.Version
+}
+
+func (this *contractResolver) Author() string {
+	return this.contract.Author
+}
+
+func (this *contractResolver) Email() string {
+	return this.contract.Email
+}
+
+func (this *contractResolver) Description() string {
+	return this.contract.Description
+}
+
+func (this *contractResolver) NeedStorage() bool {
+	return this.contract.NeedStorage
+}
+
+func (this *contractResolver) Code() string {
+	return common.ToHexString(this.contract.Code)
+}
+
+func (this *contractResolver) Events(ctx context.Context, args struct {
+	Topic       *string
+	StartHeight *int32
+	EndHeight   *int32
+	Offset      *int32
+	Limit       *int32
+}) ([]*eventResolver, error) {
+	return getEvents(ctx, this.addr, args.Topic, args.StartHeight, args.EndHeight, args.Offset, args.Limit)
+}
+
+type eventResolver struct {
+	notify *event.ContractEventNotify
+}
+
+func (this *eventResolver) TxHash() string {
+	return this.notify.TxHash.ToHexString()
+}
+
+func (this *eventResolver) Transaction(ctx context.Context) (*txResolver, error) {
+	return getTransaction(ctx, this.notify.TxHash)
+}
+
+func (this *eventResolver) Height() int32 {
+	return int32(this.notify.Height)
+}
+
+func (this *eventResolver) EventIndex() int32 {
+	return int32(this.notify.EventIndex)
+}
+
+func (this *eventResolver) Contract() string {
+	return this.notify.Notify.ContractAddress.ToHexString()
+}
+
+func (this *eventResolver) States() (string, error) {
+	return marshalStates(this.notify.Notify.States)
+}
+
+func getBlockByHeight(ctx context.Context, height uint32) (*blockResolver, error) {
+	if err := charge(ctx, 1); err != nil {
+		return nil, err
+	}
+	if height > bactor.GetCurrentBlockHeight() {
+		return nil, nil
+	}
+	block, err := bactor.GetBlockByHeight(height)
+	return newBlockResolver(block, err)
+}
+
+func getBlockByHash(ctx context.Context, hash common.Uint256) (*blockResolver, error) {
+	if err := charge(ctx, 1); err != nil {
+		return nil, err
+	}
+	block, err := bactor.GetBlockFromStore(hash)
+	return newBlockResolver(block, err)
+}
+
+func newBlockResolver(block *types.Block, err error) (*blockResolver, error) {
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if block == nil || block.Header == nil {
+		return nil, nil
+	}
+	return &blockResolver{block: block}, nil
+}
+
+func getTransaction(ctx context.Context, hash common.Uint256) (*txResolver, error) {
+	if err := charge(ctx, 1); err != nil {
+		return nil, err
+	}
+	height, tx, err := bactor.GetTxnWithHeightByTxHash(hash)
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if tx == nil {
+		return nil, nil
+	}
+	return &txResolver{tx: tx, height: height}, nil
+}
+
+func getEvents(ctx context.Context, contract common.Address, topic *string,
+	startHeight, endHeight, offset, limit *int32) ([]*eventResolver, error) {
+	if !config.DefConfig.Common.EnableEventLog {
+		return nil, errors.New("event log is disabled")
+	}
+	indexHeight, err := bactor.GetIndexStartHeight(scom.EVENT_NOTIFY_BY_CONTRACT)
+	if err != nil {
+		return nil, err
+	}
+	start, end, off, lim, err := getPageArgs(startHeight, endHeight, offset, limit, indexHeight)
+	if err != nil {
+		return nil, err
+	}
+	t := ""
+	if topic != nil {
+		t = *topic
+	}
+	if err := charge(ctx, 1); err != nil {
+		return nil, err
+	}
+	notifies, err := bactor.GetEventNotifyByContract(contract, t, start, end, off, lim)
+	if err != nil {
+		return nil, err
+	}
+	if err := charge(ctx, uint(len(notifies))); err != nil {
+		return nil, err
+	}
+	evts := make([]*eventResolver, 0, len(notifies))
+	for _, v := range notifies {
+		evts = append(evts, &eventResolver{notify: v})
+	}
+	return evts, nil
+}
+
+//getPageArgs apply the same defaults and bounds as paged rpc queries
+func getPageArgs(startHeight, endHeight, offset, limit *int32, minHeight uint32) (uint32, uint32, uint32, uint32, error) {
+	nums := []uint32{minHeight, bactor.GetCurrentBlockHeight(), 0, bcomn.MAX_EVENT_QUERY_LIMIT}
+	for i, arg := range []*int32{startHeight, endHeight, offset, limit} {
+		if arg == nil {
+			continue
+		}
+		if *arg < 0 {
+			return 0, 0, 0, 0, fmt.Errorf("invalid page argument:%d", *arg)
+		}
+		nums[i] = uint32(*arg)
+	}
+	start, end, off, lim := nums[0], nums[1], nums[2], nums[3]
+	if start < minHeight {
+		return 0, 0, 0, 0, fmt.Errorf("startHeight %d is below height %d from which the index is built", start, minHeight)
+	}
+	if start > end {
+		return 0, 0, 0, 0, fmt.Errorf("startHeight %d is greater than endHeight %d", start, end)
+	}
+	if lim == 0 || lim > bcomn.MAX_EVENT_QUERY_LIMIT {
+		return 0, 0, 0, 0, fmt.Errorf("limit should be in range [1, %d]", bcomn.MAX_EVENT_QUERY_LIMIT)
+	}
+	return start, end, off, lim, nil
+}
+
+func marshalStates(states interface{}) (string, error) {
+	data, err := json.Marshal(states)
+	if err != nil {
+		return "", fmt.Errorf("marshal states error:%s", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package graphql
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+//SCHEMA is the graphql schema of ledger query
+const SCHEMA = `
+schema {
+	query: Query
+}
+
+type Query {
+	blockHeight: Int!
+	block(height: Int, hash: String): Block
+	transaction(hash: String!): Transaction
+	account(address: String!): Account!
+	contract(address: String!): Contract
+	events(contract: String!, topic: String, startHeight: Int, endHeight: Int, offset: Int, limit: Int): [Event!]!
+}
+
+type Block {
+	hash: String!
+	height: Int!
+	timestamp: Int!
+	prevHash: String!
+	prevBlock: Block
+	transactionsRoot: String!
+	transactionCount: Int!
+	transactions: [Transaction!]!
+}
+
+type Transaction {
+	hash: String!
+	height: Int!
+	txType: Int!
+	nonce: String!
+	gasPrice: String!
+	gasLimit: String!
+	payer: Account!
+	block: Block
+	execution: Execution
+}
+
+type Execution {
+	state: Int!
+	gasConsumed: String!
+	notify: [Notify!]!
+}
+
+type Notify {
+	contract: String!
+	states: String!
+}
+
+type Account {
+	address: String!
+	ont: String!
+	ong: String!
+	transactions(startHeight: Int, endHeight: Int, offset: Int, limit: Int): [Transaction!]!
+}
+
+type Contract {
+	address: String!
+	name: String!
+	version: String!
+	author: String!
+	email: String!
+	description: String!
+	needStorage: Boolean!
+	code: String!
+	events(topic: String, startHeight: Int, endHeight: Int, offset: Int, limit: Int): [Event!]!
+}
+
+type Event {
+	txHash: String!
+	transaction: Transaction
+	height: Int!
+	eventIndex: Int!
+	contract: String!
+	states: String!
+}
+`
+
+var ErrCostExceeded = errors.New("query cost exceeds limit")
+
+type costKey struct{}
+
+//queryCost count the ledger lookups of a query, resolvers may run concurrently
+type queryCost struct {
+	lock  sync.Mutex
+	used  uint
+	limit uint
+}
+
+func withCostLimit(ctx context.Context, limit uint) context.Context {
+	return context.WithValue(ctx, costKey{}, &queryCost{limit: limit})
+}
+
+//charge add n to the cost of query in ctx, fail when over the limit
+func charge(ctx context.Context, n uint) error {
+	cost, ok := ctx.Value(costKey{}).(*queryCost)
+	if !ok || cost.limit == 0 {
+		return nil
+	}
+	cost.lock.Lock()
+	defer cost.lock.Unlock()
+	cost.used += n
+	if cost.used > cost.limit {
+		return ErrCostExceeded
+	}
+	return nil
+}
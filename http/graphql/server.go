@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package graphql privides a graphql query server over the ledger
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	gql "github.com/graph-gophers/graphql-go"
+	cfg "github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/http/base/auth"
+)
+
+const (
+	GRAPHQL_PATH      = "/graphql"
+	MAX_REQUEST_BYTES = 1024 * 1024
+)
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type handler struct {
+	schema  *gql.Schema
+	maxCost uint
+}
+
+//NewSchema parse the ledger schema with the max query depth
+func NewSchema(maxDepth uint) (*gql.Schema, error) {
+	return gql.ParseSchema(SCHEMA, &Resolver{}, gql.MaxDepth(int(maxDepth)))
+}
+
+func StartServer() error {
+	config := cfg.DefConfig.GraphQL
+	schema, err := NewSchema(config.MaxQueryDepth)
+	if err != nil {
+		return fmt.Errorf("parse graphql schema error:%s", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle(GRAPHQL_PATH, &handler{schema: schema, maxCost: config.MaxQueryCost})
+	log.Infof("GraphQL server listen on %d", config.GraphQLPort)
+	return http.ListenAndServe(":"+strconv.Itoa(int(config.GraphQLPort)), mux)
+}
+
+func (this *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !auth.Guard(w, r) {
+		return
+	}
+	granted, err := auth.Authenticate(r)
+	if err == nil {
+		err = auth.Authorize(granted, "graphql")
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	req := &request{}
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+		if vars := query.Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				http.Error(w, "invalid variables", http.StatusBadRequest)
+				return
+			}
+		}
+	case http.MethodPost:
+		err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MAX_REQUEST_BYTES)).Decode(req)
+		if err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if req.Query == "" {
+		http.Error(w, "missing query", http.StatusBadRequest)
+		return
+	}
+
+	ctx := withCostLimit(r.Context(), this.maxCost)
+	resp := this.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	data, err := json.Marshal(resp)
+	if err != nil {
+		log.Errorf("GraphQL marshal response error:%s", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
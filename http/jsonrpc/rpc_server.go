@@ -31,36 +31,43 @@ import (
 
 func StartRPCServer() error {
 	log.Debug()
-	http.HandleFunc("/", rpc.Handle)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", rpc.Handle)
 
 	rpc.HandleFunc("getbestblockhash", rpc.GetBestBlockHash)
-	rpc.HandleFunc("getblock", rpc.GetBlock)
+	rpc.HandleFunc("getblock", rpc.GetBlock, "block", "verbose")
 	rpc.HandleFunc("getblockcount", rpc.GetBlockCount)
-	rpc.HandleFunc("getblockhash", rpc.GetBlockHash)
+	rpc.HandleFunc("getblockhash", rpc.GetBlockHash, "height")
 	rpc.HandleFunc("getconnectioncount", rpc.GetConnectionCount)
 	//HandleFunc("getrawmempool", GetRawMemPool)
 
-	rpc.HandleFunc("getrawtransaction", rpc.GetRawTransaction)
-	rpc.HandleFunc("sendrawtransaction", rpc.SendRawTransaction)
-	rpc.HandleFunc("getstorage", rpc.GetStorage)
+	rpc.HandleFunc("getrawtransaction", rpc.GetRawTransaction, "hash", "verbose")
+	rpc.HandleFunc("sendrawtransaction", rpc.SendRawTransaction, "tx", "preexec")
+	rpc.HandleFunc("getstorage", rpc.GetStorage, "contract", "key")
 	rpc.HandleFunc("getversion", rpc.GetNodeVersion)
 	rpc.HandleFunc("getnetworkid", rpc.GetNetworkId)
 
-	rpc.HandleFunc("getcontractstate", rpc.GetContractState)
+	rpc.HandleFunc("getcontractstate", rpc.GetContractState, "contract", "verbose")
 	rpc.HandleFunc("getmempooltxcount", rpc.GetMemPoolTxCount)
-	rpc.HandleFunc("getmempooltxstate", rpc.GetMemPoolTxState)
-	rpc.HandleFunc("getsmartcodeevent", rpc.GetSmartCodeEvent)
-	rpc.HandleFunc("getblockheightbytxhash", rpc.GetBlockHeightByTxHash)
+	rpc.HandleFunc("getmempooltxstate", rpc.GetMemPoolTxState, "hash")
+	rpc.HandleFunc("getsmartcodeevent", rpc.GetSmartCodeEvent, "hash_or_height")
+	rpc.HandleFunc("getevents", rpc.GetEvents, "contract", "topic", "startheight", "endheight", "offset", "limit")
+	rpc.HandleFunc("getaddresstxs", rpc.GetAddressTxs, "address", "startheight", "endheight", "offset", "limit")
+	rpc.HandleFunc("getcontractabi", rpc.GetContractAbi, "contract")
+	rpc.HandleFunc("dryruntransaction", rpc.DryRunTransaction, "tx", "height", "overrides")
+	rpc.HandleFunc("tracetransaction", rpc.TraceTransaction, "hash")
+	rpc.HandleFunc("estimategas", rpc.EstimateGas, "tx")
+	rpc.HandleFunc("getblockheightbytxhash", rpc.GetBlockHeightByTxHash, "hash")
 
-	rpc.HandleFunc("getbalance", rpc.GetBalance)
-	rpc.HandleFunc("getallowance", rpc.GetAllowance)
-	rpc.HandleFunc("getmerkleproof", rpc.GetMerkleProof)
-	rpc.HandleFunc("getblocktxsbyheight", rpc.GetBlockTxsByHeight)
+	rpc.HandleFunc("getbalance", rpc.GetBalance, "address")
+	rpc.HandleFunc("getallowance", rpc.GetAllowance, "asset", "from", "to")
+	rpc.HandleFunc("getmerkleproof", rpc.GetMerkleProof, "hash")
+	rpc.HandleFunc("getblocktxsbyheight", rpc.GetBlockTxsByHeight, "height")
 	rpc.HandleFunc("getgasprice", rpc.GetGasPrice)
-	rpc.HandleFunc("getunboundong", rpc.GetUnboundOng)
-	rpc.HandleFunc("getgrantong", rpc.GetGrantOng)
+	rpc.HandleFunc("getunboundong", rpc.GetUnboundOng, "address")
+	rpc.HandleFunc("getgrantong", rpc.GetGrantOng, "address")
 
-	err := http.ListenAndServe(":"+strconv.Itoa(int(cfg.DefConfig.Rpc.HttpJsonPort)), nil)
+	err := http.ListenAndServe(":"+strconv.Itoa(int(cfg.DefConfig.Rpc.HttpJsonPort)), mux)
 	if err != nil {
 		return fmt.Errorf("ListenAndServe error:%s", err)
 	}
@@ -36,16 +36,25 @@ const (
 
 func StartLocalServer() error {
 	log.Debug()
-	http.HandleFunc(LOCAL_DIR, rpc.Handle)
+	mux := http.NewServeMux()
+	mux.HandleFunc(LOCAL_DIR, rpc.HandleLocal)
 
-	rpc.HandleFunc("getneighbor", rpc.GetNeighbor)
-	rpc.HandleFunc("getnodestate", rpc.GetNodeState)
-	rpc.HandleFunc("startconsensus", rpc.StartConsensus)
-	rpc.HandleFunc("stopconsensus", rpc.StopConsensus)
-	rpc.HandleFunc("setdebuginfo", rpc.SetDebugInfo)
+	rpc.HandleLocalFunc("getneighbor", rpc.GetNeighbor)
+	rpc.HandleLocalFunc("getnodestate", rpc.GetNodeState)
+	rpc.HandleLocalFunc("startconsensus", rpc.StartConsensus)
+	rpc.HandleLocalFunc("stopconsensus", rpc.StopConsensus)
+	rpc.HandleLocalFunc("setdebuginfo", rpc.SetDebugInfo, "level")
+	rpc.HandleLocalFunc("genblocks", rpc.GenBlocks, "count")
+	rpc.HandleLocalFunc("increasetime", rpc.IncreaseTime, "seconds")
+	rpc.HandleLocalFunc("getpeers", rpc.GetPeers)
+	rpc.HandleLocalFunc("getbanlist", rpc.GetBanList)
+	rpc.HandleLocalFunc("banpeer", rpc.BanPeer, "peer", "duration", "reason")
+	rpc.HandleLocalFunc("unbanpeer", rpc.UnbanPeer, "peer")
+	rpc.HandleLocalFunc("setcontractabi", rpc.SetContractAbi, "contract", "abi")
+	rpc.HandleLocalFunc("deletecontractabi", rpc.DeleteContractAbi, "contract")
 
 	// TODO: only listen to local host
-	err := http.ListenAndServe(":"+strconv.Itoa(int(cfg.DefConfig.Rpc.HttpLocalPort)), nil)
+	err := http.ListenAndServe(":"+strconv.Itoa(int(cfg.DefConfig.Rpc.HttpLocalPort)), mux)
 	if err != nil {
 		return fmt.Errorf("ListenAndServe error:%s", err)
 	}
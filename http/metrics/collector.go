@@ -0,0 +1,119 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package metrics
+
+import (
+	"github.com/ontio/ontology/common/log"
+	cmetrics "github.com/ontio/ontology/common/metrics"
+	bactor "github.com/ontio/ontology/http/base/actor"
+	"github.com/ontio/ontology/p2pserver"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//txStatsNames are the labels of tx pool statistics, in the order of txnpool TxnStatsType
+var txStatsNames = []string{"received", "success", "failure", "duplicate", "sig_error", "state_error"}
+
+//nodeCollector read the node state from ledger, tx pool and p2p on every scrape
+type nodeCollector struct {
+	p2p *p2pserver.P2PServer
+
+	blockHeight   *prometheus.Desc
+	headerHeight  *prometheus.Desc
+	txPoolSize    *prometheus.Desc
+	txStats       *prometheus.Desc
+	peers         *prometheus.Desc
+	syncFlights   *prometheus.Desc
+	syncCached    *prometheus.Desc
+	syncCompleted *prometheus.Desc
+}
+
+func newNodeCollector(p2p *p2pserver.P2PServer) *nodeCollector {
+	return &nodeCollector{
+		p2p: p2p,
+		blockHeight: prometheus.NewDesc(prometheus.BuildFQName(cmetrics.NAMESPACE, "ledger", "block_height"),
+			"Current block height.", nil, nil),
+		headerHeight: prometheus.NewDesc(prometheus.BuildFQName(cmetrics.NAMESPACE, "ledger", "header_height"),
+			"Current header height.", nil, nil),
+		txPoolSize: prometheus.NewDesc(prometheus.BuildFQName(cmetrics.NAMESPACE, "txpool", "size"),
+			"Count of transactions in tx pool by state.", []string{"state"}, nil),
+		txStats: prometheus.NewDesc(prometheus.BuildFQName(cmetrics.NAMESPACE, "txpool", "transactions_total"),
+			"Count of transactions handled by tx pool by result.", []string{"result"}, nil),
+		peers: prometheus.NewDesc(prometheus.BuildFQName(cmetrics.NAMESPACE, "p2p", "peers"),
+			"Count of p2p connections by direction.", []string{"direction"}, nil),
+		syncFlights: prometheus.NewDesc(prometheus.BuildFQName(cmetrics.NAMESPACE, "p2p", "sync_flights"),
+			"Count of sync requests in flight by kind.", []string{"kind"}, nil),
+		syncCached: prometheus.NewDesc(prometheus.BuildFQName(cmetrics.NAMESPACE, "p2p", "sync_cached_blocks"),
+			"Count of received blocks waiting for saving.", nil, nil),
+		syncCompleted: prometheus.NewDesc(prometheus.BuildFQName(cmetrics.NAMESPACE, "p2p", "synced"),
+			"1 if block height reach all the neighbors, otherwise 0.", nil, nil),
+	}
+}
+
+func (this *nodeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- this.blockHeight
+	ch <- this.headerHeight
+	ch <- this.txPoolSize
+	ch <- this.txStats
+	ch <- this.peers
+	ch <- this.syncFlights
+	ch <- this.syncCached
+	ch <- this.syncCompleted
+}
+
+func (this *nodeCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(this.blockHeight, prometheus.GaugeValue,
+		float64(bactor.GetCurrentBlockHeight()))
+	ch <- prometheus.MustNewConstMetric(this.headerHeight, prometheus.GaugeValue,
+		float64(bactor.GetCurrentHeaderHeight()))
+
+	count, err := bactor.GetTxnCount()
+	if err != nil {
+		log.Warnf("[metrics]get tx pool count error:%s", err)
+	} else if len(count) == 2 {
+		ch <- prometheus.MustNewConstMetric(this.txPoolSize, prometheus.GaugeValue, float64(count[0]), "verified")
+		ch <- prometheus.MustNewConstMetric(this.txPoolSize, prometheus.GaugeValue, float64(count[1]), "pending")
+	}
+	stats, err := bactor.GetTxnStats()
+	if err != nil {
+		log.Warnf("[metrics]get tx pool stats error:%s", err)
+	} else {
+		for i, v := range stats {
+			if i >= len(txStatsNames) {
+				break
+			}
+			ch <- prometheus.MustNewConstMetric(this.txStats, prometheus.CounterValue, float64(v), txStatsNames[i])
+		}
+	}
+
+	network := this.p2p.GetNetWork()
+	ch <- prometheus.MustNewConstMetric(this.peers, prometheus.GaugeValue,
+		float64(network.GetInConnRecordLen()), "inbound")
+	ch <- prometheus.MustNewConstMetric(this.peers, prometheus.GaugeValue,
+		float64(network.GetOutConnRecordLen()), "outbound")
+
+	state := this.p2p.GetSyncState()
+	ch <- prometheus.MustNewConstMetric(this.syncFlights, prometheus.GaugeValue, float64(state.FlightHeaders), "header")
+	ch <- prometheus.MustNewConstMetric(this.syncFlights, prometheus.GaugeValue, float64(state.FlightBlocks), "block")
+	ch <- prometheus.MustNewConstMetric(this.syncCached, prometheus.GaugeValue, float64(state.CachedBlocks))
+	synced := 0.0
+	if state.Synced {
+		synced = 1
+	}
+	ch <- prometheus.MustNewConstMetric(this.syncCompleted, prometheus.GaugeValue, synced)
+}
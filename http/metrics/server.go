@@ -0,0 +1,45 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package metrics privides the prometheus metrics server of node
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+
+	cfg "github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/p2pserver"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const METRICS_PATH = "/metrics"
+
+func StartServer(p2p *p2pserver.P2PServer) error {
+	err := prometheus.Register(newNodeCollector(p2p))
+	if err != nil {
+		return err
+	}
+	port := int(cfg.DefConfig.Metrics.MetricsPort)
+	mux := http.NewServeMux()
+	mux.Handle(METRICS_PATH, promhttp.Handler())
+	log.Infof("Metrics server listen on %d", port)
+	return http.ListenAndServe(":"+strconv.Itoa(port), mux)
+}
@@ -25,6 +25,7 @@ import (
 	"encoding/json"
 	cfg "github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/http/base/auth"
 	berr "github.com/ontio/ontology/http/base/error"
 	"github.com/ontio/ontology/http/base/rest"
 	"golang.org/x/net/netutil"
@@ -64,6 +65,8 @@ const (
 	GET_CONTRACT_STATE    = "/api/v1/contract/:hash"
 	GET_SMTCOCE_EVT_TXS   = "/api/v1/smartcode/event/transactions/:height"
 	GET_SMTCOCE_EVTS      = "/api/v1/smartcode/event/txhash/:hash"
+	GET_CONTRACT_EVTS     = "/api/v1/smartcode/event/contract/:addr"
+	GET_ADDRESS_TXS       = "/api/v1/address/transactions/:addr"
 	GET_BLK_HGT_BY_TXHASH = "/api/v1/block/height/txhash/:hash"
 	GET_MERKLE_PROOF      = "/api/v1/merkleproof/:hash"
 	GET_GAS_PRICE         = "/api/v1/gasprice"
@@ -75,7 +78,8 @@ const (
 	GET_VERSION           = "/api/v1/version"
 	GET_NETWORKID         = "/api/v1/networkid"
 
-	POST_RAW_TX = "/api/v1/transaction"
+	POST_RAW_TX       = "/api/v1/transaction"
+	POST_ESTIMATE_GAS = "/api/v1/estimategas"
 )
 
 //init restful server
@@ -142,6 +146,8 @@ func (this *restServer) registryMethod() {
 		GET_CONTRACT_STATE:    {name: "getcontract", handler: rest.GetContractState},
 		GET_SMTCOCE_EVT_TXS:   {name: "getsmartcodeeventbyheight", handler: rest.GetSmartCodeEventTxsByHeight},
 		GET_SMTCOCE_EVTS:      {name: "getsmartcodeeventbyhash", handler: rest.GetSmartCodeEventByTxHash},
+		GET_CONTRACT_EVTS:     {name: "getevents", handler: rest.GetEvents},
+		GET_ADDRESS_TXS:       {name: "getaddresstxs", handler: rest.GetAddressTxs},
 		GET_BLK_HGT_BY_TXHASH: {name: "getblockheightbytxhash", handler: rest.GetBlockHeightByTxHash},
 		GET_STORAGE:           {name: "getstorage", handler: rest.GetStorage},
 		GET_BALANCE:           {name: "getbalance", handler: rest.GetBalance},
@@ -157,7 +163,8 @@ func (this *restServer) registryMethod() {
 	}
 
 	postMethodMap := map[string]Action{
-		POST_RAW_TX: {name: "sendrawtransaction", handler: rest.SendRawTransaction},
+		POST_RAW_TX:       {name: "sendrawtransaction", handler: rest.SendRawTransaction},
+		POST_ESTIMATE_GAS: {name: "estimategas", handler: rest.EstimateGas},
 	}
 	this.postMap = postMethodMap
 	this.getMap = getMethodMap
@@ -180,6 +187,10 @@ func (this *restServer) getPath(url string) string {
 		return GET_SMTCOCE_EVT_TXS
 	} else if strings.Contains(url, strings.TrimRight(GET_SMTCOCE_EVTS, ":hash")) {
 		return GET_SMTCOCE_EVTS
+	} else if strings.Contains(url, strings.TrimRight(GET_CONTRACT_EVTS, ":addr")) {
+		return GET_CONTRACT_EVTS
+	} else if strings.Contains(url, strings.TrimRight(GET_ADDRESS_TXS, ":addr")) {
+		return GET_ADDRESS_TXS
 	} else if strings.Contains(url, strings.TrimRight(GET_BLK_HGT_BY_TXHASH, ":hash")) {
 		return GET_BLK_HGT_BY_TXHASH
 	} else if strings.Contains(url, strings.TrimRight(GET_STORAGE, ":hash/:key")) {
@@ -225,6 +236,14 @@ func (this *restServer) getParams(r *http.Request, url string, req map[string]in
 		req["Height"] = getParam(r, "height")
 	case GET_SMTCOCE_EVTS:
 		req["Hash"] = getParam(r, "hash")
+	case GET_CONTRACT_EVTS:
+		req["Addr"], req["Topic"] = getParam(r, "addr"), r.FormValue("topic")
+		req["StartHeight"], req["EndHeight"] = r.FormValue("startheight"), r.FormValue("endheight")
+		req["Offset"], req["Limit"] = r.FormValue("offset"), r.FormValue("limit")
+	case GET_ADDRESS_TXS:
+		req["Addr"] = getParam(r, "addr")
+		req["StartHeight"], req["EndHeight"] = r.FormValue("startheight"), r.FormValue("endheight")
+		req["Offset"], req["Limit"] = r.FormValue("offset"), r.FormValue("limit")
 	case GET_BLK_HGT_BY_TXHASH:
 		req["Hash"] = getParam(r, "hash")
 	case GET_BALANCE:
@@ -256,6 +275,9 @@ func (this *restServer) initGetHandler() {
 
 			url := this.getPath(r.URL.Path)
 			if h, ok := this.getMap[url]; ok {
+				if !this.checkAccess(w, r, h.name) {
+					return
+				}
 				req = this.getParams(r, url, req)
 				resp = h.handler(req)
 				resp["Action"] = h.name
@@ -272,16 +294,23 @@ func (this *restServer) initPostHandler() {
 	for k, _ := range this.postMap {
 		this.router.Post(k, func(w http.ResponseWriter, r *http.Request) {
 
+			url := this.getPath(r.URL.Path)
+			if h, ok := this.postMap[url]; ok && !this.checkAccess(w, r, h.name) {
+				return
+			}
 			body, _ := ioutil.ReadAll(r.Body)
 			defer r.Body.Close()
 
 			var req = make(map[string]interface{})
 			var resp map[string]interface{}
 
-			url := this.getPath(r.URL.Path)
 			if h, ok := this.postMap[url]; ok {
 				if err := json.Unmarshal(body, &req); err == nil {
 					req = this.getParams(r, url, req)
+					if preExec, _ := req["PreExec"].(string); h.name == "sendrawtransaction" && preExec == "1" &&
+						!this.authorize(w, r, auth.PREEXEC_METHOD) {
+						return
+					}
 					resp = h.handler(req)
 					resp["Action"] = h.name
 				} else {
@@ -302,8 +331,32 @@ func (this *restServer) initPostHandler() {
 	}
 
 }
+//checkAccess apply the limits and permission of api access, write the rejection and return false if denied
+func (this *restServer) checkAccess(w http.ResponseWriter, r *http.Request, name string) bool {
+	if !auth.Guard(w, r) {
+		return false
+	}
+	return this.authorize(w, r, name)
+}
+
+//authorize check the permission of method name, write the rejection and return false if denied
+func (this *restServer) authorize(w http.ResponseWriter, r *http.Request, name string) bool {
+	granted, err := auth.Authenticate(r)
+	if err == nil {
+		err = auth.Authorize(granted, name)
+	}
+	if err != nil {
+		resp := rest.ResponsePack(berr.UNAUTHORIZED)
+		resp["Action"] = name
+		resp["Result"] = err.Error()
+		this.response(w, resp)
+		return false
+	}
+	return true
+}
+
 func (this *restServer) write(w http.ResponseWriter, data []byte) {
-	w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Add("Access-Control-Allow-Headers", "Content-Type, Authorization")
 	w.Header().Set("content-type", "application/json;charset=utf-8")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Write(data)
@@ -20,6 +20,8 @@
 package websocket
 
 import (
+	"sync"
+
 	"github.com/ontio/ontology/common"
 	cfg "github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
@@ -33,11 +35,31 @@ import (
 	"github.com/ontio/ontology/smartcontract/event"
 )
 
+const TX_PUSH_QUEUE_SIZE = 4096 //Pushes of tx and tx status waiting for the worker
+
 var ws *websocket.WsServer
 
+//txPushQueue hold the pushes of pending tx, tx status and finalized block, a single worker sends them in order,
+//so that a client never gets the status of a tx before the tx or an older status
+var txPushQueue = make(chan func(), TX_PUSH_QUEUE_SIZE)
+var txPushOnce sync.Once
+
+func startTxPushWorker() {
+	txPushOnce.Do(func() {
+		go func() {
+			for push := range txPushQueue {
+				push()
+			}
+		}()
+	})
+}
+
 func StartServer() {
+	startTxPushWorker()
 	bactor.SubscribeEvent(message.TOPIC_SAVE_BLOCK_COMPLETE, sendBlock2WSclient)
 	bactor.SubscribeEvent(message.TOPIC_SMART_CODE_EVENT, pushSmartCodeEvent)
+	bactor.SubscribeEvent(message.TOPIC_NEW_TRANSACTION, pushPendingTx)
+	bactor.SubscribeEvent(message.TOPIC_TX_STATUS, pushTxStatus)
 	go func() {
 		ws = websocket.InitWsServer()
 		ws.Start()
@@ -49,6 +71,7 @@ func sendBlock2WSclient(v interface{}) {
 			pushBlock(v)
 			pushBlockTransactions(v)
 		}()
+		txPushQueue <- func() { pushFinalizedBlock(v) }
 	}
 }
 func Stop() {
@@ -126,3 +149,88 @@ func pushBlockTransactions(v interface{}) {
 		ws.BroadcastToSubscribers(nil, websocket.WSTOPIC_TXHASHS, resp)
 	}
 }
+
+//pushFinalizedBlock push the block and its txs as final, blocks saved by vbft, dbft or solo are never reverted
+func pushFinalizedBlock(v interface{}) {
+	if ws == nil {
+		return
+	}
+	block, ok := v.(types.Block)
+	if !ok {
+		return
+	}
+	for _, txn := range block.Transactions {
+		pushTxStatusInfo(txn, message.TX_STATUS_INCLUDED, "", block.Header.Height)
+	}
+	hash := block.Hash()
+	resp := rest.ResponsePack(Err.SUCCESS)
+	resp["Action"] = "sendfinalizedblock"
+	resp["Result"] = map[string]interface{}{
+		"Height": block.Header.Height,
+		"Hash":   hash.ToHexString(),
+	}
+	ws.BroadcastToSubscribers(nil, websocket.WSTOPIC_FINALIZED, resp)
+}
+
+func pushPendingTx(v interface{}) {
+	if ws == nil {
+		return
+	}
+	txn, ok := v.(*types.Transaction)
+	if !ok {
+		return
+	}
+	txPushQueue <- func() {
+		payers, contracts := getTxFilterAddrs(txn)
+		resp := rest.ResponsePack(Err.SUCCESS)
+		resp["Action"] = "sendpendingtx"
+		resp["Result"] = bcomn.TransArryByteToHexString(txn)
+		ws.BroadcastTxToSubscribers(payers, contracts, websocket.WSTOPIC_PENDING_TX, resp)
+
+		pushTxStatusInfo(txn, message.TX_STATUS_VERIFIED, "", 0)
+	}
+}
+
+func pushTxStatus(v interface{}) {
+	if ws == nil {
+		return
+	}
+	rs, ok := v.(message.TxStatusMsg)
+	if !ok {
+		return
+	}
+	txPushQueue <- func() { pushTxStatusInfo(rs.Tx, rs.Status, rs.Reason, 0) }
+}
+
+func pushTxStatusInfo(txn *types.Transaction, status string, reason string, height uint32) {
+	payers, contracts := getTxFilterAddrs(txn)
+	hash := txn.Hash()
+	info := bcomn.TxStatusInfo{
+		TxHash: hash.ToHexString(),
+		Status: status,
+		Reason: reason,
+		Height: height,
+		Payer:  txn.Payer.ToBase58(),
+	}
+	if contract, ok := bcomn.GetTxContract(txn); ok {
+		info.Contract = contract.ToHexString()
+	}
+	resp := rest.ResponsePack(Err.SUCCESS)
+	resp["Action"] = "sendtxstatus"
+	resp["Result"] = info
+	ws.BroadcastTxToSubscribers(payers, contracts, websocket.WSTOPIC_TX_STATUS, resp)
+}
+
+//getTxFilterAddrs return payer and contract of tx in both base58 and hex format
+func getTxFilterAddrs(txn *types.Transaction) (map[string]bool, map[string]bool) {
+	payers := map[string]bool{
+		txn.Payer.ToBase58():    true,
+		txn.Payer.ToHexString(): true,
+	}
+	contracts := make(map[string]bool)
+	if contract, ok := bcomn.GetTxContract(txn); ok {
+		contracts[contract.ToBase58()] = true
+		contracts[contract.ToHexString()] = true
+	}
+	return payers, contracts
+}
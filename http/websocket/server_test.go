@@ -0,0 +1,152 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package websocket
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	gws "github.com/gorilla/websocket"
+	"github.com/ontio/ontology/common"
+	cfg "github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/events/message"
+	bcomn "github.com/ontio/ontology/http/base/common"
+	"github.com/ontio/ontology/http/base/rest"
+	"github.com/ontio/ontology/http/websocket/websocket"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	log.Init(log.PATH, log.Stdout)
+}
+
+//startTestServer start the websocket server on a free port and dial it
+func startTestServer(t *testing.T) (*gws.Conn, func()) {
+	var port int
+	for port == 0 || port%1000 == rest.TLS_PORT {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		port = l.Addr().(*net.TCPAddr).Port
+		l.Close()
+	}
+	oldPort := cfg.DefConfig.Ws.HttpWsPort
+	cfg.DefConfig.Ws.HttpWsPort = uint(port)
+	ws = websocket.InitWsServer()
+	go ws.Start()
+	var conn *gws.Conn
+	var err error
+	for i := 0; i < 50; i++ {
+		conn, _, err = gws.DefaultDialer.Dial(fmt.Sprintf("ws://127.0.0.1:%d", port), nil)
+		if err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return conn, func() {
+		conn.Close()
+		ws.Stop()
+		ws = nil
+		cfg.DefConfig.Ws.HttpWsPort = oldPort
+	}
+}
+
+func readPush(t *testing.T, conn *gws.Conn) map[string]interface{} {
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	resp := make(map[string]interface{})
+	assert.Nil(t, conn.ReadJSON(&resp))
+	return resp
+}
+
+func TestPushTx(t *testing.T) {
+	conn, closeFn := startTestServer(t)
+	defer closeFn()
+	startTxPushWorker()
+
+	assert.Nil(t, conn.WriteJSON(map[string]interface{}{"Action": "subscribe", "SubscribePendingTx": true,
+		"SubscribeTxStatus": true, "SubscribeFinalizedBlock": true}))
+	assert.Equal(t, "subscribe", readPush(t, conn)["Action"])
+
+	mutable, err := bcomn.NewNativeInvokeTransaction(0, 0, utils.OntContractAddress, 0, "name", []interface{}{})
+	assert.Nil(t, err)
+	tx, err := mutable.IntoImmutable()
+	assert.Nil(t, err)
+	hash := tx.Hash()
+	block := types.Block{Header: &types.Header{Height: 10}, Transactions: []*types.Transaction{tx}}
+
+	//pushed in the order of events
+	pushPendingTx(tx)
+	pushTxStatus(message.TxStatusMsg{Tx: tx, Status: message.TX_STATUS_EVICTED, Reason: "expired"})
+	sendBlock2WSclient(block)
+
+	resp := readPush(t, conn)
+	assert.Equal(t, "sendpendingtx", resp["Action"])
+	assert.Equal(t, bcomn.TransArryByteToHexString(tx).Hash, resp["Result"].(map[string]interface{})["Hash"])
+
+	//empty reason and height are omitted
+	statuses := []struct {
+		status string
+		reason interface{}
+		height interface{}
+	}{
+		{message.TX_STATUS_VERIFIED, nil, nil},
+		{message.TX_STATUS_EVICTED, "expired", nil},
+		{message.TX_STATUS_INCLUDED, nil, float64(10)},
+	}
+	for _, s := range statuses {
+		resp = readPush(t, conn)
+		assert.Equal(t, "sendtxstatus", resp["Action"])
+		info := resp["Result"].(map[string]interface{})
+		assert.Equal(t, hash.ToHexString(), info["TxHash"])
+		assert.Equal(t, s.status, info["Status"])
+		assert.Equal(t, s.reason, info["Reason"])
+		assert.Equal(t, s.height, info["Height"])
+		assert.Equal(t, common.ADDRESS_EMPTY.ToBase58(), info["Payer"])
+		assert.Equal(t, utils.OntContractAddress.ToHexString(), info["Contract"])
+	}
+	resp = readPush(t, conn)
+	assert.Equal(t, "sendfinalizedblock", resp["Action"])
+	assert.Equal(t, float64(10), resp["Result"].(map[string]interface{})["Height"])
+}
+
+func TestGetTxFilterAddrs(t *testing.T) {
+	mutable, err := bcomn.NewNativeInvokeTransaction(0, 0, utils.OntContractAddress, 0, "name", []interface{}{})
+	assert.Nil(t, err)
+	mutable.Payer = utils.OngContractAddress
+	tx, err := mutable.IntoImmutable()
+	assert.Nil(t, err)
+	payers, contracts := getTxFilterAddrs(tx)
+	assert.Equal(t, map[string]bool{
+		utils.OngContractAddress.ToBase58():    true,
+		utils.OngContractAddress.ToHexString(): true,
+	}, payers)
+	assert.Equal(t, map[string]bool{
+		utils.OntContractAddress.ToBase58():    true,
+		utils.OntContractAddress.ToHexString(): true,
+	}, contracts)
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/ontio/ontology/common"
 	cfg "github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/http/base/auth"
 	Err "github.com/ontio/ontology/http/base/error"
 	"github.com/ontio/ontology/http/base/rest"
 	"github.com/ontio/ontology/http/websocket/session"
@@ -43,6 +44,9 @@ const (
 	WSTOPIC_JSON_BLOCK = 2
 	WSTOPIC_RAW_BLOCK  = 3
 	WSTOPIC_TXHASHS    = 4
+	WSTOPIC_PENDING_TX = 5
+	WSTOPIC_TX_STATUS  = 6
+	WSTOPIC_FINALIZED  = 7
 )
 
 type handler func(map[string]interface{}) map[string]interface{}
@@ -53,11 +57,15 @@ type Handler struct {
 
 //subscribe event for client
 type subscribe struct {
-	ContractsFilter       []string `json:"ContractsFilter"`
-	SubscribeEvent        bool     `json:"SubscribeEvent"`
-	SubscribeJsonBlock    bool     `json:"SubscribeJsonBlock"`
-	SubscribeRawBlock     bool     `json:"SubscribeRawBlock"`
-	SubscribeBlockTxHashs bool     `json:"SubscribeBlockTxHashs"`
+	ContractsFilter         []string `json:"ContractsFilter"`
+	PayersFilter            []string `json:"PayersFilter"`
+	SubscribeEvent          bool     `json:"SubscribeEvent"`
+	SubscribeJsonBlock      bool     `json:"SubscribeJsonBlock"`
+	SubscribeRawBlock       bool     `json:"SubscribeRawBlock"`
+	SubscribeBlockTxHashs   bool     `json:"SubscribeBlockTxHashs"`
+	SubscribePendingTx      bool     `json:"SubscribePendingTx"`
+	SubscribeTxStatus       bool     `json:"SubscribeTxStatus"`
+	SubscribeFinalizedBlock bool     `json:"SubscribeFinalizedBlock"`
 }
 type WsServer struct {
 	sync.RWMutex
@@ -157,6 +165,15 @@ func (self *WsServer) registryMethod() {
 		if b, ok := cmd["SubscribeBlockTxHashs"].(bool); ok {
 			sub.SubscribeBlockTxHashs = b
 		}
+		if b, ok := cmd["SubscribePendingTx"].(bool); ok {
+			sub.SubscribePendingTx = b
+		}
+		if b, ok := cmd["SubscribeTxStatus"].(bool); ok {
+			sub.SubscribeTxStatus = b
+		}
+		if b, ok := cmd["SubscribeFinalizedBlock"].(bool); ok {
+			sub.SubscribeFinalizedBlock = b
+		}
 		if ctsf, ok := cmd["ContractsFilter"].([]interface{}); ok {
 			sub.ContractsFilter = []string{}
 			for _, v := range ctsf {
@@ -165,6 +182,14 @@ func (self *WsServer) registryMethod() {
 				}
 			}
 		}
+		if pf, ok := cmd["PayersFilter"].([]interface{}); ok {
+			sub.PayersFilter = []string{}
+			for _, v := range pf {
+				if addr, k := v.(string); k {
+					sub.PayersFilter = append(sub.PayersFilter, addr)
+				}
+			}
+		}
 		self.SubscribeMap[sessionId] = sub
 
 		resp["Action"] = "subscribe"
@@ -203,6 +228,9 @@ func (self *WsServer) registryMethod() {
 		"getmempooltxstate":         {handler: rest.GetMemPoolTxState},
 		"getversion":                {handler: rest.GetNodeVersion},
 		"getnetworkid":              {handler: rest.GetNetworkId},
+		"getevents":                 {handler: rest.GetEvents},
+		"getaddresstxs":             {handler: rest.GetAddressTxs},
+		"estimategas":               {handler: rest.EstimateGas},
 
 		"getsessioncount": {handler: getsessioncount},
 	}
@@ -251,6 +279,13 @@ func (self *WsServer) checkSessionsTimeout(done chan bool) {
 }
 
 func (self *WsServer) webSocketHandler(w http.ResponseWriter, r *http.Request) {
+	if !auth.Guard(w, r) {
+		return
+	}
+	if _, err := auth.Authenticate(r); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
 	wsConn, err := self.Upgrader.Upgrade(w, r, nil)
 
 	if err != nil {
@@ -258,6 +293,9 @@ func (self *WsServer) webSocketHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	defer wsConn.Close()
+	if maxBytes := cfg.DefConfig.Auth.MaxRequestBytes; maxBytes > 0 {
+		wsConn.SetReadLimit(int64(maxBytes))
+	}
 	nsSession, err := self.SessionList.NewSession(wsConn)
 	if err != nil {
 		log.Error("websocket NewSession:", err)
@@ -322,6 +360,27 @@ func (self *WsServer) OnDataHandle(curSession *session.Session, bysMsg []byte, r
 		curSession.Send(marshalResp(resp))
 		return false
 	}
+	if !auth.AllowRequest(r) {
+		resp := rest.ResponsePack(Err.SERVICE_CEILING)
+		resp["Action"] = actionName
+		curSession.Send(marshalResp(resp))
+		return false
+	}
+	//re-authenticate every message, the jwt may expire during session
+	granted, err := auth.Authenticate(r)
+	if err == nil {
+		err = auth.Authorize(granted, actionName)
+	}
+	if preExec, _ := req["PreExec"].(string); err == nil && actionName == "sendrawtransaction" && preExec == "1" {
+		err = auth.Authorize(granted, auth.PREEXEC_METHOD)
+	}
+	if err != nil {
+		resp := rest.ResponsePack(Err.UNAUTHORIZED)
+		resp["Action"] = actionName
+		resp["Result"] = err.Error()
+		curSession.Send(marshalResp(resp))
+		return false
+	}
 	if !self.IsValidMsg(req) {
 		resp := rest.ResponsePack(Err.INVALID_PARAMS)
 		curSession.Send(marshalResp(resp))
@@ -429,6 +488,8 @@ func (self *WsServer) BroadcastToSubscribers(contractAddrs map[string]bool, sub
 			s.Send(data)
 		} else if sub == WSTOPIC_TXHASHS && v.SubscribeBlockTxHashs {
 			s.Send(data)
+		} else if sub == WSTOPIC_FINALIZED && v.SubscribeFinalizedBlock {
+			s.Send(data)
 		} else if sub == WSTOPIC_EVENT && v.SubscribeEvent {
 			if len(v.ContractsFilter) == 0 {
 				s.Send(data)
@@ -444,6 +505,44 @@ func (self *WsServer) BroadcastToSubscribers(contractAddrs map[string]bool, sub
 	}
 }
 
+//BroadcastTxToSubscribers send tx to subscribers whose payer and contract filters match.
+//payers and contracts hold every accepted form of the address, eg. base58 and hex
+func (self *WsServer) BroadcastTxToSubscribers(payers map[string]bool, contracts map[string]bool,
+	sub int, resp map[string]interface{}) {
+	self.Lock()
+	defer self.Unlock()
+	data := marshalResp(resp)
+	for sid, v := range self.SubscribeMap {
+		if sub == WSTOPIC_PENDING_TX && !v.SubscribePendingTx {
+			continue
+		}
+		if sub == WSTOPIC_TX_STATUS && !v.SubscribeTxStatus {
+			continue
+		}
+		if !matchFilter(v.PayersFilter, payers) || !matchFilter(v.ContractsFilter, contracts) {
+			continue
+		}
+		s := self.SessionList.GetSessionById(sid)
+		if s == nil {
+			continue
+		}
+		s.Send(data)
+	}
+}
+
+//matchFilter return true if filter is empty or one of its item is in addrs
+func matchFilter(filter []string, addrs map[string]bool) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, addr := range filter {
+		if addrs[addr] {
+			return true
+		}
+	}
+	return false
+}
+
 func (self *WsServer) initTlsListen() (net.Listener, error) {
 
 	certPath := cfg.DefConfig.Ws.HttpCertPath
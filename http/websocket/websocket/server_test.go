@@ -0,0 +1,160 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package websocket
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	cfg "github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/genesis"
+	"github.com/ontio/ontology/core/ledger"
+	bcomn "github.com/ontio/ontology/http/base/common"
+	Err "github.com/ontio/ontology/http/base/error"
+	"github.com/ontio/ontology/http/base/rest"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	log.Init(log.PATH, log.Stdout)
+	dataDir, err := ioutil.TempDir("", "ontology-http-ws")
+	if err != nil {
+		return
+	}
+	ledger.DefLedger, err = ledger.NewLedger(dataDir, 0)
+	if err != nil {
+		return
+	}
+	bookKeepers, err := cfg.DefConfig.GetBookkeepers()
+	if err != nil {
+		return
+	}
+	genesisBlock, err := genesis.BuildGenesisBlock(bookKeepers, cfg.DefConfig.Genesis)
+	if err != nil {
+		return
+	}
+	ledger.DefLedger.Init(bookKeepers, genesisBlock)
+}
+
+//newTestServer start the websocket handler of a new server and dial it
+func newTestServer(t *testing.T) (*WsServer, *websocket.Conn, func()) {
+	ws := InitWsServer()
+	ws.registryMethod()
+	server := httptest.NewServer(http.HandlerFunc(ws.webSocketHandler))
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if !assert.Nil(t, err) {
+		server.Close()
+		t.FailNow()
+	}
+	return ws, conn, func() {
+		conn.Close()
+		server.Close()
+	}
+}
+
+func request(t *testing.T, conn *websocket.Conn, req map[string]interface{}) map[string]interface{} {
+	assert.Nil(t, conn.WriteJSON(req))
+	resp := make(map[string]interface{})
+	assert.Nil(t, conn.ReadJSON(&resp))
+	return resp
+}
+
+func TestEstimateGas(t *testing.T) {
+	_, conn, closeFn := newTestServer(t)
+	defer closeFn()
+
+	mutable, err := bcomn.NewNativeInvokeTransaction(0, 0, utils.OntContractAddress, 0, "name", []interface{}{})
+	assert.Nil(t, err)
+	tx, err := mutable.IntoImmutable()
+	assert.Nil(t, err)
+	expect, err := bcomn.EstimateGas(tx)
+	assert.Nil(t, err)
+
+	resp := request(t, conn, map[string]interface{}{"Action": "estimategas", "Id": "1", "Data": hex.EncodeToString(tx.ToArray())})
+	assert.Equal(t, "estimategas", resp["Action"])
+	assert.Equal(t, "1", resp["Id"])
+	assert.Equal(t, float64(Err.SUCCESS), resp["Error"])
+	assert.Equal(t, float64(expect), resp["Result"])
+
+	resp = request(t, conn, map[string]interface{}{"Action": "estimategas", "Data": "not hex"})
+	assert.Equal(t, float64(Err.INVALID_PARAMS), resp["Error"])
+	resp = request(t, conn, map[string]interface{}{"Action": "estimategas", "Data": "00"})
+	assert.Equal(t, float64(Err.INVALID_TRANSACTION), resp["Error"])
+}
+
+//readPush read the next push of conn, return nil if nothing pushed in a short time
+func readPush(t *testing.T, conn *websocket.Conn) map[string]interface{} {
+	conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	defer conn.SetReadDeadline(time.Time{})
+	resp := make(map[string]interface{})
+	if err := conn.ReadJSON(&resp); err != nil {
+		return nil
+	}
+	return resp
+}
+
+func TestBroadcastTxToSubscribers(t *testing.T) {
+	ws, conn, closeFn := newTestServer(t)
+	defer closeFn()
+
+	resp := request(t, conn, map[string]interface{}{"Action": "subscribe", "SubscribePendingTx": true,
+		"PayersFilter": []string{"payer1"}, "ContractsFilter": []string{"contract1", "contract2"}})
+	assert.Equal(t, float64(Err.SUCCESS), resp["Error"])
+
+	push := func(payer, contract string, sub int) map[string]interface{} {
+		resp := rest.ResponsePack(Err.SUCCESS)
+		resp["Action"] = "sendpendingtx"
+		resp["Result"] = payer + contract
+		ws.BroadcastTxToSubscribers(map[string]bool{payer: true}, map[string]bool{contract: true}, sub, resp)
+		return readPush(t, conn)
+	}
+	resp = push("payer1", "contract2", WSTOPIC_PENDING_TX)
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, "payer1contract2", resp["Result"])
+	}
+	assert.Nil(t, push("payer2", "contract1", WSTOPIC_PENDING_TX))
+	assert.Nil(t, push("payer1", "contract3", WSTOPIC_PENDING_TX))
+	//not subscribed
+	assert.Nil(t, push("payer1", "contract1", WSTOPIC_TX_STATUS))
+
+	//an empty filter matches all
+	resp = request(t, conn, map[string]interface{}{"Action": "subscribe", "SubscribeTxStatus": true,
+		"PayersFilter": []string{}, "ContractsFilter": []string{}})
+	assert.Equal(t, float64(Err.SUCCESS), resp["Error"])
+	resp = push("payer2", "contract3", WSTOPIC_TX_STATUS)
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, "payer2contract3", resp["Result"])
+	}
+}
+
+func TestMatchFilter(t *testing.T) {
+	addrs := map[string]bool{"a": true, "b": true}
+	assert.True(t, matchFilter(nil, addrs))
+	assert.True(t, matchFilter([]string{"c", "b"}, addrs))
+	assert.False(t, matchFilter([]string{"c"}, addrs))
+	assert.False(t, matchFilter([]string{"a"}, nil))
+}
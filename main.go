@@ -44,8 +44,10 @@ import (
 	"github.com/ontio/ontology/events"
 	bactor "github.com/ontio/ontology/http/base/actor"
 	hserver "github.com/ontio/ontology/http/base/actor"
+	"github.com/ontio/ontology/http/graphql"
 	"github.com/ontio/ontology/http/jsonrpc"
 	"github.com/ontio/ontology/http/localrpc"
+	"github.com/ontio/ontology/http/metrics"
 	"github.com/ontio/ontology/http/nodeinfo"
 	"github.com/ontio/ontology/http/restful"
 	"github.com/ontio/ontology/http/websocket"
@@ -68,12 +70,14 @@ func setupAPP() *cli.App {
 	app.Copyright = "Copyright in 2018 The Ontology Authors"
 	app.Commands = []cli.Command{
 		cmd.AccountCommand,
+		cmd.WalletCommand,
 		cmd.InfoCommand,
 		cmd.AssetCommand,
 		cmd.ContractCommand,
 		cmd.ImportCommand,
 		cmd.ExportCommand,
 		cmd.TxCommond,
+		cmd.TxToolCommand,
 		cmd.SigTxCommand,
 		cmd.MultiSigAddrCommand,
 		cmd.MultiSigTxCommand,
@@ -85,11 +89,19 @@ func setupAPP() *cli.App {
 		utils.ConfigFlag,
 		utils.LogLevelFlag,
 		utils.DisableEventLogFlag,
+		utils.EnableAddressIndexFlag,
+		utils.EnableContractAbiFlag,
 		utils.DataDirFlag,
 		//account setting
 		utils.WalletFileFlag,
 		utils.AccountAddressFlag,
 		utils.AccountPassFlag,
+		utils.SignerFlag,
+		utils.Pkcs11ModuleFlag,
+		utils.Pkcs11SlotFlag,
+		utils.Pkcs11LabelFlag,
+		utils.RemoteSignerUrlFlag,
+		utils.RemoteSignerTokenFileFlag,
 		//consensus setting
 		utils.EnableConsensusFlag,
 		utils.MaxTxInBlockFlag,
@@ -109,14 +121,18 @@ func setupAPP() *cli.App {
 		utils.MaxConnInBoundFlag,
 		utils.MaxConnOutBoundFlag,
 		utils.MaxConnInBoundForSingleIPFlag,
+		utils.P2PEncryptFlag,
+		utils.NodeKeyFileFlag,
 		//test mode setting
 		utils.EnableTestModeFlag,
 		utils.TestModeGenBlockTimeFlag,
+		utils.TestModeGenBlockOnDemandFlag,
 		//rpc setting
 		utils.RPCDisabledFlag,
 		utils.RPCPortFlag,
 		utils.RPCLocalEnableFlag,
 		utils.RPCLocalProtFlag,
+		utils.RPCLegacyResponseFlag,
 		//rest setting
 		utils.RestfulEnableFlag,
 		utils.RestfulPortFlag,
@@ -124,6 +140,19 @@ func setupAPP() *cli.App {
 		//ws setting
 		utils.WsEnabledFlag,
 		utils.WsPortFlag,
+		//api access setting
+		utils.ApiAuthConfigFlag,
+		utils.ApiRateLimitFlag,
+		utils.ApiRateBurstFlag,
+		utils.ApiMaxRequestBytesFlag,
+		//graphql setting
+		utils.GraphQLEnableFlag,
+		utils.GraphQLPortFlag,
+		utils.GraphQLMaxDepthFlag,
+		utils.GraphQLMaxCostFlag,
+		//metrics setting
+		utils.MetricsEnableFlag,
+		utils.MetricsPortFlag,
 	}
 	app.Before = func(context *cli.Context) error {
 		runtime.GOMAXPROCS(runtime.NumCPU())
@@ -188,7 +217,9 @@ func startOntology(ctx *cli.Context) {
 	}
 	initRestful(ctx)
 	initWs(ctx)
+	initGraphQL(ctx)
 	initNodeInfo(ctx, p2pSvr)
+	initMetrics(ctx, p2pSvr)
 
 	go logCurrBlockHeight()
 	waitToExit()
@@ -215,19 +246,26 @@ func initAccount(ctx *cli.Context) (*account.Account, error) {
 	if !config.DefConfig.Consensus.EnableConsensus {
 		return nil, nil
 	}
-	walletFile := ctx.GlobalString(utils.GetFlagName(utils.WalletFileFlag))
-	if walletFile == "" {
-		return nil, fmt.Errorf("Please config wallet file using --wallet flag")
-	}
-	if !common.FileExisted(walletFile) {
-		return nil, fmt.Errorf("Cannot find wallet file:%s. Please create wallet first", walletFile)
+	signerType := ctx.GlobalString(utils.GetFlagName(utils.SignerFlag))
+	if signerType == cmdcom.SIGNER_WALLET {
+		walletFile := ctx.GlobalString(utils.GetFlagName(utils.WalletFileFlag))
+		if walletFile == "" {
+			return nil, fmt.Errorf("Please config wallet file using --wallet flag")
+		}
+		if !common.FileExisted(walletFile) {
+			return nil, fmt.Errorf("Cannot find wallet file:%s. Please create wallet first", walletFile)
+		}
 	}
 
-	acc, err := cmdcom.GetAccount(ctx)
+	acc, err := cmdcom.GetSignerAccount(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("get account error:%s", err)
 	}
 	log.Infof("Using account:%s", acc.Address.ToBase58())
+	//vbft elects proposers by vrf, which pkcs11 signer cannot compute
+	if config.DefConfig.Genesis.ConsensusType == config.CONSENSUS_TYPE_VBFT && !acc.SupportVrf() {
+		return nil, fmt.Errorf("signer:%s does not support vrf required by vbft consensus", signerType)
+	}
 
 	if config.DefConfig.Genesis.ConsensusType == config.CONSENSUS_TYPE_SOLO {
 		curPk := hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey))
@@ -398,6 +436,20 @@ func initWs(ctx *cli.Context) {
 	log.Infof("Ws init success")
 }
 
+func initGraphQL(ctx *cli.Context) {
+	if !config.DefConfig.GraphQL.EnableGraphQL {
+		return
+	}
+	go func() {
+		err := graphql.StartServer()
+		if err != nil {
+			log.Errorf("GraphQL server error:%s", err)
+		}
+	}()
+
+	log.Infof("GraphQL init success")
+}
+
 func initNodeInfo(ctx *cli.Context, p2pSvr *p2pserver.P2PServer) {
 	if config.DefConfig.P2PNode.HttpInfoPort == 0 {
 		return
@@ -407,6 +459,20 @@ func initNodeInfo(ctx *cli.Context, p2pSvr *p2pserver.P2PServer) {
 	log.Infof("Nodeinfo init success")
 }
 
+func initMetrics(ctx *cli.Context, p2pSvr *p2pserver.P2PServer) {
+	if !config.DefConfig.Metrics.EnableMetrics {
+		return
+	}
+	go func() {
+		err := metrics.StartServer(p2pSvr)
+		if err != nil {
+			log.Errorf("Metrics server error:%s", err)
+		}
+	}()
+
+	log.Infof("Metrics init success")
+}
+
 func logCurrBlockHeight() {
 	ticker := time.NewTicker(config.DEFAULT_GEN_BLOCK_TIME * time.Second)
 	for {
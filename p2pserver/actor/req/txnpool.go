@@ -66,3 +66,23 @@ func GetTransaction(hash common.Uint256) (*types.Transaction, error) {
 	}
 	return result.(tc.GetTxnRsp).Txn, nil
 }
+
+//get txns of compact block in txnpool by short ids, including pending ones. Missing txns are nil
+func GetTransactionsByShortID(nonce uint64, blkHash common.Uint256, shortIDs []uint64) ([]*types.Transaction, error) {
+	if txnPoolPid == nil {
+		log.Warn("[p2p]net_server tx pool pid is nil")
+		return nil, errors.NewErr("[p2p]net_server tx pool pid is nil")
+	}
+	req := &tc.GetTxnsByShortIDReq{Nonce: nonce, BlockHash: blkHash, ShortIDs: shortIDs}
+	future := txnPoolPid.RequestFuture(req, txnPoolReqTimeout)
+	result, err := future.Result()
+	if err != nil {
+		log.Warnf("[p2p]net_server GetTransactionsByShortID error: %v\n", err)
+		return nil, err
+	}
+	rsp, ok := result.(*tc.GetTxnsByShortIDRsp)
+	if !ok || len(rsp.Txs) != len(shortIDs) {
+		return nil, errors.NewErr("[p2p]net_server GetTransactionsByShortID unexpected response")
+	}
+	return rsp.Txs, nil
+}
@@ -86,6 +86,14 @@ func (this *P2PActor) Receive(ctx actor.Context) {
 		this.handleGetNodeTypeReq(ctx, msg)
 	case *TransmitConsensusMsgReq:
 		this.handleTransmitConsensusMsgReq(ctx, msg)
+	case *GetPeerInfosReq:
+		this.handleGetPeerInfosReq(ctx, msg)
+	case *GetBanListReq:
+		this.handleGetBanListReq(ctx, msg)
+	case *BanPeerReq:
+		this.handleBanPeerReq(ctx, msg)
+	case *UnbanPeerReq:
+		this.handleUnbanPeerReq(ctx, msg)
 	case *common.AppendPeerID:
 		this.server.OnAddNode(msg.ID)
 	case *common.RemovePeerID:
@@ -243,3 +251,62 @@ func (this *P2PActor) handleTransmitConsensusMsgReq(ctx actor.Context, req *Tran
 		log.Warnf("[p2p]can`t transmit consensus msg:no valid neighbor peer: %d\n", req.Target)
 	}
 }
+
+//nbr peer`s info handler
+func (this *P2PActor) handleGetPeerInfosReq(ctx actor.Context, req *GetPeerInfosReq) {
+	network := this.server.GetNetWork()
+	reputation := network.GetReputation()
+	neighbors := network.GetNeighbors()
+	peers := make([]*PeerInfo, 0, len(neighbors))
+	for _, p := range neighbors {
+		peers = append(peers, &PeerInfo{
+			Id:     p.GetID(),
+			Addr:   p.GetAddr(),
+			Height: p.GetHeight(),
+			Score:  reputation.GetScore(p.GetID()),
+		})
+	}
+	if ctx.Sender() != nil {
+		resp := &GetPeerInfosRsp{
+			Peers: peers,
+		}
+		ctx.Sender().Request(resp, ctx.Self())
+	}
+}
+
+//ban list handler
+func (this *P2PActor) handleGetBanListReq(ctx actor.Context, req *GetBanListReq) {
+	list := this.server.GetNetWork().GetReputation().GetBanList()
+	if ctx.Sender() != nil {
+		resp := &GetBanListRsp{
+			List: list,
+		}
+		ctx.Sender().Request(resp, ctx.Self())
+	}
+}
+
+//ban peer handler
+func (this *P2PActor) handleBanPeerReq(ctx actor.Context, req *BanPeerReq) {
+	this.server.GetNetWork().BanPeer(req.Id, req.Ip, req.Duration, req.Reason)
+	if ctx.Sender() != nil {
+		ctx.Sender().Request(&BanPeerRsp{}, ctx.Self())
+	}
+}
+
+//unban peer handler
+func (this *P2PActor) handleUnbanPeerReq(ctx actor.Context, req *UnbanPeerReq) {
+	reputation := this.server.GetNetWork().GetReputation()
+	unbanned := false
+	if req.Id != 0 && reputation.UnbanID(req.Id) {
+		unbanned = true
+	}
+	if req.Ip != "" && reputation.UnbanIP(req.Ip) {
+		unbanned = true
+	}
+	if ctx.Sender() != nil {
+		resp := &UnbanPeerRsp{
+			Unbanned: unbanned,
+		}
+		ctx.Sender().Request(resp, ctx.Self())
+	}
+}
@@ -19,8 +19,11 @@
 package server
 
 import (
+	"time"
+
 	types "github.com/ontio/ontology/p2pserver/common"
 	ptypes "github.com/ontio/ontology/p2pserver/message/types"
+	"github.com/ontio/ontology/p2pserver/peer"
 )
 
 //stop net server
@@ -132,3 +135,52 @@ type TransmitConsensusMsgReq struct {
 	Target uint64
 	Msg    ptypes.Message
 }
+
+//neighbor peer info with penalty score
+type PeerInfo struct {
+	Id     uint64
+	Addr   string
+	Height uint64
+	Score  uint32
+}
+
+//get all nbr`s info request
+type GetPeerInfosReq struct {
+}
+
+//response of all nbr`s info
+type GetPeerInfosRsp struct {
+	Peers []*PeerInfo
+}
+
+//get ban list request
+type GetBanListReq struct {
+}
+
+//response of ban list
+type GetBanListRsp struct {
+	List []*peer.BanEntry
+}
+
+//ban peer id or ip request, zero duration means default ban duration
+type BanPeerReq struct {
+	Id       uint64
+	Ip       string
+	Duration time.Duration
+	Reason   string
+}
+
+//response of ban peer
+type BanPeerRsp struct {
+}
+
+//unban peer id or ip request
+type UnbanPeerReq struct {
+	Id uint64
+	Ip string
+}
+
+//response of unban peer, false if neither id nor ip was banned
+type UnbanPeerRsp struct {
+	Unbanned bool
+}
@@ -235,6 +235,14 @@ type BlockSyncMgr struct {
 	nodeWeights    map[uint64]*NodeWeight               //Map NodeID => NodeStatus, using for getNextNode
 }
 
+//SyncState is the state of block sync
+type SyncState struct {
+	FlightHeaders int  //Count of header requests in flight
+	FlightBlocks  int  //Count of block requests in flight
+	CachedBlocks  int  //Count of received blocks waiting for saving
+	Synced        bool //Whether the local height reach all the neighbors
+}
+
 //NewBlockSyncMgr return a BlockSyncMgr instance
 func NewBlockSyncMgr(server *P2PServer) *BlockSyncMgr {
 	return &BlockSyncMgr{
@@ -472,6 +480,7 @@ func (this *BlockSyncMgr) OnHeaderReceive(fromID uint64, headers []*types.Header
 	this.delFlightHeader(height)
 	if err != nil {
 		this.addErrorRespCnt(fromID)
+		this.server.network.Penalize(fromID, "", p2pComm.OFFENCE_INVALID_HEADER)
 		n := this.getNodeWeight(fromID)
 		if n != nil && n.GetErrorRespCnt() >= SYNC_MAX_ERROR_RESP_TIMES {
 			this.delNode(fromID)
@@ -639,6 +648,7 @@ func (this *BlockSyncMgr) saveBlock() {
 		this.delBlockCache(nextBlockHeight)
 		if err != nil {
 			this.addErrorRespCnt(fromID)
+			this.server.network.Penalize(fromID, "", p2pComm.OFFENCE_INVALID_BLOCK)
 			n := this.getNodeWeight(fromID)
 			if n != nil && n.GetErrorRespCnt() >= SYNC_MAX_ERROR_RESP_TIMES {
 				this.delNode(fromID)
@@ -710,6 +720,15 @@ func (this *BlockSyncMgr) getFlightHeaderCount() int {
 	return len(this.flightHeaders)
 }
 
+//GetSyncState return the current flights and cache of block sync
+func (this *BlockSyncMgr) GetSyncState() *SyncState {
+	return &SyncState{
+		FlightHeaders: this.getFlightHeaderCount(),
+		FlightBlocks:  this.getFlightBlockCount(),
+		CachedBlocks:  this.getBlockCacheSize(),
+	}
+}
+
 func (this *BlockSyncMgr) isHeaderOnFlight(height uint32) bool {
 	flightInfo := this.getFlightHeader(height)
 	return flightInfo != nil
@@ -0,0 +1,45 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2pserver
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockSyncState(t *testing.T) {
+	syncMgr := NewBlockSyncMgr(&P2PServer{})
+	state := syncMgr.GetSyncState()
+	assert.Equal(t, 0, state.FlightHeaders)
+	assert.Equal(t, 0, state.FlightBlocks)
+	assert.Equal(t, 0, state.CachedBlocks)
+
+	syncMgr.addFlightHeader(1, 10)
+	syncMgr.addFlightHeader(1, 11)
+	syncMgr.addFlightBlock(1, 10, common.Uint256{1})
+	syncMgr.addFlightBlock(2, 10, common.Uint256{1})
+	syncMgr.addBlockCache(1, &types.Block{Header: &types.Header{Height: 10}}, common.Uint256{})
+	state = syncMgr.GetSyncState()
+	assert.Equal(t, 2, state.FlightHeaders)
+	assert.Equal(t, 2, state.FlightBlocks)
+	assert.Equal(t, 1, state.CachedBlocks)
+}
@@ -20,6 +20,7 @@ package common
 
 import (
 	"errors"
+	"net"
 	"strconv"
 	"strings"
 
@@ -43,6 +44,18 @@ const (
 	MAX_RESP_CACHE_SIZE = 50         //the maximum response cache
 )
 
+//compact block const
+const (
+	MAX_CMPCT_BLK_CACHE = 16    //the maximum compact blocks waiting for missing txs
+	MAX_BLK_TXN_CNT     = 60000 //the maximum tx cnt of compact blk and blktxn msg
+)
+
+//encrypted link const
+const (
+	HANDSHAKE_TIMEOUT    = 10        //encrypted link handshake timeout in sec
+	MAX_SECURE_FRAME_LEN = 64 * 1024 //the maximum plaintext length of an encrypted frame
+)
+
 //msg cmd const
 const (
 	MSG_CMD_LEN      = 12               //msg type length in byte
@@ -87,7 +100,8 @@ const (
 
 //cap flag
 const (
-	HTTP_INFO_FLAG = 0 //peer`s http info bit in cap field
+	HTTP_INFO_FLAG     = 0 //peer`s http info bit in cap field
+	COMPACT_BLOCK_FLAG = 1 //peer`s compact block support bit in cap field
 )
 
 //actor const
@@ -102,6 +116,54 @@ const (
 	RECENT_LIMIT     = 10 //recent contact list limit
 )
 
+//peer reputation const
+const (
+	BAN_SCORE          = 100          //penalty score to ban a peer
+	BAN_DURATION       = 24 * 60 * 60 //default ban duration in sec
+	SCORE_DECAY_PERIOD = 60           //one penalty point is forgiven every period in sec
+	MAX_MSG_PER_SECOND = 2000         //the maximum message rate of a link
+	BAN_FILE_NAME      = "peers.ban"
+)
+
+//Offence is the misbehaviour of a remote peer
+type Offence uint8
+
+const (
+	OFFENCE_MALFORMED_MSG     Offence = iota + 1 //message with bad magic, checksum or payload
+	OFFENCE_MSG_FLOOD                            //message rate exceed the limit
+	OFFENCE_INVALID_HEADER                       //header rejected by ledger
+	OFFENCE_INVALID_BLOCK                        //block rejected by ledger
+	OFFENCE_INVALID_CONSENSUS                    //consensus payload with bad signature
+)
+
+var offencePenalty = map[Offence]uint32{
+	OFFENCE_MALFORMED_MSG:     BAN_SCORE / 2,
+	OFFENCE_MSG_FLOOD:         BAN_SCORE / 10,
+	OFFENCE_INVALID_HEADER:    BAN_SCORE / 5,
+	OFFENCE_INVALID_BLOCK:     BAN_SCORE / 5,
+	OFFENCE_INVALID_CONSENSUS: BAN_SCORE / 10,
+}
+
+var offenceName = map[Offence]string{
+	OFFENCE_MALFORMED_MSG:     "malformed message",
+	OFFENCE_MSG_FLOOD:         "message flood",
+	OFFENCE_INVALID_HEADER:    "invalid header",
+	OFFENCE_INVALID_BLOCK:     "invalid block",
+	OFFENCE_INVALID_CONSENSUS: "invalid consensus message",
+}
+
+//Penalty return the penalty score of the offence
+func (this Offence) Penalty() uint32 {
+	return offencePenalty[this]
+}
+
+func (this Offence) String() string {
+	if name, ok := offenceName[this]; ok {
+		return name
+	}
+	return "unknown offence"
+}
+
 //PeerAddr represent peer`s net information
 type PeerAddr struct {
 	Time          int64    //latest timestamp
@@ -130,6 +192,9 @@ const (
 	GET_BLOCKS_TYPE  = "getblocks"  //req blks from peer
 	NOT_FOUND_TYPE   = "notfound"   //peer can`t find blk according to the hash
 	DISCONNECT_TYPE  = "disconnect" //peer disconnect info raise by link
+	CMPCT_BLOCK_TYPE = "cmpctblock" //blk hdr with short tx ids
+	GET_BLK_TXN_TYPE = "getblktxn"  //req txs missing from compact blk
+	BLK_TXN_TYPE     = "blktxn"     //txs missing from compact blk
 )
 
 type AppendPeerID struct {
@@ -161,6 +226,31 @@ func ParseIPAddr(s string) (string, error) {
 	return s[:i], nil
 }
 
+//ParseHostIP return the ip of host:port address, ipv6 address like [::1]:20338 is supported
+func ParseHostIP(addr string) (string, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), nil
+	}
+	return host, nil
+}
+
+//NormalizeIP return the canonical form of ip, so that ip written in different forms, with port or with ipv6
+//brackets match. Ipv4-mapped ipv6 address is returned as ipv4
+func NormalizeIP(ip string) string {
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	ip = strings.TrimSuffix(strings.TrimPrefix(ip, "["), "]")
+	if parsed := net.ParseIP(ip); parsed != nil {
+		return parsed.String()
+	}
+	return ip
+}
+
 //ParseIPPort return ip port
 func ParseIPPort(s string) (string, error) {
 	i := strings.Index(s, ":")
@@ -22,6 +22,7 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"time"
 
@@ -40,8 +41,14 @@ type Link struct {
 	time      time.Time              // The latest time the node activity
 	recvChan  chan *types.MsgPayload //msgpayload channel
 	reqRecord map[string]int64       //Map RequestId to Timestamp, using for rejecting duplicate request in specific time
+	onOffence OffenceHandler         //report misbehaviour of the remote peer
+	rxCount   uint32                 //message count in current second
+	rxSecond  int64                  //the second rxCount belongs to
 }
 
+//OffenceHandler is called when the remote peer misbehaves on the link
+type OffenceHandler func(id uint64, addr string, offence common.Offence)
+
 func NewLink() *Link {
 	link := &Link{
 		reqRecord: make(map[string]int64, 0),
@@ -69,6 +76,11 @@ func (this *Link) SetChan(msgchan chan *types.MsgPayload) {
 	this.recvChan = msgchan
 }
 
+//set offence handler of link
+func (this *Link) SetOffenceHandler(handler OffenceHandler) {
+	this.onOffence = handler
+}
+
 //get address
 func (this *Link) GetAddr() string {
 	return this.addr
@@ -99,6 +111,16 @@ func (this *Link) SetConn(conn net.Conn) {
 	this.conn = conn
 }
 
+//GetNodeID return the peer id authenticated by encrypted handshake, false
+//if the link is not encrypted
+func (this *Link) GetNodeID() (uint64, bool) {
+	sc, ok := this.conn.(*SecureConn)
+	if !ok {
+		return 0, false
+	}
+	return sc.RemoteID(), true
+}
+
 //record latest message time
 func (this *Link) UpdateRXTime(t time.Time) {
 	this.time = t
@@ -121,12 +143,21 @@ func (this *Link) Rx() {
 		msg, payloadSize, err := types.ReadMessage(reader)
 		if err != nil {
 			log.Infof("[p2p]error read from %s :%s", this.GetAddr(), err.Error())
+			if !isNetworkError(err) {
+				this.reportOffence(common.OFFENCE_MALFORMED_MSG)
+			}
 			break
 		}
 
 		t := time.Now()
 		this.UpdateRXTime(t)
 
+		if this.exceedRxRate(t) {
+			log.Warnf("[p2p]message rate of %s exceed limit", this.GetAddr())
+			this.reportOffence(common.OFFENCE_MSG_FLOOD)
+			break
+		}
+
 		if !this.needSendMsg(msg) {
 			log.Debugf("skip handle msgType:%s from:%d", msg.CmdType(), this.id)
 			continue
@@ -144,6 +175,32 @@ func (this *Link) Rx() {
 	this.disconnectNotify()
 }
 
+//exceedRxRate count received message, return true if the rate exceed limit
+func (this *Link) exceedRxRate(t time.Time) bool {
+	sec := t.Unix()
+	if sec != this.rxSecond {
+		this.rxSecond = sec
+		this.rxCount = 0
+	}
+	this.rxCount++
+	return this.rxCount > common.MAX_MSG_PER_SECOND
+}
+
+func (this *Link) reportOffence(offence common.Offence) {
+	if this.onOffence != nil {
+		this.onOffence(this.id, this.addr, offence)
+	}
+}
+
+//isNetworkError return true if err is caused by connection rather than message content
+func isNetworkError(err error) bool {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return true
+	}
+	_, ok := err.(net.Error)
+	return ok
+}
+
 //disconnectNotify push disconnect msg to channel
 func (this *Link) disconnectNotify() {
 	log.Debugf("[p2p]call disconnectNotify for %s", this.GetAddr())
@@ -0,0 +1,394 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package link
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ontio/ontology/p2pserver/common"
+)
+
+//The encrypted link handshake. Both side exchange a hello with their static
+//node key and an ephemeral key, derive the session keys from the ephemeral
+//ECDH secret bound to the hello transcript, then prove the ownership of the
+//static key by a signature on the transcript sent over the encrypted channel.
+//
+//  initiator -> responder: hello(static_i, ephemeral_i)
+//  responder -> initiator: hello(static_r, ephemeral_r)
+//  initiator -> responder: enc(sign_i(transcript))
+//  responder -> initiator: enc(sign_r(transcript))
+const (
+	handshakeVersion = 1
+	keyLen           = 65 //uncompressed P-256 point
+	sigLen           = 64
+	helloLen         = 4 + 1 + keyLen + keyLen
+	frameHeaderLen   = 4
+	sessionInfo      = "ontology p2p session"
+	authInfo         = "ontology p2p auth"
+	roleInitiator    = byte(0)
+	roleResponder    = byte(1)
+)
+
+var handshakeMagic = [4]byte{'O', 'N', 'T', 'S'}
+
+var curve = elliptic.P256()
+
+//NodeKey is the static key identifying a node on encrypted link
+type NodeKey struct {
+	priv *ecdsa.PrivateKey
+}
+
+//GenerateNodeKey return a new random node key
+func GenerateNodeKey() (*NodeKey, error) {
+	priv, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &NodeKey{priv: priv}, nil
+}
+
+//LoadNodeKey read node key from file, a new key is generated and saved if
+//the file not exist
+func LoadNodeKey(path string) (*NodeKey, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		key, err := GenerateNodeKey()
+		if err != nil {
+			return nil, fmt.Errorf("generate node key error:%s", err)
+		}
+		err = ioutil.WriteFile(path, []byte(hex.EncodeToString(key.priv.D.Bytes())), 0600)
+		if err != nil {
+			return nil, fmt.Errorf("save node key error:%s", err)
+		}
+		return key, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read node key error:%s", err)
+	}
+	d, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("decode node key error:%s", err)
+	}
+	return newNodeKey(d)
+}
+
+func newNodeKey(d []byte) (*NodeKey, error) {
+	k := new(big.Int).SetBytes(d)
+	if k.Sign() == 0 || k.Cmp(curve.Params().N) >= 0 {
+		return nil, errors.New("invalid node key")
+	}
+	priv := &ecdsa.PrivateKey{D: k}
+	priv.PublicKey.Curve = curve
+	priv.PublicKey.X, priv.PublicKey.Y = curve.ScalarBaseMult(d)
+	return &NodeKey{priv: priv}, nil
+}
+
+//PublicKey return the serialized public key
+func (this *NodeKey) PublicKey() []byte {
+	return elliptic.Marshal(curve, this.priv.X, this.priv.Y)
+}
+
+//ID return the peer id bound to the node key
+func (this *NodeKey) ID() uint64 {
+	return NodeID(this.PublicKey())
+}
+
+func (this *NodeKey) sign(data []byte) ([]byte, error) {
+	hash := sha256.Sum256(data)
+	r, s, err := ecdsa.Sign(rand.Reader, this.priv, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	sig := make([]byte, sigLen)
+	rb, sb := r.Bytes(), s.Bytes()
+	copy(sig[sigLen/2-len(rb):sigLen/2], rb)
+	copy(sig[sigLen-len(sb):], sb)
+	return sig, nil
+}
+
+//NodeID return the peer id bound to the serialized public key
+func NodeID(pubKey []byte) uint64 {
+	hash := sha256.Sum256(pubKey)
+	return binary.LittleEndian.Uint64(hash[:8])
+}
+
+func verifySig(pubKey []byte, data, sig []byte) bool {
+	x, y := elliptic.Unmarshal(curve, pubKey)
+	if x == nil || len(sig) != sigLen {
+		return false
+	}
+	hash := sha256.Sum256(data)
+	r := new(big.Int).SetBytes(sig[:sigLen/2])
+	s := new(big.Int).SetBytes(sig[sigLen/2:])
+	return ecdsa.Verify(&ecdsa.PublicKey{Curve: curve, X: x, Y: y}, hash[:], r, s)
+}
+
+//SecureConn is an encrypted and authenticated connection
+type SecureConn struct {
+	net.Conn
+	remoteKey  []byte
+	sendCipher cipher.AEAD
+	recvCipher cipher.AEAD
+	sendNonce  uint64
+	recvNonce  uint64
+	readBuf    []byte
+	readLock   sync.Mutex
+	writeLock  sync.Mutex
+}
+
+//Handshake run the encrypted link handshake on conn, initiator is the side
+//which dials the connection
+func Handshake(conn net.Conn, key *NodeKey, initiator bool) (*SecureConn, error) {
+	conn.SetDeadline(time.Now().Add(common.HANDSHAKE_TIMEOUT * time.Second))
+	defer conn.SetDeadline(time.Time{})
+
+	ephemeral, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	hello := makeHello(key.PublicKey(), elliptic.Marshal(curve, ephemeral.X, ephemeral.Y))
+
+	var remoteHello []byte
+	if initiator {
+		if _, err = conn.Write(hello); err != nil {
+			return nil, err
+		}
+		if remoteHello, err = readHello(conn); err != nil {
+			return nil, err
+		}
+	} else {
+		if remoteHello, err = readHello(conn); err != nil {
+			return nil, err
+		}
+		if _, err = conn.Write(hello); err != nil {
+			return nil, err
+		}
+	}
+	remoteStatic := remoteHello[5 : 5+keyLen]
+	remoteEphemeral := remoteHello[5+keyLen:]
+	if bytes.Equal(remoteStatic, key.PublicKey()) {
+		return nil, errors.New("handshake with itself")
+	}
+	x, y := elliptic.Unmarshal(curve, remoteEphemeral)
+	if x == nil {
+		return nil, errors.New("invalid ephemeral key")
+	}
+	shared, _ := curve.ScalarMult(x, y, ephemeral.D.Bytes())
+	secret := make([]byte, 32)
+	sharedBytes := shared.Bytes()
+	copy(secret[len(secret)-len(sharedBytes):], sharedBytes)
+
+	var transcript [sha256.Size]byte
+	if initiator {
+		transcript = sha256.Sum256(append(hello, remoteHello...))
+	} else {
+		transcript = sha256.Sum256(append(remoteHello, hello...))
+	}
+	keys := hkdf(secret, transcript[:], []byte(sessionInfo), 64)
+	initKey, respKey := keys[:32], keys[32:]
+
+	sc := &SecureConn{Conn: conn, remoteKey: append([]byte{}, remoteStatic...)}
+	localRole, remoteRole := roleInitiator, roleResponder
+	if initiator {
+		err = sc.setCiphers(initKey, respKey)
+	} else {
+		err = sc.setCiphers(respKey, initKey)
+		localRole, remoteRole = roleResponder, roleInitiator
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := key.sign(authData(localRole, transcript[:]))
+	if err != nil {
+		return nil, err
+	}
+	if initiator {
+		if _, err = sc.Write(sig); err != nil {
+			return nil, err
+		}
+	}
+	remoteSig := make([]byte, sigLen)
+	if _, err = io.ReadFull(sc, remoteSig); err != nil {
+		return nil, err
+	}
+	if !verifySig(remoteStatic, authData(remoteRole, transcript[:]), remoteSig) {
+		return nil, errors.New("node key authentication failed")
+	}
+	if !initiator {
+		if _, err = sc.Write(sig); err != nil {
+			return nil, err
+		}
+	}
+	return sc, nil
+}
+
+func makeHello(static, ephemeral []byte) []byte {
+	hello := make([]byte, 0, helloLen)
+	hello = append(hello, handshakeMagic[:]...)
+	hello = append(hello, handshakeVersion)
+	hello = append(hello, static...)
+	return append(hello, ephemeral...)
+}
+
+func readHello(conn net.Conn) ([]byte, error) {
+	hello := make([]byte, helloLen)
+	if _, err := io.ReadFull(conn, hello); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(hello[:4], handshakeMagic[:]) {
+		return nil, errors.New("invalid handshake magic")
+	}
+	if hello[4] != handshakeVersion {
+		return nil, fmt.Errorf("unsupported handshake version %d", hello[4])
+	}
+	if x, _ := elliptic.Unmarshal(curve, hello[5:5+keyLen]); x == nil {
+		return nil, errors.New("invalid node key")
+	}
+	return hello, nil
+}
+
+func authData(role byte, transcript []byte) []byte {
+	data := append([]byte(authInfo), role)
+	return append(data, transcript...)
+}
+
+//hkdf derive length bytes from secret by HKDF-SHA256
+func hkdf(secret, salt, info []byte, length int) []byte {
+	extractor := hmac.New(sha256.New, salt)
+	extractor.Write(secret)
+	prk := extractor.Sum(nil)
+
+	var out, prev []byte
+	for i := byte(1); len(out) < length; i++ {
+		expander := hmac.New(sha256.New, prk)
+		expander.Write(prev)
+		expander.Write(info)
+		expander.Write([]byte{i})
+		prev = expander.Sum(nil)
+		out = append(out, prev...)
+	}
+	return out[:length]
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (this *SecureConn) setCiphers(sendKey, recvKey []byte) error {
+	var err error
+	if this.sendCipher, err = newAEAD(sendKey); err != nil {
+		return err
+	}
+	this.recvCipher, err = newAEAD(recvKey)
+	return err
+}
+
+func makeNonce(aead cipher.AEAD, counter uint64) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], counter)
+	return nonce
+}
+
+//RemotePublicKey return the authenticated node key of remote peer
+func (this *SecureConn) RemotePublicKey() []byte {
+	return this.remoteKey
+}
+
+//RemoteID return the peer id bound to the node key of remote peer
+func (this *SecureConn) RemoteID() uint64 {
+	return NodeID(this.remoteKey)
+}
+
+//Write encrypt b and send it in frames
+func (this *SecureConn) Write(b []byte) (int, error) {
+	this.writeLock.Lock()
+	defer this.writeLock.Unlock()
+
+	written := 0
+	for written < len(b) {
+		end := written + common.MAX_SECURE_FRAME_LEN
+		if end > len(b) {
+			end = len(b)
+		}
+		sealed := this.sendCipher.Seal(nil, makeNonce(this.sendCipher, this.sendNonce), b[written:end], nil)
+		this.sendNonce++
+		frame := make([]byte, frameHeaderLen, frameHeaderLen+len(sealed))
+		binary.BigEndian.PutUint32(frame, uint32(len(sealed)))
+		frame = append(frame, sealed...)
+		if _, err := this.Conn.Write(frame); err != nil {
+			return written, err
+		}
+		written = end
+	}
+	return written, nil
+}
+
+//Read receive and decrypt frames into b
+func (this *SecureConn) Read(b []byte) (int, error) {
+	this.readLock.Lock()
+	defer this.readLock.Unlock()
+
+	if len(this.readBuf) == 0 {
+		var header [frameHeaderLen]byte
+		if _, err := io.ReadFull(this.Conn, header[:]); err != nil {
+			return 0, err
+		}
+		length := binary.BigEndian.Uint32(header[:])
+		if length > uint32(common.MAX_SECURE_FRAME_LEN+this.recvCipher.Overhead()) {
+			return 0, fmt.Errorf("encrypted frame length %d exceed limit", length)
+		}
+		sealed := make([]byte, length)
+		if _, err := io.ReadFull(this.Conn, sealed); err != nil {
+			return 0, err
+		}
+		plain, err := this.recvCipher.Open(sealed[:0], makeNonce(this.recvCipher, this.recvNonce), sealed, nil)
+		if err != nil {
+			return 0, errors.New("decrypt frame failed")
+		}
+		this.recvNonce++
+		this.readBuf = plain
+	}
+	n := copy(b, this.readBuf)
+	this.readBuf = this.readBuf[n:]
+	return n, nil
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package link
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ontio/ontology/p2pserver/common"
+)
+
+func secureConnPair(t *testing.T, cliKey, serKey *NodeKey) (*SecureConn, *SecureConn) {
+	cliConn, serConn := net.Pipe()
+	serChan := make(chan *SecureConn, 1)
+	go func() {
+		sc, err := Handshake(serConn, serKey, false)
+		if err != nil {
+			t.Error(err)
+		}
+		serChan <- sc
+	}()
+	cli, err := Handshake(cliConn, cliKey, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ser := <-serChan
+	if ser == nil {
+		t.FailNow()
+	}
+	return cli, ser
+}
+
+func TestSecureHandshake(t *testing.T) {
+	cliKey, _ := GenerateNodeKey()
+	serKey, _ := GenerateNodeKey()
+	cli, ser := secureConnPair(t, cliKey, serKey)
+	defer cli.Close()
+	defer ser.Close()
+
+	if cli.RemoteID() != serKey.ID() || ser.RemoteID() != cliKey.ID() {
+		t.Fatal("remote id not bound to node key")
+	}
+	if !bytes.Equal(cli.RemotePublicKey(), serKey.PublicKey()) {
+		t.Fatal("unexpected remote public key")
+	}
+
+	msg := bytes.Repeat([]byte{0x5a}, 3*common.MAX_SECURE_FRAME_LEN+7)
+	go cli.Write(msg)
+	buf := make([]byte, len(msg))
+	if _, err := io.ReadFull(ser, buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, msg) {
+		t.Fatal("message mismatch")
+	}
+
+	go ser.Write([]byte("pong"))
+	buf = make([]byte, 4)
+	if _, err := io.ReadFull(cli, buf); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "pong" {
+		t.Fatal("message mismatch")
+	}
+}
+
+func TestSecureHandshakeWithItself(t *testing.T) {
+	key, _ := GenerateNodeKey()
+	cliConn, serConn := net.Pipe()
+	defer cliConn.Close()
+	go func() {
+		Handshake(serConn, key, false)
+		serConn.Close()
+	}()
+	if _, err := Handshake(cliConn, key, true); err == nil {
+		t.Fatal("handshake with itself should fail")
+	}
+}
+
+func TestSecureTamper(t *testing.T) {
+	cliKey, _ := GenerateNodeKey()
+	serKey, _ := GenerateNodeKey()
+	cliConn, serConn := net.Pipe()
+	serChan := make(chan *SecureConn, 1)
+	go func() {
+		sc, _ := Handshake(serConn, serKey, false)
+		serChan <- sc
+	}()
+	cli, err := Handshake(cliConn, cliKey, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ser := <-serChan
+
+	//flip a bit of the ciphertext on the wire
+	sealed := cli.sendCipher.Seal(nil, makeNonce(cli.sendCipher, cli.sendNonce), []byte("hello"), nil)
+	sealed[0] ^= 1
+	frame := []byte{0, 0, 0, byte(len(sealed))}
+	go cliConn.Write(append(frame, sealed...))
+	if _, err := ser.Read(make([]byte, 5)); err == nil {
+		t.Fatal("tampered frame should be rejected")
+	}
+}
+
+func TestLoadNodeKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nodekey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "nodekey")
+
+	key, err := LoadNodeKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadNodeKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.ID() != loaded.ID() {
+		t.Fatal("node key not persisted")
+	}
+}
@@ -174,6 +174,7 @@ func NewVersion(n p2pnet.P2P, isCons bool, height uint32) mt.Message {
 	} else {
 		version.P.Cap[msgCommon.HTTP_INFO_FLAG] = 0x00
 	}
+	version.P.Cap[msgCommon.COMPACT_BLOCK_FLAG] = 0x01
 	return &version
 }
 
@@ -197,6 +198,16 @@ func NewBlkDataReq(hash common.Uint256) mt.Message {
 	return &dataReq
 }
 
+//compact block request package
+func NewCompactBlkDataReq(hash common.Uint256) mt.Message {
+	log.Trace()
+	var dataReq mt.DataReq
+	dataReq.DataType = common.COMPACT_BLOCK
+	dataReq.Hash = hash
+
+	return &dataReq
+}
+
 //consensus request package
 func NewConsensusDataReq(hash common.Uint256) mt.Message {
 	log.Trace()
@@ -206,3 +217,29 @@ func NewConsensusDataReq(hash common.Uint256) mt.Message {
 
 	return &dataReq
 }
+
+//compact block package
+func NewCompactBlock(bk *ct.Block, merkleRoot common.Uint256, nonce uint64) mt.Message {
+	log.Trace()
+	return mt.NewCompactBlock(bk, merkleRoot, nonce)
+}
+
+//missing txs of compact block request package
+func NewGetBlockTxn(hash common.Uint256, indexes []uint32) mt.Message {
+	log.Trace()
+	var req mt.GetBlockTxn
+	req.BlockHash = hash
+	req.Indexes = indexes
+
+	return &req
+}
+
+//missing txs of compact block package
+func NewBlockTxn(hash common.Uint256, txs []*ct.Transaction) mt.Message {
+	log.Trace()
+	var blkTxn mt.BlockTxn
+	blkTxn.BlockHash = hash
+	blkTxn.Txs = txs
+
+	return &blkTxn
+}
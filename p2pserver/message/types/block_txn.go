@@ -0,0 +1,115 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ontio/ontology/common"
+	ct "github.com/ontio/ontology/core/types"
+	comm "github.com/ontio/ontology/p2pserver/common"
+)
+
+//GetBlockTxn request the txs of a compact block missing from txnpool
+type GetBlockTxn struct {
+	BlockHash common.Uint256
+	Indexes   []uint32 //tx indexes in block
+}
+
+//Serialize message payload
+func (this *GetBlockTxn) Serialization(sink *common.ZeroCopySink) error {
+	sink.WriteHash(this.BlockHash)
+	sink.WriteUint32(uint32(len(this.Indexes)))
+	for _, index := range this.Indexes {
+		sink.WriteUint32(index)
+	}
+	return nil
+}
+
+func (this *GetBlockTxn) CmdType() string {
+	return comm.GET_BLK_TXN_TYPE
+}
+
+//Deserialize message payload
+func (this *GetBlockTxn) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.BlockHash, eof = source.NextHash()
+	count, eof := source.NextUint32()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	if count > comm.MAX_BLK_TXN_CNT {
+		return fmt.Errorf("getblocktxn index count %d exceed limit", count)
+	}
+	this.Indexes = make([]uint32, 0, count)
+	for i := uint32(0); i < count; i++ {
+		index, eof := source.NextUint32()
+		if eof {
+			return io.ErrUnexpectedEOF
+		}
+		this.Indexes = append(this.Indexes, index)
+	}
+	return nil
+}
+
+//BlockTxn is the response of GetBlockTxn, txs are in the order of request indexes
+type BlockTxn struct {
+	BlockHash common.Uint256
+	Txs       []*ct.Transaction
+}
+
+//Serialize message payload
+func (this *BlockTxn) Serialization(sink *common.ZeroCopySink) error {
+	sink.WriteHash(this.BlockHash)
+	sink.WriteUint32(uint32(len(this.Txs)))
+	for _, tx := range this.Txs {
+		err := tx.Serialization(sink)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (this *BlockTxn) CmdType() string {
+	return comm.BLK_TXN_TYPE
+}
+
+//Deserialize message payload
+func (this *BlockTxn) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.BlockHash, eof = source.NextHash()
+	count, eof := source.NextUint32()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	if count > comm.MAX_BLK_TXN_CNT {
+		return fmt.Errorf("blocktxn tx count %d exceed limit", count)
+	}
+	for i := uint32(0); i < count; i++ {
+		tx := new(ct.Transaction)
+		err := tx.Deserialization(source)
+		if err != nil {
+			return fmt.Errorf("read blocktxn transaction error:%s", err)
+		}
+		this.Txs = append(this.Txs, tx)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ontio/ontology/common"
+	ct "github.com/ontio/ontology/core/types"
+	comm "github.com/ontio/ontology/p2pserver/common"
+	tc "github.com/ontio/ontology/txnpool/common"
+)
+
+//CompactBlock is a block header with the short ids of its transactions,
+//the receiver rebuild the block from its txnpool
+type CompactBlock struct {
+	Header     *ct.Header
+	MerkleRoot common.Uint256
+	Nonce      uint64   //salt of short ids
+	ShortIDs   []uint64 //short ids of txs in block order
+}
+
+//NewCompactBlock make the compact block of blk with nonce as short id salt
+func NewCompactBlock(blk *ct.Block, merkleRoot common.Uint256, nonce uint64) *CompactBlock {
+	blkHash := blk.Hash()
+	ids := make([]uint64, 0, len(blk.Transactions))
+	for _, tx := range blk.Transactions {
+		ids = append(ids, ShortTxID(nonce, blkHash, tx.Hash()))
+	}
+	return &CompactBlock{
+		Header:     blk.Header,
+		MerkleRoot: merkleRoot,
+		Nonce:      nonce,
+		ShortIDs:   ids,
+	}
+}
+
+//ShortTxID return the short id of tx in the block salted by nonce, which is shared with txnpool
+//to look up the txs of compact block
+func ShortTxID(nonce uint64, blkHash common.Uint256, txHash common.Uint256) uint64 {
+	return tc.ShortTxID(nonce, blkHash, txHash)
+}
+
+//Serialize message payload
+func (this *CompactBlock) Serialization(sink *common.ZeroCopySink) error {
+	err := this.Header.Serialization(sink)
+	if err != nil {
+		return err
+	}
+	sink.WriteHash(this.MerkleRoot)
+	sink.WriteUint64(this.Nonce)
+	sink.WriteUint32(uint32(len(this.ShortIDs)))
+	for _, id := range this.ShortIDs {
+		sink.WriteUint64(id)
+	}
+	return nil
+}
+
+func (this *CompactBlock) CmdType() string {
+	return comm.CMPCT_BLOCK_TYPE
+}
+
+//Deserialize message payload
+func (this *CompactBlock) Deserialization(source *common.ZeroCopySource) error {
+	this.Header = new(ct.Header)
+	err := this.Header.Deserialization(source)
+	if err != nil {
+		return fmt.Errorf("read compact block header error:%s", err)
+	}
+	var eof bool
+	this.MerkleRoot, eof = source.NextHash()
+	this.Nonce, eof = source.NextUint64()
+	count, eof := source.NextUint32()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	if count > comm.MAX_BLK_TXN_CNT {
+		return fmt.Errorf("compact block tx count %d exceed limit", count)
+	}
+	this.ShortIDs = make([]uint64, 0, count)
+	for i := uint32(0); i < count; i++ {
+		id, eof := source.NextUint64()
+		if eof {
+			return io.ErrUnexpectedEOF
+		}
+		this.ShortIDs = append(this.ShortIDs, id)
+	}
+	return nil
+}
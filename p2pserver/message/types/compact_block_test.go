@@ -0,0 +1,93 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/common"
+	ct "github.com/ontio/ontology/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const testRawTx = "00d1af758596f401000000000000204e000000000000b09ba6a4fe99eb2b2dc1d86a6d453423a6be03f02e0101011552c1126765744469736b506c61796572734c697374676a6f1082c6cec3a1bcbb5a3892cf770061e4b98200014241015d434467639fd8e7b4331d2f3fc0d4168e2d68a203593c6399f5746d2324217aeeb3db8ff31ba0fdb1b13aa6f4c3cd25f7b3d0d26c144bbd75e2963d0a443629232103fdcae8110c9a60d1fc47f8111a12c1941e1f3584b0b0028157736ed1eecd101eac"
+
+func newTestBlock(t *testing.T) *ct.Block {
+	raw, _ := common.HexToBytes(testRawTx)
+	tx, err := ct.TransactionFromRawBytes(raw)
+	assert.Nil(t, err)
+
+	header := &ct.Header{
+		Height:      1,
+		Bookkeepers: make([]keypair.PublicKey, 0),
+		SigData:     make([][]byte, 0),
+	}
+	blk := &ct.Block{
+		Header:       header,
+		Transactions: []*ct.Transaction{tx},
+	}
+	blk.RebuildMerkleRoot()
+	return blk
+}
+
+func readMessageBytes(t *testing.T, msg Message) Message {
+	sink := common.NewZeroCopySink(nil)
+	err := WriteMessage(sink, msg)
+	assert.Nil(t, err)
+
+	demsg, _, err := ReadMessage(bytes.NewBuffer(sink.Bytes()))
+	assert.Nil(t, err)
+	return demsg
+}
+
+func TestCompactBlockSerializationDeserialization(t *testing.T) {
+	blk := newTestBlock(t)
+	msg := NewCompactBlock(blk, common.UINT256_EMPTY, 0x1234)
+
+	demsg := readMessageBytes(t, msg).(*CompactBlock)
+	assert.Equal(t, blk.Hash(), demsg.Header.Hash())
+	assert.Equal(t, msg.MerkleRoot, demsg.MerkleRoot)
+	assert.Equal(t, msg.Nonce, demsg.Nonce)
+	assert.Equal(t, msg.ShortIDs, demsg.ShortIDs)
+	assert.Equal(t, ShortTxID(0x1234, blk.Hash(), blk.Transactions[0].Hash()), demsg.ShortIDs[0])
+	assert.NotEqual(t, ShortTxID(0x1235, blk.Hash(), blk.Transactions[0].Hash()), demsg.ShortIDs[0])
+}
+
+func TestGetBlockTxnSerializationDeserialization(t *testing.T) {
+	msg := &GetBlockTxn{
+		BlockHash: newTestBlock(t).Hash(),
+		Indexes:   []uint32{0, 3, 7},
+	}
+	MessageTest(t, msg)
+}
+
+func TestBlockTxnSerializationDeserialization(t *testing.T) {
+	blk := newTestBlock(t)
+	msg := &BlockTxn{
+		BlockHash: blk.Hash(),
+		Txs:       blk.Transactions,
+	}
+
+	demsg := readMessageBytes(t, msg).(*BlockTxn)
+	assert.Equal(t, msg.BlockHash, demsg.BlockHash)
+	assert.Equal(t, 1, len(demsg.Txs))
+	assert.Equal(t, blk.Transactions[0].Hash(), demsg.Txs[0].Hash())
+}
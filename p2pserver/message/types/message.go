@@ -177,6 +177,12 @@ func MakeEmptyMessage(cmdType string) (Message, error) {
 		return &Disconnected{}, nil
 	case common.GET_BLOCKS_TYPE:
 		return &BlocksReq{}, nil
+	case common.CMPCT_BLOCK_TYPE:
+		return &CompactBlock{}, nil
+	case common.GET_BLK_TXN_TYPE:
+		return &GetBlockTxn{}, nil
+	case common.BLK_TXN_TYPE:
+		return &BlockTxn{}, nil
 	default:
 		return nil, errors.New("unsupported cmd type:" + cmdType)
 	}
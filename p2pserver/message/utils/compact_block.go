@@ -0,0 +1,249 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+	evtActor "github.com/ontio/ontology-eventbus/actor"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/ledger"
+	"github.com/ontio/ontology/core/types"
+	actor "github.com/ontio/ontology/p2pserver/actor/req"
+	msgCommon "github.com/ontio/ontology/p2pserver/common"
+	"github.com/ontio/ontology/p2pserver/message/msg_pack"
+	msgTypes "github.com/ontio/ontology/p2pserver/message/types"
+	"github.com/ontio/ontology/p2pserver/net/protocol"
+	"github.com/ontio/ontology/p2pserver/peer"
+)
+
+//pendingCompactBlock is a compact block waiting for its missing txs
+type pendingCompactBlock struct {
+	fromID      uint64
+	payloadSize uint32
+	block       *msgTypes.CompactBlock
+	txs         []*types.Transaction
+	missing     []uint32
+}
+
+//cmpctBlkCache cache the pending compact blocks by block hash
+var cmpctBlkCache *lru.ARCCache
+var cmpctBlkCacheOnce sync.Once
+
+func getPendingCompactBlock(hash common.Uint256) *pendingCompactBlock {
+	if cmpctBlkCache == nil {
+		return nil
+	}
+	if pending, ok := cmpctBlkCache.Get(hash); ok {
+		return pending.(*pendingCompactBlock)
+	}
+	return nil
+}
+
+func savePendingCompactBlock(hash common.Uint256, pending *pendingCompactBlock) bool {
+	cmpctBlkCacheOnce.Do(func() {
+		var err error
+		cmpctBlkCache, err = lru.NewARC(msgCommon.MAX_CMPCT_BLK_CACHE)
+		if err != nil {
+			log.Errorf("[p2p]create compact block cache error:%s", err)
+		}
+	})
+	if cmpctBlkCache == nil {
+		return false
+	}
+	cmpctBlkCache.Add(hash, pending)
+	return true
+}
+
+//compactBlockReqHandle reply the compact block of hash to remote peer
+func compactBlockReqHandle(remotePeer *peer.Peer, hash common.Uint256, p2p p2p.P2P) {
+	reqID := fmt.Sprintf("%x%s", common.COMPACT_BLOCK, hash.ToHexString())
+	msg, _ := getRespCacheValue(reqID).(*msgTypes.CompactBlock)
+	if msg == nil {
+		block, err := ledger.DefLedger.GetBlockByHash(hash)
+		if err != nil || block == nil || block.Header == nil {
+			log.Debug("[p2p]can't get block by hash: ", hash, " ,send not found message")
+			if err := p2p.Send(remotePeer, msgpack.NewNotFound(hash), false); err != nil {
+				log.Warn(err)
+			}
+			return
+		}
+		merkleRoot, err := ledger.DefLedger.GetStateMerkleRoot(block.Header.Height)
+		if err != nil {
+			log.Debugf("[p2p]failed to get state merkel root at height %v, err %v",
+				block.Header.Height, err)
+			if err := p2p.Send(remotePeer, msgpack.NewNotFound(hash), false); err != nil {
+				log.Warn(err)
+			}
+			return
+		}
+		msg = msgpack.NewCompactBlock(block, merkleRoot, rand.Uint64()).(*msgTypes.CompactBlock)
+		saveRespCache(reqID, msg)
+	}
+	if err := p2p.Send(remotePeer, msg, false); err != nil {
+		log.Warn(err)
+	}
+}
+
+// CompactBlockHandle rebuilds the block from txnpool, and requests the
+// missing txs from peer
+func CompactBlockHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args ...interface{}) {
+	log.Trace("[p2p]receive compact block message from ", data.Addr, data.Id)
+
+	var cmpct = data.Payload.(*msgTypes.CompactBlock)
+	remotePeer := p2p.GetPeer(data.Id)
+	if remotePeer == nil {
+		log.Debug("[p2p]remotePeer invalid in CompactBlockHandle")
+		return
+	}
+	hash := cmpct.Header.Hash()
+	if isContain, err := ledger.DefLedger.IsContainBlock(hash); err != nil || isContain {
+		return
+	}
+
+	//short id collisions in txnpool are returned as nil, and left to getblocktxn
+	poolTxs, err := actor.GetTransactionsByShortID(cmpct.Nonce, hash, cmpct.ShortIDs)
+	if err != nil {
+		log.Warnf("[p2p]get txnpool txs error:%s, request full block %x", err, hash)
+		requestFullBlock(remotePeer, hash, p2p)
+		return
+	}
+
+	pending := &pendingCompactBlock{
+		fromID:      data.Id,
+		payloadSize: data.PayloadSize,
+		block:       cmpct,
+		txs:         poolTxs,
+	}
+	for i, tx := range poolTxs {
+		if tx == nil {
+			pending.missing = append(pending.missing, uint32(i))
+		}
+	}
+	if len(pending.missing) == 0 {
+		completeCompactBlock(pending, remotePeer, p2p, pid)
+		return
+	}
+
+	log.Debugf("[p2p]compact block %x missing %d of %d txs", hash,
+		len(pending.missing), len(cmpct.ShortIDs))
+	if !savePendingCompactBlock(hash, pending) {
+		requestFullBlock(remotePeer, hash, p2p)
+		return
+	}
+	err = p2p.Send(remotePeer, msgpack.NewGetBlockTxn(hash, pending.missing), false)
+	if err != nil {
+		log.Warn(err)
+	}
+}
+
+// GetBlockTxnHandle replies the requested txs of block to peer
+func GetBlockTxnHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args ...interface{}) {
+	log.Trace("[p2p]receive getblocktxn message from ", data.Addr, data.Id)
+
+	var req = data.Payload.(*msgTypes.GetBlockTxn)
+	remotePeer := p2p.GetPeer(data.Id)
+	if remotePeer == nil {
+		log.Debug("[p2p]remotePeer invalid in GetBlockTxnHandle")
+		return
+	}
+	block, err := ledger.DefLedger.GetBlockByHash(req.BlockHash)
+	if err != nil || block == nil {
+		log.Debug("[p2p]can't get block by hash: ", req.BlockHash, " ,send not found message")
+		if err := p2p.Send(remotePeer, msgpack.NewNotFound(req.BlockHash), false); err != nil {
+			log.Warn(err)
+		}
+		return
+	}
+	txs := make([]*types.Transaction, 0, len(req.Indexes))
+	for _, index := range req.Indexes {
+		if int(index) >= len(block.Transactions) {
+			log.Debugf("[p2p]getblocktxn index %d out of range from %d", index, data.Id)
+			p2p.Penalize(data.Id, data.Addr, msgCommon.OFFENCE_MALFORMED_MSG)
+			return
+		}
+		txs = append(txs, block.Transactions[index])
+	}
+	if err := p2p.Send(remotePeer, msgpack.NewBlockTxn(req.BlockHash, txs), false); err != nil {
+		log.Warn(err)
+	}
+}
+
+// BlockTxnHandle fills the missing txs into the pending compact block
+func BlockTxnHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args ...interface{}) {
+	log.Trace("[p2p]receive blocktxn message from ", data.Addr, data.Id)
+
+	var blkTxn = data.Payload.(*msgTypes.BlockTxn)
+	remotePeer := p2p.GetPeer(data.Id)
+	if remotePeer == nil {
+		log.Debug("[p2p]remotePeer invalid in BlockTxnHandle")
+		return
+	}
+	pending := getPendingCompactBlock(blkTxn.BlockHash)
+	if pending == nil || pending.fromID != data.Id {
+		return
+	}
+	cmpctBlkCache.Remove(blkTxn.BlockHash)
+	if len(blkTxn.Txs) != len(pending.missing) {
+		log.Debugf("[p2p]blocktxn tx count %d mismatch %d", len(blkTxn.Txs), len(pending.missing))
+		requestFullBlock(remotePeer, blkTxn.BlockHash, p2p)
+		return
+	}
+	for i, index := range pending.missing {
+		pending.txs[index] = blkTxn.Txs[i]
+	}
+	pending.payloadSize += data.PayloadSize
+	completeCompactBlock(pending, remotePeer, p2p, pid)
+}
+
+//completeCompactBlock check the rebuilt block and append it to block sync
+func completeCompactBlock(pending *pendingCompactBlock, remotePeer *peer.Peer, p2p p2p.P2P, pid *evtActor.PID) {
+	hashes := make([]common.Uint256, 0, len(pending.txs))
+	for _, tx := range pending.txs {
+		hashes = append(hashes, tx.Hash())
+	}
+	block := &types.Block{
+		Header:       pending.block.Header,
+		Transactions: pending.txs,
+	}
+	if common.ComputeMerkleRoot(hashes) != block.Header.TransactionsRoot {
+		//short id collision with txnpool, fall back to full block
+		log.Debugf("[p2p]rebuilt compact block %x mismatch transaction root", block.Hash())
+		requestFullBlock(remotePeer, block.Hash(), p2p)
+		return
+	}
+	if pid != nil {
+		pid.Tell(&msgCommon.AppendBlock{
+			FromID:     pending.fromID,
+			BlockSize:  pending.payloadSize,
+			Block:      block,
+			MerkleRoot: pending.block.MerkleRoot,
+		})
+	}
+}
+
+func requestFullBlock(remotePeer *peer.Peer, hash common.Uint256, p2p p2p.P2P) {
+	if err := p2p.Send(remotePeer, msgpack.NewBlkDataReq(hash), false); err != nil {
+		log.Warn(err)
+	}
+}
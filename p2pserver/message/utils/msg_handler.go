@@ -179,6 +179,7 @@ func ConsensusHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID,
 		var consensus = data.Payload.(*msgTypes.Consensus)
 		if err := consensus.Cons.Verify(); err != nil {
 			log.Warn(err)
+			p2p.Penalize(data.Id, data.Addr, msgCommon.OFFENCE_INVALID_CONSENSUS)
 			return
 		}
 		consensus.Cons.PeerId = data.Id
@@ -240,6 +241,25 @@ func VersionHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, ar
 
 	}
 
+	//the peer id must be the one bound to the node key of encrypted link
+	versionLink := remotePeer.SyncLink
+	if version.P.IsConsensus {
+		versionLink = remotePeer.ConsLink
+	}
+	if id, ok := versionLink.GetNodeID(); ok && id != version.P.Nonce {
+		remotePeer.CloseSync()
+		remotePeer.CloseCons()
+		log.Warnf("[p2p]peer id %d not match node key of %s,close", version.P.Nonce, data.Addr)
+		return
+	}
+
+	if p2p.GetReputation().IsIDBanned(version.P.Nonce) {
+		remotePeer.CloseSync()
+		remotePeer.CloseCons()
+		log.Infof("[p2p]peer %d is banned,close %s", version.P.Nonce, data.Addr)
+		return
+	}
+
 	if version.P.IsConsensus == true {
 		if config.DefConfig.P2PNode.DualPortSupport == false {
 			log.Warn("[p2p]consensus port not surpport", data.Addr)
@@ -358,6 +378,7 @@ func VersionHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, ar
 			remotePeer.SetHttpInfoState(false)
 		}
 		remotePeer.SetHttpInfoPort(version.P.HttpInfoPort)
+		remotePeer.SetCompactBlockState(version.P.Cap[msgCommon.COMPACT_BLOCK_FLAG] == 0x01)
 
 		remotePeer.UpdateInfo(time.Now(), version.P.Version,
 			version.P.Services, version.P.SyncPort,
@@ -548,6 +569,9 @@ func DataReqHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, ar
 			return
 		}
 
+	case common.COMPACT_BLOCK:
+		compactBlockReqHandle(remotePeer, hash, p2p)
+
 	case common.TRANSACTION:
 		txn, err := ledger.DefLedger.GetTransaction(hash)
 		if err != nil {
@@ -618,7 +642,12 @@ func InvHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args .
 				msgTypes.LastInvHash = id
 				// send the block request
 				log.Infof("[p2p]inv request block hash: %x", id)
-				msg := msgpack.NewBlkDataReq(id)
+				var msg msgTypes.Message
+				if remotePeer.GetCompactBlockState() {
+					msg = msgpack.NewCompactBlkDataReq(id)
+				} else {
+					msg = msgpack.NewBlkDataReq(id)
+				}
 				err = p2p.Send(remotePeer, msg, false)
 				if err != nil {
 					log.Warn(err)
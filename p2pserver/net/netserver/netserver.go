@@ -22,6 +22,7 @@ import (
 	"errors"
 	"math/rand"
 	"net"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -30,6 +31,7 @@ import (
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/core/ledger"
 	"github.com/ontio/ontology/p2pserver/common"
+	"github.com/ontio/ontology/p2pserver/link"
 	"github.com/ontio/ontology/p2pserver/message/msg_pack"
 	"github.com/ontio/ontology/p2pserver/message/types"
 	"github.com/ontio/ontology/p2pserver/net/protocol"
@@ -64,6 +66,8 @@ type NetServer struct {
 	inConnRecord  InConnectionRecord
 	outConnRecord OutConnectionRecord
 	OwnAddress    string //network`s own address(ip : sync port),which get from version check
+	reputation    *peer.Reputation
+	nodeKey       *link.NodeKey //static key of encrypted link
 }
 
 //InConnectionRecord include all addr connected
@@ -123,6 +127,15 @@ func (this *NetServer) init() error {
 
 	rand.Seed(time.Now().UnixNano())
 	id := rand.Uint64()
+	if config.DefConfig.P2PNode.IsEncrypted {
+		key, err := link.LoadNodeKey(config.DefConfig.P2PNode.NodeKeyPath)
+		if err != nil {
+			log.Errorf("[p2p]load node key error:%s", err)
+			return err
+		}
+		this.nodeKey = key
+		id = key.ID()
+	}
 
 	this.base.SetID(id)
 
@@ -130,6 +143,12 @@ func (this *NetServer) init() error {
 	this.Np = &peer.NbrPeers{}
 	this.Np.Init()
 
+	banFile := filepath.Join(config.DefConfig.Common.DataDir, config.DefConfig.P2PNode.NetworkName, common.BAN_FILE_NAME)
+	this.reputation = peer.NewReputation(banFile)
+	if err := this.reputation.Load(); err != nil {
+		log.Warnf("[p2p]load ban list error:%s", err)
+	}
+
 	return nil
 }
 
@@ -314,6 +333,12 @@ func (this *NetServer) Connect(addr string, isConsensus bool) error {
 			return err
 		}
 	}
+	conn, err = this.secureConn(conn, true)
+	if err != nil {
+		this.RemoveFromConnectingList(addr)
+		log.Debugf("[p2p]handshake with %s failed:%s", addr, err.Error())
+		return err
+	}
 
 	addr = conn.RemoteAddr().String()
 	log.Debugf("[p2p]peer %s connect with %s with %s",
@@ -327,6 +352,7 @@ func (this *NetServer) Connect(addr string, isConsensus bool) error {
 		remotePeer.SyncLink.SetAddr(addr)
 		remotePeer.SyncLink.SetConn(conn)
 		remotePeer.AttachSyncChan(this.SyncChan)
+		remotePeer.AttachOffenceHandler(this.Penalize)
 		go remotePeer.SyncLink.Rx()
 		remotePeer.SetSyncState(common.HAND)
 
@@ -336,6 +362,7 @@ func (this *NetServer) Connect(addr string, isConsensus bool) error {
 		remotePeer.ConsLink.SetAddr(addr)
 		remotePeer.ConsLink.SetConn(conn)
 		remotePeer.AttachConsChan(this.ConsChan)
+		remotePeer.AttachOffenceHandler(this.Penalize)
 		go remotePeer.ConsLink.Rx()
 		remotePeer.SetConsState(common.HAND)
 	}
@@ -474,17 +501,28 @@ func (this *NetServer) startSyncAccept(listener net.Listener) {
 			continue
 		}
 
-		remotePeer := peer.NewPeer()
 		addr := conn.RemoteAddr().String()
 		this.AddInConnRecord(addr)
+		go this.acceptSyncConn(conn, addr)
+	}
+}
 
-		this.AddPeerSyncAddress(addr, remotePeer)
-
-		remotePeer.SyncLink.SetAddr(addr)
-		remotePeer.SyncLink.SetConn(conn)
-		remotePeer.AttachSyncChan(this.SyncChan)
-		go remotePeer.SyncLink.Rx()
+//acceptSyncConn attach the sync connection from the inbound peer to a new peer
+func (this *NetServer) acceptSyncConn(conn net.Conn, addr string) {
+	conn, err := this.secureConn(conn, false)
+	if err != nil {
+		log.Debugf("[p2p]handshake with %s failed:%s", addr, err.Error())
+		this.RemoveFromInConnRecord(addr)
+		return
 	}
+	remotePeer := peer.NewPeer()
+	this.AddPeerSyncAddress(addr, remotePeer)
+
+	remotePeer.SyncLink.SetAddr(addr)
+	remotePeer.SyncLink.SetConn(conn)
+	remotePeer.AttachSyncChan(this.SyncChan)
+	remotePeer.AttachOffenceHandler(this.Penalize)
+	remotePeer.SyncLink.Rx()
 }
 
 //startConsAccept accepts the consensus connnection from the inbound peer
@@ -514,15 +552,47 @@ func (this *NetServer) startConsAccept(listener net.Listener) {
 			continue
 		}
 
-		remotePeer := peer.NewPeer()
-		addr := conn.RemoteAddr().String()
-		this.AddPeerConsAddress(addr, remotePeer)
+		go this.acceptConsConn(conn, conn.RemoteAddr().String())
+	}
+}
 
-		remotePeer.ConsLink.SetAddr(addr)
-		remotePeer.ConsLink.SetConn(conn)
-		remotePeer.AttachConsChan(this.ConsChan)
-		go remotePeer.ConsLink.Rx()
+//acceptConsConn attach the consensus connection from the inbound peer to a new peer
+func (this *NetServer) acceptConsConn(conn net.Conn, addr string) {
+	conn, err := this.secureConn(conn, false)
+	if err != nil {
+		log.Debugf("[p2p]handshake with %s failed:%s", addr, err.Error())
+		return
 	}
+	remotePeer := peer.NewPeer()
+	this.AddPeerConsAddress(addr, remotePeer)
+
+	remotePeer.ConsLink.SetAddr(addr)
+	remotePeer.ConsLink.SetConn(conn)
+	remotePeer.AttachConsChan(this.ConsChan)
+	remotePeer.AttachOffenceHandler(this.Penalize)
+	remotePeer.ConsLink.Rx()
+}
+
+//secureConn run the encrypted link handshake on conn if encryption is enabled,
+//conn is closed if the handshake failed
+func (this *NetServer) secureConn(conn net.Conn, initiator bool) (net.Conn, error) {
+	if !config.DefConfig.P2PNode.IsEncrypted {
+		return conn, nil
+	}
+	if this.nodeKey == nil {
+		conn.Close()
+		return nil, errors.New("[p2p]node key not loaded")
+	}
+	sc, err := link.Handshake(conn, this.nodeKey, initiator)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if this.reputation.IsIDBanned(sc.RemoteID()) {
+		conn.Close()
+		return nil, errors.New("[p2p]peer is banned")
+	}
+	return sc, nil
 }
 
 //record the peer which is going to be dialed and sent version message but not in establish state
@@ -776,6 +846,10 @@ func (this *NetServer) GetOutConnRecordLen() int {
 
 //AddrValid whether the addr could be connect or accept
 func (this *NetServer) AddrValid(addr string) bool {
+	if this.reputation.IsIPBanned(addr) {
+		log.Infof("[p2p]remote %s is banned", addr)
+		return false
+	}
 	if config.DefConfig.P2PNode.ReservedPeersOnly && len(config.DefConfig.P2PNode.ReservedCfg.ReservedPeers) > 0 {
 		for _, ip := range config.DefConfig.P2PNode.ReservedCfg.ReservedPeers {
 			if strings.HasPrefix(addr, ip) {
@@ -788,6 +862,48 @@ func (this *NetServer) AddrValid(addr string) bool {
 	return true
 }
 
+//GetReputation return the peer reputation of net layer
+func (this *NetServer) GetReputation() *peer.Reputation {
+	return this.reputation
+}
+
+//Penalize punish the peer for offence, the peer is disconnected once banned
+func (this *NetServer) Penalize(id uint64, addr string, offence common.Offence) {
+	p := this.GetPeer(id)
+	if addr == "" && p != nil {
+		addr = p.GetAddr()
+	}
+	ip := common.NormalizeIP(addr)
+	log.Debugf("[p2p]penalize peer %d %s for %s", id, addr, offence)
+	if this.reputation.Penalize(id, ip, offence) {
+		this.disconnect(id, ip)
+	}
+}
+
+//BanPeer ban the peer id or ip for duration and disconnect the banned peers
+func (this *NetServer) BanPeer(id uint64, ip string, duration time.Duration, reason string) {
+	if id != 0 {
+		this.reputation.BanID(id, duration, reason)
+	}
+	if ip != "" {
+		this.reputation.BanIP(ip, duration, reason)
+	}
+	this.disconnect(id, ip)
+}
+
+//disconnect close the neighbor with id or ip
+func (this *NetServer) disconnect(id uint64, ip string) {
+	ip = common.NormalizeIP(ip)
+	for _, p := range this.Np.GetNeighbors() {
+		peerIp := common.NormalizeIP(p.GetAddr())
+		if (id != 0 && p.GetID() == id) || (ip != "" && peerIp == ip) {
+			log.Infof("[p2p]disconnect banned peer %d %s", p.GetID(), p.GetAddr())
+			p.CloseSync()
+			p.CloseCons()
+		}
+	}
+}
+
 //check own network address
 func (this *NetServer) IsOwnAddress(addr string) bool {
 	if addr == this.OwnAddress {
@@ -20,6 +20,8 @@
 package p2p
 
 import (
+	"time"
+
 	"github.com/ontio/ontology/p2pserver/common"
 	"github.com/ontio/ontology/p2pserver/message/types"
 	"github.com/ontio/ontology/p2pserver/peer"
@@ -51,6 +53,7 @@ type P2P interface {
 	GetPeerFromAddr(addr string) *peer.Peer
 	AddOutConnectingList(addr string) (added bool)
 	GetOutConnRecordLen() int
+	GetInConnRecordLen() int
 	RemoveFromConnectingList(addr string)
 	RemoveFromOutConnRecord(addr string)
 	RemoveFromInConnRecord(addr string)
@@ -66,4 +69,7 @@ type P2P interface {
 	SetOwnAddress(addr string)
 	IsOwnAddress(addr string) bool
 	IsAddrFromConnecting(addr string) bool
+	GetReputation() *peer.Reputation
+	Penalize(id uint64, addr string, offence common.Offence)
+	BanPeer(id uint64, ip string, duration time.Duration, reason string)
 }
@@ -228,6 +228,13 @@ func (this *P2PServer) GetPID() *evtActor.PID {
 	return this.pid
 }
 
+//GetSyncState return the block sync state of node
+func (this *P2PServer) GetSyncState() *SyncState {
+	state := this.blockSync.GetSyncState()
+	state.Synced = this.blockSyncFinished()
+	return state
+}
+
 //blockSyncFinished compare all nbr peers and self height at beginning
 func (this *P2PServer) blockSyncFinished() bool {
 	peers := this.network.GetNeighbors()
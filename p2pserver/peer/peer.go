@@ -326,6 +326,12 @@ func (this *Peer) AttachConsChan(msgchan chan *types.MsgPayload) {
 	this.ConsLink.SetChan(msgchan)
 }
 
+//AttachOffenceHandler set offence handler to sync and consensus link
+func (this *Peer) AttachOffenceHandler(handler conn.OffenceHandler) {
+	this.SyncLink.SetOffenceHandler(handler)
+	this.ConsLink.SetOffenceHandler(handler)
+}
+
 //Send transfer buffer by sync or cons link
 func (this *Peer) Send(msg types.Message, isConsensus bool) error {
 	if isConsensus && this.ConsLink.Valid() {
@@ -348,6 +354,20 @@ func (this *Peer) GetHttpInfoState() bool {
 	return this.cap[common.HTTP_INFO_FLAG] == 1
 }
 
+//SetCompactBlockState set whether peer support compact block relay
+func (this *Peer) SetCompactBlockState(compact bool) {
+	if compact {
+		this.cap[common.COMPACT_BLOCK_FLAG] = 0x01
+	} else {
+		this.cap[common.COMPACT_BLOCK_FLAG] = 0x00
+	}
+}
+
+//GetCompactBlockState return whether peer support compact block relay
+func (this *Peer) GetCompactBlockState() bool {
+	return this.cap[common.COMPACT_BLOCK_FLAG] == 1
+}
+
 //GetHttpInfoPort return peer`s httpinfo port
 func (this *Peer) GetHttpInfoPort() uint16 {
 	return this.base.GetHttpInfoPort()
@@ -0,0 +1,311 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package peer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/p2pserver/common"
+)
+
+//BanEntry is a banned peer id or ip. Exactly one of ID and IP is set
+type BanEntry struct {
+	ID       uint64 `json:"id,omitempty"`
+	IP       string `json:"ip,omitempty"`
+	Reason   string `json:"reason"`
+	ExpireAt int64  `json:"expire_at"` //unix time in sec
+}
+
+//penalty score of a peer, decayed over time
+type score struct {
+	value      uint32
+	lastUpdate int64
+}
+
+func (this *score) current(now int64) uint32 {
+	decay := (now - this.lastUpdate) / common.SCORE_DECAY_PERIOD
+	if decay >= int64(this.value) {
+		return 0
+	}
+	return this.value - uint32(decay)
+}
+
+//Reputation keeps penalty scores of peers and the ban list
+type Reputation struct {
+	sync.RWMutex
+	idScores  map[uint64]*score
+	ipScores  map[string]*score
+	bannedIDs map[uint64]*BanEntry
+	bannedIPs map[string]*BanEntry
+	banFile   string //ban list persist file, empty means no persistence
+}
+
+//NewReputation return a reputation with the ban list persisted in banFile
+func NewReputation(banFile string) *Reputation {
+	return &Reputation{
+		idScores:  make(map[uint64]*score),
+		ipScores:  make(map[string]*score),
+		bannedIDs: make(map[uint64]*BanEntry),
+		bannedIPs: make(map[string]*BanEntry),
+		banFile:   banFile,
+	}
+}
+
+//Load restore the unexpired ban entries from ban file
+func (this *Reputation) Load() error {
+	if this.banFile == "" {
+		return nil
+	}
+	if _, err := os.Stat(this.banFile); os.IsNotExist(err) {
+		return nil
+	}
+	buf, err := ioutil.ReadFile(this.banFile)
+	if err != nil {
+		return fmt.Errorf("read ban file error:%s", err)
+	}
+	var entries []*BanEntry
+	if err := json.Unmarshal(buf, &entries); err != nil {
+		return fmt.Errorf("parse ban file error:%s", err)
+	}
+	now := time.Now().Unix()
+	this.Lock()
+	defer this.Unlock()
+	for _, entry := range entries {
+		if entry.ExpireAt <= now {
+			continue
+		}
+		if entry.IP != "" {
+			entry.IP = common.NormalizeIP(entry.IP)
+			this.bannedIPs[entry.IP] = entry
+		} else {
+			this.bannedIDs[entry.ID] = entry
+		}
+	}
+	return nil
+}
+
+//Penalize add the offence penalty to the peer id and ip, return true if
+//the peer is banned
+func (this *Reputation) Penalize(id uint64, ip string, offence common.Offence) bool {
+	ip = common.NormalizeIP(ip)
+	this.Lock()
+	defer this.Unlock()
+
+	now := time.Now().Unix()
+	banned := false
+	if id != 0 && this.addScore(this.idScore(id), offence.Penalty(), now) {
+		delete(this.idScores, id)
+		this.bannedIDs[id] = &BanEntry{
+			ID:       id,
+			Reason:   offence.String(),
+			ExpireAt: now + common.BAN_DURATION,
+		}
+		banned = true
+	}
+	if ip != "" && this.addScore(this.ipScore(ip), offence.Penalty(), now) {
+		delete(this.ipScores, ip)
+		this.bannedIPs[ip] = &BanEntry{
+			IP:       ip,
+			Reason:   offence.String(),
+			ExpireAt: now + common.BAN_DURATION,
+		}
+		banned = true
+	}
+	if banned {
+		log.Infof("[p2p]peer %d %s banned for %s", id, ip, offence)
+		this.save()
+	}
+	return banned
+}
+
+func (this *Reputation) idScore(id uint64) *score {
+	s, ok := this.idScores[id]
+	if !ok {
+		s = &score{}
+		this.idScores[id] = s
+	}
+	return s
+}
+
+func (this *Reputation) ipScore(ip string) *score {
+	s, ok := this.ipScores[ip]
+	if !ok {
+		s = &score{}
+		this.ipScores[ip] = s
+	}
+	return s
+}
+
+//addScore return true if the score reach the ban threshold
+func (this *Reputation) addScore(s *score, penalty uint32, now int64) bool {
+	s.value = s.current(now) + penalty
+	s.lastUpdate = now
+	return s.value >= common.BAN_SCORE
+}
+
+//GetScore return the current penalty score of the peer id
+func (this *Reputation) GetScore(id uint64) uint32 {
+	this.RLock()
+	defer this.RUnlock()
+	s, ok := this.idScores[id]
+	if !ok {
+		return 0
+	}
+	return s.current(time.Now().Unix())
+}
+
+//BanID ban the peer id for duration, 0 means the default ban duration
+func (this *Reputation) BanID(id uint64, duration time.Duration, reason string) {
+	this.Lock()
+	defer this.Unlock()
+	this.bannedIDs[id] = &BanEntry{
+		ID:       id,
+		Reason:   reason,
+		ExpireAt: expireAt(duration),
+	}
+	this.save()
+}
+
+//BanIP ban the ip for duration, 0 means the default ban duration
+func (this *Reputation) BanIP(ip string, duration time.Duration, reason string) {
+	ip = common.NormalizeIP(ip)
+	this.Lock()
+	defer this.Unlock()
+	this.bannedIPs[ip] = &BanEntry{
+		IP:       ip,
+		Reason:   reason,
+		ExpireAt: expireAt(duration),
+	}
+	this.save()
+}
+
+//UnbanID remove the peer id from ban list, return false if not banned
+func (this *Reputation) UnbanID(id uint64) bool {
+	this.Lock()
+	defer this.Unlock()
+	if _, ok := this.bannedIDs[id]; !ok {
+		return false
+	}
+	delete(this.bannedIDs, id)
+	delete(this.idScores, id)
+	this.save()
+	return true
+}
+
+//UnbanIP remove the ip from ban list, return false if not banned
+func (this *Reputation) UnbanIP(ip string) bool {
+	ip = common.NormalizeIP(ip)
+	this.Lock()
+	defer this.Unlock()
+	if _, ok := this.bannedIPs[ip]; !ok {
+		return false
+	}
+	delete(this.bannedIPs, ip)
+	delete(this.ipScores, ip)
+	this.save()
+	return true
+}
+
+//IsIDBanned return whether the peer id is banned
+func (this *Reputation) IsIDBanned(id uint64) bool {
+	this.Lock()
+	defer this.Unlock()
+	entry, ok := this.bannedIDs[id]
+	if !ok {
+		return false
+	}
+	if entry.ExpireAt <= time.Now().Unix() {
+		delete(this.bannedIDs, id)
+		return false
+	}
+	return true
+}
+
+//IsIPBanned return whether the ip is banned
+func (this *Reputation) IsIPBanned(ip string) bool {
+	ip = common.NormalizeIP(ip)
+	this.Lock()
+	defer this.Unlock()
+	entry, ok := this.bannedIPs[ip]
+	if !ok {
+		return false
+	}
+	if entry.ExpireAt <= time.Now().Unix() {
+		delete(this.bannedIPs, ip)
+		return false
+	}
+	return true
+}
+
+//GetBanList return all unexpired ban entries
+func (this *Reputation) GetBanList() []*BanEntry {
+	this.RLock()
+	defer this.RUnlock()
+	return this.banList(time.Now().Unix())
+}
+
+func (this *Reputation) banList(now int64) []*BanEntry {
+	entries := make([]*BanEntry, 0, len(this.bannedIDs)+len(this.bannedIPs))
+	for _, entry := range this.bannedIDs {
+		if entry.ExpireAt > now {
+			e := *entry
+			entries = append(entries, &e)
+		}
+	}
+	for _, entry := range this.bannedIPs {
+		if entry.ExpireAt > now {
+			e := *entry
+			entries = append(entries, &e)
+		}
+	}
+	return entries
+}
+
+//save persist the ban list, the caller must hold the lock
+func (this *Reputation) save() {
+	if this.banFile == "" {
+		return
+	}
+	buf, err := json.Marshal(this.banList(time.Now().Unix()))
+	if err != nil {
+		log.Errorf("[p2p]marshal ban list error:%s", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(this.banFile), 0700); err != nil {
+		log.Errorf("[p2p]create ban file dir error:%s", err)
+		return
+	}
+	if err := ioutil.WriteFile(this.banFile, buf, 0600); err != nil {
+		log.Errorf("[p2p]write ban file error:%s", err)
+	}
+}
+
+func expireAt(duration time.Duration) int64 {
+	if duration <= 0 {
+		duration = common.BAN_DURATION * time.Second
+	}
+	return time.Now().Add(duration).Unix()
+}
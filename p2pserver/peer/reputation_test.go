@@ -0,0 +1,148 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package peer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology/p2pserver/common"
+)
+
+func TestReputationPenalize(t *testing.T) {
+	rep := NewReputation("")
+	id := uint64(0x7533345)
+	ip := "127.0.0.1"
+
+	for i := 0; i < 4; i++ {
+		if rep.Penalize(id, ip, common.OFFENCE_INVALID_BLOCK) {
+			t.Fatalf("peer banned after %d offences", i+1)
+		}
+	}
+	if rep.GetScore(id) != common.OFFENCE_INVALID_BLOCK.Penalty()*4 {
+		t.Fatalf("unexpected score %d", rep.GetScore(id))
+	}
+	if !rep.Penalize(id, ip, common.OFFENCE_INVALID_BLOCK) {
+		t.Fatal("peer not banned after reaching ban score")
+	}
+	if !rep.IsIDBanned(id) || !rep.IsIPBanned(ip) {
+		t.Fatal("peer id and ip should be banned")
+	}
+	if !rep.UnbanID(id) || !rep.UnbanIP(ip) {
+		t.Fatal("unban failed")
+	}
+	if rep.IsIDBanned(id) || rep.IsIPBanned(ip) {
+		t.Fatal("peer id and ip should be unbanned")
+	}
+	if rep.UnbanID(id) {
+		t.Fatal("unban a peer not in ban list")
+	}
+}
+
+func TestReputationMsgFlood(t *testing.T) {
+	rep := NewReputation("")
+	if rep.Penalize(1, "127.0.0.1", common.OFFENCE_MSG_FLOOD) {
+		t.Fatal("single message burst should not ban the peer")
+	}
+}
+
+func TestReputationIPv6(t *testing.T) {
+	rep := NewReputation("")
+	ip, err := common.ParseHostIP("[2001:db8:0::1]:20338")
+	if err != nil || ip != "2001:db8::1" {
+		t.Fatalf("unexpected ipv6 %s error:%v", ip, err)
+	}
+	rep.BanIP("2001:0db8::0001", time.Hour, "manual")
+	if !rep.IsIPBanned(ip) {
+		t.Fatal("ipv6 should be banned")
+	}
+	if !rep.UnbanIP("2001:db8:0:0::1") {
+		t.Fatal("unban ipv6 failed")
+	}
+}
+
+func TestReputationNormalizeIP(t *testing.T) {
+	rep := NewReputation("")
+	//offences from different ports of the ip add up
+	for i := 0; i < 5; i++ {
+		rep.Penalize(0, fmt.Sprintf("127.0.0.1:%d", 20338+i), common.OFFENCE_INVALID_BLOCK)
+	}
+	if !rep.IsIPBanned("127.0.0.1") || !rep.IsIPBanned("127.0.0.1:30338") || !rep.IsIPBanned("::ffff:127.0.0.1") {
+		t.Fatal("ip with port or in ipv4-mapped form should be banned")
+	}
+	rep.BanIP("[2001:db8::1]:20338", time.Hour, "manual")
+	if !rep.IsIPBanned("2001:0db8::0001") || !rep.IsIPBanned("[2001:db8::1]") {
+		t.Fatal("ipv6 with port or brackets should be banned")
+	}
+	for _, entry := range rep.GetBanList() {
+		if entry.IP != "" && entry.IP != "127.0.0.1" && entry.IP != "2001:db8::1" {
+			t.Fatalf("ban list ip %s is not normalized", entry.IP)
+		}
+	}
+}
+
+func TestReputationExpire(t *testing.T) {
+	rep := NewReputation("")
+	rep.BanIP("10.0.0.1", time.Second, "test")
+	if !rep.IsIPBanned("10.0.0.1") {
+		t.Fatal("ip should be banned")
+	}
+	rep.bannedIPs["10.0.0.1"].ExpireAt = time.Now().Unix() - 1
+	if rep.IsIPBanned("10.0.0.1") {
+		t.Fatal("ban should be expired")
+	}
+	if len(rep.GetBanList()) != 0 {
+		t.Fatal("expired entry in ban list")
+	}
+}
+
+func TestReputationPersist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reputation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, common.BAN_FILE_NAME)
+
+	rep := NewReputation(file)
+	rep.BanID(1, 0, "manual")
+	rep.BanIP("10.0.0.2", time.Hour, "manual")
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("unexpected ban file mode %v", info.Mode().Perm())
+	}
+
+	restored := NewReputation(file)
+	if err := restored.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if !restored.IsIDBanned(1) || !restored.IsIPBanned("10.0.0.2") {
+		t.Fatal("ban list not restored")
+	}
+	if len(restored.GetBanList()) != 2 {
+		t.Fatalf("unexpected ban list size %d", len(restored.GetBanList()))
+	}
+}
@@ -18,15 +18,21 @@
 package main
 
 import (
+	"fmt"
+	"github.com/ontio/ontology/account"
 	"github.com/ontio/ontology/cmd"
 	"github.com/ontio/ontology/cmd/abi"
+	cmdcom "github.com/ontio/ontology/cmd/common"
 	cmdsvr "github.com/ontio/ontology/cmd/sigsvr"
 	clisvrcom "github.com/ontio/ontology/cmd/sigsvr/common"
+	"github.com/ontio/ontology/cmd/sigsvr/policy"
 	"github.com/ontio/ontology/cmd/sigsvr/store"
 	"github.com/ontio/ontology/cmd/utils"
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/common/password"
 	"github.com/urfave/cli"
+	"net"
 	"os"
 	"os/signal"
 	"runtime"
@@ -46,9 +52,20 @@ func setupSigSvr() *cli.App {
 		utils.CliAddressFlag,
 		utils.CliRpcPortFlag,
 		utils.CliABIPathFlag,
+		//node rpc port to broadcast multisig transaction
+		utils.RPCPortFlag,
+		utils.CliRemoteSignerFlag,
+		utils.RemoteSignerTokenFileFlag,
+		utils.CliTlsCertFlag,
+		utils.CliTlsKeyFlag,
+		//signing policy
+		utils.CliPolicyFlag,
+		utils.CliAuditLogFlag,
+		utils.CliAuditAccountFlag,
 	}
 	app.Commands = []cli.Command{
 		cmdsvr.ImportWalletCommand,
+		cmdsvr.VerifyAuditCommand,
 	}
 	app.Before = func(context *cli.Context) error {
 		runtime.GOMAXPROCS(runtime.NumCPU())
@@ -87,6 +104,37 @@ func startSigSvr(ctx *cli.Context) {
 		log.Errorf("Please using sig server port by --%s flag", utils.GetFlagName(utils.CliRpcPortFlag))
 		return
 	}
+	cmd.SetRpcPort(ctx)
+
+	certFile := ctx.String(utils.GetFlagName(utils.CliTlsCertFlag))
+	keyFile := ctx.String(utils.GetFlagName(utils.CliTlsKeyFlag))
+	if (certFile == "") != (keyFile == "") {
+		log.Errorf("Please using --%s and --%s flag together", utils.GetFlagName(utils.CliTlsCertFlag),
+			utils.GetFlagName(utils.CliTlsKeyFlag))
+		return
+	}
+	cmdsvr.DefCliRpcSvr.SetTls(certFile, keyFile)
+
+	err = initPolicy(ctx.String(utils.GetFlagName(utils.CliPolicyFlag)), ctx.String(utils.GetFlagName(utils.CliAuditLogFlag)),
+		ctx.String(utils.GetFlagName(utils.CliAuditAccountFlag)))
+	if err != nil {
+		log.Errorf("initPolicy error:%s", err)
+		return
+	}
+
+	signerAddr := ctx.String(utils.GetFlagName(utils.CliRemoteSignerFlag))
+	if signerAddr != "" {
+		token, err := cmdcom.GetRemoteSignerToken(ctx)
+		if err != nil {
+			log.Errorf("GetRemoteSignerToken error:%s", err)
+			return
+		}
+		err = initRemoteSigner(signerAddr, token, rpcAddress)
+		if err != nil {
+			log.Errorf("initRemoteSigner error:%s", err)
+			return
+		}
+	}
 	go cmdsvr.DefCliRpcSvr.Start(rpcAddress, rpcPort)
 
 	abiPath := ctx.GlobalString(utils.GetFlagName(utils.CliABIPathFlag))
@@ -108,6 +156,84 @@ func startSigSvr(ctx *cli.Context) {
 	<-exit
 }
 
+func unlockAccount(address, usage string) (*account.Account, error) {
+	passwd, err := password.GetHiddenInput(fmt.Sprintf("Password of %s account %s:", usage, address))
+	if err != nil {
+		return nil, fmt.Errorf("input password error:%s", err)
+	}
+	acc, err := clisvrcom.DefWalletStore.GetAccountByAddress(address, passwd)
+	if err != nil {
+		return nil, err
+	}
+	if acc == nil {
+		return nil, fmt.Errorf("cannot find account by %s", address)
+	}
+	return acc, nil
+}
+
+func initRemoteSigner(address, token, bindAddress string) error {
+	if token == "" {
+		return fmt.Errorf("remote signer needs a token, set by --%s flag or env %s",
+			utils.GetFlagName(utils.RemoteSignerTokenFileFlag), utils.REMOTE_SIGNER_TOKEN_ENV)
+	}
+	if !cmdsvr.DefCliRpcSvr.IsTls() && !isLoopback(bindAddress) {
+		return fmt.Errorf("remote signer on non-loopback address %s needs tls, set by --%s and --%s flag",
+			bindAddress, utils.GetFlagName(utils.CliTlsCertFlag), utils.GetFlagName(utils.CliTlsKeyFlag))
+	}
+	acc, err := unlockAccount(address, "remote signer")
+	if err != nil {
+		return err
+	}
+	cmdsvr.DefCliRpcSvr.RegHttpHandler("/signer/", cmdsvr.NewRemoteSignerHandler(acc, token))
+	log.Infof("Remote signer of account:%s init success", address)
+	return nil
+}
+
+//isLoopback return whether the bind address only accepts local connections
+func isLoopback(address string) bool {
+	if address == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(address)
+	return ip != nil && ip.IsLoopback()
+}
+
+func initPolicy(policyFile, auditFile, auditAddress string) error {
+	if auditFile != "" {
+		if auditAddress == "" {
+			return fmt.Errorf("audit log needs --%s flag", utils.GetFlagName(utils.CliAuditAccountFlag))
+		}
+		acc, err := unlockAccount(auditAddress, "audit")
+		if err != nil {
+			return err
+		}
+		auditLog, entries, err := policy.OpenAuditLog(auditFile, acc)
+		if err != nil {
+			return err
+		}
+		clisvrcom.DefAuditLog = auditLog
+		log.Infof("Audit log:%s loaded, entry number:%d", auditFile, len(entries))
+		if policyFile != "" {
+			clisvrcom.DefPolicyEngine, err = policy.LoadPolicyEngine(policyFile)
+			if err != nil {
+				return err
+			}
+			clisvrcom.DefPolicyEngine.Replay(entries)
+		}
+	} else if policyFile != "" {
+		log.Warnf("Policy without audit log, daily limit is reset when sig server restarts")
+		var err error
+		clisvrcom.DefPolicyEngine, err = policy.LoadPolicyEngine(policyFile)
+		if err != nil {
+			return err
+		}
+	}
+	if policyFile != "" {
+		log.Infof("Signing policy:%s loaded", policyFile)
+	}
+	return nil
+}
+
 func main() {
 	if err := setupSigSvr().Run(os.Args); err != nil {
 		cmd.PrintErrorMsg(err.Error())
@@ -17,7 +17,13 @@
  */
 package abi
 
-import "strings"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ontio/ontology/common"
+)
 
 const (
 	NEOVM_PARAM_TYPE_BOOL       = "boolean"
@@ -36,6 +42,21 @@ type NeovmContractAbi struct {
 	Events     []*NeovmContractEventAbi    `json:"events"`
 }
 
+//ParseNeovmContractAbi parse the NeoVM abi json of contract, which has functions or events. The hash of abi
+//is set to contract address
+func ParseNeovmContractAbi(contract common.Address, abiData []byte) (*NeovmContractAbi, error) {
+	contractAbi := &NeovmContractAbi{}
+	err := json.Unmarshal(abiData, contractAbi)
+	if err != nil {
+		return nil, fmt.Errorf("json.Unmarshal NeovmContractAbi error:%s", err)
+	}
+	if len(contractAbi.Functions) == 0 && len(contractAbi.Events) == 0 {
+		return nil, fmt.Errorf("abi has neither functions nor events")
+	}
+	contractAbi.Address = contract.ToHexString()
+	return contractAbi, nil
+}
+
 func (this *NeovmContractAbi) GetFunc(method string) *NeovmContractFunctionAbi {
 	method = strings.ToLower(method)
 	for _, funcAbi := range this.Functions {
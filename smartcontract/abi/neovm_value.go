@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package abi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ontio/ontology/common"
+)
+
+//NeovmEventValue is the event of NeoVM contract decoded by ABI
+type NeovmEventValue struct {
+	Name   string                 `json:"name"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+//DecodeReturn decode raw return value of method by return type of function ABI
+func (this *NeovmContractAbi) DecodeReturn(method string, rawValue interface{}) (interface{}, error) {
+	funcAbi := this.GetFunc(method)
+	if funcAbi == nil {
+		return nil, fmt.Errorf("cannot find abi of method:%s", method)
+	}
+	return DecodeNeovmValue(funcAbi.ReturnType, rawValue)
+}
+
+//DecodeEvent decode states of NeoVM notify by event ABI. The first state is the event name,
+//and the others are the fields of event in order of ABI parameters.
+func (this *NeovmContractAbi) DecodeEvent(states interface{}) (*NeovmEventValue, error) {
+	items, ok := states.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("invalid event states:%v", states)
+	}
+	rawName, ok := items[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid event name:%v", items[0])
+	}
+	name, err := hex.DecodeString(rawName)
+	if err != nil {
+		return nil, fmt.Errorf("hex.DecodeString event name:%s error:%s", rawName, err)
+	}
+	evtAbi := this.GetEvent(string(name))
+	if evtAbi == nil {
+		return nil, fmt.Errorf("cannot find abi of event:%s", name)
+	}
+	if len(items)-1 != len(evtAbi.Parameters) {
+		return nil, fmt.Errorf("event:%s need %d fields, got %d", evtAbi.Name, len(evtAbi.Parameters), len(items)-1)
+	}
+	fields := make(map[string]interface{}, len(evtAbi.Parameters))
+	for i, paramAbi := range evtAbi.Parameters {
+		value, err := DecodeNeovmValue(paramAbi.Type, items[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("event:%s field:%s error:%s", evtAbi.Name, paramAbi.Name, err)
+		}
+		fields[paramAbi.Name] = value
+	}
+	return &NeovmEventValue{
+		Name:   evtAbi.Name,
+		Fields: fields,
+	}, nil
+}
+
+//DecodeNeovmValue decode raw value of NeoVM by ABI type. Raw value is hex string, or array of raw value.
+//integer is decoded to *big.Int, boolean to bool, string to string, and bytearray keeps hex string.
+//Items of array and any type are kept as raw value, since their type is unknown.
+func DecodeNeovmValue(paramType string, rawValue interface{}) (interface{}, error) {
+	switch strings.ToLower(paramType) {
+	case NEOVM_PARAM_TYPE_VOID:
+		return nil, nil
+	case NEOVM_PARAM_TYPE_ARRAY:
+		v, ok := rawValue.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid array value:%v", rawValue)
+		}
+		return v, nil
+	case NEOVM_PARAM_TYPE_ANY, "":
+		return rawValue, nil
+	}
+	hexStr, ok := rawValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s value type:%s", paramType, reflect.TypeOf(rawValue))
+	}
+	data, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, fmt.Errorf("hex.DecodeString:%s error:%s", hexStr, err)
+	}
+	switch strings.ToLower(paramType) {
+	case NEOVM_PARAM_TYPE_INTEGER:
+		return common.BigIntFromNeoBytes(data), nil
+	case NEOVM_PARAM_TYPE_BOOL:
+		for _, b := range data {
+			if b != 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	case NEOVM_PARAM_TYPE_STRING:
+		return string(data), nil
+	case NEOVM_PARAM_TYPE_BYTE_ARRAY:
+		return hexStr, nil
+	default:
+		return nil, fmt.Errorf("unsupported abi type:%s", paramType)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package abi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testNeovmAbi = `{
+  "hash": "0xe827bf96529b5780ad0702757b8bad315e2bb8ce",
+  "entrypoint": "Main",
+  "functions": [
+    {
+      "name": "BalanceOf",
+      "parameters": [
+        {
+          "name": "address",
+          "type": "ByteArray"
+        }
+      ],
+      "returntype": "Integer"
+    }
+  ],
+  "events": [
+    {
+      "name": "transfer",
+      "parameters": [
+        {
+          "name": "from",
+          "type": "ByteArray"
+        },
+        {
+          "name": "to",
+          "type": "ByteArray"
+        },
+        {
+          "name": "amount",
+          "type": "Integer"
+        }
+      ],
+      "returntype": "Void"
+    }
+  ]
+}`
+
+func TestDecodeNeovmValue(t *testing.T) {
+	value, err := DecodeNeovmValue("Integer", "e803")
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(1000), value)
+
+	value, err = DecodeNeovmValue("Integer", "")
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(0), value)
+
+	value, err = DecodeNeovmValue("Boolean", "01")
+	assert.Nil(t, err)
+	assert.Equal(t, true, value)
+
+	value, err = DecodeNeovmValue("Boolean", "")
+	assert.Nil(t, err)
+	assert.Equal(t, false, value)
+
+	value, err = DecodeNeovmValue("String", hex.EncodeToString([]byte("foo")))
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", value)
+
+	value, err = DecodeNeovmValue("ByteArray", "0102")
+	assert.Nil(t, err)
+	assert.Equal(t, "0102", value)
+
+	value, err = DecodeNeovmValue("Void", "")
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+
+	_, err = DecodeNeovmValue("Integer", []interface{}{"01"})
+	assert.NotNil(t, err)
+	_, err = DecodeNeovmValue("String", "zz")
+	assert.NotNil(t, err)
+}
+
+func TestDecodeEvent(t *testing.T) {
+	contractAbi := &NeovmContractAbi{}
+	err := json.Unmarshal([]byte(testNeovmAbi), contractAbi)
+	assert.Nil(t, err)
+
+	value, err := contractAbi.DecodeReturn("balanceOf", "e803")
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(1000), value)
+
+	states := []interface{}{hex.EncodeToString([]byte("transfer")), "01", "02", "64"}
+	evt, err := contractAbi.DecodeEvent(states)
+	assert.Nil(t, err)
+	assert.Equal(t, "transfer", evt.Name)
+	assert.Equal(t, "01", evt.Fields["from"])
+	assert.Equal(t, "02", evt.Fields["to"])
+	assert.Equal(t, big.NewInt(100), evt.Fields["amount"])
+
+	_, err = contractAbi.DecodeEvent(states[:3])
+	assert.NotNil(t, err)
+	_, err = contractAbi.DecodeEvent([]interface{}{hex.EncodeToString([]byte("approve"))})
+	assert.NotNil(t, err)
+	_, err = contractAbi.DecodeEvent("0102")
+	assert.NotNil(t, err)
+}
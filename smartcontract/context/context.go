@@ -42,6 +42,23 @@ type ContextRef interface {
 	CheckExecStep() bool
 }
 
+// Tracer capture the neovm execution steps, it is only set when debugging a contract
+// CaptureOp is called before executing an opcode with its gas cost
+// CaptureSyscall is called before executing a syscall with its gas cost
+// CaptureEnter and CaptureExit are called around AppCall and native invocation
+type Tracer interface {
+	CaptureOp(contract common.Address, pc int, op string, gas uint64)
+	CaptureSyscall(contract common.Address, name string, gas uint64)
+	CaptureEnter(kind string, contract common.Address, method string)
+	CaptureExit(err error)
+}
+
+const (
+	CALL_KIND_ENTRY   = "Entry"        //Entry code of invoke transaction
+	CALL_KIND_APPCALL = "AppCall"      //Call neovm contract by APPCALL opcode
+	CALL_KIND_NATIVE  = "NativeInvoke" //Call native contract by syscall
+)
+
 type Engine interface {
 	Invoke() (interface{}, error)
 }
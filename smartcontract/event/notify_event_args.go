@@ -46,3 +46,32 @@ type ExecuteNotify struct {
 	GasConsumed uint64
 	Notify      []*NotifyEventInfo
 }
+
+// ContractEventNotify describe a smart contract event notify found by contract index
+type ContractEventNotify struct {
+	Height     uint32
+	TxHash     common.Uint256
+	EventIndex uint32
+	Notify     *NotifyEventInfo
+}
+
+// Topic return the first state of event notify, which is used to index event notify. The topic of
+// native contract is the event name, e.g. "transfer", while the states of NeoVM contract are converted
+// to hex strings, so the topic is the hex of the first notified value
+func (this *NotifyEventInfo) Topic() string {
+	switch states := this.States.(type) {
+	case string:
+		return states
+	case []interface{}:
+		if len(states) > 0 {
+			if topic, ok := states[0].(string); ok {
+				return topic
+			}
+		}
+	case []string:
+		if len(states) > 0 {
+			return states[0]
+		}
+	}
+	return ""
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/smartcontract/context"
 	"github.com/ontio/ontology/smartcontract/service/native"
 	"github.com/ontio/ontology/smartcontract/states"
 	vm "github.com/ontio/ontology/vm/neovm"
@@ -81,7 +82,13 @@ func NativeInvoke(service *NeoVmService, engine *vm.ExecutionEngine) error {
 		ServiceMap:  make(map[string]native.Handler),
 	}
 
+	if service.Tracer != nil {
+		service.Tracer.CaptureEnter(context.CALL_KIND_NATIVE, addr, contract.Method)
+	}
 	result, err := native.Invoke()
+	if service.Tracer != nil {
+		service.Tracer.CaptureExit(err)
+	}
 	if err != nil {
 		return err
 	}
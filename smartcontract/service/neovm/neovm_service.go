@@ -128,6 +128,7 @@ type NeoVmService struct {
 	BlockHash     scommon.Uint256
 	Engine        *vm.ExecutionEngine
 	PreExec       bool
+	Tracer        context.Tracer
 }
 
 // Invoke a smart contract
@@ -157,6 +158,10 @@ func (this *NeoVmService) Invoke() (interface{}, error) {
 			}
 		}
 		if this.Engine.OpCode >= vm.PUSHBYTES1 && this.Engine.OpCode <= vm.PUSHBYTES75 {
+			if this.Tracer != nil {
+				this.Tracer.CaptureOp(this.ContextRef.CurrentContext().ContractAddress,
+					this.Engine.Context.GetInstructionPointer()-1, fmt.Sprintf("PUSHBYTES%d", this.Engine.OpCode), OPCODE_GAS)
+			}
 			if !this.ContextRef.CheckUseGas(OPCODE_GAS) {
 				return nil, ERR_GAS_INSUFFICIENT
 			}
@@ -168,6 +173,10 @@ func (this *NeoVmService) Invoke() (interface{}, error) {
 			if err != nil {
 				return nil, err
 			}
+			if this.Tracer != nil {
+				this.Tracer.CaptureOp(this.ContextRef.CurrentContext().ContractAddress,
+					this.Engine.Context.GetInstructionPointer()-1, this.Engine.OpExec.Name, price)
+			}
 			if !this.ContextRef.CheckUseGas(price) {
 				return nil, ERR_GAS_INSUFFICIENT
 			}
@@ -232,7 +241,13 @@ func (this *NeoVmService) Invoke() (interface{}, error) {
 				return nil, err
 			}
 			this.Engine.EvaluationStack.CopyTo(service.(*NeoVmService).Engine.EvaluationStack)
+			if this.Tracer != nil {
+				this.Tracer.CaptureEnter(context.CALL_KIND_APPCALL, addr, "")
+			}
 			result, err := service.Invoke()
+			if this.Tracer != nil {
+				this.Tracer.CaptureExit(err)
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -275,6 +290,9 @@ func (this *NeoVmService) SystemCall(engine *vm.ExecutionEngine) error {
 	if err != nil {
 		return err
 	}
+	if this.Tracer != nil {
+		this.Tracer.CaptureSyscall(this.ContextRef.CurrentContext().ContractAddress, serviceName, price)
+	}
 	if !this.ContextRef.CheckUseGas(price) {
 		return ERR_GAS_INSUFFICIENT
 	}
@@ -46,6 +46,7 @@ type SmartContract struct {
 	Gas           uint64
 	ExecStep      int
 	PreExec       bool
+	Tracer        context.Tracer // capture execution steps, nil if not debugging
 }
 
 // Config describe smart contract need parameters configuration
@@ -137,6 +138,7 @@ func (this *SmartContract) NewExecuteEngine(code []byte) (context.Engine, error)
 		BlockHash:  this.Config.BlockHash,
 		Engine:     vm.NewExecutionEngine(),
 		PreExec:    this.PreExec,
+		Tracer:     this.Tracer,
 	}
 	return service, nil
 }
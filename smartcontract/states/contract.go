@@ -116,3 +116,79 @@ type PreExecResult struct {
 	Result interface{}
 	Notify []*event.NotifyEventInfo
 }
+
+// DebugExecResult is the result of dry-run a transaction with tracing
+type DebugExecResult struct {
+	State         byte
+	Gas           uint64
+	Result        interface{}
+	Error         string                   //execution error message if failed
+	Notify        []*event.NotifyEventInfo //notifications before the execution finish or fail
+	GasDetail     map[string]uint64        //gas cost grouped by syscall name, other opcodes and code length
+	Steps         []*ExecStep              //neovm opcode trace
+	Truncated     bool                     //whether the opcode trace is truncated
+	StorageWrites []*StorageWrite          //storage items written by the execution
+}
+
+// ExecStep is a neovm opcode step captured when debugging
+type ExecStep struct {
+	Contract common.Address
+	PC       int
+	Op       string
+	Gas      uint64
+}
+
+// StorageWrite is a storage item written by execution, Value is nil if the item is deleted
+type StorageWrite struct {
+	Key   []byte
+	Value []byte
+}
+
+// StateOverrides replace the storage items and native asset balances before dry-run a transaction
+type StateOverrides struct {
+	Storage  []*StorageOverride
+	Balances []*BalanceOverride
+}
+
+// StorageOverride replace the storage item of contract, the item is deleted if Value is empty
+type StorageOverride struct {
+	Contract common.Address
+	Key      []byte
+	Value    []byte
+}
+
+// BalanceOverride replace the balance of account in native asset contract
+type BalanceOverride struct {
+	Asset   common.Address
+	Account common.Address
+	Balance uint64
+}
+
+// CallFrame is a contract invocation captured when tracing, Calls are the invocations made by it
+type CallFrame struct {
+	Kind     string
+	Contract common.Address
+	Method   string
+	Error    string
+	Calls    []*CallFrame
+}
+
+// StorageDiff is a storage item changed by transaction, Before or After is nil if the item not exist
+type StorageDiff struct {
+	Key    []byte
+	Before []byte
+	After  []byte
+}
+
+// TxTraceResult is the result of re-executing a mined transaction with tracing
+type TxTraceResult struct {
+	TxHash       common.Uint256
+	Height       uint32 //Block height of transaction
+	StateHeight  uint32 //Block height of the state the transaction re-executed on
+	State        byte
+	GasConsumed  uint64
+	Error        string //Execution error message if failed
+	Notify       []*event.NotifyEventInfo
+	Calls        []*CallFrame
+	StorageDiffs []*StorageDiff
+}
@@ -74,6 +74,15 @@ func (self *CacheDB) Commit() {
 	})
 }
 
+// ForEachStorage iterate the storage items changed in cache, the value of deleted item is empty
+func (self *CacheDB) ForEachStorage(fn func(key, val []byte)) {
+	self.memdb.ForEach(func(key, val []byte) {
+		if len(key) != 0 && key[0] == byte(common.ST_STORAGE) {
+			fn(key[1:], val)
+		}
+	})
+}
+
 func (self *CacheDB) Put(key []byte, value []byte) {
 	self.put(common.ST_STORAGE, key, value)
 }
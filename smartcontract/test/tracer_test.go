@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract"
+	"github.com/ontio/ontology/smartcontract/context"
+	"github.com/ontio/ontology/smartcontract/service/neovm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecTracer(t *testing.T) {
+	//PUSH1 PUSH2 ADD SYSCALL System.Runtime.GetTime
+	code := []byte{0x51, 0x52, 0x93, 0x68, byte(len(neovm.RUNTIME_GETTIME_NAME))}
+	code = append(code, []byte(neovm.RUNTIME_GETTIME_NAME)...)
+
+	tracer := smartcontract.NewExecTracer()
+	sc := smartcontract.SmartContract{
+		Config: &smartcontract.Config{
+			Time:   10,
+			Height: 10,
+			Tx:     &types.Transaction{},
+		},
+		Gas:    100000,
+		Tracer: tracer,
+	}
+	engine, err := sc.NewExecuteEngine(code)
+	assert.Nil(t, err)
+	_, err = engine.Invoke()
+	assert.Nil(t, err)
+
+	ops := []string{"PUSH1", "PUSH2", "ADD", "SYSCALL"}
+	assert.Equal(t, len(ops), len(tracer.Steps))
+	for i, step := range tracer.Steps {
+		assert.Equal(t, ops[i], step.Op)
+		assert.Equal(t, i, step.PC)
+		assert.Equal(t, common.AddressFromVmCode(code), step.Contract)
+	}
+	assert.False(t, tracer.Truncated)
+	_, ok := tracer.GasDetail[neovm.RUNTIME_GETTIME_NAME]
+	assert.True(t, ok)
+
+	var total uint64
+	for _, gas := range tracer.GasDetail {
+		total += gas
+	}
+	assert.Equal(t, uint64(100000)-sc.Gas, total)
+}
+
+func TestExecTracerCalls(t *testing.T) {
+	tracer := smartcontract.NewExecTracer()
+	tracer.CaptureEnter(context.CALL_KIND_ENTRY, common.Address{1}, "")
+	tracer.CaptureEnter(context.CALL_KIND_APPCALL, common.Address{2}, "")
+	tracer.CaptureEnter(context.CALL_KIND_NATIVE, common.Address{3}, "transfer")
+	tracer.CaptureExit(nil)
+	tracer.CaptureExit(errors.New("vm execute state fault"))
+	tracer.CaptureExit(errors.New("vm execute state fault"))
+
+	assert.Equal(t, 1, len(tracer.Calls))
+	entry := tracer.Calls[0]
+	assert.Equal(t, common.Address{1}, entry.Contract)
+	assert.Equal(t, 1, len(entry.Calls))
+	appCall := entry.Calls[0]
+	assert.Equal(t, context.CALL_KIND_APPCALL, appCall.Kind)
+	assert.Equal(t, "vm execute state fault", appCall.Error)
+	assert.Equal(t, 1, len(appCall.Calls))
+	assert.Equal(t, "transfer", appCall.Calls[0].Method)
+	assert.Equal(t, "", appCall.Calls[0].Error)
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartcontract
+
+import (
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/smartcontract/states"
+)
+
+const (
+	MAX_TRACE_STEPS = 100000   //Max opcode steps kept in trace
+	OPCODE_GAS_NAME = "OPCODE" //Gas detail name of the opcodes other than syscall
+)
+
+// ExecTracer collect the neovm opcode steps, gas cost of each syscall and the call tree
+type ExecTracer struct {
+	Steps     []*states.ExecStep
+	Truncated bool
+	GasDetail map[string]uint64
+	Calls     []*states.CallFrame
+	callStack []*states.CallFrame
+}
+
+// NewExecTracer return a new execution tracer
+func NewExecTracer() *ExecTracer {
+	return &ExecTracer{
+		GasDetail: make(map[string]uint64),
+	}
+}
+
+// CaptureOp record the opcode step, the gas of opcode is accumulated in OPCODE_GAS_NAME
+func (this *ExecTracer) CaptureOp(contract common.Address, pc int, op string, gas uint64) {
+	this.GasDetail[OPCODE_GAS_NAME] += gas
+	if len(this.Steps) >= MAX_TRACE_STEPS {
+		this.Truncated = true
+		return
+	}
+	this.Steps = append(this.Steps, &states.ExecStep{
+		Contract: contract,
+		PC:       pc,
+		Op:       op,
+		Gas:      gas,
+	})
+}
+
+// CaptureSyscall accumulate the gas of syscall by name
+func (this *ExecTracer) CaptureSyscall(contract common.Address, name string, gas uint64) {
+	this.GasDetail[name] += gas
+}
+
+// CaptureEnter push the invocation to call stack
+func (this *ExecTracer) CaptureEnter(kind string, contract common.Address, method string) {
+	frame := &states.CallFrame{
+		Kind:     kind,
+		Contract: contract,
+		Method:   method,
+	}
+	if len(this.callStack) == 0 {
+		this.Calls = append(this.Calls, frame)
+	} else {
+		parent := this.callStack[len(this.callStack)-1]
+		parent.Calls = append(parent.Calls, frame)
+	}
+	this.callStack = append(this.callStack, frame)
+}
+
+// CaptureExit pop the invocation from call stack and record its error
+func (this *ExecTracer) CaptureExit(err error) {
+	if len(this.callStack) == 0 {
+		return
+	}
+	frame := this.callStack[len(this.callStack)-1]
+	if err != nil {
+		frame.Error = err.Error()
+	}
+	this.callStack = this.callStack[:len(this.callStack)-1]
+}
@@ -170,6 +170,27 @@ func (tp *TXPool) GetTransaction(hash common.Uint256) *types.Transaction {
 	return tp.txList[hash].Tx
 }
 
+// GetTransactions returns all the transactions in the pool.
+func (tp *TXPool) GetTransactions() []*types.Transaction {
+	tp.RLock()
+	defer tp.RUnlock()
+	txs := make([]*types.Transaction, 0, len(tp.txList))
+	for _, txEntry := range tp.txList {
+		txs = append(txs, txEntry.Tx)
+	}
+	return txs
+}
+
+// RangeTransactions calls f on every transaction in the pool without copying
+// them out, f must not call back into the pool.
+func (tp *TXPool) RangeTransactions(f func(tx *types.Transaction)) {
+	tp.RLock()
+	defer tp.RUnlock()
+	for _, txEntry := range tp.txList {
+		f(txEntry.Tx)
+	}
+}
+
 // GetTxStatus returns a transaction status if it is contained in the pool
 // and nil otherwise.
 func (tp *TXPool) GetTxStatus(hash common.Uint256) *TxStatus {
@@ -237,14 +258,17 @@ func (tp *TXPool) GetUnverifiedTxs(txs []*types.Transaction,
 }
 
 // RemoveTxsBelowGasPrice drops all transactions below the gas price
-func (tp *TXPool) RemoveTxsBelowGasPrice(gasPrice uint64) {
+func (tp *TXPool) RemoveTxsBelowGasPrice(gasPrice uint64) []*types.Transaction {
 	tp.Lock()
 	defer tp.Unlock()
+	removed := make([]*types.Transaction, 0)
 	for _, txEntry := range tp.txList {
 		if txEntry.Tx.GasPrice < gasPrice {
 			delete(tp.txList, txEntry.Tx.Hash())
+			removed = append(removed, txEntry.Tx)
 		}
 	}
+	return removed
 }
 
 // Remain returns the remaining tx list to cleanup
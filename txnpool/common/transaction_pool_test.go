@@ -98,3 +98,25 @@ func TestTxPool(t *testing.T) {
 		return
 	}
 }
+
+func TestRemoveTxsBelowGasPrice(t *testing.T) {
+	txPool := &TXPool{}
+	txPool.Init()
+
+	mutable := &types.MutableTransaction{
+		TxType:   types.Invoke,
+		Nonce:    uint32(time.Now().Unix()) + 1,
+		GasPrice: 500,
+		Payload:  &payload.InvokeCode{Code: []byte{}},
+	}
+	highTx, _ := mutable.IntoImmutable()
+
+	txPool.AddTxList(&TXEntry{Tx: txn, Attrs: []*TXAttr{}})
+	txPool.AddTxList(&TXEntry{Tx: highTx, Attrs: []*TXAttr{}})
+
+	removed := txPool.RemoveTxsBelowGasPrice(500)
+	assert.Equal(t, 1, len(removed))
+	assert.Equal(t, txn.Hash(), removed[0].Hash())
+	assert.Equal(t, 1, txPool.GetTransactionCount())
+	assert.NotNil(t, txPool.GetTransaction(highTx.Hash()))
+}
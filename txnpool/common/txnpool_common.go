@@ -19,6 +19,9 @@
 package common
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/errors"
@@ -192,6 +195,30 @@ type GetPendingTxnRsp struct {
 	Txs []*types.Transaction
 }
 
+// GetTxnsByShortIDReq specifies the api that how to get the transactions
+// of a compact block by their short ids, from verified and pending ones.
+type GetTxnsByShortIDReq struct {
+	Nonce     uint64
+	BlockHash common.Uint256
+	ShortIDs  []uint64
+}
+
+// GetTxnsByShortIDRsp returns the transactions in the order of short ids of
+// GetTxnsByShortIDReq, nil for the missing or ambiguous ones.
+type GetTxnsByShortIDRsp struct {
+	Txs []*types.Transaction
+}
+
+// ShortTxID returns the short id of tx in the block salted by nonce
+func ShortTxID(nonce uint64, blkHash common.Uint256, txHash common.Uint256) uint64 {
+	var buf [8 + common.UINT256_SIZE*2]byte
+	binary.LittleEndian.PutUint64(buf[:8], nonce)
+	copy(buf[8:], blkHash[:])
+	copy(buf[8+common.UINT256_SIZE:], txHash[:])
+	hash := sha256.Sum256(buf[:])
+	return binary.LittleEndian.Uint64(hash[:8])
+}
+
 // consensus messages
 // GetTxnPoolReq specifies the api that how to get the valid transaction list.
 type GetTxnPoolReq struct {
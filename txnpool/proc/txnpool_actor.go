@@ -128,6 +128,7 @@ func (ta *TxActor) handleTransaction(sender tc.SenderType, self *actor.PID,
 		if sender == tc.HttpSender && txResultCh != nil {
 			replyTxResult(txResultCh, txn.Hash(), errors.ErrUnknown, "size is over 1M")
 		}
+		publishTxStatus(txn, message.TX_STATUS_REJECTED, "size is over 1M")
 		return
 	}
 
@@ -149,6 +150,7 @@ func (ta *TxActor) handleTransaction(sender tc.SenderType, self *actor.PID,
 			replyTxResult(txResultCh, txn.Hash(), errors.ErrTxPoolFull,
 				"transaction pool is full")
 		}
+		publishTxStatus(txn, message.TX_STATUS_REJECTED, "transaction pool is full")
 	} else {
 		if _, overflow := common.SafeMul(txn.GasLimit, txn.GasPrice); overflow {
 			log.Debugf("handleTransaction: gasLimit %v, gasPrice %v overflow",
@@ -158,6 +160,9 @@ func (ta *TxActor) handleTransaction(sender tc.SenderType, self *actor.PID,
 					fmt.Sprintf("gasLimit %d * gasPrice %d overflow",
 						txn.GasLimit, txn.GasPrice))
 			}
+			publishTxStatus(txn, message.TX_STATUS_REJECTED,
+				fmt.Sprintf("gasLimit %d * gasPrice %d overflow",
+					txn.GasLimit, txn.GasPrice))
 			return
 		}
 
@@ -171,6 +176,9 @@ func (ta *TxActor) handleTransaction(sender tc.SenderType, self *actor.PID,
 					fmt.Sprintf("Please input gasLimit >= %d and gasPrice >= %d",
 						gasLimitConfig, gasPriceConfig))
 			}
+			publishTxStatus(txn, message.TX_STATUS_REJECTED,
+				fmt.Sprintf("gasLimit %d or gasPrice %d is below %d, %d",
+					txn.GasLimit, txn.GasPrice, gasLimitConfig, gasPriceConfig))
 			return
 		}
 
@@ -182,6 +190,9 @@ func (ta *TxActor) handleTransaction(sender tc.SenderType, self *actor.PID,
 					fmt.Sprintf("Deploy tx gaslimit should >= %d",
 						neovm.CONTRACT_CREATE_GAS))
 			}
+			publishTxStatus(txn, message.TX_STATUS_REJECTED,
+				fmt.Sprintf("Deploy tx gaslimit should >= %d",
+					neovm.CONTRACT_CREATE_GAS))
 			return
 		}
 
@@ -191,6 +202,7 @@ func (ta *TxActor) handleTransaction(sender tc.SenderType, self *actor.PID,
 				if sender == tc.HttpSender && txResultCh != nil {
 					replyTxResult(txResultCh, txn.Hash(), errors.ErrUnknown, desc)
 				}
+				publishTxStatus(txn, message.TX_STATUS_REJECTED, desc)
 				return
 			}
 			log.Debugf("handleTransaction: preExecCheck tx %x passed", txn.Hash())
@@ -241,6 +253,17 @@ func (ta *TxActor) Receive(context actor.Context) {
 				context.Self())
 		}
 
+	case *tc.GetTxnsByShortIDReq:
+		sender := context.Sender()
+
+		log.Debugf("txpool-tx actor receives getting txs by short id req from %v", sender)
+
+		res := ta.server.getTxnsByShortID(msg)
+		if sender != nil {
+			sender.Request(&tc.GetTxnsByShortIDRsp{Txs: res},
+				context.Self())
+		}
+
 	case *tc.CheckTxnReq:
 		sender := context.Sender()
 
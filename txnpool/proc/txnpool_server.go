@@ -31,6 +31,8 @@ import (
 	"github.com/ontio/ontology/core/ledger"
 	tx "github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/errors"
+	"github.com/ontio/ontology/events"
+	"github.com/ontio/ontology/events/message"
 	httpcom "github.com/ontio/ontology/http/base/common"
 	params "github.com/ontio/ontology/smartcontract/service/native/global_params"
 	nutils "github.com/ontio/ontology/smartcontract/service/native/utils"
@@ -277,6 +279,14 @@ func (s *TXPoolServer) removePendingTx(hash common.Uint256,
 	if pt.sender == tc.HttpSender && pt.ch != nil {
 		replyTxResult(pt.ch, hash, err, err.Error())
 	}
+	if err != errors.ErrNoError && err != errors.ErrDuplicateInput {
+		if pt.sender == tc.NilSender {
+			//re-verification of a tx already in pool
+			publishTxStatus(pt.tx, message.TX_STATUS_EVICTED, err.Error())
+		} else {
+			publishTxStatus(pt.tx, message.TX_STATUS_REJECTED, err.Error())
+		}
+	}
 
 	delete(s.allPendingTxs, hash)
 
@@ -525,6 +535,47 @@ func (s *TXPoolServer) getPendingTxs(byCount bool) []*tx.Transaction {
 	return ret
 }
 
+// getTxnsByShortID returns the verified or pending txs matching the short ids
+// of compact block in order, nil for the missing ones and the ones whose
+// short id is shared by different txs
+func (s *TXPoolServer) getTxnsByShortID(req *tc.GetTxnsByShortIDReq) []*tx.Transaction {
+	positions := make(map[uint64][]int, len(req.ShortIDs))
+	for i, id := range req.ShortIDs {
+		positions[id] = append(positions[id], i)
+	}
+	matched := make(map[uint64]*tx.Transaction)
+	collided := make(map[uint64]bool)
+	match := func(t *tx.Transaction) {
+		hash := t.Hash()
+		id := tc.ShortTxID(req.Nonce, req.BlockHash, hash)
+		if _, ok := positions[id]; !ok {
+			return
+		}
+		if old, ok := matched[id]; ok && old.Hash() != hash {
+			collided[id] = true
+			return
+		}
+		matched[id] = t
+	}
+	s.txPool.RangeTransactions(match)
+	s.mu.RLock()
+	for _, v := range s.allPendingTxs {
+		match(v.tx)
+	}
+	s.mu.RUnlock()
+
+	txs := make([]*tx.Transaction, len(req.ShortIDs))
+	for id, t := range matched {
+		if collided[id] {
+			continue
+		}
+		for _, i := range positions[id] {
+			txs[i] = t
+		}
+	}
+	return txs
+}
+
 // cleanTransactionList cleans the txs in the block from the ledger
 func (s *TXPoolServer) cleanTransactionList(txs []*tx.Transaction, height uint32) {
 	s.txPool.CleanTransactionList(txs)
@@ -543,15 +594,20 @@ func (s *TXPoolServer) cleanTransactionList(txs []*tx.Transaction, height uint32
 		}
 
 		if oldGasPrice < gasPrice {
-			s.txPool.RemoveTxsBelowGasPrice(gasPrice)
+			removed := s.txPool.RemoveTxsBelowGasPrice(gasPrice)
+			for _, t := range removed {
+				publishTxStatus(t, message.TX_STATUS_EVICTED,
+					fmt.Sprintf("gasPrice %d is below %d", t.GasPrice, gasPrice))
+			}
 		}
 	}
 	// Cleanup tx pool
 	if !s.disablePreExec {
 		remain := s.txPool.Remain()
 		for _, t := range remain {
-			if ok, _ := preExecCheck(t); !ok {
+			if ok, desc := preExecCheck(t); !ok {
 				log.Debugf("cleanTransactionList: preExecCheck tx %x failed", t.Hash())
+				publishTxStatus(t, message.TX_STATUS_EVICTED, desc)
 				continue
 			}
 			s.reVerifyStateful(t, tc.NilSender)
@@ -569,10 +625,23 @@ func (s *TXPoolServer) addTxList(txEntry *tc.TXEntry) bool {
 	ret := s.txPool.AddTxList(txEntry)
 	if !ret {
 		s.increaseStats(tc.DuplicateStats)
+		return ret
+	}
+	if events.DefActorPublisher != nil {
+		events.DefActorPublisher.Publish(message.TOPIC_NEW_TRANSACTION,
+			&message.NewTransactionMsg{Tx: txEntry.Tx})
 	}
 	return ret
 }
 
+// publishTxStatus publishes the rejected or evicted status of a transaction
+func publishTxStatus(t *tx.Transaction, status string, reason string) {
+	if events.DefActorPublisher != nil {
+		events.DefActorPublisher.Publish(message.TOPIC_TX_STATUS,
+			&message.TxStatusMsg{Tx: t, Status: status, Reason: reason})
+	}
+}
+
 // increaseStats increases the count with the stats type
 func (s *TXPoolServer) increaseStats(v tc.TxnStatsType) {
 	s.stats.Lock()
@@ -287,3 +287,33 @@ func TestValidator(t *testing.T) {
 
 	t.Log("Ending validator testing")
 }
+
+func TestGetTxnsByShortID(t *testing.T) {
+	s := NewTxPoolServer(tc.MAX_WORKER_NUM, true, false)
+	if s == nil {
+		t.Error("Test case: new tx pool server failed")
+		return
+	}
+	defer s.Stop()
+
+	s.addTxList(&tc.TXEntry{
+		Tx:    txn,
+		Attrs: []*tc.TXAttr{},
+	})
+	blkHash := txn.Hash()
+	id := tc.ShortTxID(1, blkHash, txn.Hash())
+	req := &tc.GetTxnsByShortIDReq{
+		Nonce:     1,
+		BlockHash: blkHash,
+		ShortIDs:  []uint64{id, id + 1},
+	}
+	txs := s.getTxnsByShortID(req)
+	assert.Equal(t, 2, len(txs))
+	assert.Equal(t, txn, txs[0])
+	assert.Nil(t, txs[1])
+
+	//short id is salted by nonce
+	req.Nonce = 2
+	txs = s.getTxnsByShortID(req)
+	assert.Nil(t, txs[0])
+}